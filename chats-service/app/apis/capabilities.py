@@ -0,0 +1,51 @@
+# app/api/capabilities.py
+from fastapi import APIRouter, Request, status
+
+from app.helpers.capabilities import get_capabilities
+from app.helpers import inference_queue
+from app.middleware.auth_context import get_auth
+from app.utils.response import APIResponse
+from app.utils import error_codes
+
+router = APIRouter(tags=["capabilities"])
+
+
+@router.get("/capabilities")
+async def capabilities(request: Request, refresh: bool = False):
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(
+            True,
+            "Unauthorized",
+            None,
+            status.HTTP_401_UNAUTHORIZED,
+            code=error_codes.UNAUTHORIZED,
+        )
+
+    caps = await get_capabilities(force_refresh=refresh)
+    return APIResponse(False, "Capabilities fetched", caps, status.HTTP_200_OK)
+
+
+@router.get("/metrics/inference-queue")
+async def inference_queue_metrics(request: Request):
+    """Depth, wait-time and admission counters for the inference queue.
+    Owner only; mainly for dashboards and saturation debugging."""
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    if auth.role != "owner":
+        return APIResponse(
+            True,
+            "Only owners can view inference queue metrics",
+            None,
+            status.HTTP_403_FORBIDDEN,
+            code=error_codes.FORBIDDEN,
+        )
+
+    return APIResponse(
+        False,
+        "Inference queue metrics fetched",
+        inference_queue.queue_stats(),
+        status.HTTP_200_OK,
+    )
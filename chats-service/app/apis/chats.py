@@ -1,16 +1,34 @@
 # app/api/chats.py
 from fastapi import APIRouter, Request, status
-from fastapi.responses import StreamingResponse
+from fastapi.responses import JSONResponse, StreamingResponse
 from pydantic import BaseModel
 from typing import Optional
 import asyncio
 import json
+import os
+import secrets
 from app.helpers.rag_graph import query_rag_openai_stream
-from app.helpers.chat import create_chat
+from app.helpers.capabilities import require_embeddings, CHAT_MODEL
+from app.helpers.predict_params import (
+    validate_predict_params,
+    check_model_allowed,
+    sanitize_query_message,
+)
+from app.helpers.quota import consume_prediction_quota
+from app.helpers.prompt_templates import render_prompt_template
+from app.helpers.users_client import resolve_users
+from app.helpers.flags import PREDICT_ENABLED, flag_enabled
+from app.helpers import inference_queue
+from app.helpers.chat import create_chat, get_chat_settings, update_chat_settings, save_message_to_db
+from app.helpers.export_transcript import render_markdown, build_transcript
+from app.helpers.onboarding import mark_onboarding_step, STEP_FIRST_PREDICTION
+from app.middleware.auth_context import get_auth
 from app.utils.response import APIResponse
+from app.utils import error_codes
 from app.database.postgres_client import get_db_cursor
 
 router = APIRouter(prefix="/chats", tags=["chats"])
+shared_router = APIRouter(prefix="/shared", tags=["shared"])
 
 # --------------------------
 # List chats
@@ -20,35 +38,91 @@ class ChatListResponse(BaseModel):
     title: str
     last_message_at: Optional[str]
 
+# Allowed `sort` fields (API name -> column); mirrors utils.ParseSort in
+# users-service. Unknown fields are rejected so user input never reaches SQL.
+CHAT_SORT_FIELDS = {
+    "title": "title",
+    "created_at": "created_at",
+    "last_message_at": "last_message_at",
+}
+
+def parse_chat_sort(sort: str | None) -> tuple[str | None, str | None]:
+    """Returns (order_by_clause, error). Empty sort means caller default."""
+    if not sort:
+        return None, None
+    clauses = []
+    for part in sort.split(","):
+        part = part.strip()
+        if not part:
+            continue
+        direction = "ASC"
+        if part.startswith("-"):
+            direction = "DESC"
+            part = part[1:]
+        column = CHAT_SORT_FIELDS.get(part)
+        if not column:
+            return None, f"unsupported sort field '{part}'"
+        clauses.append(f"{column} {direction}")
+    return ", ".join(clauses) or None, None
+
 @router.get("/list")
-async def get_chats_list(request: Request):
-    claims = getattr(request.state, "claims", None)
-    if not claims:
+async def get_chats_list(
+    request: Request,
+    sort: str | None = None,
+    limit: int = 50,
+    offset: int = 0,
+):
+    auth = get_auth(request)
+    if not auth:
         return APIResponse(
             True,
             "Unauthorized",
             None,
             status.HTTP_401_UNAUTHORIZED,
+            code=error_codes.UNAUTHORIZED,
         )
 
-    org_id = claims.get("organization_id")
-    user_id = claims.get("user_id")
+    org_id = auth.org_id
+    user_id = auth.user_id
+
+    order_by, sort_error = parse_chat_sort(sort)
+    if sort_error:
+        return APIResponse(True, sort_error, None, status.HTTP_400_BAD_REQUEST)
+    if not order_by:
+        order_by = "last_message_at DESC NULLS LAST, created_at DESC"
+
+    limit = max(1, min(limit, 100))
+    offset = max(0, offset)
 
     try:
         # Use the shared connection pool via get_db_cursor
         async with get_db_cursor() as cur:
             await cur.execute(
                 """
-                SELECT id, title, last_message_at
+                SELECT COUNT(*) AS total
                 FROM chats
                 WHERE organization_id = %s
                   AND user_id = %s
                   AND status = 'active'
                   AND deleted_at IS NULL
-                ORDER BY last_message_at DESC NULLS LAST, created_at DESC
                 """,
                 (org_id, user_id),
             )
+            total = (await cur.fetchone())["total"]
+
+            await cur.execute(
+                f"""
+                SELECT id, title, last_message_at
+                FROM chats
+                WHERE organization_id = %s
+                  AND user_id = %s
+                  AND status = 'active'
+                  AND deleted_at IS NULL
+                ORDER BY {order_by}
+                LIMIT %s OFFSET %s
+                """,
+                (org_id, user_id, limit, offset),
+            )
             rows = await cur.fetchall()
 
         chat_list = [
@@ -60,7 +134,11 @@ async def get_chats_list(request: Request):
             for r in rows
         ]
 
-        return APIResponse(False, "Chats fetched successfully", chat_list)
+        return APIResponse(
+            False,
+            "Chats fetched successfully",
+            {"chats": chat_list, "total": total, "limit": limit, "offset": offset},
+        )
 
     except Exception as e:
         print(f"[CHAT LIST ERROR] {e}")
@@ -71,44 +149,290 @@ async def get_chats_list(request: Request):
             status.HTTP_500_INTERNAL_SERVER_ERROR,
         )
 
+# --------------------------
+# Full-text Search Endpoint
+# --------------------------
+@router.get("/search")
+async def search_chats(
+    request: Request,
+    q: str = "",
+    org: bool = False,
+    limit: int = 20,
+    offset: int = 0,
+):
+    """
+    Full-text search over conversation history, scoped to the calling user.
+    Owners may pass ?org=true to search org-wide (audited).
+    Quoted phrases are supported via websearch_to_tsquery.
+    """
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    org_id = auth.org_id
+    user_id = auth.user_id
+    role = auth.role
+
+    if len(q.strip()) < 3:
+        return APIResponse(
+            True,
+            "Search query must be at least 3 characters",
+            None,
+            status.HTTP_400_BAD_REQUEST,
+        )
+
+    if org and role != "owner":
+        return APIResponse(
+            True,
+            "Only owners can search org-wide",
+            None,
+            status.HTTP_403_FORBIDDEN,
+            code=error_codes.FORBIDDEN,
+        )
+
+    limit = max(1, min(limit, 100))
+    offset = max(0, offset)
+
+    try:
+        query = """
+            SELECT
+                c.id AS chat_id,
+                c.title,
+                m.id AS message_id,
+                m.role,
+                m.created_at,
+                ts_headline('english', m.content, query,
+                            'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') AS snippet,
+                ts_rank(m.content_tsv, query) AS rank
+            FROM messages m
+            JOIN chats c ON c.id = m.chat_id,
+                 websearch_to_tsquery('english', %s) query
+            WHERE m.organization_id = %s
+              AND m.content_tsv @@ query
+              AND c.deleted_at IS NULL
+              AND c.status = 'active'
+        """
+        params = [q, org_id]
+
+        if not org:
+            query += " AND c.user_id = %s"
+            params.append(user_id)
+
+        query += """
+            ORDER BY rank DESC, m.created_at DESC
+            LIMIT %s OFFSET %s
+        """
+        params.extend([limit, offset])
+
+        async with get_db_cursor() as cur:
+            await cur.execute(query, params)
+            rows = await cur.fetchall()
+
+        if org:
+            # Org-wide searches are a compliance feature and must be audited
+            print(f"[AUDIT] org-wide chat search by user={user_id} org={org_id} q={q!r}")
+
+        return APIResponse(
+            False,
+            "Search results fetched successfully",
+            {
+                "results": rows,
+                "limit": limit,
+                "offset": offset,
+            },
+        )
+
+    except Exception as e:
+        print(f"[CHAT SEARCH ERROR] {e}")
+        return APIResponse(
+            True,
+            f"Failed to search chats: {e}",
+            None,
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
 # --------------------------
 # Chat Query SSE Endpoint
 # --------------------------
+
 class ChatQuerySchema(BaseModel):
     chatId: str | None = None
-    message: str
+    message: str = ""
     documentId: str | None = None
+    # Reference an org prompt template instead of (or composed with) message
+    templateId: str | None = None
+    variables: dict | None = None
+    # Prediction parameters; explicitly provided values are pinned on the
+    # chat row and inherited by later messages in the thread
+    model: str | None = None
+    temperature: float | None = None
+    maxTokens: int | None = None
 
 @router.post("/query")
 async def chat_query_sse(payload: ChatQuerySchema, request: Request):
-    claims = getattr(request.state, "claims", None)
-    if not claims:
-        return {"error": "Unauthorized"}, status.HTTP_401_UNAUTHORIZED
+    auth = get_auth(request)
+    if not auth:
+        # Same envelope as everywhere else; a bare dict here used to leak a
+        # 200 with a tuple body
+        return JSONResponse(
+            content=APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED,
+                                code=error_codes.UNAUTHORIZED),
+            status_code=status.HTTP_401_UNAUTHORIZED,
+        )
 
-    org_id = claims.get("organization_id")
-    user_id = claims.get("user_id")
+    org_id = auth.org_id
+    user_id = auth.user_id
     chat_id = payload.chatId
 
+    # Maintenance kill switch, flippable per org (e.g. one abusive tenant)
+    if not await flag_enabled(PREDICT_ENABLED, org_id):
+        return JSONResponse(
+            content=APIResponse(
+                True,
+                "Predictions are temporarily disabled for maintenance, please try again later",
+                None,
+                status.HTTP_503_SERVICE_UNAVAILABLE,
+            ),
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            headers={"Retry-After": "120"},
+        )
+
+    # Template queries render server-side; the client only sends id + variables
+    raw_message = payload.message
+    if payload.templateId:
+        rendered, template_error = await render_prompt_template(
+            org_id, payload.templateId, payload.variables
+        )
+        if template_error:
+            return APIResponse(
+                True,
+                template_error,
+                None,
+                status.HTTP_404_NOT_FOUND
+                if template_error == "prompt template not found"
+                else status.HTTP_400_BAD_REQUEST,
+            )
+        raw_message = rendered
+
+    message, input_error = sanitize_query_message(raw_message)
+    if input_error:
+        return APIResponse(
+            True,
+            input_error,
+            None,
+            status.HTTP_400_BAD_REQUEST,
+        )
+
+    # Per-request prediction parameters share the same rules as the chat
+    # settings PATCH; reject before the request counts against the quota
+    param_error = validate_predict_params(payload.model, payload.temperature, payload.maxTokens)
+    if not param_error and payload.model:
+        param_error = await check_model_allowed(org_id, payload.model)
+    if param_error:
+        return APIResponse(
+            True,
+            param_error,
+            None,
+            status.HTTP_400_BAD_REQUEST,
+        )
+
+    # Fail fast with a clear message when the backend can't serve RAG
+    capability_error = await require_embeddings()
+    if capability_error:
+        return APIResponse(
+            True,
+            capability_error,
+            None,
+            status.HTTP_501_NOT_IMPLEMENTED,
+        )
+
+    # Monthly prediction quota: block, degrade to a fallback model, or warn
+    quota = await consume_prediction_quota(org_id)
+    if quota["blocked"]:
+        return APIResponse(
+            True,
+            quota["message"],
+            None,
+            status.HTTP_429_TOO_MANY_REQUESTS,
+        )
+
     # ✅ If chatId is not provided, create a new chat
     if not chat_id:
-        chat_id, _ = await create_chat(org_id, user_id, title=payload.message[:50])
+        chat_id, _ = await create_chat(org_id, user_id, title=message[:50])
         new_chat_created = True
+        pinned = None
     else:
         new_chat_created = False
+        pinned = await get_chat_settings(org_id, chat_id)
+
+    # Explicitly provided parameters are pinned on the chat so the rest of
+    # the thread inherits them; absent ones fall back to the pin, then the
+    # deployment default
+    explicit = {}
+    if payload.model is not None:
+        explicit["model"] = payload.model.strip()
+    if payload.temperature is not None:
+        explicit["temperature"] = payload.temperature
+    if payload.maxTokens is not None:
+        explicit["max_tokens"] = payload.maxTokens
+    if explicit:
+        await update_chat_settings(org_id, chat_id, explicit)
+
+    model = explicit.get("model") or (pinned or {}).get("model") or quota["model"]
+    if quota["model"] != CHAT_MODEL:
+        # Quota degradation wins over any pin: the org exhausted its quota
+        # and chose the fallback model
+        model = quota["model"]
+    temperature = explicit.get("temperature")
+    if temperature is None:
+        temperature = (pinned or {}).get("temperature")
+    if temperature is None:
+        temperature = 0.2
+    max_tokens = explicit.get("max_tokens")
+    if max_tokens is None:
+        max_tokens = (pinned or {}).get("max_tokens")
+
+    await mark_onboarding_step(org_id, STEP_FIRST_PREDICTION)
+
+    # Weighted fair admission: when the backend is saturated, paying tiers
+    # get proportionally more slots instead of first-come-first-served
+    try:
+        await inference_queue.acquire(org_id)
+    except (inference_queue.QueueFull, inference_queue.QueueTimeout) as e:
+        return JSONResponse(
+            content=APIResponse(
+                True,
+                f"Inference backend is busy ({e.depth} requests waiting), please try again shortly",
+                {"queue_depth": e.depth},
+                status.HTTP_503_SERVICE_UNAVAILABLE,
+            ),
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            headers={"Retry-After": "15"},
+        )
 
     async def event_generator():
         # Send chat_id first (helps frontend initialize UI immediately)
         yield f"data: {json.dumps({'event': 'chat_id', 'chatId': str(chat_id), 'new': new_chat_created})}\n\n"
         await asyncio.sleep(0)
 
+        rag_events = query_rag_openai_stream(
+            org_id=org_id,
+            user_id=user_id,
+            chat_id=chat_id,
+            user_message=message,
+            document_id=payload.documentId,
+            user_role=auth.role,
+            model=model,
+            temperature=temperature,
+            max_tokens=max_tokens,
+            quota_warning=quota["quota_warning"],
+        )
         try:
-            async for event in query_rag_openai_stream(
-                org_id=org_id,
-                user_id=user_id,
-                chat_id=chat_id,
-                user_message=payload.message,
-                document_id=payload.documentId,
-            ):
+            async for event in rag_events:
+                # Stop pulling as soon as the client aborts; closing the
+                # generator below cancels the in-flight LLM stream too
+                if await request.is_disconnected():
+                    break
                 # Always send line-by-line SSE
                 msg = f"data: {json.dumps(event, ensure_ascii=False)}\n\n"
                 yield msg.encode("utf-8")
@@ -116,6 +440,9 @@ async def chat_query_sse(payload: ChatQuerySchema, request: Request):
         except Exception as e:
             error_event = {"event": "error", "content": str(e)}
             yield f"data: {json.dumps(error_event)}\n\n".encode("utf-8")
+        finally:
+            await rag_events.aclose()
+            inference_queue.release()
 
     return StreamingResponse(
         event_generator(),
@@ -128,39 +455,146 @@ async def chat_query_sse(payload: ChatQuerySchema, request: Request):
     )
 
 
+# --------------------------
+# Export Chat Endpoint
+# --------------------------
+@router.get("/{chat_id}/export")
+async def export_chat(chat_id: str, request: Request, format: str = "json"):
+    """
+    Export a chat transcript as Markdown or JSON (selected by ?format=).
+    Organization ID is taken from JWT claims for multi-tenant safety.
+    """
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    org_id = auth.org_id
+
+    if format not in ("json", "markdown"):
+        return APIResponse(
+            True,
+            "Invalid format, use 'json' or 'markdown'",
+            None,
+            status.HTTP_400_BAD_REQUEST,
+        )
+
+    try:
+        async with get_db_cursor() as cur:
+            # 1️⃣ Verify chat exists in caller's org
+            await cur.execute(
+                """
+                SELECT id, title
+                FROM chats
+                WHERE id=%s AND organization_id=%s AND deleted_at IS NULL
+                """,
+                (chat_id, org_id),
+            )
+            chat = await cur.fetchone()
+
+            if not chat:
+                return APIResponse(True, "Chat not found", None, status.HTTP_404_NOT_FOUND, code=error_codes.NOT_FOUND)
+
+            # 2️⃣ Fetch messages
+            await cur.execute(
+                """
+                SELECT role, content, created_at
+                FROM messages
+                WHERE chat_id=%s AND organization_id=%s
+                ORDER BY created_at ASC
+                """,
+                (chat_id, org_id),
+            )
+            rows = await cur.fetchall()
+
+        title = chat["title"] or "chat"
+
+        if format == "markdown":
+            async def markdown_generator():
+                for chunk in render_markdown(title, rows):
+                    yield chunk
+
+            return StreamingResponse(
+                markdown_generator(),
+                media_type="text/markdown; charset=utf-8",
+                headers={
+                    "Content-Disposition": f'attachment; filename="{chat_id}.md"',
+                },
+            )
+
+        transcript = build_transcript(chat["id"], title, rows)
+
+        async def json_generator():
+            yield json.dumps(transcript, ensure_ascii=False, indent=2)
+
+        return StreamingResponse(
+            json_generator(),
+            media_type="application/json",
+            headers={
+                "Content-Disposition": f'attachment; filename="{chat_id}.json"',
+            },
+        )
+
+    except Exception as e:
+        print(f"[CHAT EXPORT ERROR] {e}")
+        return APIResponse(
+            True,
+            f"Failed to export chat: {e}",
+            None,
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
 # --------------------------
 # Chat Messages Endpoint
 # --------------------------
 @router.get("/{chat_id}")
-async def get_chat_messages(chat_id: str, request: Request):
+async def get_chat_messages(chat_id: str, request: Request, limit: int = 100, offset: int = 0):
     """
-    Fetch all messages for a given chat.
+    Fetch messages for a given chat (oldest first, paginated).
     Organization ID is taken from JWT claims for multi-tenant safety.
     """
-    claims = getattr(request.state, "claims", None)
-    if not claims:
-        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED)
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
 
-    org_id = claims.get("organization_id")
+    org_id = auth.org_id
 
+    limit = max(1, min(limit, 500))
+    offset = max(0, offset)
 
     try:
         async with get_db_cursor() as cur:
             await cur.execute(
                 """
-                SELECT id, role, content, created_at
+                SELECT COUNT(*) AS total
                 FROM messages
                 WHERE chat_id=%s AND organization_id=%s
-                ORDER BY created_at ASC
                 """,
                 (chat_id, org_id)
             )
+            total = (await cur.fetchone())["total"]
+
+            await cur.execute(
+                """
+                SELECT id, role, content, citations, created_at
+                FROM messages
+                WHERE chat_id=%s AND organization_id=%s
+                ORDER BY created_at ASC
+                LIMIT %s OFFSET %s
+                """,
+                (chat_id, org_id, limit, offset)
+            )
             rows = await cur.fetchall()
 
+        # Pinned prediction settings ride along so clients can show what the
+        # thread is configured with (None values = deployment default)
+        settings = await get_chat_settings(org_id, chat_id)
+
         return APIResponse(
             error=False,
             message="Chat messages fetched successfully",
-            data={"messages": rows},
+            data={"messages": rows, "total": total, "limit": limit, "offset": offset,
+                  "settings": settings},
             status_code=status.HTTP_200_OK
         )
 
@@ -174,6 +608,87 @@ async def get_chat_messages(chat_id: str, request: Request):
         )
 
 
+# --------------------------
+# Chat Settings Endpoint
+# --------------------------
+class ChatSettingsSchema(BaseModel):
+    model: str | None = None
+    temperature: float | None = None
+    maxTokens: int | None = None
+
+@router.patch("/{chat_id}/settings")
+async def patch_chat_settings(chat_id: str, payload: ChatSettingsSchema, request: Request):
+    """
+    Change a chat's pinned prediction settings mid-thread. Only the chat's
+    owner may change them; the change is recorded as a system message so the
+    transcript shows which answers used which settings.
+    """
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    org_id = auth.org_id
+    user_id = auth.user_id
+
+    changes = {}
+    if payload.model is not None:
+        changes["model"] = payload.model.strip()
+    if payload.temperature is not None:
+        changes["temperature"] = payload.temperature
+    if payload.maxTokens is not None:
+        changes["max_tokens"] = payload.maxTokens
+    if not changes:
+        return APIResponse(
+            True,
+            "provide at least one of model, temperature or maxTokens",
+            None,
+            status.HTTP_400_BAD_REQUEST,
+        )
+
+    # Same rules as /query parameters, including the org's model allowlist
+    param_error = validate_predict_params(payload.model, payload.temperature, payload.maxTokens)
+    if not param_error and payload.model:
+        param_error = await check_model_allowed(org_id, payload.model)
+    if param_error:
+        return APIResponse(True, param_error, None, status.HTTP_400_BAD_REQUEST)
+
+    try:
+        async with get_db_cursor() as cur:
+            await cur.execute(
+                """
+                SELECT user_id FROM chats
+                WHERE id=%s AND organization_id=%s AND deleted_at IS NULL
+                """,
+                (chat_id, org_id),
+            )
+            chat = await cur.fetchone()
+
+        if not chat:
+            return APIResponse(True, "Chat not found", None, status.HTTP_404_NOT_FOUND,
+                               code=error_codes.NOT_FOUND)
+        if str(chat["user_id"]) != str(user_id):
+            return APIResponse(True, "Only the chat owner can change its settings", None,
+                               status.HTTP_403_FORBIDDEN, code=error_codes.FORBIDDEN)
+
+        await update_chat_settings(org_id, chat_id, changes)
+
+        described = ", ".join(f"{k} → {v}" for k, v in changes.items())
+        await save_message_to_db(org_id, chat_id, user_id, "system",
+                                 f"Settings changed: {described}")
+
+        settings = await get_chat_settings(org_id, chat_id)
+        return APIResponse(False, "Chat settings updated", {"settings": settings})
+
+    except Exception as e:
+        print(f"[CHAT SETTINGS ERROR] {e}")
+        return APIResponse(
+            True,
+            f"Failed to update chat settings: {e}",
+            None,
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
 # --------------------------
 # Delete Chat Endpoint
 # --------------------------
@@ -184,8 +699,8 @@ async def delete_chat(chat_id: str, request: Request):
     Organization ID is taken from JWT claims for multi-tenant safety.
     """
 
-    claims = getattr(request.state, "claims", None)
-    if not claims:
+    auth = get_auth(request)
+    if not auth:
         return APIResponse(
             True,
             "Unauthorized",
@@ -193,7 +708,7 @@ async def delete_chat(chat_id: str, request: Request):
             status.HTTP_401_UNAUTHORIZED
         )
 
-    org_id = claims.get("organization_id")
+    org_id = auth.org_id
 
     try:
         async with get_db_cursor(commit=True) as cur:
@@ -246,3 +761,277 @@ async def delete_chat(chat_id: str, request: Request):
             None,
             status.HTTP_500_INTERNAL_SERVER_ERROR
         )
+
+
+# --------------------------
+# Share Chat Endpoints
+# --------------------------
+class ShareChatSchema(BaseModel):
+    expires_in_hours: Optional[int] = None
+    snapshot: bool = True
+
+@router.post("/{chat_id}/share")
+async def share_chat(chat_id: str, payload: ShareChatSchema, request: Request):
+    """
+    Create (or return) an org-scoped read-only share link for a chat.
+    Any authenticated member of the same org can view it.
+    """
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    org_id = auth.org_id
+    user_id = auth.user_id
+
+    try:
+        async with get_db_cursor(commit=True) as cur:
+
+            # 1️⃣ Verify chat belongs to the caller
+            await cur.execute(
+                """
+                SELECT id
+                FROM chats
+                WHERE id=%s AND organization_id=%s AND user_id=%s AND deleted_at IS NULL
+                """,
+                (chat_id, org_id, user_id),
+            )
+            chat = await cur.fetchone()
+
+            if not chat:
+                return APIResponse(True, "Chat not found", None, status.HTTP_404_NOT_FOUND, code=error_codes.NOT_FOUND)
+
+            # 2️⃣ Reuse an existing active share if present
+            await cur.execute(
+                """
+                SELECT slug, snapshot_at, expires_at
+                FROM chat_shares
+                WHERE chat_id=%s AND revoked_at IS NULL
+                  AND (expires_at IS NULL OR expires_at > NOW())
+                """,
+                (chat_id,),
+            )
+            existing = await cur.fetchone()
+            if existing:
+                return APIResponse(
+                    False,
+                    "Chat already shared",
+                    {
+                        "path": f"/shared/{existing['slug']}",
+                        "slug": existing["slug"],
+                        "snapshot_at": existing["snapshot_at"],
+                        "expires_at": existing["expires_at"],
+                    },
+                )
+
+            # 3️⃣ Create the share record
+            slug = secrets.token_urlsafe(16)
+            await cur.execute(
+                """
+                INSERT INTO chat_shares
+                    (chat_id, organization_id, created_by, slug, snapshot_at, expires_at)
+                VALUES (
+                    %s, %s, %s, %s,
+                    CASE WHEN %s THEN NOW() ELSE NULL END,
+                    CASE WHEN %s::int IS NULL THEN NULL
+                         ELSE NOW() + (%s::int * INTERVAL '1 hour') END
+                )
+                RETURNING slug, snapshot_at, expires_at
+                """,
+                (
+                    chat_id, org_id, user_id, slug,
+                    payload.snapshot,
+                    payload.expires_in_hours, payload.expires_in_hours,
+                ),
+            )
+            share = await cur.fetchone()
+
+        return APIResponse(
+            False,
+            "Chat shared successfully",
+            {
+                "path": f"/shared/{share['slug']}",
+                "slug": share["slug"],
+                "snapshot_at": share["snapshot_at"],
+                "expires_at": share["expires_at"],
+            },
+        )
+
+    except Exception as e:
+        print(f"[SHARE CHAT ERROR] {e}")
+        return APIResponse(
+            True,
+            f"Failed to share chat: {e}",
+            None,
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
+@router.delete("/{chat_id}/share")
+async def revoke_chat_share(chat_id: str, request: Request):
+    """Revoke any active share link for a chat."""
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    org_id = auth.org_id
+    user_id = auth.user_id
+
+    try:
+        async with get_db_cursor(commit=True) as cur:
+            await cur.execute(
+                """
+                UPDATE chat_shares cs
+                SET revoked_at = NOW()
+                FROM chats c
+                WHERE c.id = cs.chat_id
+                  AND cs.chat_id = %s
+                  AND cs.organization_id = %s
+                  AND cs.revoked_at IS NULL
+                  AND (c.user_id = %s OR %s = 'owner')
+                RETURNING cs.id
+                """,
+                (chat_id, org_id, user_id, auth.role),
+            )
+            revoked = await cur.fetchall()
+
+        if not revoked:
+            return APIResponse(True, "No active share found", None, status.HTTP_404_NOT_FOUND, code=error_codes.NOT_FOUND)
+
+        return APIResponse(False, "Share revoked successfully", {"chat_id": chat_id})
+
+    except Exception as e:
+        print(f"[REVOKE SHARE ERROR] {e}")
+        return APIResponse(
+            True,
+            f"Failed to revoke share: {e}",
+            None,
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
+@shared_router.get("/{slug}")
+async def get_shared_chat(slug: str, request: Request):
+    """
+    View a shared conversation transcript (read-only).
+    The viewer must belong to the same organization as the share.
+    Revoked or expired slugs return 404.
+    """
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    org_id = auth.org_id
+
+    try:
+        async with get_db_cursor() as cur:
+            await cur.execute(
+                """
+                SELECT cs.chat_id, cs.organization_id, cs.snapshot_at, c.title
+                FROM chat_shares cs
+                JOIN chats c ON c.id = cs.chat_id
+                WHERE cs.slug = %s
+                  AND cs.revoked_at IS NULL
+                  AND (cs.expires_at IS NULL OR cs.expires_at > NOW())
+                  AND c.deleted_at IS NULL
+                """,
+                (slug,),
+            )
+            share = await cur.fetchone()
+
+            # Org mismatch is indistinguishable from a missing share on purpose
+            if not share or str(share["organization_id"]) != str(org_id):
+                return APIResponse(True, "Shared chat not found", None, status.HTTP_404_NOT_FOUND, code=error_codes.NOT_FOUND)
+
+            query = """
+                SELECT role, content, created_at
+                FROM messages
+                WHERE chat_id = %s AND organization_id = %s
+            """
+            params = [share["chat_id"], org_id]
+
+            # Snapshot shares exclude messages added after the share was created
+            if share["snapshot_at"]:
+                query += " AND created_at <= %s"
+                params.append(share["snapshot_at"])
+
+            query += " ORDER BY created_at ASC"
+            await cur.execute(query, params)
+            rows = await cur.fetchall()
+
+        return APIResponse(
+            False,
+            "Shared chat fetched successfully",
+            {
+                "title": share["title"],
+                "live": share["snapshot_at"] is None,
+                "snapshot_at": share["snapshot_at"],
+                "messages": rows,
+            },
+        )
+
+    except Exception as e:
+        print(f"[SHARED CHAT ERROR] {e}")
+        return APIResponse(
+            True,
+            f"Failed to fetch shared chat: {e}",
+            None,
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
+@shared_router.get("")
+async def list_chat_shares(request: Request):
+    """List active shares in the organization. Owner only."""
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    if auth.role != "owner":
+        return APIResponse(
+            True,
+            "Only owners can list shares",
+            None,
+            status.HTTP_403_FORBIDDEN,
+            code=error_codes.FORBIDDEN,
+        )
+
+    org_id = auth.org_id
+
+    try:
+        async with get_db_cursor() as cur:
+            await cur.execute(
+                """
+                SELECT cs.slug, cs.chat_id, c.title, cs.created_by,
+                       cs.snapshot_at, cs.expires_at, cs.created_at
+                FROM chat_shares cs
+                JOIN chats c ON c.id = cs.chat_id
+                WHERE cs.organization_id = %s
+                  AND cs.revoked_at IS NULL
+                  AND (cs.expires_at IS NULL OR cs.expires_at > NOW())
+                ORDER BY cs.created_at DESC
+                """,
+                (org_id,),
+            )
+            shares = await cur.fetchall()
+
+        # Best-effort enrichment with creator display info from users-service;
+        # the listing still works if that service is down
+        creator_ids = list({str(s["created_by"]) for s in shares if s["created_by"]})
+        if creator_ids:
+            try:
+                found, _missing = await resolve_users(creator_ids)
+                for share in shares:
+                    share["created_by_user"] = found.get(str(share["created_by"]))
+            except Exception as e:
+                print(f"[LIST SHARES WARN] could not resolve creators: {e}")
+
+        return APIResponse(False, "Shares fetched successfully", shares)
+
+    except Exception as e:
+        print(f"[LIST SHARES ERROR] {e}")
+        return APIResponse(
+            True,
+            f"Failed to list shares: {e}",
+            None,
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
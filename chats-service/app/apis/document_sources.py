@@ -0,0 +1,284 @@
+from fastapi import APIRouter, Request, status
+from pydantic import BaseModel
+from typing import Optional
+from urllib.parse import urlparse
+
+from app.utils.response import APIResponse
+from app.utils import error_codes
+from app.database.postgres_client import get_db_cursor
+from app.helpers.permissions import role_has_capability
+from app.middleware.auth_context import get_auth
+
+router = APIRouter(prefix="/document-sources", tags=["Document Sources"])
+
+MIN_FETCH_INTERVAL_MINUTES = 5
+
+
+class CreateDocumentSourceRequest(BaseModel):
+    url: str
+    fetch_interval_minutes: int = 60
+    # Optional "Header-Name: value" sent with every fetch (e.g. a wiki token)
+    auth_header: Optional[str] = None
+
+
+class UpdateDocumentSourceRequest(BaseModel):
+    url: Optional[str] = None
+    fetch_interval_minutes: Optional[int] = None
+    auth_header: Optional[str] = None
+    enabled: Optional[bool] = None
+
+
+def _validate_source_url(url: str) -> str | None:
+    parsed = urlparse(url)
+    if parsed.scheme not in ("http", "https") or not parsed.hostname:
+        return "url must be absolute http(s)"
+    return None
+
+
+def _require_manager(request: Request):
+    """Sources are managed by owners/maintainers (same gate as doc deletion)."""
+    auth = get_auth(request)
+    if not auth:
+        return None, APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+    if not role_has_capability(auth.role, "documents.delete"):
+        return None, APIResponse(
+            True,
+            "You do not have permission to manage document sources",
+            None,
+            status.HTTP_403_FORBIDDEN,
+            code=error_codes.FORBIDDEN,
+        )
+    return auth, None
+
+
+# =======================
+# ➕ Create Source
+# =======================
+@router.post("")
+async def create_document_source(request: Request, body: CreateDocumentSourceRequest):
+    auth, err = _require_manager(request)
+    if err:
+        return err
+
+    if msg := _validate_source_url(body.url):
+        return APIResponse(True, msg, None, status.HTTP_400_BAD_REQUEST)
+    if body.fetch_interval_minutes < MIN_FETCH_INTERVAL_MINUTES:
+        return APIResponse(
+            True,
+            f"fetch_interval_minutes must be at least {MIN_FETCH_INTERVAL_MINUTES}",
+            None,
+            status.HTTP_400_BAD_REQUEST,
+        )
+
+    try:
+        async with get_db_cursor(commit=True) as cur:
+            await cur.execute(
+                """
+                INSERT INTO document_sources
+                    (organization_id, created_by, url, auth_header,
+                    fetch_interval_minutes, next_fetch_at)
+                VALUES (%s, %s, %s, %s, %s, NOW())
+                RETURNING id, url, fetch_interval_minutes, enabled,
+                          next_fetch_at, created_at
+                """,
+                (auth.org_id, auth.user_id, body.url, body.auth_header,
+                 body.fetch_interval_minutes),
+            )
+            source = await cur.fetchone()
+
+        return APIResponse(False, "Document source created", source)
+
+    except Exception as e:
+        print(f"[SOURCE CREATE ERROR] {e}")
+        return APIResponse(
+            True,
+            "Failed to create document source",
+            {"error": str(e)},
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
+# =======================
+# 📋 List Sources
+# =======================
+@router.get("")
+async def list_document_sources(request: Request):
+    auth, err = _require_manager(request)
+    if err:
+        return err
+
+    try:
+        async with get_db_cursor() as cur:
+            # auth_header values are secrets; only expose whether one is set
+            await cur.execute(
+                """
+                SELECT id, url, document_id, fetch_interval_minutes, enabled,
+                       content_hash, failure_count, last_fetched_at,
+                       next_fetch_at, created_at,
+                       (auth_header IS NOT NULL) AS has_auth_header
+                FROM document_sources
+                WHERE organization_id = %s
+                ORDER BY created_at DESC
+                """,
+                (auth.org_id,),
+            )
+            sources = await cur.fetchall()
+
+        return APIResponse(False, "Document sources fetched", {"sources": sources})
+
+    except Exception as e:
+        print(f"[SOURCE LIST ERROR] {e}")
+        return APIResponse(
+            True,
+            "Failed to fetch document sources",
+            {"error": str(e)},
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
+# =======================
+# ✏️ Update Source
+# =======================
+@router.patch("/{source_id}")
+async def update_document_source(source_id: str, request: Request, body: UpdateDocumentSourceRequest):
+    auth, err = _require_manager(request)
+    if err:
+        return err
+
+    updates = []
+    params = []
+    if body.url is not None:
+        if msg := _validate_source_url(body.url):
+            return APIResponse(True, msg, None, status.HTTP_400_BAD_REQUEST)
+        updates.append("url = %s")
+        params.append(body.url)
+        # A new URL means the stored hash no longer applies
+        updates.append("content_hash = NULL")
+    if body.fetch_interval_minutes is not None:
+        if body.fetch_interval_minutes < MIN_FETCH_INTERVAL_MINUTES:
+            return APIResponse(
+                True,
+                f"fetch_interval_minutes must be at least {MIN_FETCH_INTERVAL_MINUTES}",
+                None,
+                status.HTTP_400_BAD_REQUEST,
+            )
+        updates.append("fetch_interval_minutes = %s")
+        params.append(body.fetch_interval_minutes)
+    if body.auth_header is not None:
+        updates.append("auth_header = %s")
+        params.append(body.auth_header or None)
+    if body.enabled is not None:
+        updates.append("enabled = %s")
+        params.append(body.enabled)
+        if body.enabled:
+            updates.append("failure_count = 0")
+            updates.append("next_fetch_at = NOW()")
+
+    if not updates:
+        return APIResponse(True, "No changes to apply", None, status.HTTP_400_BAD_REQUEST)
+
+    try:
+        async with get_db_cursor(commit=True) as cur:
+            await cur.execute(
+                f"""
+                UPDATE document_sources
+                SET {", ".join(updates)}, updated_at = NOW()
+                WHERE id = %s AND organization_id = %s
+                RETURNING id
+                """,
+                (*params, source_id, auth.org_id),
+            )
+            updated = await cur.fetchone()
+
+        if not updated:
+            return APIResponse(True, "Document source not found", None, status.HTTP_404_NOT_FOUND, code=error_codes.NOT_FOUND)
+
+        return APIResponse(False, "Document source updated", None)
+
+    except Exception as e:
+        print(f"[SOURCE UPDATE ERROR] {e}")
+        return APIResponse(
+            True,
+            "Failed to update document source",
+            {"error": str(e)},
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
+# =======================
+# 🗑️ Delete Source
+# =======================
+@router.delete("/{source_id}")
+async def delete_document_source(source_id: str, request: Request):
+    auth, err = _require_manager(request)
+    if err:
+        return err
+
+    try:
+        async with get_db_cursor(commit=True) as cur:
+            # The backing document stays; only the sync stops
+            await cur.execute(
+                """
+                DELETE FROM document_sources
+                WHERE id = %s AND organization_id = %s
+                RETURNING id
+                """,
+                (source_id, auth.org_id),
+            )
+            deleted = await cur.fetchone()
+
+        if not deleted:
+            return APIResponse(True, "Document source not found", None, status.HTTP_404_NOT_FOUND, code=error_codes.NOT_FOUND)
+
+        return APIResponse(False, "Document source deleted", None)
+
+    except Exception as e:
+        print(f"[SOURCE DELETE ERROR] {e}")
+        return APIResponse(
+            True,
+            "Failed to delete document source",
+            {"error": str(e)},
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
+# =======================
+# 🕘 Fetch History
+# =======================
+@router.get("/{source_id}/history")
+async def get_document_source_history(source_id: str, request: Request):
+    auth, err = _require_manager(request)
+    if err:
+        return err
+
+    try:
+        async with get_db_cursor() as cur:
+            await cur.execute(
+                "SELECT id FROM document_sources WHERE id = %s AND organization_id = %s",
+                (source_id, auth.org_id),
+            )
+            if not await cur.fetchone():
+                return APIResponse(True, "Document source not found", None, status.HTTP_404_NOT_FOUND, code=error_codes.NOT_FOUND)
+
+            await cur.execute(
+                """
+                SELECT id, status, http_status, bytes, duration_ms, error, created_at
+                FROM document_source_fetches
+                WHERE source_id = %s
+                ORDER BY created_at DESC
+                LIMIT 50
+                """,
+                (source_id,),
+            )
+            history = await cur.fetchall()
+
+        return APIResponse(False, "Fetch history fetched", {"history": history})
+
+    except Exception as e:
+        print(f"[SOURCE HISTORY ERROR] {e}")
+        return APIResponse(
+            True,
+            "Failed to fetch source history",
+            {"error": str(e)},
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
@@ -1,25 +1,38 @@
+import os
+
 from fastapi import APIRouter, Request, UploadFile, File, status, HTTPException, Query
 from app.utils.response import APIResponse
+from app.utils import error_codes
 from app.database.postgres_client import get_db_cursor
-from app.helpers.s3_storage import upload_file_to_s3, get_presigned_url
+from app.helpers.s3_storage import upload_file_to_s3, get_presigned_url, delete_s3_object
 from app.helpers.train_document import run_training_job
+from app.helpers.document_access import document_access_clause, document_access_params
+from app.helpers.permissions import role_has_capability
+from app.helpers.trash import TRASH_RETENTION_DAYS
+from app.helpers.storage_quota import reserve_storage, release_storage, StorageQuotaExceeded
+from app.helpers.onboarding import mark_onboarding_step, STEP_DOCUMENT_UPLOADED
+from app.middleware.auth_context import get_auth
 from pydantic import BaseModel
 from typing import List, Optional, Literal
 from hashlib import sha256
 
 router = APIRouter(prefix="/documents", tags=["Documents"])
 
+# Pasted snippets are capped well below file uploads; anything bigger
+# belongs in a real document
+SNIPPET_MAX_CHARS = int(os.getenv("SNIPPET_MAX_CHARS", "100000"))
+
 # =======================
 # 📄 1️⃣ Upload Document
 # =======================
 @router.post("/upload")
 async def upload_document(request: Request, file: UploadFile = File(...)):
-    claims = getattr(request.state, "claims", None)
-    if not claims:
-        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED)
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
 
-    org_id = claims.get("organization_id")
-    user_id = claims.get("user_id")
+    org_id = auth.org_id
+    user_id = auth.user_id
 
     allowed_types = [
         "application/pdf",
@@ -52,18 +65,38 @@ async def upload_document(request: Request, file: UploadFile = File(...)):
             content_type=file.content_type,
         )
 
-        async with get_db_cursor(commit=True) as cur:
-            await cur.execute(
-                """
-                INSERT INTO documents
-                    (created_by, organization_id, file_name, s3_key,
-                    file_size, status, trainable, created_at, updated_at)
-                VALUES (%s, %s, %s, %s, %s, 'untrained', TRUE, NOW(), NOW())
-                RETURNING id, file_name, file_size, created_at
-                """,
-                (user_id, org_id, file.filename, s3_key, file_size),
+        try:
+            async with get_db_cursor(commit=True) as cur:
+                # Quota reservation and document row commit together; the
+                # guarded UPDATE stops concurrent uploads from overshooting
+                await reserve_storage(cur, org_id, file_size)
+                await cur.execute(
+                    """
+                    INSERT INTO documents
+                        (created_by, organization_id, file_name, s3_key,
+                        file_size, status, trainable, created_at, updated_at)
+                    VALUES (%s, %s, %s, %s, %s, 'untrained', TRUE, NOW(), NOW())
+                    RETURNING id, file_name, file_size, created_at
+                    """,
+                    (user_id, org_id, file.filename, s3_key, file_size),
+                )
+                document = await cur.fetchone()
+        except StorageQuotaExceeded as e:
+            # The object is already in S3; clean it up so rejected uploads
+            # don't leak storage
+            try:
+                await delete_s3_object(s3_key)
+            except Exception as cleanup_err:
+                print(f"[UPLOAD WARN] failed to clean up rejected upload {s3_key}: {cleanup_err}")
+            return APIResponse(
+                True,
+                "Storage quota exceeded",
+                {"used_bytes": e.used, "quota_bytes": e.limit, "file_size": file_size},
+                status.HTTP_413_REQUEST_ENTITY_TOO_LARGE,
+                code=error_codes.QUOTA_EXCEEDED,
             )
-            document = await cur.fetchone()
+
+        await mark_onboarding_step(org_id, STEP_DOCUMENT_UPLOADED)
 
         return APIResponse(False, "Document uploaded successfully", document)
 
@@ -77,21 +110,135 @@ async def upload_document(request: Request, file: UploadFile = File(...)):
         )
 
 # =======================
-# 📥 2️⃣ Download Document
+# ✍️ Text Snippets
 # =======================
-@router.get("/download/{document_id}")
-async def download_document(document_id: str, request: Request):
-    claims = getattr(request.state, "claims", None)
-    if not claims:
-        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED)
+class CreateSnippetRequest(BaseModel):
+    title: str
+    # Raw text or markdown; stored and indexed as-is
+    body: str
+
+class UpdateSnippetRequest(BaseModel):
+    body: str
+
+
+@router.post("/text")
+async def create_snippet(request: Request, payload: CreateSnippetRequest):
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
 
-    org_id = claims.get("organization_id")
+    org_id = auth.org_id
+    user_id = auth.user_id
+
+    title = payload.title.strip()
+    body = payload.body.strip()
+    if not title or not body:
+        return APIResponse(True, "title and body are required", None, status.HTTP_400_BAD_REQUEST)
+    if len(body) > SNIPPET_MAX_CHARS:
+        return APIResponse(
+            True,
+            f"Snippet body exceeds the {SNIPPET_MAX_CHARS} character limit",
+            {"length": len(body), "max_chars": SNIPPET_MAX_CHARS},
+            status.HTTP_400_BAD_REQUEST,
+        )
 
     try:
-        async with get_db_cursor() as cur:
+        # Snippets go through the same storage backend as uploads so
+        # download/export and the training pipeline need no special cases
+        body_bytes = body.encode("utf-8")
+        file_size = len(body_bytes)
+
+        s3_key, _, _ = upload_file_to_s3(
+            file_bytes=body_bytes,
+            org_id=org_id,
+            filename=f"{title}.md",
+            content_type="text/markdown",
+        )
+
+        try:
+            async with get_db_cursor(commit=True) as cur:
+                await reserve_storage(cur, org_id, file_size)
+                await cur.execute(
+                    """
+                    INSERT INTO documents
+                        (created_by, organization_id, file_name, s3_key, file_size,
+                        source_type, status, trainable, created_at, updated_at)
+                    VALUES (%s, %s, %s, %s, %s, 'snippet', 'untrained', TRUE, NOW(), NOW())
+                    RETURNING id, file_name, file_size, source_type, created_at
+                    """,
+                    (user_id, org_id, title, s3_key, file_size),
+                )
+                document = await cur.fetchone()
+
+                # Index immediately; nobody pastes a snippet and then
+                # separately remembers to train it
+                await cur.execute(
+                    """
+                    INSERT INTO training_jobs
+                        (organization_id, initiated_by, status, created_at)
+                    VALUES (%s, %s, 'pending', NOW())
+                    RETURNING id
+                    """,
+                    (org_id, user_id),
+                )
+                job = await cur.fetchone()
+        except StorageQuotaExceeded as e:
+            try:
+                await delete_s3_object(s3_key)
+            except Exception as cleanup_err:
+                print(f"[SNIPPET WARN] failed to clean up rejected snippet {s3_key}: {cleanup_err}")
+            return APIResponse(
+                True,
+                "Storage quota exceeded",
+                {"used_bytes": e.used, "quota_bytes": e.limit, "file_size": file_size},
+                status.HTTP_413_REQUEST_ENTITY_TOO_LARGE,
+                code=error_codes.QUOTA_EXCEEDED,
+            )
+
+        run_training_job.delay(job["id"], org_id, user_id, [document["id"]])
+
+        await mark_onboarding_step(org_id, STEP_DOCUMENT_UPLOADED)
+
+        return APIResponse(False, "Snippet created successfully", document)
+
+    except Exception as e:
+        print(f"[SNIPPET CREATE ERROR] {e}")
+        return APIResponse(
+            True,
+            "Failed to create snippet",
+            {"error": str(e)},
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
+@router.put("/{document_id}/text")
+async def update_snippet(document_id: str, request: Request, payload: UpdateSnippetRequest):
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    org_id = auth.org_id
+    user_id = auth.user_id
+    role = auth.role
+
+    body = payload.body.strip()
+    if not body:
+        return APIResponse(True, "body is required", None, status.HTTP_400_BAD_REQUEST)
+    if len(body) > SNIPPET_MAX_CHARS:
+        return APIResponse(
+            True,
+            f"Snippet body exceeds the {SNIPPET_MAX_CHARS} character limit",
+            {"length": len(body), "max_chars": SNIPPET_MAX_CHARS},
+            status.HTTP_400_BAD_REQUEST,
+        )
+
+    try:
+        async with get_db_cursor(commit=True) as cur:
+
+            # 1️⃣ Must be a live snippet, not an uploaded file
             await cur.execute(
                 """
-                SELECT s3_key
+                SELECT id, created_by, file_name, file_size, s3_key, source_type
                 FROM documents
                 WHERE id=%s AND organization_id=%s AND deleted_at IS NULL
                 """,
@@ -99,8 +246,131 @@ async def download_document(document_id: str, request: Request):
             )
             doc = await cur.fetchone()
 
+            if not doc:
+                return APIResponse(True, "Document not found", None, status.HTTP_404_NOT_FOUND, code=error_codes.NOT_FOUND)
+            if doc["source_type"] != "snippet":
+                return APIResponse(
+                    True,
+                    "Only text snippets can be edited; re-upload files instead",
+                    None,
+                    status.HTTP_400_BAD_REQUEST,
+                )
+
+            # 2️⃣ The author may always edit their own snippet; otherwise the
+            # same audience that can delete documents
+            if not role_has_capability(role, "documents.delete") and str(
+                doc["created_by"]
+            ) != str(user_id):
+                return APIResponse(
+                    True,
+                    "You do not have permission to edit this snippet",
+                    None,
+                    status.HTTP_403_FORBIDDEN,
+                    code=error_codes.FORBIDDEN,
+                )
+
+            body_bytes = body.encode("utf-8")
+            file_size = len(body_bytes)
+            old_s3_key = doc["s3_key"]
+
+            s3_key, _, _ = upload_file_to_s3(
+                file_bytes=body_bytes,
+                org_id=org_id,
+                filename=f"{doc['file_name']}.md",
+                content_type="text/markdown",
+            )
+
+            # 3️⃣ Quota follows the size delta; a rejected edit rolls back
+            # with the transaction
+            await release_storage(cur, org_id, doc["file_size"] or 0)
+            try:
+                await reserve_storage(cur, org_id, file_size)
+            except StorageQuotaExceeded as e:
+                try:
+                    await delete_s3_object(s3_key)
+                except Exception as cleanup_err:
+                    print(f"[SNIPPET WARN] failed to clean up rejected snippet {s3_key}: {cleanup_err}")
+                return APIResponse(
+                    True,
+                    "Storage quota exceeded",
+                    {"used_bytes": e.used, "quota_bytes": e.limit, "file_size": file_size},
+                    status.HTTP_413_REQUEST_ENTITY_TOO_LARGE,
+                    code=error_codes.QUOTA_EXCEEDED,
+                )
+
+            # 4️⃣ Swap the stored body and queue a re-index of just this
+            # document; old chunks are replaced by the training run
+            await cur.execute(
+                """
+                UPDATE documents
+                SET s3_key=%s, file_size=%s, status='untrained',
+                    last_trained_at=NULL, updated_at=NOW()
+                WHERE id=%s AND organization_id=%s
+                """,
+                (s3_key, file_size, document_id, org_id),
+            )
+
+            await cur.execute(
+                """
+                INSERT INTO training_jobs
+                    (organization_id, initiated_by, status, created_at)
+                VALUES (%s, %s, 'pending', NOW())
+                RETURNING id
+                """,
+                (org_id, user_id),
+            )
+            job = await cur.fetchone()
+
+        try:
+            await delete_s3_object(old_s3_key)
+        except Exception as cleanup_err:
+            print(f"[SNIPPET WARN] failed to delete old snippet body {old_s3_key}: {cleanup_err}")
+
+        run_training_job.delay(job["id"], org_id, user_id, [document_id])
+
+        return APIResponse(
+            False,
+            "Snippet updated successfully",
+            {"id": document_id, "file_size": file_size, "job_id": job["id"]},
+        )
+
+    except Exception as e:
+        print(f"[SNIPPET UPDATE ERROR] {e}")
+        return APIResponse(
+            True,
+            "Failed to update snippet",
+            {"error": str(e)},
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+# =======================
+# 📥 2️⃣ Download Document
+# =======================
+@router.get("/download/{document_id}")
+async def download_document(document_id: str, request: Request):
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    org_id = auth.org_id
+
+    try:
+        async with get_db_cursor() as cur:
+            # Same ACL as listings/search: a restricted document's id must not
+            # be enough to mint a presigned URL for the full file
+            await cur.execute(
+                f"""
+                SELECT d.s3_key
+                FROM documents d
+                WHERE d.id=%s AND d.organization_id=%s AND d.deleted_at IS NULL
+                  AND {document_access_clause("d")}
+                """,
+                (document_id, org_id, *document_access_params(auth.user_id, auth.role)),
+            )
+            doc = await cur.fetchone()
+
         if not doc:
-            return APIResponse(True, "Document not found", None, status.HTTP_404_NOT_FOUND)
+            return APIResponse(True, "Document not found", None, status.HTTP_404_NOT_FOUND, code=error_codes.NOT_FOUND)
 
         presigned_url, expires_at = get_presigned_url(
             s3_key=doc["s3_key"], return_expiry=True
@@ -131,15 +401,15 @@ class TrainRequest(BaseModel):
 
 @router.post("/train")
 async def train_documents_endpoint(request: Request, body: TrainRequest):
-    claims = getattr(request.state, "claims", None)
-    if not claims:
+    auth = get_auth(request)
+    if not auth:
         return APIResponse(
             True, "Unauthorized", None,
             status.HTTP_401_UNAUTHORIZED
         )
 
-    org_id = claims.get("organization_id")
-    user_id = claims.get("user_id")
+    org_id = auth.org_id
+    user_id = auth.user_id
     document_ids = body.document_ids or []
 
     try:
@@ -243,32 +513,148 @@ async def list_documents(
     limit: int = 50,
     offset: int = 0,
 ):
-    claims = getattr(request.state, "claims", None)
-    if not claims:
+    auth = get_auth(request)
+    if not auth:
         raise HTTPException(status_code=401, detail="Unauthorized")
 
-    org_id = claims.get("organization_id")
+    org_id = auth.org_id
+    user_id = auth.user_id
+    role = auth.role
+
+    limit = max(1, min(limit, 100))
+    offset = max(0, offset)
 
     async with get_db_cursor() as cur:
-        query = """
-            SELECT id, file_name, status, created_at, file_size
-            FROM documents
-            WHERE organization_id = %s
-              AND deleted_at IS NULL
+        where = f"""
+            WHERE d.organization_id = %s
+              AND d.deleted_at IS NULL
+              AND {document_access_clause("d")}
         """
-        params = [org_id]
+        params = [org_id, *document_access_params(user_id, role)]
 
         if status_filter:
-            query += " AND status = %s"
+            where += " AND d.status = %s"
             params.append(status_filter)
 
-        query += " ORDER BY created_at DESC LIMIT %s OFFSET %s"
-        params.extend([limit, offset])
+        await cur.execute(f"SELECT COUNT(*) AS total FROM documents d {where}", tuple(params))
+        total = (await cur.fetchone())["total"]
 
-        await cur.execute(query, tuple(params))
+        query = f"""
+            SELECT d.id, d.file_name, d.status, d.visibility, d.created_at, d.file_size,
+                   d.source_type
+            FROM documents d
+            {where}
+            ORDER BY d.created_at DESC LIMIT %s OFFSET %s
+        """
+        await cur.execute(query, tuple(params + [limit, offset]))
         documents = await cur.fetchall()
 
-    return APIResponse(False, "Documents fetched successfully", documents)
+    return APIResponse(
+        False,
+        "Documents fetched successfully",
+        {"documents": documents, "total": total, "limit": limit, "offset": offset},
+    )
+
+# =======================
+# 🔐 Document Permissions
+# =======================
+class PermissionEntry(BaseModel):
+    user_id: Optional[str] = None
+    role: Optional[str] = None
+
+class DocumentPermissionsRequest(BaseModel):
+    visibility: Literal["org", "restricted"]
+    permissions: List[PermissionEntry] = []
+
+@router.put("/{document_id}/permissions")
+async def set_document_permissions(document_id: str, request: Request, body: DocumentPermissionsRequest):
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    org_id = auth.org_id
+    user_id = auth.user_id
+    role = auth.role
+
+    for entry in body.permissions:
+        if not entry.user_id and not entry.role:
+            return APIResponse(
+                True,
+                "Each permission entry needs a user_id or a role",
+                None,
+                status.HTTP_400_BAD_REQUEST,
+            )
+
+    try:
+        async with get_db_cursor(commit=True) as cur:
+
+            # 1️⃣ Verify document & ownership
+            await cur.execute(
+                """
+                SELECT id, created_by
+                FROM documents
+                WHERE id=%s AND organization_id=%s AND deleted_at IS NULL
+                """,
+                (document_id, org_id),
+            )
+            doc = await cur.fetchone()
+
+            if not doc:
+                return APIResponse(True, "Document not found", None, status.HTTP_404_NOT_FOUND, code=error_codes.NOT_FOUND)
+
+            # 2️⃣ Only the uploader or an owner can manage permissions
+            if role != "owner" and str(doc["created_by"]) != str(user_id):
+                return APIResponse(
+                    True,
+                    "Only the uploader or an owner can manage document permissions",
+                    None,
+                    status.HTTP_403_FORBIDDEN,
+                    code=error_codes.FORBIDDEN,
+                )
+
+            # 3️⃣ Replace visibility + permission entries atomically
+            await cur.execute(
+                """
+                UPDATE documents
+                SET visibility=%s, updated_at=NOW()
+                WHERE id=%s AND organization_id=%s
+                """,
+                (body.visibility, document_id, org_id),
+            )
+
+            await cur.execute(
+                "DELETE FROM document_permissions WHERE document_id=%s",
+                (document_id,),
+            )
+
+            for entry in body.permissions:
+                await cur.execute(
+                    """
+                    INSERT INTO document_permissions (document_id, user_id, role)
+                    VALUES (%s, %s, %s)
+                    """,
+                    (document_id, entry.user_id, entry.role),
+                )
+
+        return APIResponse(
+            False,
+            "Document permissions updated successfully",
+            {
+                "document_id": document_id,
+                "visibility": body.visibility,
+                "permissions": [e.model_dump() for e in body.permissions],
+            },
+        )
+
+    except Exception as e:
+        print(f"[PERMISSIONS ERROR] {e}")
+        return APIResponse(
+            True,
+            "Failed to update document permissions",
+            {"error": str(e)},
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
 
 # =======================
 # ⚙️ 5️⃣ Set Trainable (Bulk)
@@ -282,11 +668,11 @@ class TrainableUpdateBulkRequest(BaseModel):
 
 @router.patch("/set-trainable-bulk")
 async def set_trainable_bulk(request: Request, body: TrainableUpdateBulkRequest):
-    claims = getattr(request.state, "claims", None)
-    if not claims:
-        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED)
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
 
-    org_id = claims.get("organization_id")
+    org_id = auth.org_id
     updated_ids = []
 
     try:
@@ -323,8 +709,8 @@ async def set_trainable_bulk(request: Request, body: TrainableUpdateBulkRequest)
 # # =======================
 # @router.delete("/delete/{document_id}")
 # async def delete_document(document_id: str, request: Request):
-#     claims = getattr(request.state, "claims", None)
-#     if not claims:
+#     auth = get_auth(request)
+#     if not auth:
 #         return APIResponse(
 #             True,
 #             "Unauthorized",
@@ -332,7 +718,7 @@ async def set_trainable_bulk(request: Request, body: TrainableUpdateBulkRequest)
 #             status.HTTP_401_UNAUTHORIZED,
 #         )
 
-#     org_id = claims.get("organization_id")
+#     org_id = auth.org_id
 
 #     try:
 #         async with get_db_cursor() as cur:
@@ -400,16 +786,19 @@ async def set_trainable_bulk(request: Request, body: TrainableUpdateBulkRequest)
 # =======================
 @router.delete("/delete/{document_id}")
 async def delete_document(document_id: str, request: Request):
-    claims = getattr(request.state, "claims", None)
-    if not claims:
+    auth = get_auth(request)
+    if not auth:
         return APIResponse(
             True,
             "Unauthorized",
             None,
             status.HTTP_401_UNAUTHORIZED,
+            code=error_codes.UNAUTHORIZED,
         )
 
-    org_id = claims.get("organization_id")
+    org_id = auth.org_id
+    user_id = auth.user_id
+    role = auth.role
 
     try:
         async with get_db_cursor(commit=True) as cur:
@@ -417,7 +806,7 @@ async def delete_document(document_id: str, request: Request):
             # 1️⃣ Verify document exists & ownership
             await cur.execute(
                 """
-                SELECT id
+                SELECT id, created_by, file_size
                 FROM documents
                 WHERE id=%s
                   AND organization_id=%s
@@ -433,15 +822,29 @@ async def delete_document(document_id: str, request: Request):
                     "Document not found",
                     None,
                     status.HTTP_404_NOT_FOUND,
+                    code=error_codes.NOT_FOUND,
+                )
+
+            # 2️⃣ Permission matrix check; the uploader may always delete
+            # their own document
+            if not role_has_capability(role, "documents.delete") and str(
+                doc["created_by"]
+            ) != str(user_id):
+                return APIResponse(
+                    True,
+                    "You do not have permission to delete this document",
+                    None,
+                    status.HTTP_403_FORBIDDEN,
+                    code=error_codes.FORBIDDEN,
                 )
 
-            # 2️⃣ Soft delete
+            # 3️⃣ Soft delete. Training state is kept: deleted_at alone hides
+            # the document from listings, search and retrieval, and keeping
+            # the chunks makes a restore instant instead of a re-embed.
             await cur.execute(
                 """
                 UPDATE documents
                 SET deleted_at = NOW(),
-                    last_trained_at = NULL,
-                    status = 'untrained',
                     updated_at = NOW()
                 WHERE id=%s
                   AND organization_id=%s
@@ -449,6 +852,10 @@ async def delete_document(document_id: str, request: Request):
                 (document_id, org_id),
             )
 
+            # 4️⃣ Trashed documents no longer count against the quota;
+            # restore re-reserves before bringing one back
+            await release_storage(cur, org_id, doc["file_size"] or 0)
+
         return APIResponse(
             False,
             "Document deleted successfully",
@@ -463,3 +870,147 @@ async def delete_document(document_id: str, request: Request):
             {"error": str(e)},
             status.HTTP_500_INTERNAL_SERVER_ERROR,
         )
+
+
+# =======================
+# 🗑️ Trash View
+# =======================
+@router.get("/trash")
+async def list_trash(request: Request):
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    org_id = auth.org_id
+    user_id = auth.user_id
+    role = auth.role
+
+    try:
+        async with get_db_cursor() as cur:
+            # Admin-ish roles see the whole org's trash; everyone else sees
+            # only their own deleted uploads (mirrors who may restore)
+            sql = """
+                SELECT id, file_name, file_size, source_type, created_by, deleted_at,
+                       deleted_at + (%s * INTERVAL '1 day') AS purge_after
+                FROM documents
+                WHERE organization_id = %s
+                  AND deleted_at IS NOT NULL
+            """
+            params = [TRASH_RETENTION_DAYS, org_id]
+
+            if not role_has_capability(role, "documents.delete"):
+                sql += " AND created_by = %s"
+                params.append(user_id)
+
+            sql += " ORDER BY deleted_at DESC"
+
+            await cur.execute(sql, params)
+            documents = await cur.fetchall()
+
+        return APIResponse(
+            False,
+            "Trash fetched successfully",
+            {"documents": documents, "retention_days": TRASH_RETENTION_DAYS},
+        )
+
+    except Exception as e:
+        print(f"[TRASH ERROR] {e}")
+        return APIResponse(
+            True,
+            "Failed to fetch trash",
+            {"error": str(e)},
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
+# =======================
+# ♻️ Restore Document
+# =======================
+@router.post("/{document_id}/restore")
+async def restore_document(document_id: str, request: Request):
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    org_id = auth.org_id
+    user_id = auth.user_id
+    role = auth.role
+
+    try:
+        async with get_db_cursor(commit=True) as cur:
+
+            # 1️⃣ The document must actually be in the trash
+            await cur.execute(
+                """
+                SELECT id, created_by, file_size
+                FROM documents
+                WHERE id=%s
+                  AND organization_id=%s
+                  AND deleted_at IS NOT NULL
+                """,
+                (document_id, org_id),
+            )
+            doc = await cur.fetchone()
+
+            if not doc:
+                return APIResponse(
+                    True,
+                    "Document not found in trash",
+                    None,
+                    status.HTTP_404_NOT_FOUND,
+                    code=error_codes.NOT_FOUND,
+                )
+
+            # 2️⃣ Same audience as delete: permission matrix or the uploader
+            if not role_has_capability(role, "documents.delete") and str(
+                doc["created_by"]
+            ) != str(user_id):
+                return APIResponse(
+                    True,
+                    "You do not have permission to restore this document",
+                    None,
+                    status.HTTP_403_FORBIDDEN,
+                    code=error_codes.FORBIDDEN,
+                )
+
+            # 3️⃣ Refuse if restoring would push the org past its storage
+            # quota; the guarded reservation rolls back with the restore
+            try:
+                await reserve_storage(cur, org_id, doc["file_size"] or 0)
+            except StorageQuotaExceeded as e:
+                return APIResponse(
+                    True,
+                    "Restoring this document would exceed the organization's storage quota",
+                    {"used_bytes": e.used, "quota_bytes": e.limit},
+                    status.HTTP_413_REQUEST_ENTITY_TOO_LARGE,
+                    code=error_codes.QUOTA_EXCEEDED,
+                )
+
+            # 4️⃣ Restore. A single UPDATE is all it takes: chunks were kept
+            # through the soft delete, so clearing deleted_at atomically puts
+            # the document back into listings, search and retrieval.
+            await cur.execute(
+                """
+                UPDATE documents
+                SET deleted_at = NULL,
+                    updated_at = NOW()
+                WHERE id=%s
+                  AND organization_id=%s
+                """,
+                (document_id, org_id),
+            )
+
+        return APIResponse(
+            False,
+            "Document restored successfully",
+            None,
+        )
+
+    except Exception as e:
+        print(f"[RESTORE ERROR] {e}")
+        return APIResponse(
+            True,
+            "Failed to restore document",
+            {"error": str(e)},
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
@@ -0,0 +1,353 @@
+# app/api/messages.py
+import os
+
+from fastapi import APIRouter, Request, status
+from pydantic import BaseModel
+from typing import Literal, Optional
+from app.helpers.message_feedback import FEEDBACK_UPSERT_SQL, OWNED_ASSISTANT_MESSAGE_SQL
+from app.middleware.auth_context import get_auth
+from app.utils.response import APIResponse
+from app.utils import error_codes
+from app.database.postgres_client import get_db_cursor
+
+router = APIRouter(tags=["messages"])
+
+# --------------------------
+# Message Feedback Endpoint
+# --------------------------
+class MessageFeedbackSchema(BaseModel):
+    rating: Literal[-1, 0, 1]
+    comment: Optional[str] = None
+
+@router.post("/messages/{message_id}/feedback")
+async def submit_message_feedback(message_id: str, payload: MessageFeedbackSchema, request: Request):
+    """
+    Record thumbs-up/down feedback on an assistant message.
+    One feedback row per user per message (upsert).
+    """
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    org_id = auth.org_id
+    user_id = auth.user_id
+
+    try:
+        async with get_db_cursor(commit=True) as cur:
+
+            # 1️⃣ Verify the message is an assistant message in a chat the user owns
+            await cur.execute(
+                OWNED_ASSISTANT_MESSAGE_SQL,
+                (message_id, org_id, user_id),
+            )
+            message = await cur.fetchone()
+
+            if not message:
+                return APIResponse(
+                    True,
+                    "Message not found or feedback not allowed",
+                    None,
+                    status.HTTP_404_NOT_FOUND,
+                    code=error_codes.NOT_FOUND,
+                )
+
+            # 2️⃣ Upsert feedback (one row per user per message)
+            await cur.execute(
+                FEEDBACK_UPSERT_SQL,
+                (message_id, org_id, user_id, payload.rating, payload.comment),
+            )
+            feedback = await cur.fetchone()
+
+        return APIResponse(False, "Feedback recorded successfully", feedback)
+
+    except Exception as e:
+        print(f"[FEEDBACK ERROR] {e}")
+        return APIResponse(
+            True,
+            f"Failed to record feedback: {e}",
+            None,
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
+# --------------------------
+# Abuse Reporting
+# --------------------------
+ABUSE_REPORT_CATEGORIES = {"harmful", "hateful", "sexual", "privacy", "misinformation", "other"}
+
+# Flag the org once it accumulates more than this many reports in the window
+ABUSE_REPORT_THRESHOLD = int(os.getenv("ABUSE_REPORT_THRESHOLD", "5"))
+ABUSE_REPORT_WINDOW_DAYS = int(os.getenv("ABUSE_REPORT_WINDOW_DAYS", "7"))
+# When enabled, flagging also flips the org's moderation mode to strict
+ABUSE_AUTO_STRICT = os.getenv("ABUSE_AUTO_STRICT_MODERATION", "false").lower() == "true"
+
+
+class AbuseReportSchema(BaseModel):
+    category: str
+    details: Optional[str] = None
+
+
+async def _maybe_flag_org(cur, org_id: str):
+    """
+    Flag the org when its report volume crosses the threshold. The flag is
+    set once (guarded UPDATE) and shows up in the internal admin listing;
+    strict moderation mode is opt-in via env.
+    """
+    await cur.execute(
+        """
+        SELECT COUNT(*) AS total
+        FROM abuse_reports
+        WHERE organization_id = %s
+          AND created_at > NOW() - make_interval(days => %s)
+        """,
+        (org_id, ABUSE_REPORT_WINDOW_DAYS),
+    )
+    total = (await cur.fetchone())["total"]
+    if total <= ABUSE_REPORT_THRESHOLD:
+        return
+
+    strict_clause = ", moderation_mode = 'strict'" if ABUSE_AUTO_STRICT else ""
+    await cur.execute(
+        f"""
+        UPDATE organizations
+        SET abuse_flagged_at = NOW(){strict_clause}
+        WHERE id = %s AND abuse_flagged_at IS NULL
+        """,
+        (org_id,),
+    )
+    if cur.rowcount:
+        print(f"[ABUSE] org {org_id} flagged: {total} reports in {ABUSE_REPORT_WINDOW_DAYS} days")
+
+
+@router.post("/messages/{message_id}/report")
+async def report_message(message_id: str, payload: AbuseReportSchema, request: Request):
+    """
+    Report problematic AI output. One report per user per message; reporting
+    again updates the existing report instead of duplicating it.
+    """
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    org_id = auth.org_id
+    user_id = auth.user_id
+
+    if payload.category not in ABUSE_REPORT_CATEGORIES:
+        return APIResponse(
+            True,
+            f"category must be one of: {', '.join(sorted(ABUSE_REPORT_CATEGORIES))}",
+            None,
+            status.HTTP_400_BAD_REQUEST,
+        )
+    details = (payload.details or "").strip()[:2000] or None
+
+    try:
+        async with get_db_cursor(commit=True) as cur:
+
+            # 1️⃣ Same visibility rule as feedback: an assistant message in a
+            # chat the reporting user owns
+            await cur.execute(
+                OWNED_ASSISTANT_MESSAGE_SQL,
+                (message_id, org_id, user_id),
+            )
+            message = await cur.fetchone()
+
+            if not message:
+                return APIResponse(
+                    True,
+                    "Message not found or cannot be reported",
+                    None,
+                    status.HTTP_404_NOT_FOUND,
+                    code=error_codes.NOT_FOUND,
+                )
+
+            # 2️⃣ Upsert the report (re-reporting updates, never duplicates)
+            await cur.execute(
+                """
+                INSERT INTO abuse_reports (message_id, organization_id, reporter_user_id, category, details)
+                VALUES (%s, %s, %s, %s, %s)
+                ON CONFLICT (message_id, reporter_user_id)
+                DO UPDATE SET category = EXCLUDED.category,
+                              details = EXCLUDED.details,
+                              updated_at = NOW()
+                RETURNING id, category, details, status, created_at
+                """,
+                (message_id, org_id, user_id, payload.category, details),
+            )
+            report = await cur.fetchone()
+
+            # 3️⃣ Abusive-tenant detection
+            await _maybe_flag_org(cur, org_id)
+
+        return APIResponse(False, "Report recorded successfully", report)
+
+    except Exception as e:
+        print(f"[ABUSE REPORT ERROR] {e}")
+        return APIResponse(
+            True,
+            f"Failed to record report: {e}",
+            None,
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
+@router.get("/messages/reports")
+async def list_my_reports(request: Request, limit: int = 50, offset: int = 0):
+    """
+    List the calling user's own abuse reports, newest first.
+    """
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    limit = max(1, min(limit, 100))
+    offset = max(0, offset)
+
+    try:
+        async with get_db_cursor() as cur:
+            await cur.execute(
+                """
+                SELECT id, message_id, category, details, status, created_at, updated_at
+                FROM abuse_reports
+                WHERE organization_id = %s AND reporter_user_id = %s
+                ORDER BY created_at DESC
+                LIMIT %s OFFSET %s
+                """,
+                (auth.org_id, auth.user_id, limit, offset),
+            )
+            rows = await cur.fetchall()
+
+        return APIResponse(False, "Reports fetched successfully", {"reports": rows})
+
+    except Exception as e:
+        print(f"[ABUSE REPORT LIST ERROR] {e}")
+        return APIResponse(
+            True,
+            f"Failed to fetch reports: {e}",
+            None,
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
+@router.get("/organization/abuse-reports")
+async def list_org_reports(
+    request: Request,
+    report_status: str | None = None,
+    category: str | None = None,
+    limit: int = 50,
+    offset: int = 0,
+):
+    """
+    List abuse reports across the organization. Owner only.
+    """
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    if auth.role != "owner":
+        return APIResponse(
+            True,
+            "Only owners can view abuse reports",
+            None,
+            status.HTTP_403_FORBIDDEN,
+            code=error_codes.FORBIDDEN,
+        )
+
+    if report_status and report_status not in ("open", "reviewed", "dismissed"):
+        return APIResponse(True, "status must be open, reviewed or dismissed", None, status.HTTP_400_BAD_REQUEST)
+    if category and category not in ABUSE_REPORT_CATEGORIES:
+        return APIResponse(
+            True,
+            f"category must be one of: {', '.join(sorted(ABUSE_REPORT_CATEGORIES))}",
+            None,
+            status.HTTP_400_BAD_REQUEST,
+        )
+
+    limit = max(1, min(limit, 100))
+    offset = max(0, offset)
+
+    try:
+        conditions = ["organization_id = %s"]
+        params: list = [auth.org_id]
+        if report_status:
+            conditions.append("status = %s")
+            params.append(report_status)
+        if category:
+            conditions.append("category = %s")
+            params.append(category)
+
+        async with get_db_cursor() as cur:
+            await cur.execute(
+                f"""
+                SELECT id, message_id, reporter_user_id, category, details, status, created_at, updated_at
+                FROM abuse_reports
+                WHERE {' AND '.join(conditions)}
+                ORDER BY created_at DESC
+                LIMIT %s OFFSET %s
+                """,
+                (*params, limit, offset),
+            )
+            rows = await cur.fetchall()
+
+        return APIResponse(False, "Abuse reports fetched successfully", {"reports": rows})
+
+    except Exception as e:
+        print(f"[ABUSE REPORT LIST ERROR] {e}")
+        return APIResponse(
+            True,
+            f"Failed to fetch abuse reports: {e}",
+            None,
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
+
+
+# --------------------------
+# Feedback Stats (owners)
+# --------------------------
+@router.get("/organization/feedback-stats")
+async def get_feedback_stats(request: Request):
+    """
+    Aggregate feedback counts for the organization. Owner only.
+    """
+    auth = get_auth(request)
+    if not auth:
+        return APIResponse(True, "Unauthorized", None, status.HTTP_401_UNAUTHORIZED, code=error_codes.UNAUTHORIZED)
+
+    if auth.role != "owner":
+        return APIResponse(
+            True,
+            "Only owners can view feedback stats",
+            None,
+            status.HTTP_403_FORBIDDEN,
+            code=error_codes.FORBIDDEN,
+        )
+
+    org_id = auth.org_id
+
+    try:
+        async with get_db_cursor() as cur:
+            await cur.execute(
+                """
+                SELECT
+                    COUNT(*) AS total_feedback,
+                    COUNT(*) FILTER (WHERE rating = 1) AS positive,
+                    COUNT(*) FILTER (WHERE rating = -1) AS negative,
+                    COUNT(*) FILTER (WHERE rating = 0) AS neutral,
+                    COUNT(*) FILTER (WHERE comment IS NOT NULL AND comment <> '') AS with_comment
+                FROM message_feedback
+                WHERE organization_id = %s
+                """,
+                (org_id,),
+            )
+            stats = await cur.fetchone()
+
+        return APIResponse(False, "Feedback stats fetched successfully", stats)
+
+    except Exception as e:
+        print(f"[FEEDBACK STATS ERROR] {e}")
+        return APIResponse(
+            True,
+            f"Failed to fetch feedback stats: {e}",
+            None,
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+        )
@@ -25,5 +25,17 @@ class Settings:
     RABBITMQ_BACKEND = os.getenv("RABBITMQ_BACKEND")
 
     FRONTEND_BASE_URL = os.getenv("FRONTEND_BASE_URL")
-    
+
+    # Startup probe for the inference backend. The OpenAI client is lazy, so
+    # without a probe an unreachable backend only surfaces on the first request.
+    INFERENCE_STARTUP_TIMEOUT = float(os.getenv("INFERENCE_STARTUP_TIMEOUT", "5"))
+    INFERENCE_STARTUP_REQUIRED = os.getenv("INFERENCE_STARTUP_REQUIRED", "false").lower() == "true"
+
+    # API docs (Swagger UI + openapi.json) are opt-in per deployment
+    ENABLE_API_DOCS = os.getenv("ENABLE_API_DOCS", "false").lower() == "true"
+
+    # Default document storage cap per org (0 = unlimited); orgs with a
+    # storage_quota_bytes of their own override it.
+    DOCUMENT_STORAGE_QUOTA_BYTES = int(os.getenv("DOCUMENT_STORAGE_QUOTA_BYTES", "0"))
+
 settings = Settings()
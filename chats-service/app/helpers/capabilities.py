@@ -0,0 +1,67 @@
+import asyncio
+import time
+
+from openai import OpenAI
+
+from app.core.config import settings
+
+# Models this service is wired against; the probe checks the backend
+# actually serves them so the frontend can hide unsupported features.
+CHAT_MODEL = "gpt-4o-mini"
+EMBEDDING_MODEL = "text-embedding-3-small"
+
+_CACHE_TTL_SECONDS = 300
+
+_cached: dict | None = None
+_cached_at: float = 0.0
+_lock = asyncio.Lock()
+
+
+async def get_capabilities(force_refresh: bool = False) -> dict:
+    """
+    Probe the inference backend and report what it supports. The result is
+    cached; a failed probe is re-attempted after the TTL (or on demand with
+    force_refresh) so a recovered backend is picked up.
+    """
+    global _cached, _cached_at
+
+    async with _lock:
+        now = time.monotonic()
+        if _cached and not force_refresh and (now - _cached_at) < _CACHE_TTL_SECONDS:
+            return _cached
+
+        caps = {
+            "backend": "openai",
+            "reachable": False,
+            "chat_model": CHAT_MODEL,
+            "embedding_model": EMBEDDING_MODEL,
+            "supports_streaming": False,
+            "supports_embeddings": False,
+        }
+
+        try:
+            client = OpenAI(api_key=settings.OPENAI_API_KEY)
+            models = await asyncio.to_thread(client.models.list)
+            available = {m.id for m in models.data}
+            caps["reachable"] = True
+            caps["supports_streaming"] = CHAT_MODEL in available
+            caps["supports_embeddings"] = EMBEDDING_MODEL in available
+        except Exception as e:
+            print(f"[CAPABILITIES WARN] Backend probe failed: {e}")
+
+        _cached = caps
+        _cached_at = now
+        return caps
+
+
+async def require_embeddings() -> str | None:
+    """
+    Returns a human-readable error when the backend cannot serve RAG queries
+    (embeddings unavailable), or None when everything is supported.
+    """
+    caps = await get_capabilities()
+    if not caps["reachable"]:
+        return "inference backend is unreachable"
+    if not caps["supports_embeddings"]:
+        return "backend does not support embeddings"
+    return None
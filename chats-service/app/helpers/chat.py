@@ -1,15 +1,19 @@
 from app.database.postgres_client import get_db_cursor
+import json
 import uuid
 
-# Save message and update last_message_at
-async def save_message_to_db(org_id: str, chat_id: str, user_id: str, role: str, content: str):
+# Save message and update last_message_at; returns the new message id.
+# `citations` (assistant messages only) is stored as JSONB next to the content.
+async def save_message_to_db(org_id: str, chat_id: str, user_id: str, role: str, content: str, citations: list | None = None):
+    message_id = str(uuid.uuid4())
     async with get_db_cursor(commit=True) as cur:
         await cur.execute(
             """
-            INSERT INTO messages (id, chat_id, organization_id, sender_user_id, role, content, created_at)
-            VALUES (%s, %s, %s, %s, %s, %s, NOW())
+            INSERT INTO messages (id, chat_id, organization_id, sender_user_id, role, content, citations, created_at)
+            VALUES (%s, %s, %s, %s, %s, %s, %s::jsonb, NOW())
             """,
-            (str(uuid.uuid4()), chat_id, org_id, user_id, role, content)
+            (message_id, chat_id, org_id, user_id, role, content,
+             json.dumps(citations) if citations is not None else None)
         )
         # Update chat's last_message_at
         await cur.execute(
@@ -18,6 +22,7 @@ async def save_message_to_db(org_id: str, chat_id: str, user_id: str, role: str,
             """,
             (chat_id,)
         )
+    return message_id
 
 
 # --------------------------
@@ -37,6 +42,37 @@ async def create_chat(org_id: str, user_id: str, title: str):
 
 
 
+# --------------------------
+# Pinned prediction settings
+# --------------------------
+# Chats remember model/temperature/max_tokens once a caller sets them
+# explicitly; NULL columns mean "use the deployment default".
+async def get_chat_settings(org_id: str, chat_id: str) -> dict | None:
+    async with get_db_cursor() as cur:
+        await cur.execute(
+            """
+            SELECT model, temperature, max_tokens
+            FROM chats
+            WHERE id=%s AND organization_id=%s AND deleted_at IS NULL
+            """,
+            (chat_id, org_id),
+        )
+        return await cur.fetchone()
+
+
+async def update_chat_settings(org_id: str, chat_id: str, settings: dict):
+    """Persist only the keys present in `settings` (model / temperature / max_tokens)."""
+    columns = {k: v for k, v in settings.items() if k in ("model", "temperature", "max_tokens")}
+    if not columns:
+        return
+    assignments = ", ".join(f"{k}=%s" for k in columns)
+    async with get_db_cursor(commit=True) as cur:
+        await cur.execute(
+            f"UPDATE chats SET {assignments}, updated_at=NOW() WHERE id=%s AND organization_id=%s",
+            (*columns.values(), chat_id, org_id),
+        )
+
+
 # --------------------------
 # Fetch last N messages
 # --------------------------
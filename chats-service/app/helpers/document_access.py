@@ -0,0 +1,30 @@
+# Shared SQL fragment for document-level access control.
+#
+# Documents default to visibility 'org' (any member of the org can see them).
+# 'restricted' documents are visible only to their uploader, org owners, and
+# users/roles granted an entry in document_permissions. The clause is meant to
+# be applied inside the retrieval query itself (listings, search, and the RAG
+# chunk query) so restricted content is filtered at the database level and
+# never leaks through excerpt snippets.
+
+
+def document_access_clause(alias: str = "d") -> str:
+    """Return a SQL clause restricting documents to those the caller can see.
+
+    Expects the params from document_access_params() appended in order.
+    """
+    return f"""
+        ({alias}.visibility IS DISTINCT FROM 'restricted'
+         OR {alias}.created_by = %s
+         OR %s = 'owner'
+         OR EXISTS (
+             SELECT 1 FROM document_permissions dp
+             WHERE dp.document_id = {alias}.id
+               AND (dp.user_id = %s OR dp.role = %s)
+         ))
+    """
+
+
+def document_access_params(user_id: str, role: str) -> list:
+    """Params matching the placeholders in document_access_clause()."""
+    return [user_id, role, user_id, role]
@@ -0,0 +1,259 @@
+import os
+import asyncio
+import ipaddress
+import time
+from hashlib import sha256
+from urllib.parse import urlparse
+
+import httpx
+
+from app.database.postgres_client import get_db_cursor
+from app.helpers.s3_storage import upload_file_to_s3
+from app.helpers.storage_quota import reserve_storage, release_storage, StorageQuotaExceeded
+from app.helpers.train_document import run_training_job
+
+# Fetch limits: external wikis shouldn't be able to stall the scheduler or
+# fill the disk.
+SYNC_MAX_BYTES = int(os.getenv("DOCUMENT_SYNC_MAX_BYTES", str(10 * 1024 * 1024)))
+SYNC_TIMEOUT_SECONDS = float(os.getenv("DOCUMENT_SYNC_TIMEOUT_SECONDS", "15"))
+SYNC_POLL_SECONDS = int(os.getenv("DOCUMENT_SYNC_POLL_SECONDS", "60"))
+# Backoff cap for repeatedly failing sources (interval doubles per failure)
+SYNC_MAX_BACKOFF_MINUTES = int(os.getenv("DOCUMENT_SYNC_MAX_BACKOFF_MINUTES", str(24 * 60)))
+
+
+class FetchError(Exception):
+    """A fetch that failed validation or transport; message is user-visible."""
+
+    def __init__(self, message: str, http_status: int | None = None):
+        self.http_status = http_status
+        super().__init__(message)
+
+
+async def _resolve_is_private(host: str) -> bool:
+    """Resolve the host and report whether any of its addresses is private.
+
+    Private, loopback, link-local and reserved ranges are all off-limits —
+    a source URL must not be usable to probe the internal network (SSRF).
+    """
+    try:
+        infos = await asyncio.get_event_loop().getaddrinfo(host, None)
+    except OSError:
+        raise FetchError(f"could not resolve host {host!r}")
+    for info in infos:
+        addr = ipaddress.ip_address(info[4][0])
+        if addr.is_private or addr.is_loopback or addr.is_link_local or addr.is_reserved:
+            return True
+    return False
+
+
+async def fetch_source_content(url: str, auth_header: str | None) -> tuple[int, bytes]:
+    """Fetch a source URL with the SSRF and size/timeout guards applied.
+
+    Redirects are not followed at all — a public URL redirecting into a
+    private range is the classic SSRF bypass, so a 3xx is treated as an
+    error. Returns (http_status, body bytes).
+    """
+    parsed = urlparse(url)
+    if parsed.scheme not in ("http", "https") or not parsed.hostname:
+        raise FetchError("source URL must be absolute http(s)")
+    if await _resolve_is_private(parsed.hostname):
+        raise FetchError("source URL resolves to a private address")
+
+    headers = {}
+    if auth_header and ":" in auth_header:
+        name, value = auth_header.split(":", 1)
+        headers[name.strip()] = value.strip()
+
+    async with httpx.AsyncClient(follow_redirects=False, timeout=SYNC_TIMEOUT_SECONDS) as client:
+        async with client.stream("GET", url, headers=headers) as resp:
+            if resp.is_redirect:
+                raise FetchError("redirects are not followed for document sources", resp.status_code)
+            if resp.status_code != 200:
+                raise FetchError(f"source returned HTTP {resp.status_code}", resp.status_code)
+
+            body = b""
+            async for chunk in resp.aiter_bytes():
+                body += chunk
+                if len(body) > SYNC_MAX_BYTES:
+                    raise FetchError(f"source exceeds the {SYNC_MAX_BYTES} byte limit", resp.status_code)
+            return resp.status_code, body
+
+
+async def _record_fetch(source_id, org_id, fetch_status, http_status, size, started, error=None):
+    duration_ms = int((time.monotonic() - started) * 1000)
+    async with get_db_cursor(commit=True) as cur:
+        await cur.execute(
+            """
+            INSERT INTO document_source_fetches
+                (source_id, organization_id, status, http_status, bytes, duration_ms, error)
+            VALUES (%s, %s, %s, %s, %s, %s, %s)
+            """,
+            (source_id, org_id, fetch_status, http_status, size, duration_ms, error),
+        )
+
+
+async def _apply_synced_content(source: dict, body: bytes, content_hash: str) -> None:
+    """Store the new payload and (re)build the backing document.
+
+    The document row, quota movement and source bookkeeping commit together;
+    ingestion is queued after the commit like a manual /train call.
+    """
+    org_id = source["organization_id"]
+    file_name = urlparse(source["url"]).path.rsplit("/", 1)[-1] or urlparse(source["url"]).hostname
+
+    s3_key, _, _ = upload_file_to_s3(
+        file_bytes=body,
+        org_id=org_id,
+        filename=file_name,
+        content_type="text/plain",
+    )
+
+    async with get_db_cursor(commit=True) as cur:
+        doc_id = source["document_id"]
+        if doc_id:
+            await cur.execute(
+                "SELECT file_size FROM documents WHERE id=%s AND organization_id=%s",
+                (doc_id, org_id),
+            )
+            existing = await cur.fetchone()
+            if existing:
+                await release_storage(cur, org_id, existing["file_size"] or 0)
+                await reserve_storage(cur, org_id, len(body))
+                await cur.execute(
+                    """
+                    UPDATE documents
+                    SET s3_key=%s, file_size=%s, status='untrained',
+                        deleted_at=NULL, updated_at=NOW()
+                    WHERE id=%s AND organization_id=%s
+                    """,
+                    (s3_key, len(body), doc_id, org_id),
+                )
+            else:
+                doc_id = None
+
+        if not doc_id:
+            await reserve_storage(cur, org_id, len(body))
+            await cur.execute(
+                """
+                INSERT INTO documents
+                    (created_by, organization_id, file_name, s3_key,
+                    file_size, status, trainable, created_at, updated_at)
+                VALUES (%s, %s, %s, %s, %s, 'untrained', TRUE, NOW(), NOW())
+                RETURNING id
+                """,
+                (source["created_by"], org_id, file_name, s3_key, len(body)),
+            )
+            doc_id = (await cur.fetchone())["id"]
+
+        await cur.execute(
+            """
+            UPDATE document_sources
+            SET document_id=%s, content_hash=%s, failure_count=0,
+                last_fetched_at=NOW(),
+                next_fetch_at=NOW() + (fetch_interval_minutes * INTERVAL '1 minute'),
+                updated_at=NOW()
+            WHERE id=%s
+            """,
+            (doc_id, content_hash, source["id"]),
+        )
+
+        await cur.execute(
+            """
+            INSERT INTO training_jobs
+                (organization_id, initiated_by, status, created_at)
+            VALUES (%s, %s, 'pending', NOW())
+            RETURNING id
+            """,
+            (org_id, source["created_by"]),
+        )
+        job = await cur.fetchone()
+
+    run_training_job.delay(job["id"], org_id, source["created_by"], [doc_id])
+
+
+async def sync_source(source: dict) -> None:
+    """Fetch one source, and when the content hash changed, re-ingest it."""
+    started = time.monotonic()
+    source_id = source["id"]
+    org_id = source["organization_id"]
+
+    try:
+        http_status, body = await fetch_source_content(source["url"], source["auth_header"])
+    except (FetchError, httpx.HTTPError) as e:
+        http_status = getattr(e, "http_status", None)
+        await _record_fetch(source_id, org_id, "failed", http_status, 0, started, str(e))
+        async with get_db_cursor(commit=True) as cur:
+            # Exponential backoff, capped: interval * 2^failures
+            await cur.execute(
+                """
+                UPDATE document_sources
+                SET failure_count = failure_count + 1,
+                    last_fetched_at = NOW(),
+                    next_fetch_at = NOW() + LEAST(
+                        fetch_interval_minutes * POWER(2, LEAST(failure_count + 1, 10)),
+                        %s
+                    ) * INTERVAL '1 minute',
+                    updated_at = NOW()
+                WHERE id = %s
+                """,
+                (SYNC_MAX_BACKOFF_MINUTES, source_id),
+            )
+        return
+
+    content_hash = sha256(body).hexdigest()
+
+    if content_hash == source["content_hash"]:
+        await _record_fetch(source_id, org_id, "unchanged", http_status, len(body), started)
+        async with get_db_cursor(commit=True) as cur:
+            await cur.execute(
+                """
+                UPDATE document_sources
+                SET failure_count=0, last_fetched_at=NOW(),
+                    next_fetch_at=NOW() + (fetch_interval_minutes * INTERVAL '1 minute'),
+                    updated_at=NOW()
+                WHERE id=%s
+                """,
+                (source_id,),
+            )
+        return
+
+    try:
+        await _apply_synced_content(source, body, content_hash)
+    except StorageQuotaExceeded as e:
+        await _record_fetch(source_id, org_id, "failed", http_status, len(body), started, str(e))
+        return
+
+    await _record_fetch(source_id, org_id, "synced", http_status, len(body), started)
+
+
+async def run_due_syncs() -> int:
+    """Sync every enabled source whose next_fetch_at has passed."""
+    async with get_db_cursor() as cur:
+        await cur.execute(
+            """
+            SELECT id, organization_id, created_by, document_id, url,
+                   auth_header, fetch_interval_minutes, content_hash
+            FROM document_sources
+            WHERE enabled AND next_fetch_at <= NOW()
+            ORDER BY next_fetch_at
+            """
+        )
+        due = await cur.fetchall()
+
+    for source in due:
+        try:
+            await sync_source(source)
+        except Exception as e:
+            print(f"[DOC SYNC ERROR] source={source['id']}: {e}")
+
+    return len(due)
+
+
+async def document_sync_loop() -> None:
+    """Background task: poll for due document sources."""
+    while True:
+        try:
+            await run_due_syncs()
+        except Exception as e:
+            print(f"[DOC SYNC LOOP ERROR] {e}")
+        await asyncio.sleep(SYNC_POLL_SECONDS)
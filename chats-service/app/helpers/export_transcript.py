@@ -0,0 +1,31 @@
+# Pure transcript rendering for the chat export endpoint. Kept free of
+# framework and database imports so the output formats can be unit-tested
+# directly; the route streams what these functions produce.
+
+
+def render_markdown(title, rows):
+    """Yield Markdown chunks: a document title, then one heading per turn.
+
+    rows are message dicts with role, content and created_at (datetime or
+    None), already ordered by creation time.
+    """
+    yield f"# {title}\n\n"
+    for r in rows:
+        ts = r["created_at"].isoformat() if r["created_at"] else ""
+        yield f"## {r['role'].capitalize()} — {ts}\n\n{r['content']}\n\n"
+
+
+def build_transcript(chat_id, title, rows):
+    """Build the JSON-serializable transcript document for ?format=json."""
+    return {
+        "chat_id": str(chat_id),
+        "title": title,
+        "messages": [
+            {
+                "role": r["role"],
+                "content": r["content"],
+                "created_at": r["created_at"].isoformat() if r["created_at"] else None,
+            }
+            for r in rows
+        ],
+    }
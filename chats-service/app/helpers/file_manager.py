@@ -63,13 +63,15 @@ class FileManager:
     # 🔹 Chunking
     # ---------------------------
     @staticmethod
-    def chunk_text(
+    def chunk_text_with_offsets(
         text: str,
         chunk_size: int = 1000,
         overlap: int = 200,
-    ) -> List[str]:
+    ) -> List[dict]:
         """
-        Split text into overlapping chunks.
+        Split text into overlapping chunks, each with its character offsets
+        into the whitespace-normalized text: {"text", "char_start", "char_end"}.
+        The offsets drive chunk-level citations / source highlighting.
         """
         if not text:
             return []
@@ -83,11 +85,25 @@ class FileManager:
             end = min(start + chunk_size, length)
             chunk = text[start:end].strip()
             if chunk:
-                chunks.append(chunk)
+                chunks.append({"text": chunk, "char_start": start, "char_end": end})
             start += chunk_size - overlap
 
         return chunks
 
+    @staticmethod
+    def chunk_text(
+        text: str,
+        chunk_size: int = 1000,
+        overlap: int = 200,
+    ) -> List[str]:
+        """
+        Split text into overlapping chunks (text only).
+        """
+        return [
+            c["text"]
+            for c in FileManager.chunk_text_with_offsets(text, chunk_size, overlap)
+        ]
+
     # ---------------------------
     # 🔹 Unified Entry Point
     # ---------------------------
@@ -0,0 +1,61 @@
+import os
+import time
+
+from app.database.postgres_client import get_db_cursor
+
+# Feature-flag evaluation against the shared feature_flags table. Mirrors
+# shared/flags in the Go services: per-org override beats the global row,
+# which beats the FLAG_* env default; flags default to enabled. Evaluations
+# are cached with a short TTL so a flip propagates without hitting the DB on
+# every request.
+
+CACHE_TTL_SECONDS = int(os.getenv("FLAG_CACHE_TTL_SECONDS", "10"))
+
+PREDICT_ENABLED = "predict_enabled"
+
+# (name, org_id) -> (expires_at, enabled)
+_cache: dict[tuple[str, str], tuple[float, bool]] = {}
+
+
+def _env_default(name: str) -> bool:
+    raw = os.getenv(f"FLAG_{name.upper()}", "")
+    if raw.lower() in ("false", "0", "f", "no"):
+        return False
+    return True
+
+
+async def flag_enabled(name: str, org_id: str | None) -> bool:
+    key = (name, org_id or "")
+    entry = _cache.get(key)
+    now = time.monotonic()
+    if entry and entry[0] > now:
+        return entry[1]
+
+    enabled = _env_default(name)
+    try:
+        async with get_db_cursor() as cur:
+            if org_id:
+                await cur.execute(
+                    """
+                    SELECT enabled FROM feature_flags
+                    WHERE name = %s
+                      AND (organization_id = %s OR organization_id IS NULL)
+                    ORDER BY organization_id NULLS LAST
+                    LIMIT 1
+                    """,
+                    (name, org_id),
+                )
+            else:
+                await cur.execute(
+                    "SELECT enabled FROM feature_flags WHERE name = %s AND organization_id IS NULL",
+                    (name,),
+                )
+            row = await cur.fetchone()
+        if row is not None:
+            enabled = row["enabled"]
+    except Exception as e:
+        # Fail open: a flaky flags table must not take gated endpoints down
+        print(f"[FLAGS WARN] evaluation failed for {name}: {e}")
+
+    _cache[key] = (now + CACHE_TTL_SECONDS, enabled)
+    return enabled
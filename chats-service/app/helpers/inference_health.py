@@ -0,0 +1,43 @@
+import asyncio
+
+from app.core.config import settings
+from app.helpers.get_embedding_with_retry import client
+
+
+async def check_inference_backend(timeout: float | None = None) -> bool:
+    """Probe the inference backend once with a hard deadline.
+
+    The OpenAI client is lazy: constructing it never touches the network, so a
+    bad key or unreachable endpoint would otherwise only surface on the first
+    chat request. A cheap models.list call at startup tells us up front.
+    """
+    timeout = timeout or settings.INFERENCE_STARTUP_TIMEOUT
+    try:
+        await asyncio.wait_for(
+            asyncio.to_thread(
+                client.with_options(timeout=timeout).models.list
+            ),
+            timeout=timeout,
+        )
+        return True
+    except Exception as e:
+        print(f"[INFERENCE WARN] backend unreachable at startup: {e}")
+        return False
+
+
+async def wait_for_inference_backend() -> None:
+    """Block startup until the backend answers, retrying every couple of
+    seconds within INFERENCE_STARTUP_TIMEOUT. Raises if it never comes up,
+    so a required deployment fails fast instead of serving dead endpoints.
+    """
+    deadline = asyncio.get_event_loop().time() + settings.INFERENCE_STARTUP_TIMEOUT
+    while True:
+        remaining = deadline - asyncio.get_event_loop().time()
+        if await check_inference_backend(timeout=max(remaining, 1)):
+            return
+        if asyncio.get_event_loop().time() >= deadline:
+            raise RuntimeError(
+                "inference backend unreachable within "
+                f"{settings.INFERENCE_STARTUP_TIMEOUT}s"
+            )
+        await asyncio.sleep(2)
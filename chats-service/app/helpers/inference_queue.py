@@ -0,0 +1,204 @@
+import asyncio
+import os
+import time
+from collections import deque
+
+from app.database.postgres_client import get_db_cursor
+
+# Weighted fair admission in front of the inference backend. Without it a
+# saturated backend serves whoever connected first, so free-tier orgs can
+# starve paying customers. Each org queues into its own bounded lane; a
+# smooth weighted round-robin over the lanes (weighted by the org's billing
+# tier) decides who gets the next free slot. Single-tenant deployments keep
+# the queue off (the default) and requests pass straight through.
+
+QUEUE_ENABLED = os.getenv("INFERENCE_QUEUE_ENABLED", "false").lower() == "true"
+MAX_CONCURRENT = int(os.getenv("INFERENCE_QUEUE_CONCURRENCY", "4"))
+MAX_ORG_DEPTH = int(os.getenv("INFERENCE_QUEUE_ORG_DEPTH", "8"))
+MAX_WAIT_SECONDS = float(os.getenv("INFERENCE_QUEUE_MAX_WAIT_SECONDS", "15"))
+TIER_CACHE_TTL_SECONDS = int(os.getenv("TIER_CACHE_TTL_SECONDS", "30"))
+
+# Tier comes from organizations.tier (set by the internal billing API)
+TIER_WEIGHTS = {"free": 1, "pro": 4, "enterprise": 8}
+
+# org_id -> deque of waiting futures; a future resolves when a slot is granted
+_lanes: dict[str, deque] = {}
+# org_id -> tier weight of the waiters in that lane
+_weights: dict[str, int] = {}
+# org_id -> accumulated scheduling credit (smooth weighted round-robin)
+_credits: dict[str, float] = {}
+_active = 0
+
+# org_id -> (expires_at, tier)
+_tier_cache: dict[str, tuple[float, str]] = {}
+
+_stats = {"admitted": 0, "timeouts": 0, "rejected": 0}
+_wait_ewma = 0.0
+_wait_max = 0.0
+
+
+class QueueTimeout(Exception):
+    """Request waited past the budget; carries the current depth as a hint."""
+
+    def __init__(self, depth: int):
+        super().__init__(f"inference queue wait budget exceeded ({depth} waiting)")
+        self.depth = depth
+
+
+class QueueFull(Exception):
+    """The org's lane is at its bounded depth."""
+
+    def __init__(self, depth: int):
+        super().__init__(f"inference queue is full ({depth} waiting)")
+        self.depth = depth
+
+
+async def _org_tier(org_id: str) -> str:
+    entry = _tier_cache.get(org_id)
+    now = time.monotonic()
+    if entry and entry[0] > now:
+        return entry[1]
+
+    tier = "free"
+    try:
+        async with get_db_cursor() as cur:
+            await cur.execute("SELECT tier FROM organizations WHERE id = %s", (org_id,))
+            row = await cur.fetchone()
+        if row and row.get("tier"):
+            tier = row["tier"]
+    except Exception as e:
+        # Fail open on the cheapest weight; a flaky lookup must not block
+        print(f"[QUEUE WARN] tier lookup failed for {org_id}: {e}")
+
+    _tier_cache[org_id] = (now + TIER_CACHE_TTL_SECONDS, tier)
+    return tier
+
+
+def queue_depth() -> int:
+    return sum(len(lane) for lane in _lanes.values())
+
+
+def queue_stats() -> dict:
+    by_tier: dict[str, int] = {}
+    for org_id, lane in _lanes.items():
+        weight = _weights.get(org_id, 1)
+        tier = next((t for t, w in TIER_WEIGHTS.items() if w == weight), "free")
+        by_tier[tier] = by_tier.get(tier, 0) + len(lane)
+    return {
+        "enabled": QUEUE_ENABLED,
+        "active": _active,
+        "max_concurrent": MAX_CONCURRENT,
+        "depth": queue_depth(),
+        "depth_by_tier": by_tier,
+        "avg_wait_seconds": round(_wait_ewma, 3),
+        "max_wait_seconds": round(_wait_max, 3),
+        "admitted": _stats["admitted"],
+        "timeouts": _stats["timeouts"],
+        "rejected": _stats["rejected"],
+    }
+
+
+def _note_wait(waited: float) -> None:
+    global _wait_ewma, _wait_max
+    _wait_ewma = waited if _wait_ewma == 0 else 0.8 * _wait_ewma + 0.2 * waited
+    _wait_max = max(_wait_max, waited)
+
+
+def _pick_lane() -> str | None:
+    waiting = [org for org, lane in _lanes.items() if lane]
+    if not waiting:
+        return None
+    total = 0
+    for org in waiting:
+        weight = _weights.get(org, 1)
+        _credits[org] = _credits.get(org, 0) + weight
+        total += weight
+    picked = max(waiting, key=lambda org: _credits[org])
+    _credits[picked] -= total
+    return picked
+
+
+def _dispatch() -> None:
+    global _active
+    while _active < MAX_CONCURRENT:
+        org = _pick_lane()
+        if org is None:
+            return
+        lane = _lanes[org]
+        fut = lane.popleft()
+        if not lane:
+            _lanes.pop(org, None)
+            _weights.pop(org, None)
+            _credits.pop(org, None)
+        if fut.cancelled():
+            continue
+        _active += 1
+        fut.set_result(None)
+
+
+def _remove_waiter(org_id: str, fut) -> None:
+    lane = _lanes.get(org_id)
+    if lane is None:
+        return
+    try:
+        lane.remove(fut)
+    except ValueError:
+        pass
+    if not lane:
+        _lanes.pop(org_id, None)
+        _weights.pop(org_id, None)
+        _credits.pop(org_id, None)
+
+
+async def acquire(org_id: str) -> None:
+    """Waits for an inference slot. Raises QueueFull when the org's lane is
+    at capacity and QueueTimeout when the wait budget runs out. Every
+    successful acquire must be paired with a release()."""
+    global _active
+
+    if not QUEUE_ENABLED:
+        return
+
+    # Fast path: free slot and nobody waiting ahead
+    if _active < MAX_CONCURRENT and not _lanes:
+        _active += 1
+        _stats["admitted"] += 1
+        return
+
+    lane = _lanes.setdefault(org_id, deque())
+    if len(lane) >= MAX_ORG_DEPTH:
+        if not lane:
+            _lanes.pop(org_id, None)
+        _stats["rejected"] += 1
+        raise QueueFull(queue_depth())
+
+    _weights[org_id] = TIER_WEIGHTS.get(await _org_tier(org_id), 1)
+    fut = asyncio.get_running_loop().create_future()
+    lane.append(fut)
+
+    started = time.monotonic()
+    try:
+        await asyncio.wait_for(fut, timeout=MAX_WAIT_SECONDS)
+    except asyncio.TimeoutError:
+        _remove_waiter(org_id, fut)
+        _stats["timeouts"] += 1
+        raise QueueTimeout(queue_depth()) from None
+    except asyncio.CancelledError:
+        if fut.done() and not fut.cancelled():
+            # Slot was granted in the same tick the caller went away
+            release()
+        else:
+            _remove_waiter(org_id, fut)
+        raise
+
+    _note_wait(time.monotonic() - started)
+    _stats["admitted"] += 1
+
+
+def release() -> None:
+    """Frees a slot and hands it to the next weighted-fair waiter."""
+    global _active
+    if not QUEUE_ENABLED:
+        return
+    _active = max(0, _active - 1)
+    _dispatch()
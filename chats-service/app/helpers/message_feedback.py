@@ -0,0 +1,28 @@
+# SQL for message feedback, kept importable without the framework so the
+# ownership filter and the one-row-per-user-per-message upsert can be
+# unit-tested against a real schema.
+
+# A user may rate only assistant messages inside their own, non-deleted chats
+# within their org.
+OWNED_ASSISTANT_MESSAGE_SQL = """
+    SELECT m.id
+    FROM messages m
+    JOIN chats c ON c.id = m.chat_id
+    WHERE m.id = %s
+      AND m.organization_id = %s
+      AND m.role = 'assistant'
+      AND c.user_id = %s
+      AND c.deleted_at IS NULL
+"""
+
+# Re-submitting replaces the previous rating/comment (uq_message_feedback on
+# message_id, user_id), so each user holds at most one row per message.
+FEEDBACK_UPSERT_SQL = """
+    INSERT INTO message_feedback (message_id, organization_id, user_id, rating, comment)
+    VALUES (%s, %s, %s, %s, %s)
+    ON CONFLICT (message_id, user_id)
+    DO UPDATE SET rating = EXCLUDED.rating,
+                  comment = EXCLUDED.comment,
+                  updated_at = NOW()
+    RETURNING id, rating, comment
+"""
@@ -0,0 +1,31 @@
+from app.database.postgres_client import get_db_cursor
+
+# Step keys for the checklist steps this service owns; they must match the
+# constants in users-service services/onboarding.go, which serves the
+# checklist from the same organizations.onboarding_state JSONB.
+STEP_DOCUMENT_UPLOADED = "document_uploaded"
+STEP_FIRST_PREDICTION = "first_prediction"
+
+
+async def mark_onboarding_step(org_id: str, step: str) -> None:
+    """Record a completed getting-started step with its timestamp.
+
+    The guarded UPDATE only fires when the key is absent, so a second upload
+    or prediction is a no-op and the original completion time is kept.
+    Best-effort: the checklist must never fail the action that completed it.
+    """
+    try:
+        async with get_db_cursor(commit=True) as cur:
+            await cur.execute(
+                """
+                UPDATE organizations
+                SET onboarding_state = jsonb_set(
+                        COALESCE(onboarding_state, '{}'::jsonb),
+                        ARRAY[%s], to_jsonb(NOW()), true)
+                WHERE id = %s
+                  AND NOT jsonb_exists(COALESCE(onboarding_state, '{}'::jsonb), %s)
+                """,
+                (step, org_id, step),
+            )
+    except Exception as e:
+        print(f"[ONBOARDING WARN] failed to mark step {step} for org {org_id}: {e}")
@@ -0,0 +1,24 @@
+# Role → capability matrix mirroring shared/constants/permissions.go in the
+# Go services. Keep the two in sync when adding capabilities.
+
+ROLE_PERMISSIONS = {
+    "owner": {
+        "org.settings.read",
+        "org.settings.write",
+        "users.invite",
+        "users.suspend",
+        "documents.delete",
+    },
+    "maintainer": {
+        "org.settings.read",
+        "users.invite",
+        "users.suspend",
+        "documents.delete",
+    },
+    "member": set(),
+}
+
+
+def role_has_capability(role: str | None, capability: str) -> bool:
+    """Unknown roles grant nothing."""
+    return capability in ROLE_PERMISSIONS.get(role or "", set())
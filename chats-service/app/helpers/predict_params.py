@@ -0,0 +1,77 @@
+import os
+
+# Validation rules for prediction parameters, shared by /chats/query and the
+# per-chat settings PATCH so both paths accept exactly the same values.
+
+TEMPERATURE_MIN = 0.0
+TEMPERATURE_MAX = 2.0
+
+# Upper bound for a per-request/per-chat max_tokens override
+MAX_TOKENS_CAP = int(os.getenv("PREDICT_MAX_TOKENS_CAP", "4096"))
+
+# Longest query we forward to the inference backend
+MAX_INPUT_CHARS = int(os.getenv("PREDICT_MAX_INPUT_CHARS", "8000"))
+
+
+def sanitize_query_message(message: str) -> tuple[str | None, str | None]:
+    """
+    Returns (cleaned_message, error). Null bytes are stripped (they break
+    downstream processing), other control characters besides whitespace are
+    rejected, and over-long input gets a clear 400 message.
+    """
+    cleaned = message.replace("\x00", "").strip()
+    if not cleaned:
+        return None, "message cannot be empty"
+    if len(cleaned) > MAX_INPUT_CHARS:
+        return None, f"message exceeds the maximum length of {MAX_INPUT_CHARS} characters"
+    if any(ch < " " and ch not in "\n\r\t" for ch in cleaned):
+        return None, "message contains unsupported control characters"
+    return cleaned, None
+
+
+def validate_predict_params(
+    model: str | None,
+    temperature: float | None,
+    max_tokens: int | None,
+) -> str | None:
+    """Returns an error message, or None when every provided value is valid."""
+    if model is not None:
+        if not model.strip():
+            return "model cannot be empty"
+        if len(model) > 100:
+            return "model name is too long"
+    if temperature is not None:
+        if not (TEMPERATURE_MIN <= temperature <= TEMPERATURE_MAX):
+            return f"temperature must be between {TEMPERATURE_MIN} and {TEMPERATURE_MAX}"
+    if max_tokens is not None:
+        if not (1 <= max_tokens <= MAX_TOKENS_CAP):
+            return f"max_tokens must be between 1 and {MAX_TOKENS_CAP}"
+    return None
+
+
+async def check_model_allowed(org_id: str, model: str) -> str | None:
+    """
+    Check a model against the org's allowlist (allowed_chat_models on the
+    organizations row, comma-separated; empty = any model). Returns an error
+    message when the model is not allowed.
+    """
+    from app.database.postgres_client import get_db_cursor
+
+    try:
+        async with get_db_cursor() as cur:
+            await cur.execute(
+                "SELECT allowed_chat_models FROM organizations WHERE id = %s",
+                (org_id,),
+            )
+            row = await cur.fetchone()
+    except Exception as e:
+        # Fail open like the other org-settings lookups: a read blip should
+        # not block predictions
+        print(f"[PREDICT PARAMS WARN] allowlist lookup failed: {e}")
+        return None
+
+    raw = (row or {}).get("allowed_chat_models") or ""
+    allowed = [m.strip() for m in raw.split(",") if m.strip()]
+    if allowed and model not in allowed:
+        return f"model '{model}' is not in your organization's allowed models"
+    return None
@@ -0,0 +1,59 @@
+import re
+
+# Must match placeholderPattern in users-service, where templates are managed
+PLACEHOLDER_PATTERN = re.compile(r"\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}")
+
+
+def render_template_body(body: str, variables: dict) -> tuple[str | None, str | None]:
+    """
+    Returns (rendered, error). Every {{placeholder}} in the body must have a
+    value in `variables`; unresolved markers would otherwise leak raw template
+    syntax into the model prompt.
+    """
+    missing = []
+
+    def substitute(match):
+        name = match.group(1)
+        value = variables.get(name)
+        if value is None:
+            missing.append(name)
+            return ""
+        return str(value)
+
+    rendered = PLACEHOLDER_PATTERN.sub(substitute, body)
+    if missing:
+        return None, f"missing template variables: {', '.join(sorted(set(missing)))}"
+    return rendered, None
+
+
+async def render_prompt_template(
+    org_id: str, template_id: str, variables: dict | None
+) -> tuple[str | None, str | None]:
+    """
+    Fetch a template scoped to the caller's org and render it with the given
+    variables. A template from another org is indistinguishable from a missing
+    one. Returns (rendered_message, error).
+    """
+    # Imported here so render_template_body stays importable (and testable)
+    # without the database driver
+    from app.database.postgres_client import get_db_cursor
+
+    try:
+        async with get_db_cursor() as cur:
+            await cur.execute(
+                """
+                SELECT body
+                FROM prompt_templates
+                WHERE id = %s AND organization_id = %s
+                """,
+                (template_id, org_id),
+            )
+            template = await cur.fetchone()
+    except Exception as e:
+        print(f"[PROMPT TEMPLATE ERROR] {e}")
+        return None, "failed to load prompt template"
+
+    if not template:
+        return None, "prompt template not found"
+
+    return render_template_body(template["body"], variables or {})
@@ -0,0 +1,158 @@
+import os
+import asyncio
+import smtplib
+from email.mime.text import MIMEText
+from datetime import datetime, timezone
+
+from app.database.postgres_client import get_db_cursor
+from app.helpers.capabilities import CHAT_MODEL
+
+# Fraction of the monthly quota at which a warning is attached to responses
+# and a one-time email goes to the org owner.
+QUOTA_SOFT_THRESHOLD = float(os.getenv("QUOTA_SOFT_THRESHOLD", "0.8"))
+
+
+def _current_period(now: datetime) -> tuple:
+    """Return (period_start date, resets_at datetime) for the UTC month of `now`."""
+    period_start = now.date().replace(day=1)
+    if now.month == 12:
+        resets_at = datetime(now.year + 1, 1, 1, tzinfo=timezone.utc)
+    else:
+        resets_at = datetime(now.year, now.month + 1, 1, tzinfo=timezone.utc)
+    return period_start, resets_at
+
+
+def _send_owner_email(to: str, org_name: str, percent_used: float, resets_at: datetime):
+    """Blocking SMTP send; run via asyncio.to_thread. Same env vars as users-service."""
+    host = os.getenv("SMTP_HOST")
+    port = os.getenv("SMTP_PORT")
+    user = os.getenv("SMTP_USER")
+    password = os.getenv("SMTP_PASSWORD")
+    if not (host and port and user and password):
+        print("[QUOTA WARN] SMTP not configured, skipping quota warning email")
+        return
+
+    msg = MIMEText(
+        f"<p>Your organization <b>{org_name}</b> has used "
+        f"{percent_used:.0f}% of its monthly prediction quota.</p>"
+        f"<p>The quota resets at {resets_at.strftime('%Y-%m-%d')} UTC.</p>",
+        "html",
+    )
+    msg["Subject"] = "Prediction quota warning"
+    msg["From"] = user
+    msg["To"] = to
+
+    with smtplib.SMTP(host, int(port)) as server:
+        server.starttls()
+        server.login(user, password)
+        server.sendmail(user, [to], msg.as_string())
+
+
+async def consume_prediction_quota(org_id: str) -> dict:
+    """
+    Count one prediction against the org's monthly quota and decide what to do.
+
+    Returns a dict:
+      blocked        -> True when the org chose `block` and the quota is exhausted
+      model          -> chat model to use (fallback model when degrading)
+      quota_warning  -> {"percent_used", "resets_at"} past the soft threshold, else None
+      message        -> human-readable reason when blocked
+    """
+    result = {"blocked": False, "model": CHAT_MODEL, "quota_warning": None, "message": None}
+
+    now = datetime.now(timezone.utc)
+    period_start, resets_at = _current_period(now)
+
+    owner_email = None
+    org_name = None
+    percent_used = 0.0
+    send_warning_email = False
+
+    async with get_db_cursor(commit=True) as cur:
+        await cur.execute(
+            """
+            SELECT name, prediction_quota_monthly, quota_overflow_behavior, quota_fallback_model
+            FROM organizations
+            WHERE id = %s
+            """,
+            (org_id,),
+        )
+        org = await cur.fetchone()
+        if not org:
+            return result
+
+        limit = org["prediction_quota_monthly"] or 0
+        if limit <= 0:  # 0 = unlimited
+            return result
+        org_name = org["name"]
+
+        # Race-safe counting: the period row is created atomically on first use,
+        # so concurrent requests crossing the rollover all land on the same row.
+        await cur.execute(
+            """
+            INSERT INTO org_quota_periods (organization_id, period_start, predictions_used)
+            VALUES (%s, %s, 1)
+            ON CONFLICT (organization_id, period_start)
+            DO UPDATE SET predictions_used = org_quota_periods.predictions_used + 1
+            RETURNING predictions_used
+            """,
+            (org_id, period_start),
+        )
+        row = await cur.fetchone()
+        used = row["predictions_used"]
+        percent_used = used * 100.0 / limit
+
+        if used > limit:
+            behavior = org["quota_overflow_behavior"] or "block"
+            if behavior == "degrade" and org["quota_fallback_model"]:
+                result["model"] = org["quota_fallback_model"]
+                result["quota_warning"] = {
+                    "percent_used": round(min(percent_used, 100.0), 1),
+                    "resets_at": resets_at.isoformat(),
+                }
+            else:
+                result["blocked"] = True
+                result["message"] = "monthly prediction quota exhausted"
+            return result
+
+        if used >= QUOTA_SOFT_THRESHOLD * limit:
+            result["quota_warning"] = {
+                "percent_used": round(percent_used, 1),
+                "resets_at": resets_at.isoformat(),
+            }
+
+            # Claim the one-time warning email for this period; only the request
+            # that flips warning_sent_at actually sends it.
+            await cur.execute(
+                """
+                UPDATE org_quota_periods
+                SET warning_sent_at = NOW()
+                WHERE organization_id = %s AND period_start = %s AND warning_sent_at IS NULL
+                RETURNING warning_sent_at
+                """,
+                (org_id, period_start),
+            )
+            if await cur.fetchone():
+                await cur.execute(
+                    """
+                    SELECT email FROM users
+                    WHERE organization_id = %s AND role = 'owner' AND deleted_at IS NULL
+                    ORDER BY created_at ASC
+                    LIMIT 1
+                    """,
+                    (org_id,),
+                )
+                owner = await cur.fetchone()
+                if owner:
+                    owner_email = owner["email"]
+                    send_warning_email = True
+
+    if send_warning_email:
+        try:
+            await asyncio.to_thread(
+                _send_owner_email, owner_email, org_name, percent_used, resets_at
+            )
+        except Exception as e:
+            print(f"[QUOTA ERROR] failed to send warning email: {e}")
+
+    return result
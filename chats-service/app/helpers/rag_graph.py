@@ -1,10 +1,13 @@
+import asyncio
 import re
 import numpy as np
 from openai import AsyncOpenAI
 from app.database.postgres_client import get_db_cursor
+from app.middleware.tracing import outbound_headers
 from app.helpers.chat import save_message_to_db, fetch_recent_messages
 from app.helpers.get_embedding_with_retry import get_embedding_with_retry
 from app.helpers.token_usage import record_token_usage
+from app.helpers.document_access import document_access_clause, document_access_params
 from app.core.config import settings
 
 client = AsyncOpenAI(api_key=settings.OPENAI_API_KEY)
@@ -65,6 +68,7 @@ Optimized Query:
             {"role": "system", "content": system_prompt.strip()},
             {"role": "user", "content": user_prompt.strip()},
         ],
+        extra_headers=outbound_headers(),
     )
 
     return response.choices[0].message.content.strip()
@@ -121,9 +125,14 @@ async def query_rag_openai_stream(
     chat_id: str,
     user_message: str,
     document_id: str | None = None,
+    user_role: str | None = None,
+    model: str = "gpt-4o-mini",
+    temperature: float = 0.2,
+    max_tokens: int | None = None,
+    quota_warning: dict | None = None,
 ):
-    # Save original user message
-    await save_message_to_db(org_id, chat_id, user_id, "user", user_message)
+    # Save original user message (attributed to the authenticated caller)
+    user_message_id = await save_message_to_db(org_id, chat_id, user_id, "user", user_message)
     yield {"event": "status", "content": "💬 User message saved"}
 
     # Query Optimization (conditional)
@@ -149,11 +158,14 @@ async def query_rag_openai_stream(
 
     yield {"event": "status", "content": "🧠 Embedding generated"}
 
-    # Vector Search (ORG-WIDE)
+    # Vector Search (ORG-WIDE, filtered by document ACLs at the query level)
     async with get_db_cursor() as cur:
-        sql = """
+        sql = f"""
             SELECT
+                dc.id AS chunk_id,
                 dc.chunk_text,
+                dc.char_start,
+                dc.char_end,
                 dc.document_id,
                 d.file_name AS document_title
             FROM document_chunks dc
@@ -161,8 +173,9 @@ async def query_rag_openai_stream(
             WHERE dc.organization_id = %s
               AND d.deleted_at IS NULL
               AND d.status = 'trained'
+              AND {document_access_clause("d")}
         """
-        params = [org_id]
+        params = [org_id, *document_access_params(user_id, user_role)]
 
         if document_id:
             sql += " AND dc.document_id = %s"
@@ -181,6 +194,23 @@ async def query_rag_openai_stream(
 
     sources = [{"id": k, "title": v} for k, v in source_map.items()]
 
+    # Chunk-level citations for the chunks actually injected into the prompt.
+    # The OpenAI backend doesn't report which chunks it drew on, so every
+    # injected chunk is flagged "retrieved"; a backend that returns used
+    # chunk ids would upgrade those entries to "cited".
+    citations = [
+        {
+            "chunk_id": str(c["chunk_id"]),
+            "document_id": str(c["document_id"]),
+            "document_title": c["document_title"],
+            "char_start": c["char_start"],
+            "char_end": c["char_end"],
+            "text": c["chunk_text"],
+            "confidence": "retrieved",
+        }
+        for c in chunks[:MAX_CHUNKS_IN_PROMPT]
+    ]
+
     # Context
     context_text = "\n\n".join(
         c["chunk_text"] for c in chunks[:MAX_CHUNKS_IN_PROMPT]
@@ -203,16 +233,22 @@ async def query_rag_openai_stream(
     completion_tokens = 0
     full_response = ""
 
-    # LLM Streaming
+    # LLM Streaming. When the caller disconnects, the route closes this
+    # generator (or the task is cancelled); the cleanup below shuts the
+    # upstream LLM stream promptly instead of generating tokens nobody reads.
+    stream = None
     try:
         stream = await client.chat.completions.create(
-            model="gpt-4o-mini",
-            temperature=0.2,
+            model=model,
+            temperature=temperature,
+            max_tokens=max_tokens,
             stream=True,
             messages=[
                 {"role": "system", "content": system_prompt},
                 {"role": "user", "content": user_prompt},
             ],
+            # Ties the backend call into the request's trace
+            extra_headers=outbound_headers(),
         )
 
         async for chunk in stream:
@@ -232,9 +268,11 @@ async def query_rag_openai_stream(
 
         # Save assistant message
 
+        assistant_message_id = None
         if full_response.strip():
-            await save_message_to_db(
-                org_id, chat_id, None, "assistant", full_response.strip()
+            assistant_message_id = await save_message_to_db(
+                org_id, chat_id, None, "assistant", full_response.strip(),
+                citations=citations or None,
             )
 
 
@@ -243,7 +281,7 @@ async def query_rag_openai_stream(
         await record_token_usage(
             organization_id=org_id,
             user_id=user_id,
-            model="gpt-4o-mini",
+            model=model,
             prompt_tokens=prompt_tokens,
             completion_tokens=completion_tokens,
         )
@@ -251,12 +289,36 @@ async def query_rag_openai_stream(
 
         # Final payload
 
-        yield {
+        final_payload = {
             "event": "final",
             "chatId": chat_id,
+            "userMessageId": user_message_id,
+            "assistantMessageId": assistant_message_id,
             "answer": full_response.strip(),
             "sources": sources,
+            "citations": citations,
         }
-
+        if quota_warning:
+            final_payload["quota_warning"] = quota_warning
+
+        yield final_payload
+
+    except (asyncio.CancelledError, GeneratorExit):
+        # Client went away mid-stream: stop the upstream call and still
+        # account for the tokens that were already generated.
+        if stream is not None:
+            await stream.close()
+        if completion_tokens:
+            try:
+                await record_token_usage(
+                    organization_id=org_id,
+                    user_id=user_id,
+                    model=model,
+                    prompt_tokens=prompt_tokens,
+                    completion_tokens=completion_tokens,
+                )
+            except Exception:
+                pass
+        raise
     except Exception as e:
         yield {"event": "error", "content": f"❌ {str(e)}"}
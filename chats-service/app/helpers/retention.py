@@ -0,0 +1,103 @@
+import asyncio
+
+# Messages are hard-deleted in batches so the purge never holds long locks.
+PURGE_BATCH_SIZE = 1000
+PURGE_INTERVAL_SECONDS = 24 * 60 * 60  # nightly
+
+# Kept as module constants so the boundary semantics (strictly older than the
+# cutoff; the batched subquery shape) are unit-testable without the driver.
+PURGE_MESSAGES_SQL = """
+    DELETE FROM messages
+    WHERE id IN (
+        SELECT id FROM messages
+        WHERE organization_id = %s
+          AND created_at < NOW() - (%s * INTERVAL '1 day')
+        LIMIT %s
+    )
+    RETURNING id
+"""
+
+# The age guard matters: a chat row is committed before its first message
+# exists (messages are only recorded after streaming completes), so a purge
+# pass without it would delete a chat mid-first-conversation.
+PURGE_EMPTY_CHATS_SQL = """
+    DELETE FROM chats
+    WHERE organization_id = %s
+      AND created_at < NOW() - INTERVAL '1 day'
+      AND NOT EXISTS (SELECT 1 FROM messages m WHERE m.chat_id = chats.id)
+    RETURNING id
+"""
+
+
+async def purge_org_messages(org_id: str, retention_days: int) -> int:
+    """Hard-delete messages older than the org's retention policy in batches.
+
+    Returns the total number of messages deleted.
+    """
+    from app.database.postgres_client import get_db_cursor
+
+    total_deleted = 0
+
+    while True:
+        async with get_db_cursor(commit=True) as cur:
+            await cur.execute(
+                PURGE_MESSAGES_SQL,
+                (org_id, retention_days, PURGE_BATCH_SIZE),
+            )
+            deleted = await cur.fetchall()
+
+        if not deleted:
+            break
+
+        total_deleted += len(deleted)
+        print(f"[RETENTION] org={org_id} deleted batch of {len(deleted)} messages (total {total_deleted})")
+        await asyncio.sleep(0)
+
+    # Remove chats left with no messages at all
+    async with get_db_cursor(commit=True) as cur:
+        await cur.execute(PURGE_EMPTY_CHATS_SQL, (org_id,))
+        empty_chats = await cur.fetchall()
+
+    if empty_chats:
+        print(f"[RETENTION] org={org_id} removed {len(empty_chats)} empty chats")
+
+    return total_deleted
+
+
+async def run_retention_purge() -> None:
+    """Run one purge pass over all orgs with a retention policy (> 0 days)."""
+    from app.database.postgres_client import get_db_cursor
+
+    async with get_db_cursor() as cur:
+        await cur.execute(
+            """
+            SELECT id, message_retention_days
+            FROM organizations
+            WHERE message_retention_days > 0
+            """
+        )
+        orgs = await cur.fetchall()
+
+    for org in orgs:
+        org_id = org["id"]
+        retention_days = org["message_retention_days"]
+        try:
+            deleted = await purge_org_messages(org_id, retention_days)
+            async with get_db_cursor(commit=True) as cur:
+                await cur.execute(
+                    "UPDATE organizations SET last_purge_at = NOW() WHERE id = %s",
+                    (org_id,),
+                )
+            print(f"[RETENTION] org={org_id} purge complete, deleted {deleted} messages")
+        except Exception as e:
+            print(f"[RETENTION ERROR] org={org_id}: {e}")
+
+
+async def retention_purge_loop() -> None:
+    """Background task: purge messages past retention on a nightly schedule."""
+    while True:
+        try:
+            await run_retention_purge()
+        except Exception as e:
+            print(f"[RETENTION LOOP ERROR] {e}")
+        await asyncio.sleep(PURGE_INTERVAL_SECONDS)
@@ -0,0 +1,131 @@
+import asyncio
+from app.database.postgres_client import get_db_cursor
+from app.core.config import settings
+
+# How often the drift-correction pass recomputes storage_used_bytes from the
+# documents table.
+RECONCILE_INTERVAL_SECONDS = 24 * 60 * 60  # nightly
+
+# The effective quota is the org's storage_quota_bytes, falling back to the
+# deployment default when unset; 0 means unlimited either way.
+_EFFECTIVE_QUOTA_SQL = """
+    CASE WHEN storage_quota_bytes > 0
+         THEN storage_quota_bytes
+         ELSE %(env_quota)s
+    END
+"""
+
+
+class StorageQuotaExceeded(Exception):
+    """Raised when a reservation would push the org past its quota."""
+
+    def __init__(self, used: int, limit: int):
+        self.used = used
+        self.limit = limit
+        super().__init__(f"storage quota exceeded ({used}/{limit} bytes)")
+
+
+async def reserve_storage(cur, org_id: str, size_bytes: int) -> None:
+    """Atomically count `size_bytes` against the org's storage quota.
+
+    The check and the increment are a single guarded UPDATE, so concurrent
+    uploads near the limit cannot collectively overshoot. Runs on the
+    caller's cursor so the reservation commits (or rolls back) with the
+    document row. Raises StorageQuotaExceeded when over.
+    """
+    await cur.execute(
+        f"""
+        UPDATE organizations
+        SET storage_used_bytes = storage_used_bytes + %(size)s
+        WHERE id = %(org_id)s
+          AND ({_EFFECTIVE_QUOTA_SQL} = 0
+               OR storage_used_bytes + %(size)s <= {_EFFECTIVE_QUOTA_SQL})
+        RETURNING storage_used_bytes
+        """,
+        {"org_id": org_id, "size": size_bytes, "env_quota": settings.DOCUMENT_STORAGE_QUOTA_BYTES},
+    )
+    if await cur.fetchone():
+        return
+
+    # Guard refused: report the numbers so the client can show used/limit
+    await cur.execute(
+        f"""
+        SELECT storage_used_bytes AS used, {_EFFECTIVE_QUOTA_SQL} AS quota
+        FROM organizations
+        WHERE id = %(org_id)s
+        """,
+        {"org_id": org_id, "env_quota": settings.DOCUMENT_STORAGE_QUOTA_BYTES},
+    )
+    row = await cur.fetchone()
+    used = row["used"] if row else 0
+    quota = row["quota"] if row else 0
+    raise StorageQuotaExceeded(used, quota)
+
+
+async def release_storage(cur, org_id: str, size_bytes: int) -> None:
+    """Return `size_bytes` to the org's quota (document deleted or trashed).
+
+    GREATEST guards against the counter ever going negative from drift.
+    """
+    await cur.execute(
+        """
+        UPDATE organizations
+        SET storage_used_bytes = GREATEST(storage_used_bytes - %s, 0)
+        WHERE id = %s
+        """,
+        (size_bytes, org_id),
+    )
+
+
+async def reconcile_storage_counters() -> int:
+    """Recompute storage_used_bytes from actual live document sizes.
+
+    Catches drift from crashes between S3 and DB writes or from rows touched
+    outside the API. Returns the number of orgs corrected.
+    """
+    async with get_db_cursor(commit=True) as cur:
+        await cur.execute(
+            """
+            UPDATE organizations o
+            SET storage_used_bytes = COALESCE(d.actual, 0)
+            FROM (
+                SELECT organization_id, SUM(file_size) AS actual
+                FROM documents
+                WHERE deleted_at IS NULL
+                GROUP BY organization_id
+            ) d
+            WHERE d.organization_id = o.id
+              AND o.storage_used_bytes <> COALESCE(d.actual, 0)
+            RETURNING o.id
+            """
+        )
+        corrected = await cur.fetchall()
+
+        # Orgs with no live documents at all fall out of the join above
+        await cur.execute(
+            """
+            UPDATE organizations o
+            SET storage_used_bytes = 0
+            WHERE o.storage_used_bytes <> 0
+              AND NOT EXISTS (
+                  SELECT 1 FROM documents d
+                  WHERE d.organization_id = o.id AND d.deleted_at IS NULL
+              )
+            RETURNING o.id
+            """
+        )
+        corrected += await cur.fetchall()
+
+    if corrected:
+        print(f"[STORAGE] reconciled usage counters for {len(corrected)} orgs")
+    return len(corrected)
+
+
+async def storage_reconcile_loop() -> None:
+    """Background task: correct storage counter drift on a nightly schedule."""
+    while True:
+        try:
+            await reconcile_storage_counters()
+        except Exception as e:
+            print(f"[STORAGE LOOP ERROR] {e}")
+        await asyncio.sleep(RECONCILE_INTERVAL_SECONDS)
@@ -50,3 +50,25 @@ async def record_token_usage(
                 total_cost,
             ),
         )
+
+        # Daily rollup (UTC buckets) backing the org usage report
+        await cur.execute(
+            """
+            INSERT INTO token_usage_daily (
+                organization_id, user_id, day,
+                prompt_tokens, completion_tokens, requests
+            )
+            VALUES (%s, %s, (NOW() AT TIME ZONE 'UTC')::date, %s, %s, 1)
+            ON CONFLICT (organization_id, user_id, day)
+            DO UPDATE SET
+                prompt_tokens = token_usage_daily.prompt_tokens + EXCLUDED.prompt_tokens,
+                completion_tokens = token_usage_daily.completion_tokens + EXCLUDED.completion_tokens,
+                requests = token_usage_daily.requests + 1
+            """,
+            (
+                organization_id,
+                user_id,
+                prompt_tokens,
+                completion_tokens,
+            ),
+        )
@@ -169,13 +169,13 @@ async def train_sources(
             if not content.strip():
                 raise ValueError("Empty document")
 
-            chunks = FileManager.chunk_text(content)
+            chunks = FileManager.chunk_text_with_offsets(content)
             if not chunks:
                 raise ValueError("No chunks generated")
 
             embeddings = []
             for chunk in chunks:
-                emb = await get_embedding_with_retry(chunk, org_id, user_id)
+                emb = await get_embedding_with_retry(chunk["text"], org_id, user_id)
                 arr = _to_float_array(emb)
                 if arr.size == 0:
                     raise ValueError("Invalid embedding")
@@ -197,15 +197,19 @@ async def train_sources(
                             organization_id,
                             chunk_index,
                             chunk_text,
+                            char_start,
+                            char_end,
                             embedding
                         )
-                        VALUES (%s, %s, %s, %s, %s::vector)
+                        VALUES (%s, %s, %s, %s, %s, %s, %s::vector)
                         """,
                         (
                             doc_id,
                             org_id,
                             idx,
-                            chunk,
+                            chunk["text"],
+                            chunk["char_start"],
+                            chunk["char_end"],
                             emb_literal,
                         ),
                     )
@@ -0,0 +1,66 @@
+import os
+import asyncio
+from app.database.postgres_client import get_db_cursor
+from app.helpers.s3_storage import delete_s3_object
+
+# Soft-deleted documents stay restorable for this many days before the purge
+# removes them for good (DB row, chunks via FK cascade, storage object).
+TRASH_RETENTION_DAYS = int(os.getenv("DOCUMENT_TRASH_RETENTION_DAYS", "30"))
+TRASH_PURGE_BATCH_SIZE = 200
+TRASH_PURGE_INTERVAL_SECONDS = 24 * 60 * 60  # nightly
+
+
+async def purge_trashed_documents() -> int:
+    """Permanently remove documents soft-deleted more than TRASH_RETENTION_DAYS ago.
+
+    The row delete cascades to chunks and permissions; the storage object is
+    removed after the commit so a failed S3 call never strands a half-deleted
+    row. The org's storage counter was already released at soft-delete time,
+    so the purge doesn't touch it. Returns the number of documents purged.
+    """
+    total_purged = 0
+
+    while True:
+        async with get_db_cursor(commit=True) as cur:
+            await cur.execute(
+                """
+                DELETE FROM documents
+                WHERE id IN (
+                    SELECT id FROM documents
+                    WHERE deleted_at IS NOT NULL
+                      AND deleted_at < NOW() - (%s * INTERVAL '1 day')
+                    LIMIT %s
+                )
+                RETURNING id, s3_key
+                """,
+                (TRASH_RETENTION_DAYS, TRASH_PURGE_BATCH_SIZE),
+            )
+            purged = await cur.fetchall()
+
+        if not purged:
+            break
+
+        for doc in purged:
+            try:
+                await delete_s3_object(doc["s3_key"])
+            except Exception as e:
+                # Orphaned objects are cheaper than stuck purges; log and move on
+                print(f"[TRASH WARN] failed to delete s3 object {doc['s3_key']}: {e}")
+
+        total_purged += len(purged)
+        print(f"[TRASH] purged batch of {len(purged)} documents (total {total_purged})")
+        await asyncio.sleep(0)
+
+    return total_purged
+
+
+async def trash_purge_loop() -> None:
+    """Background task: permanently purge expired trash on a nightly schedule."""
+    while True:
+        try:
+            purged = await purge_trashed_documents()
+            if purged:
+                print(f"[TRASH] purge complete, removed {purged} documents")
+        except Exception as e:
+            print(f"[TRASH LOOP ERROR] {e}")
+        await asyncio.sleep(TRASH_PURGE_INTERVAL_SECONDS)
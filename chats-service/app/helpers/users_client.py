@@ -0,0 +1,68 @@
+import os
+import time
+
+import httpx
+
+# Typed client for users-service's internal bulk lookup
+# (GET /api/v1/internal/v1/users?ids=...). Keeps chats-service off the users
+# table so the schemas stay decoupled. Mirrors the Go client in shared/client:
+# short TTL cache, unknown IDs reported separately instead of failing.
+
+USERS_SERVICE_URL = os.getenv("USERS_SERVICE_URL", "http://localhost:8080").rstrip("/")
+SERVICE_API_KEY = os.getenv("SERVICE_API_KEY", "")
+CACHE_TTL_SECONDS = int(os.getenv("USERS_CLIENT_CACHE_TTL_SECONDS", "30"))
+MAX_RESOLVE_IDS = 100
+
+# id -> (expires_at, display_info | None); None caches a known-missing ID
+_cache: dict[str, tuple[float, dict | None]] = {}
+
+
+async def resolve_users(ids: list[str]) -> tuple[dict[str, dict], list[str]]:
+    """
+    Resolve user IDs to sanitized display info ({id, name, email, role,
+    status}). Returns (found_by_id, missing_ids). Raises on transport errors —
+    callers decide whether enrichment is best-effort or required.
+    """
+    now = time.monotonic()
+    found: dict[str, dict] = {}
+    missing: list[str] = []
+    uncached: list[str] = []
+
+    for user_id in dict.fromkeys(ids):  # de-dupe, keep order
+        entry = _cache.get(user_id)
+        if entry and entry[0] > now:
+            if entry[1] is None:
+                missing.append(user_id)
+            else:
+                found[user_id] = entry[1]
+        else:
+            uncached.append(user_id)
+
+    if not uncached:
+        return found, missing
+
+    if len(uncached) > MAX_RESOLVE_IDS:
+        raise ValueError(f"too many ids: {len(uncached)} (max {MAX_RESOLVE_IDS})")
+
+    async with httpx.AsyncClient(timeout=10.0) as client:
+        response = await client.get(
+            f"{USERS_SERVICE_URL}/api/v1/internal/v1/users",
+            params={"ids": ",".join(uncached)},
+            headers={"X-Service-Key": SERVICE_API_KEY},
+        )
+    body = response.json()
+    if response.status_code != 200 or body.get("error"):
+        raise RuntimeError(
+            f"users-service returned {response.status_code}: {body.get('message')}"
+        )
+
+    expires_at = time.monotonic() + CACHE_TTL_SECONDS
+    data = body.get("data") or {}
+    for info in data.get("users") or []:
+        _cache[info["id"]] = (expires_at, info)
+        found[info["id"]] = info
+    for user_id in data.get("missing") or []:
+        _cache[user_id] = (expires_at, None)
+        missing.append(user_id)
+
+    return found, missing
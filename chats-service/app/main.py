@@ -1,13 +1,31 @@
 from fastapi import FastAPI
 from fastapi.middleware.cors import CORSMiddleware
+import asyncio
 from app.database.postgres_client import init_db, close_db
+from app.helpers.retention import retention_purge_loop
+from app.helpers.trash import trash_purge_loop
+from app.helpers.storage_quota import storage_reconcile_loop
+from app.helpers.document_sync import document_sync_loop
+from app.helpers.inference_health import check_inference_backend, wait_for_inference_backend
 from app.apis.documents import router as documents
-from app.apis.chats import router as chats
+from app.apis.document_sources import router as document_sources
+from app.apis.chats import router as chats, shared_router as shared_chats
+from app.apis.messages import router as messages
+from app.apis.capabilities import router as capabilities
 from app.middleware.auth import AuthMiddleware
+from app.middleware.tracing import TracingMiddleware
 from app.utils.errors import register_exception_handlers
+from app.utils.route_table import assert_no_duplicate_routes, route_table
 from app.core.config import settings
 
-app = FastAPI(title="Chats Service")
+# Docs are generated by FastAPI from the route/request models; only serve
+# them when the deployment opts in.
+app = FastAPI(
+    title="Chats Service",
+    docs_url="/api/v1/docs" if settings.ENABLE_API_DOCS else None,
+    redoc_url=None,
+    openapi_url="/api/v1/openapi.json" if settings.ENABLE_API_DOCS else None,
+)
 
 # ✅ CORS for frontend
 app.add_middleware(
@@ -22,20 +40,51 @@ app.add_middleware(
 @app.on_event("startup")
 async def on_startup():
     await init_db()
+    # Verify the inference backend is actually reachable; the client alone
+    # never dials. Required deployments block (and fail) instead of warning.
+    if settings.INFERENCE_STARTUP_REQUIRED:
+        await wait_for_inference_backend()
+    else:
+        await check_inference_backend()
+    app.state.retention_task = asyncio.create_task(retention_purge_loop())
+    app.state.trash_task = asyncio.create_task(trash_purge_loop())
+    app.state.storage_task = asyncio.create_task(storage_reconcile_loop())
+    app.state.doc_sync_task = asyncio.create_task(document_sync_loop())
 
 @app.on_event("shutdown")
 async def on_shutdown():
+    for attr in ("retention_task", "trash_task", "storage_task", "doc_sync_task"):
+        task = getattr(app.state, attr, None)
+        if task:
+            task.cancel()
     await close_db()
 
 # Error handling & auth
 register_exception_handlers(app)
 app.add_middleware(AuthMiddleware)
+# Outermost (added last): every request gets a trace id, even auth failures
+app.add_middleware(TracingMiddleware)
 
 # Routers
 app.include_router(documents, prefix="/api/v1")
+app.include_router(document_sources, prefix="/api/v1")
 app.include_router(chats, prefix="/api/v1")
+app.include_router(shared_chats, prefix="/api/v1")
+app.include_router(messages, prefix="/api/v1")
+app.include_router(capabilities, prefix="/api/v1")
 
 # Health check
 @app.get("/health")
 async def health_check():
     return {"message": "Chats Service is running"}
+
+
+# Effective route table for local debugging (non-production only)
+if settings.ENV != "production":
+    @app.get("/api/v1/debug/routes")
+    async def debug_routes():
+        return {"error": False, "message": "routes fetched", "data": route_table(app)}
+
+# Refuse to boot on conflicting registrations; starlette would otherwise
+# silently serve whichever route matched first
+assert_no_duplicate_routes(app)
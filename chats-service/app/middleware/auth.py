@@ -8,10 +8,40 @@ from jwt import ExpiredSignatureError, InvalidTokenError, DecodeError
 from psycopg import OperationalError, InterfaceError
 
 from app.database.postgres_client import get_db_cursor
+from app.middleware.auth_context import AuthContext
 from app.utils.response import APIResponse
 
 JWT_SECRET = os.getenv("JWT_SECRET", "YOUR_SUPER_SECRET_KEY")
-JWT_ALGORITHM = "HS256"
+JWT_ALGORITHM = os.getenv("JWT_ALG", "HS256")
+
+# Keyset for rotation, mirroring users-service: JWT_KEYS holds kid:secret
+# pairs ("v1:oldsecret,v2:newsecret") and tokens carry the kid they were
+# signed under. Tokens without a kid fall back to the legacy single secret.
+JWT_KEYS = {
+    kid: secret
+    for kid, _, secret in (
+        pair.strip().partition(":") for pair in os.getenv("JWT_KEYS", "").split(",") if pair.strip()
+    )
+    if kid and secret
+}
+
+# Deprecation window for kid-less tokens signed with the legacy single
+# secret; set LEGACY_JWT_TOKENS=false (with a keyset active) to retire it.
+LEGACY_JWT_TOKENS = os.getenv("LEGACY_JWT_TOKENS", "").lower() != "false"
+
+
+def _verification_key(token_str: str) -> str | None:
+    """Pick the verification secret from the token's kid header.
+
+    Returns None for a kid that is no longer in the keyset (rotated out),
+    or for a kid-less token once the legacy window is closed.
+    """
+    kid = jwt.get_unverified_header(token_str).get("kid")
+    if not kid:
+        if JWT_KEYS and not LEGACY_JWT_TOKENS:
+            return None
+        return JWT_SECRET
+    return JWT_KEYS.get(kid)
 
 
 class AuthMiddleware(BaseHTTPMiddleware):
@@ -36,7 +66,10 @@ class AuthMiddleware(BaseHTTPMiddleware):
 
             # 🔹 2. Decode token safely
             try:
-                claims = jwt.decode(token_str, JWT_SECRET, algorithms=[JWT_ALGORITHM])
+                key = _verification_key(token_str)
+                if key is None:
+                    raise HTTPException(status_code=401, detail="Invalid token")
+                claims = jwt.decode(token_str, key, algorithms=[JWT_ALGORITHM])
             except ExpiredSignatureError:
                 raise HTTPException(status_code=401, detail="Token has expired")
             except (DecodeError, InvalidTokenError):
@@ -78,9 +111,14 @@ class AuthMiddleware(BaseHTTPMiddleware):
             if user["token_version"] != token_version:
                 raise HTTPException(status_code=401, detail="Token invalid due to password change")
 
-            # 🔹 6. Attach user info to request
-            request.state.user = user
-            request.state.claims = claims
+            # 🔹 6. Attach the resolved identity once; handlers read this
+            # instead of re-extracting claims. Role comes from the DB row so
+            # demotions take effect without waiting for token expiry.
+            request.state.auth = AuthContext(
+                user_id=str(user["id"]),
+                org_id=claims.get("organization_id"),
+                role=user["role"],
+            )
 
             return await call_next(request)
 
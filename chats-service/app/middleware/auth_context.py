@@ -0,0 +1,31 @@
+from dataclasses import dataclass
+
+from fastapi import Request
+
+
+@dataclass(frozen=True)
+class AuthContext:
+    """Identity resolved once per request by AuthMiddleware.
+
+    Handlers and helpers take this instead of digging through raw JWT claims,
+    so the org scoping is explicit in every signature. Tests can construct one
+    directly without minting a token.
+    """
+
+    user_id: str
+    org_id: str
+    role: str
+
+    @classmethod
+    def from_claims(cls, claims: dict) -> "AuthContext":
+        return cls(
+            user_id=claims.get("user_id"),
+            org_id=claims.get("organization_id"),
+            role=claims.get("role"),
+        )
+
+
+def get_auth(request: Request) -> AuthContext | None:
+    """Returns the AuthContext attached by AuthMiddleware, or None on paths
+    that skipped auth (health, docs)."""
+    return getattr(request.state, "auth", None)
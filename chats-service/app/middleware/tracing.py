@@ -0,0 +1,100 @@
+import asyncio
+import contextvars
+import os
+import secrets
+import time
+
+import httpx
+from starlette.middleware.base import BaseHTTPMiddleware
+
+# W3C trace-context propagation plus a minimal OTLP/HTTP JSON exporter,
+# mirroring shared/tracing on the Go side. Without OTEL_EXPORTER_OTLP_ENDPOINT
+# nothing is exported, but trace ids still flow through so logs across
+# services stay correlatable.
+OTLP_ENDPOINT = os.getenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+SERVICE_NAME = os.getenv("OTEL_SERVICE_NAME", "chats-service")
+
+# (trace_id, span_id) of the current request's server span
+_current: contextvars.ContextVar[tuple | None] = contextvars.ContextVar("trace", default=None)
+
+
+def _parse_traceparent(header: str | None) -> tuple | None:
+    if not header:
+        return None
+    parts = header.split("-")
+    if len(parts) != 4 or len(parts[1]) != 32 or len(parts[2]) != 16:
+        return None
+    if parts[1] == "0" * 32 or parts[2] == "0" * 16:
+        return None
+    return parts[1], parts[2]
+
+
+def outbound_headers() -> dict:
+    """Headers to attach to outgoing calls (inference backend, webhooks).
+
+    Issues a fresh child span id under the current trace; empty outside a
+    traced request so callers can always splat the result.
+    """
+    current = _current.get()
+    if not current:
+        return {}
+    trace_id, _ = current
+    return {"traceparent": f"00-{trace_id}-{secrets.token_hex(8)}-01"}
+
+
+async def _export_span(trace_id, span_id, parent_id, name, start_ns, end_ns, status_code):
+    payload = {
+        "resourceSpans": [{
+            "resource": {"attributes": [
+                {"key": "service.name", "value": {"stringValue": SERVICE_NAME}},
+            ]},
+            "scopeSpans": [{
+                "scope": {"name": "app/middleware/tracing"},
+                "spans": [{
+                    "traceId": trace_id,
+                    "spanId": span_id,
+                    **({"parentSpanId": parent_id} if parent_id else {}),
+                    "name": name,
+                    "kind": 2,  # SPAN_KIND_SERVER
+                    "startTimeUnixNano": str(start_ns),
+                    "endTimeUnixNano": str(end_ns),
+                    "attributes": [
+                        {"key": "http.status_code", "value": {"stringValue": str(status_code)}},
+                    ],
+                    "status": {"code": 2 if status_code >= 500 else 0},
+                }],
+            }],
+        }],
+    }
+    try:
+        async with httpx.AsyncClient(timeout=10) as client:
+            await client.post(f"{OTLP_ENDPOINT.rstrip('/')}/v1/traces", json=payload)
+    except Exception as e:
+        print(f"[TRACE WARN] span export failed: {e}")
+
+
+class TracingMiddleware(BaseHTTPMiddleware):
+    async def dispatch(self, request, call_next):
+        parent = _parse_traceparent(request.headers.get("traceparent"))
+        trace_id = parent[0] if parent else secrets.token_hex(16)
+        parent_id = parent[1] if parent else None
+        span_id = secrets.token_hex(8)
+
+        token = _current.set((trace_id, span_id))
+        start_ns = time.time_ns()
+        try:
+            response = await call_next(request)
+        finally:
+            _current.reset(token)
+
+        response.headers["X-Trace-Id"] = trace_id
+
+        if OTLP_ENDPOINT:
+            # Fire-and-forget; a collector outage costs spans, never requests
+            asyncio.create_task(_export_span(
+                trace_id, span_id, parent_id,
+                f"{request.method} {request.url.path}",
+                start_ns, time.time_ns(), response.status_code,
+            ))
+
+        return response
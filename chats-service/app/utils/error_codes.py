@@ -0,0 +1,8 @@
+# Stable machine codes for the envelope's "code" field. Kept snake_case to
+# match the codes users-service attaches through apperrors.WithCode, so a
+# client can switch on one vocabulary no matter which service answered.
+UNAUTHORIZED = "unauthorized"
+FORBIDDEN = "forbidden"
+NOT_FOUND = "not_found"
+VALIDATION_ERROR = "validation_error"
+QUOTA_EXCEEDED = "quota_exceeded"
@@ -1,8 +1,17 @@
-from fastapi import FastAPI, Request, HTTPException
+from fastapi import FastAPI, Request
 from fastapi.responses import JSONResponse
 from starlette import status
+from starlette.exceptions import HTTPException as StarletteHTTPException
 from app.utils.response import APIResponse
 
+# Machine codes for the framework-raised statuses (unknown path / wrong
+# method), so those answers match the JSON envelope used everywhere else
+# instead of Starlette's plain {"detail": ...}.
+_STATUS_CODES = {
+    status.HTTP_404_NOT_FOUND: "ROUTE_NOT_FOUND",
+    status.HTTP_405_METHOD_NOT_ALLOWED: "METHOD_NOT_ALLOWED",
+}
+
 def register_exception_handlers(app: FastAPI):
     @app.exception_handler(Exception)
     async def global_exception_handler(request: Request, exc: Exception):
@@ -11,9 +20,14 @@ def register_exception_handlers(app: FastAPI):
             content=APIResponse(True, str(exc), None, status.HTTP_500_INTERNAL_SERVER_ERROR)
         )
 
-    @app.exception_handler(HTTPException)
-    async def http_exception_handler(request: Request, exc: HTTPException):
+    # Starlette's HTTPException is the base class, so this also catches
+    # fastapi.HTTPException raised by route handlers *and* the router's own
+    # 404/405 responses.
+    @app.exception_handler(StarletteHTTPException)
+    async def http_exception_handler(request: Request, exc: StarletteHTTPException):
         return JSONResponse(
             status_code=exc.status_code,
-            content=APIResponse(True, exc.detail, None, exc.status_code)
+            content=APIResponse(True, exc.detail, None, exc.status_code,
+                                code=_STATUS_CODES.get(exc.status_code)),
+            headers=getattr(exc, "headers", None),
         )
@@ -1,7 +1,11 @@
-def APIResponse(error: bool, message: str, data: any = None, status_code: int = 200):
-    return {
+def APIResponse(error: bool, message: str, data: any = None, status_code: int = 200, code: str | None = None):
+    body = {
         "error": error,
         "message": message,
         "status": status_code,
         "data": data
-    }
\ No newline at end of file
+    }
+    # stable machine code (mirrors the Go envelope's omitempty "code" field)
+    if code:
+        body["code"] = code
+    return body
\ No newline at end of file
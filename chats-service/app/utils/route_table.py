@@ -0,0 +1,43 @@
+from fastapi import FastAPI
+from fastapi.routing import APIRoute
+
+# Starlette happily accepts two routes with the same method+path and silently
+# serves whichever matched first, so copy-paste mistakes in the routers go
+# unnoticed. We check the table once at startup instead and refuse to boot.
+
+
+def route_table(app: FastAPI) -> list[dict]:
+    """The effective route table (method, path, endpoint), sorted for
+    deterministic output."""
+    table = []
+    for route in app.routes:
+        if not isinstance(route, APIRoute):
+            continue
+        for method in sorted(route.methods or []):
+            table.append(
+                {
+                    "method": method,
+                    "path": route.path,
+                    "handler": f"{route.endpoint.__module__}.{route.endpoint.__name__}",
+                }
+            )
+    table.sort(key=lambda r: (r["path"], r["method"]))
+    return table
+
+
+def assert_no_duplicate_routes(app: FastAPI) -> None:
+    """Fails startup with the full list of conflicts when the same
+    method+path is registered more than once."""
+    seen: dict[tuple[str, str], str] = {}
+    conflicts = []
+    for entry in route_table(app):
+        key = (entry["method"], entry["path"])
+        if key in seen:
+            conflicts.append(
+                f"{entry['method']} {entry['path']} "
+                f"({seen[key]} and {entry['handler']})"
+            )
+        else:
+            seen[key] = entry["handler"]
+    if conflicts:
+        raise RuntimeError("duplicate routes registered: " + "; ".join(conflicts))
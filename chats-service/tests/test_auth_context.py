@@ -0,0 +1,52 @@
+# Run from chats-service/: python3 -m unittest discover -s tests -t .
+#
+# AuthContext is the per-request identity the middleware resolves once and
+# every service method receives; these tests pin the claims mapping and the
+# direct construction path handlers and tests rely on.
+import sys
+import types
+import unittest
+
+# auth_context type-annotates against fastapi.Request; the framework is not
+# installed here, so a stub is enough for the import
+if "fastapi" not in sys.modules:
+    _fastapi = types.ModuleType("fastapi")
+    _fastapi.Request = object
+    sys.modules["fastapi"] = _fastapi
+
+from app.middleware.auth_context import AuthContext, get_auth  # noqa: E402
+
+
+class AuthContextTests(unittest.TestCase):
+    def test_direct_construction_for_tests(self):
+        auth = AuthContext(user_id="u-1", org_id="o-1", role="member")
+        self.assertEqual(auth.user_id, "u-1")
+        self.assertEqual(auth.org_id, "o-1")
+        self.assertEqual(auth.role, "member")
+
+    def test_from_claims_maps_token_fields(self):
+        auth = AuthContext.from_claims({
+            "user_id": "u-2",
+            "organization_id": "o-2",
+            "role": "owner",
+            "exp": 123,
+        })
+        self.assertEqual(auth, AuthContext(user_id="u-2", org_id="o-2", role="owner"))
+
+    def test_context_is_immutable(self):
+        auth = AuthContext(user_id="u-1", org_id="o-1", role="member")
+        with self.assertRaises(Exception):
+            auth.role = "owner"
+
+    def test_get_auth_reads_request_state(self):
+        auth = AuthContext(user_id="u-1", org_id="o-1", role="member")
+        request = types.SimpleNamespace(state=types.SimpleNamespace(auth=auth))
+        self.assertIs(get_auth(request), auth)
+
+    def test_get_auth_is_none_on_unauthenticated_paths(self):
+        request = types.SimpleNamespace(state=types.SimpleNamespace())
+        self.assertIsNone(get_auth(request))
+
+
+if __name__ == "__main__":
+    unittest.main()
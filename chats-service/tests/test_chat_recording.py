@@ -0,0 +1,142 @@
+# Run from chats-service/: python3 -m unittest discover -s tests -t .
+#
+# The authenticated predict path attributes every chat and message it creates
+# to the caller's user and org. These tests run app.helpers.chat against an
+# in-memory SQLite stand-in for the async pool (installed in sys.modules
+# before the helper is imported) and assert the association columns are
+# written, since the real driver is not available here.
+import asyncio
+import sqlite3
+import sys
+import types
+import unittest
+from contextlib import asynccontextmanager
+
+
+# The fake pool is installed once, before app.helpers.chat is imported, and
+# hands out cursors on whatever connection the current test has opened.
+_current_conn = None
+
+
+class _Cursor:
+    def __init__(self, db):
+        self._db = db
+        self._rows = None
+
+    async def execute(self, sql, params=()):
+        sql = sql.replace("%s", "?")
+        sql = sql.replace("?::jsonb", "?")
+        sql = sql.replace("NOW()", "CURRENT_TIMESTAMP")
+        self._rows = self._db.execute(sql, params)
+
+    async def fetchone(self):
+        row = self._rows.fetchone()
+        if row is None:
+            return None
+        return {d[0]: row[i] for i, d in enumerate(self._rows.description)}
+
+
+@asynccontextmanager
+async def _fake_get_db_cursor(commit=False):
+    yield _Cursor(_current_conn)
+    if commit:
+        _current_conn.commit()
+
+
+_fake_module = types.ModuleType("app.database.postgres_client")
+_fake_module.get_db_cursor = _fake_get_db_cursor
+sys.modules.setdefault("app.database.postgres_client", _fake_module)
+
+
+class ChatRecordingTests(unittest.TestCase):
+    def setUp(self):
+        self.db = sqlite3.connect(":memory:")
+        self.db.row_factory = sqlite3.Row
+        self.db.executescript(
+            """
+            CREATE TABLE chats (
+                id TEXT PRIMARY KEY,
+                organization_id TEXT NOT NULL,
+                user_id TEXT NOT NULL,
+                title TEXT,
+                status TEXT,
+                model TEXT,
+                temperature REAL,
+                max_tokens INTEGER,
+                deleted_at TIMESTAMP,
+                last_message_at TIMESTAMP,
+                created_at TIMESTAMP,
+                updated_at TIMESTAMP
+            );
+            CREATE TABLE messages (
+                id TEXT PRIMARY KEY,
+                chat_id TEXT NOT NULL,
+                organization_id TEXT NOT NULL,
+                sender_user_id TEXT,
+                role TEXT,
+                content TEXT,
+                citations TEXT,
+                created_at TIMESTAMP
+            );
+            """
+        )
+        global _current_conn
+        _current_conn = self.db
+        from app.helpers import chat
+
+        self.chat = chat
+
+    def tearDown(self):
+        global _current_conn
+        _current_conn = None
+        self.db.close()
+
+    def test_create_chat_records_caller_org_and_user(self):
+        chat_id, title = asyncio.run(
+            self.chat.create_chat("org-1", "user-1", "First question")
+        )
+        self.assertEqual(title, "First question")
+
+        row = self.db.execute(
+            "SELECT organization_id, user_id, status FROM chats WHERE id = ?",
+            (chat_id,),
+        ).fetchone()
+        self.assertIsNotNone(row)
+        self.assertEqual(row["organization_id"], "org-1")
+        self.assertEqual(row["user_id"], "user-1")
+        self.assertEqual(row["status"], "active")
+
+    def test_save_message_records_sender_and_org(self):
+        chat_id, _ = asyncio.run(self.chat.create_chat("org-1", "user-1", "t"))
+        message_id = asyncio.run(
+            self.chat.save_message_to_db("org-1", chat_id, "user-1", "user", "hello")
+        )
+
+        row = self.db.execute(
+            "SELECT organization_id, sender_user_id, role, content FROM messages WHERE id = ?",
+            (message_id,),
+        ).fetchone()
+        self.assertIsNotNone(row)
+        self.assertEqual(row["organization_id"], "org-1")
+        self.assertEqual(row["sender_user_id"], "user-1")
+        self.assertEqual(row["role"], "user")
+        self.assertEqual(row["content"], "hello")
+
+        touched = self.db.execute(
+            "SELECT last_message_at FROM chats WHERE id = ?", (chat_id,)
+        ).fetchone()
+        self.assertIsNotNone(touched["last_message_at"])
+
+    def test_messages_stay_scoped_to_their_chat(self):
+        chat_a, _ = asyncio.run(self.chat.create_chat("org-1", "user-1", "a"))
+        chat_b, _ = asyncio.run(self.chat.create_chat("org-2", "user-2", "b"))
+        asyncio.run(self.chat.save_message_to_db("org-1", chat_a, "user-1", "user", "x"))
+
+        count_b = self.db.execute(
+            "SELECT COUNT(*) AS n FROM messages WHERE chat_id = ?", (chat_b,)
+        ).fetchone()
+        self.assertEqual(count_b["n"], 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
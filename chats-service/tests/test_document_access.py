@@ -0,0 +1,72 @@
+# Run from chats-service/: python3 -m unittest discover -s tests -t .
+#
+# The access clause is plain SQL, so it is executed here against an in-memory
+# SQLite schema mirroring documents/document_permissions (SQLite understands
+# IS DISTINCT FROM; only the psycopg %s placeholders need swapping for ?).
+import sqlite3
+import unittest
+
+from app.helpers.document_access import document_access_clause, document_access_params
+
+ORG_DOC = "doc-org"
+RESTRICTED_DOC = "doc-restricted"
+UPLOADER = "user-uploader"
+MEMBER = "user-member"
+GRANTED_USER = "user-granted"
+
+
+class DocumentAccessClauseTests(unittest.TestCase):
+    def setUp(self):
+        self.db = sqlite3.connect(":memory:")
+        self.db.executescript(
+            """
+            CREATE TABLE documents (
+                id TEXT PRIMARY KEY,
+                created_by TEXT,
+                visibility TEXT
+            );
+            CREATE TABLE document_permissions (
+                document_id TEXT,
+                user_id TEXT,
+                role TEXT
+            );
+            """
+        )
+        self.db.execute("INSERT INTO documents VALUES (?, ?, 'org')", (ORG_DOC, UPLOADER))
+        self.db.execute("INSERT INTO documents VALUES (?, ?, 'restricted')", (RESTRICTED_DOC, UPLOADER))
+        self.db.execute(
+            "INSERT INTO document_permissions VALUES (?, ?, NULL)",
+            (RESTRICTED_DOC, GRANTED_USER),
+        )
+        self.db.execute(
+            "INSERT INTO document_permissions VALUES (?, NULL, 'maintainer')",
+            (RESTRICTED_DOC,),
+        )
+
+    def visible_ids(self, user_id, role):
+        sql = "SELECT id FROM documents d WHERE " + document_access_clause("d").replace("%s", "?")
+        rows = self.db.execute(sql, document_access_params(user_id, role)).fetchall()
+        return sorted(r[0] for r in rows)
+
+    def test_params_match_placeholders(self):
+        clause = document_access_clause()
+        self.assertEqual(clause.count("%s"), len(document_access_params("u", "member")))
+
+    def test_member_cannot_see_restricted_doc_owner_can(self):
+        # The identical query returns nothing restricted for a plain member
+        # but finds it for an owner
+        self.assertEqual(self.visible_ids(MEMBER, "member"), [ORG_DOC])
+        self.assertEqual(self.visible_ids(MEMBER, "owner"), [ORG_DOC, RESTRICTED_DOC])
+
+    def test_uploader_sees_their_own_restricted_doc(self):
+        self.assertEqual(self.visible_ids(UPLOADER, "member"), [ORG_DOC, RESTRICTED_DOC])
+
+    def test_user_grant_opens_the_restricted_doc(self):
+        self.assertEqual(self.visible_ids(GRANTED_USER, "member"), [ORG_DOC, RESTRICTED_DOC])
+
+    def test_role_grant_opens_the_restricted_doc(self):
+        self.assertEqual(self.visible_ids(MEMBER, "maintainer"), [ORG_DOC, RESTRICTED_DOC])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,56 @@
+# Run from chats-service/: python3 -m unittest discover -s tests -t .
+import json
+import unittest
+from datetime import datetime, timezone
+
+from app.helpers.export_transcript import build_transcript, render_markdown
+
+ROWS = [
+    {
+        "role": "user",
+        "content": "What is our pricing formula?",
+        "created_at": datetime(2026, 1, 2, 9, 30, tzinfo=timezone.utc),
+    },
+    {
+        "role": "assistant",
+        "content": "Base price plus per-seat cost.",
+        "created_at": datetime(2026, 1, 2, 9, 31, tzinfo=timezone.utc),
+    },
+    {"role": "user", "content": "Thanks!", "created_at": None},
+]
+
+
+class RenderMarkdownTests(unittest.TestCase):
+    def test_renders_title_and_one_heading_per_turn(self):
+        out = "".join(render_markdown("Pricing chat", ROWS))
+        self.assertTrue(out.startswith("# Pricing chat\n\n"))
+        self.assertIn("## User — 2026-01-02T09:30:00+00:00\n\nWhat is our pricing formula?\n\n", out)
+        self.assertIn("## Assistant — 2026-01-02T09:31:00+00:00\n\nBase price plus per-seat cost.\n\n", out)
+        self.assertEqual(out.count("## "), len(ROWS))
+
+    def test_missing_timestamp_renders_empty(self):
+        out = "".join(render_markdown("t", ROWS))
+        self.assertIn("## User — \n\nThanks!\n\n", out)
+
+
+class BuildTranscriptTests(unittest.TestCase):
+    def test_json_document_shape(self):
+        doc = build_transcript("chat-1", "Pricing chat", ROWS)
+        # Must round-trip through json.dumps (what the route streams)
+        parsed = json.loads(json.dumps(doc))
+        self.assertEqual(parsed["chat_id"], "chat-1")
+        self.assertEqual(parsed["title"], "Pricing chat")
+        self.assertEqual(len(parsed["messages"]), 3)
+        self.assertEqual(
+            parsed["messages"][0],
+            {
+                "role": "user",
+                "content": "What is our pricing formula?",
+                "created_at": "2026-01-02T09:30:00+00:00",
+            },
+        )
+        self.assertIsNone(parsed["messages"][2]["created_at"])
+
+
+if __name__ == "__main__":
+    unittest.main()
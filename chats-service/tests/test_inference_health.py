@@ -0,0 +1,100 @@
+# Run from chats-service/: python3 -m unittest discover -s tests -t .
+#
+# The startup probe exists because the OpenAI client is lazy: without it an
+# unreachable inference backend only surfaces on the first chat request.
+# These tests drive check_inference_backend / wait_for_inference_backend
+# against a stub client (installed in sys.modules before the helper is
+# imported) that can answer, hang, or refuse.
+import asyncio
+import sys
+import time
+import types
+import unittest
+
+# app.core.config calls load_dotenv at import; the real package is absent here
+if "dotenv" not in sys.modules:
+    _dotenv = types.ModuleType("dotenv")
+    _dotenv.load_dotenv = lambda *args, **kwargs: None
+    sys.modules["dotenv"] = _dotenv
+
+
+# What the next models.list call should do: "ok", "refuse", or "hang"
+_backend_mode = "ok"
+
+
+class _Models:
+    def __init__(self, timeout):
+        self._timeout = timeout
+
+    def list(self):
+        if _backend_mode == "refuse":
+            raise ConnectionError("connection refused")
+        if _backend_mode == "hang":
+            time.sleep(self._timeout + 0.5)
+            raise TimeoutError("request timed out")
+        return []
+
+
+class _Client:
+    def __init__(self, timeout=None):
+        self.models = _Models(timeout)
+
+    def with_options(self, timeout=None):
+        return _Client(timeout)
+
+
+_fake_embeddings = types.ModuleType("app.helpers.get_embedding_with_retry")
+_fake_embeddings.client = _Client()
+sys.modules.setdefault("app.helpers.get_embedding_with_retry", _fake_embeddings)
+
+from app.core.config import settings  # noqa: E402
+from app.helpers import inference_health  # noqa: E402
+
+
+class CheckInferenceBackendTests(unittest.TestCase):
+    def setUp(self):
+        global _backend_mode
+        _backend_mode = "ok"
+
+    def test_reachable_backend_reports_up(self):
+        up = asyncio.run(inference_health.check_inference_backend(timeout=1))
+        self.assertTrue(up)
+
+    def test_refused_connection_reports_down(self):
+        global _backend_mode
+        _backend_mode = "refuse"
+        up = asyncio.run(inference_health.check_inference_backend(timeout=1))
+        self.assertFalse(up)
+
+    def test_hung_backend_hits_the_deadline(self):
+        global _backend_mode
+        _backend_mode = "hang"
+        started = time.monotonic()
+        up = asyncio.run(inference_health.check_inference_backend(timeout=0.1))
+        self.assertFalse(up)
+        # wait_for enforced the deadline instead of riding out the hang
+        self.assertLess(time.monotonic() - started, 2)
+
+
+class WaitForInferenceBackendTests(unittest.TestCase):
+    def setUp(self):
+        global _backend_mode
+        _backend_mode = "ok"
+        self._saved_timeout = settings.INFERENCE_STARTUP_TIMEOUT
+        settings.INFERENCE_STARTUP_TIMEOUT = 0.2
+
+    def tearDown(self):
+        settings.INFERENCE_STARTUP_TIMEOUT = self._saved_timeout
+
+    def test_returns_once_backend_answers(self):
+        asyncio.run(inference_health.wait_for_inference_backend())
+
+    def test_raises_when_backend_never_comes_up(self):
+        global _backend_mode
+        _backend_mode = "refuse"
+        with self.assertRaises(RuntimeError):
+            asyncio.run(inference_health.wait_for_inference_backend())
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,197 @@
+# Run from chats-service/: python3 -m unittest discover -s tests -t .
+#
+# The weighted fair queue sits in front of the inference backend so free-tier
+# orgs cannot starve paying customers when the backend is saturated. These
+# tests drive acquire/release directly with the tier cache pre-seeded, so no
+# database is involved; the fake postgres_client module only satisfies the
+# import.
+import asyncio
+import sys
+import time
+import types
+import unittest
+from contextlib import asynccontextmanager
+
+
+@asynccontextmanager
+async def _no_db_cursor(commit=False):
+    raise ConnectionError("no database in tests")
+    yield
+
+
+_fake_db = types.ModuleType("app.database.postgres_client")
+_fake_db.get_db_cursor = _no_db_cursor
+sys.modules.setdefault("app.database.postgres_client", _fake_db)
+
+from app.helpers import inference_queue as queue  # noqa: E402
+
+
+class QueueTestCase(unittest.TestCase):
+    """Resets the module-level queue state and knobs around every test."""
+
+    def setUp(self):
+        self._saved = {
+            name: getattr(queue, name)
+            for name in ("QUEUE_ENABLED", "MAX_CONCURRENT", "MAX_ORG_DEPTH", "MAX_WAIT_SECONDS")
+        }
+        queue.QUEUE_ENABLED = True
+        queue.MAX_CONCURRENT = 1
+        queue.MAX_ORG_DEPTH = 8
+        queue.MAX_WAIT_SECONDS = 5.0
+        self._reset_state()
+
+    def tearDown(self):
+        for name, value in self._saved.items():
+            setattr(queue, name, value)
+        self._reset_state()
+
+    def _reset_state(self):
+        queue._lanes.clear()
+        queue._weights.clear()
+        queue._credits.clear()
+        queue._tier_cache.clear()
+        queue._active = 0
+        queue._stats.update(admitted=0, timeouts=0, rejected=0)
+        queue._wait_ewma = 0.0
+        queue._wait_max = 0.0
+
+    def _seed_tier(self, org_id, tier):
+        queue._tier_cache[org_id] = (time.monotonic() + 3600, tier)
+
+
+class DisabledQueueTests(QueueTestCase):
+    def test_disabled_queue_passes_straight_through(self):
+        queue.QUEUE_ENABLED = False
+
+        async def run():
+            for _ in range(20):
+                await queue.acquire("org-a")
+            queue.release()
+
+        asyncio.run(run())
+        self.assertEqual(queue.queue_depth(), 0)
+
+
+class AdmissionTests(QueueTestCase):
+    def test_release_hands_the_slot_to_a_waiter(self):
+        self._seed_tier("org-a", "free")
+
+        async def run():
+            await queue.acquire("org-a")  # fast path takes the only slot
+            waiter = asyncio.ensure_future(queue.acquire("org-a"))
+            await asyncio.sleep(0)
+            self.assertEqual(queue.queue_depth(), 1)
+            self.assertFalse(waiter.done())
+
+            queue.release()
+            await waiter
+            self.assertEqual(queue.queue_depth(), 0)
+            queue.release()
+
+        asyncio.run(run())
+        self.assertEqual(queue._stats["admitted"], 2)
+
+    def test_bounded_lane_rejects_with_depth_hint(self):
+        queue.MAX_ORG_DEPTH = 2
+        self._seed_tier("org-a", "free")
+
+        async def run():
+            await queue.acquire("org-a")
+            waiters = [asyncio.ensure_future(queue.acquire("org-a")) for _ in range(2)]
+            await asyncio.sleep(0)
+
+            with self.assertRaises(queue.QueueFull) as caught:
+                await queue.acquire("org-a")
+            self.assertEqual(caught.exception.depth, 2)
+
+            queue.release()
+            for waiter in waiters:
+                await waiter
+                queue.release()
+
+        asyncio.run(run())
+        self.assertEqual(queue._stats["rejected"], 1)
+
+    def test_wait_budget_times_out_with_depth_hint(self):
+        queue.MAX_WAIT_SECONDS = 0.05
+        self._seed_tier("org-a", "free")
+
+        async def run():
+            await queue.acquire("org-a")  # slot never released
+            with self.assertRaises(queue.QueueTimeout) as caught:
+                await queue.acquire("org-a")
+            self.assertEqual(caught.exception.depth, 0)
+
+        asyncio.run(run())
+        self.assertEqual(queue._stats["timeouts"], 1)
+        self.assertEqual(queue.queue_depth(), 0)
+
+
+class FairnessTests(QueueTestCase):
+    def test_enterprise_outweighs_free_without_starving_it(self):
+        self._seed_tier("org-ent", "enterprise")
+        self._seed_tier("org-free", "free")
+        order = []
+
+        async def worker(org_id):
+            await queue.acquire(org_id)
+            order.append(org_id)
+            queue.release()
+
+        async def run():
+            await queue.acquire("org-hold")  # saturate the single slot
+            tasks = [asyncio.ensure_future(worker("org-ent")) for _ in range(8)]
+            tasks += [asyncio.ensure_future(worker("org-free")) for _ in range(2)]
+            await asyncio.sleep(0)
+            self.assertEqual(queue.queue_depth(), 10)
+
+            queue.release()  # the chain of releases drains both lanes
+            await asyncio.gather(*tasks)
+
+        asyncio.run(run())
+
+        # Smooth weighted round-robin at 8:1 — the enterprise lane takes
+        # eight of the first nine slots, but the free lane is never starved
+        self.assertEqual(len(order), 10)
+        self.assertEqual(order[:9].count("org-free"), 1)
+        self.assertEqual(order.count("org-free"), 2)
+
+    def test_unknown_tier_falls_back_to_cheapest_weight(self):
+        async def run():
+            await queue.acquire("org-hold")
+            waiter = asyncio.ensure_future(queue.acquire("org-mystery"))
+            await asyncio.sleep(0)
+            # The failed lookup was cached as free, the cheapest weight
+            self.assertEqual(queue._weights.get("org-mystery"), 1)
+            queue.release()
+            await waiter
+            queue.release()
+
+        asyncio.run(run())
+
+
+class StatsTests(QueueTestCase):
+    def test_stats_report_depth_and_counters(self):
+        self._seed_tier("org-a", "pro")
+
+        async def run():
+            await queue.acquire("org-a")
+            waiter = asyncio.ensure_future(queue.acquire("org-a"))
+            await asyncio.sleep(0)
+
+            stats = queue.queue_stats()
+            self.assertTrue(stats["enabled"])
+            self.assertEqual(stats["active"], 1)
+            self.assertEqual(stats["depth"], 1)
+            self.assertEqual(stats["depth_by_tier"], {"pro": 1})
+
+            queue.release()
+            await waiter
+            queue.release()
+
+        asyncio.run(run())
+        self.assertEqual(queue.queue_stats()["admitted"], 2)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,112 @@
+# Run from chats-service/: python3 -m unittest discover -s tests -t .
+#
+# The feedback SQL runs here against an in-memory SQLite schema mirroring
+# messages/chats/message_feedback; only the psycopg %s placeholders and the
+# Postgres NOW() call need swapping.
+import sqlite3
+import unittest
+
+from app.helpers.message_feedback import FEEDBACK_UPSERT_SQL, OWNED_ASSISTANT_MESSAGE_SQL
+
+ORG = "org-1"
+OWNER_OF_CHAT = "user-1"
+OTHER_USER = "user-2"
+
+
+def sqlite_sql(sql):
+    return sql.replace("%s", "?").replace("NOW()", "CURRENT_TIMESTAMP")
+
+
+class MessageFeedbackSQLTests(unittest.TestCase):
+    def setUp(self):
+        self.db = sqlite3.connect(":memory:")
+        self.db.executescript(
+            """
+            CREATE TABLE chats (
+                id TEXT PRIMARY KEY,
+                organization_id TEXT,
+                user_id TEXT,
+                deleted_at TIMESTAMP
+            );
+            CREATE TABLE messages (
+                id TEXT PRIMARY KEY,
+                chat_id TEXT,
+                organization_id TEXT,
+                role TEXT
+            );
+            CREATE TABLE message_feedback (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                message_id TEXT,
+                organization_id TEXT,
+                user_id TEXT,
+                rating INTEGER,
+                comment TEXT,
+                updated_at TIMESTAMP,
+                CONSTRAINT uq_message_feedback UNIQUE (message_id, user_id)
+            );
+            """
+        )
+        self.db.execute("INSERT INTO chats VALUES ('chat-1', ?, ?, NULL)", (ORG, OWNER_OF_CHAT))
+        self.db.execute("INSERT INTO chats VALUES ('chat-deleted', ?, ?, CURRENT_TIMESTAMP)", (ORG, OWNER_OF_CHAT))
+        self.db.execute("INSERT INTO messages VALUES ('msg-assistant', 'chat-1', ?, 'assistant')", (ORG,))
+        self.db.execute("INSERT INTO messages VALUES ('msg-user', 'chat-1', ?, 'user')", (ORG,))
+        self.db.execute("INSERT INTO messages VALUES ('msg-gone', 'chat-deleted', ?, 'assistant')", (ORG,))
+
+    def can_rate(self, message_id, user_id, org=ORG):
+        rows = self.db.execute(
+            sqlite_sql(OWNED_ASSISTANT_MESSAGE_SQL), (message_id, org, user_id)
+        ).fetchall()
+        return bool(rows)
+
+    def upsert(self, message_id, user_id, rating, comment=None):
+        row = self.db.execute(
+            sqlite_sql(FEEDBACK_UPSERT_SQL), (message_id, ORG, user_id, rating, comment)
+        ).fetchone()
+        self.db.commit()
+        return row
+
+    # Ownership checks
+
+    def test_owner_can_rate_assistant_message(self):
+        self.assertTrue(self.can_rate("msg-assistant", OWNER_OF_CHAT))
+
+    def test_user_messages_cannot_be_rated(self):
+        self.assertFalse(self.can_rate("msg-user", OWNER_OF_CHAT))
+
+    def test_other_users_chat_is_off_limits(self):
+        self.assertFalse(self.can_rate("msg-assistant", OTHER_USER))
+
+    def test_deleted_chat_is_off_limits(self):
+        self.assertFalse(self.can_rate("msg-gone", OWNER_OF_CHAT))
+
+    def test_wrong_org_is_off_limits(self):
+        self.assertFalse(self.can_rate("msg-assistant", OWNER_OF_CHAT, org="org-2"))
+
+    # Upsert behavior
+
+    def test_resubmitting_replaces_instead_of_duplicating(self):
+        first = self.upsert("msg-assistant", OWNER_OF_CHAT, 1)
+        second = self.upsert("msg-assistant", OWNER_OF_CHAT, -1, "wrong answer")
+
+        self.assertEqual(first[0], second[0])  # same row id: updated, not inserted
+        self.assertEqual(second[1], -1)
+        self.assertEqual(second[2], "wrong answer")
+
+        count = self.db.execute(
+            "SELECT COUNT(*) FROM message_feedback WHERE message_id = ? AND user_id = ?",
+            ("msg-assistant", OWNER_OF_CHAT),
+        ).fetchone()[0]
+        self.assertEqual(count, 1)
+
+    def test_each_user_keeps_their_own_rating(self):
+        self.upsert("msg-assistant", OWNER_OF_CHAT, 1)
+        self.upsert("msg-assistant", OTHER_USER, -1)
+
+        count = self.db.execute(
+            "SELECT COUNT(*) FROM message_feedback WHERE message_id = ?", ("msg-assistant",)
+        ).fetchone()[0]
+        self.assertEqual(count, 2)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,90 @@
+# Run from chats-service/: python3 -m unittest discover -s tests -t .
+#
+# sanitize_query_message guards the inference backend: it strips null bytes,
+# rejects other non-whitespace control characters, and caps the input length
+# (PREDICT_MAX_INPUT_CHARS, read at import). validate_predict_params is the
+# shared rule set for model/temperature/max_tokens.
+import importlib
+import os
+import unittest
+
+from app.helpers import predict_params
+
+
+class SanitizeQueryMessageTests(unittest.TestCase):
+    def test_plain_message_passes_through_trimmed(self):
+        cleaned, err = predict_params.sanitize_query_message("  hello world  ")
+        self.assertIsNone(err)
+        self.assertEqual(cleaned, "hello world")
+
+    def test_null_bytes_are_stripped_not_rejected(self):
+        cleaned, err = predict_params.sanitize_query_message("he\x00llo")
+        self.assertIsNone(err)
+        self.assertEqual(cleaned, "hello")
+
+    def test_only_null_bytes_is_empty(self):
+        cleaned, err = predict_params.sanitize_query_message("\x00\x00")
+        self.assertIsNone(cleaned)
+        self.assertEqual(err, "message cannot be empty")
+
+    def test_whitespace_control_characters_are_allowed(self):
+        cleaned, err = predict_params.sanitize_query_message("line one\nline two\ttabbed\r")
+        self.assertIsNone(err)
+        self.assertIn("\n", cleaned)
+
+    def test_other_control_characters_are_rejected(self):
+        for ch in ("\x01", "\x07", "\x1b"):
+            with self.subTest(ch=repr(ch)):
+                cleaned, err = predict_params.sanitize_query_message(f"bad{ch}input")
+                self.assertIsNone(cleaned)
+                self.assertEqual(err, "message contains unsupported control characters")
+
+    def test_length_cap_boundary(self):
+        at_cap = "a" * predict_params.MAX_INPUT_CHARS
+        cleaned, err = predict_params.sanitize_query_message(at_cap)
+        self.assertIsNone(err)
+        self.assertEqual(cleaned, at_cap)
+
+        cleaned, err = predict_params.sanitize_query_message(at_cap + "a")
+        self.assertIsNone(cleaned)
+        self.assertIn("maximum length", err)
+        self.assertIn(str(predict_params.MAX_INPUT_CHARS), err)
+
+    def test_length_cap_is_configurable_via_env(self):
+        os.environ["PREDICT_MAX_INPUT_CHARS"] = "10"
+        try:
+            importlib.reload(predict_params)
+            cleaned, err = predict_params.sanitize_query_message("a" * 11)
+            self.assertIsNone(cleaned)
+            self.assertIn("maximum length of 10", err)
+        finally:
+            del os.environ["PREDICT_MAX_INPUT_CHARS"]
+            importlib.reload(predict_params)
+
+
+class ValidatePredictParamsTests(unittest.TestCase):
+    def test_all_none_is_valid(self):
+        self.assertIsNone(predict_params.validate_predict_params(None, None, None))
+
+    def test_temperature_bounds(self):
+        self.assertIsNone(predict_params.validate_predict_params(None, 0.0, None))
+        self.assertIsNone(predict_params.validate_predict_params(None, 2.0, None))
+        self.assertIn("temperature", predict_params.validate_predict_params(None, 2.1, None))
+        self.assertIn("temperature", predict_params.validate_predict_params(None, -0.1, None))
+
+    def test_max_tokens_bounds(self):
+        self.assertIsNone(predict_params.validate_predict_params(None, None, 1))
+        self.assertIn("max_tokens", predict_params.validate_predict_params(None, None, 0))
+        self.assertIn(
+            "max_tokens",
+            predict_params.validate_predict_params(None, None, predict_params.MAX_TOKENS_CAP + 1),
+        )
+
+    def test_model_name_rules(self):
+        self.assertIsNone(predict_params.validate_predict_params("gpt-4o-mini", None, None))
+        self.assertIn("empty", predict_params.validate_predict_params("   ", None, None))
+        self.assertIn("too long", predict_params.validate_predict_params("m" * 101, None, None))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,48 @@
+# Run from chats-service/: python3 -m unittest discover -s tests -t .
+import unittest
+
+from app.helpers.prompt_templates import render_template_body
+
+
+class RenderTemplateBodyTests(unittest.TestCase):
+    def test_substitutes_every_placeholder(self):
+        rendered, error = render_template_body(
+            "Summarize {{document}} in a {{tone}} tone",
+            {"document": "Q3 report", "tone": "formal"},
+        )
+        self.assertIsNone(error)
+        self.assertEqual(rendered, "Summarize Q3 report in a formal tone")
+
+    def test_repeated_and_whitespace_placeholders(self):
+        rendered, error = render_template_body(
+            "{{ name }} said: {{quote}}. — {{name}}",
+            {"name": "Ada", "quote": "hello"},
+        )
+        self.assertIsNone(error)
+        self.assertEqual(rendered, "Ada said: hello. — Ada")
+
+    def test_non_string_values_are_stringified(self):
+        rendered, error = render_template_body("Top {{n}} results", {"n": 5})
+        self.assertIsNone(error)
+        self.assertEqual(rendered, "Top 5 results")
+
+    def test_no_placeholders_passes_through(self):
+        rendered, error = render_template_body("plain prompt", {})
+        self.assertIsNone(error)
+        self.assertEqual(rendered, "plain prompt")
+
+    def test_missing_variables_fail_instead_of_leaking_markers(self):
+        rendered, error = render_template_body(
+            "{{a}} {{b}} {{a}}", {"b": "present"}
+        )
+        self.assertIsNone(rendered)
+        self.assertEqual(error, "missing template variables: a")
+
+    def test_missing_variables_are_listed_sorted(self):
+        rendered, error = render_template_body("{{z}} {{a}}", {})
+        self.assertIsNone(rendered)
+        self.assertEqual(error, "missing template variables: a, z")
+
+
+if __name__ == "__main__":
+    unittest.main()
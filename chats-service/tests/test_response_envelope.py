@@ -0,0 +1,58 @@
+# Run from chats-service/: python3 -m unittest discover -s tests -t .
+import unittest
+
+from app.utils import error_codes
+from app.utils.response import APIResponse
+
+
+class APIResponseEnvelopeTests(unittest.TestCase):
+    def test_success_shape(self):
+        body = APIResponse(False, "Chats fetched successfully", {"chats": []})
+        self.assertEqual(
+            body,
+            {
+                "error": False,
+                "message": "Chats fetched successfully",
+                "status": 200,
+                "data": {"chats": []},
+            },
+        )
+
+    def test_error_shape_carries_machine_code(self):
+        body = APIResponse(
+            True, "Unauthorized", None, 401, code=error_codes.UNAUTHORIZED
+        )
+        self.assertEqual(
+            body,
+            {
+                "error": True,
+                "message": "Unauthorized",
+                "status": 401,
+                "data": None,
+                "code": "unauthorized",
+            },
+        )
+
+    def test_code_is_omitted_when_unset(self):
+        # Mirrors the Go envelope's omitempty: no code key, not code=None
+        body = APIResponse(True, "boom", None, 500)
+        self.assertNotIn("code", body)
+
+    def test_data_defaults_to_none(self):
+        self.assertIsNone(APIResponse(False, "ok")["data"])
+
+    def test_codes_are_snake_case_and_distinct(self):
+        codes = [
+            value
+            for name, value in vars(error_codes).items()
+            if name.isupper() and isinstance(value, str)
+        ]
+        self.assertTrue(codes)
+        self.assertEqual(len(codes), len(set(codes)))
+        for code in codes:
+            self.assertEqual(code, code.lower())
+            self.assertNotIn(" ", code)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,133 @@
+# Run from chats-service/: python3 -m unittest discover -s tests -t .
+#
+# The purge SQL runs against in-memory SQLite with the time "frozen": message
+# timestamps are written relative to a fixed now, and NOW() minus the interval
+# is rewritten to SQLite's DATETIME arithmetic with the same strictly-older
+# comparison, so the boundary-day semantics are exactly what Postgres applies.
+import sqlite3
+import unittest
+from datetime import datetime, timedelta
+
+from app.helpers.retention import PURGE_EMPTY_CHATS_SQL, PURGE_MESSAGES_SQL
+
+ORG = "org-1"
+NOW = datetime(2026, 3, 1, 12, 0, 0)
+RETENTION_DAYS = 30
+
+
+def sqlite_purge_sql(frozen_now):
+    sql = PURGE_MESSAGES_SQL.replace(
+        "NOW() - (%s * INTERVAL '1 day')",
+        f"DATETIME('{frozen_now.isoformat(sep=' ')}', '-' || ? || ' days')",
+    )
+    return sql.replace("%s", "?")
+
+
+def sqlite_empty_chats_sql(frozen_now):
+    sql = PURGE_EMPTY_CHATS_SQL.replace(
+        "NOW() - INTERVAL '1 day'",
+        f"DATETIME('{frozen_now.isoformat(sep=' ')}', '-1 day')",
+    )
+    return sql.replace("%s", "?")
+
+
+class RetentionPurgeTests(unittest.TestCase):
+    def setUp(self):
+        self.db = sqlite3.connect(":memory:")
+        self.db.executescript(
+            """
+            CREATE TABLE chats (
+                id TEXT PRIMARY KEY,
+                organization_id TEXT,
+                created_at TIMESTAMP
+            );
+            CREATE TABLE messages (
+                id TEXT PRIMARY KEY,
+                chat_id TEXT,
+                organization_id TEXT,
+                created_at TIMESTAMP
+            );
+            """
+        )
+        self.add_chat("chat-1")
+
+    def add_chat(self, chat_id, age=timedelta(days=2), org=ORG):
+        created = NOW - age
+        self.db.execute(
+            "INSERT INTO chats VALUES (?, ?, ?)",
+            (chat_id, org, created.isoformat(sep=" ")),
+        )
+
+    def add_message(self, msg_id, age, org=ORG, chat="chat-1"):
+        created = NOW - age
+        self.db.execute(
+            "INSERT INTO messages VALUES (?, ?, ?, ?)",
+            (msg_id, chat, org, created.isoformat(sep=" ")),
+        )
+
+    def purge(self, batch_size=1000):
+        deleted = self.db.execute(
+            sqlite_purge_sql(NOW), (ORG, RETENTION_DAYS, batch_size)
+        ).fetchall()
+        self.db.commit()
+        return sorted(r[0] for r in deleted)
+
+    def remaining(self):
+        rows = self.db.execute("SELECT id FROM messages ORDER BY id").fetchall()
+        return [r[0] for r in rows]
+
+    def test_boundary_day(self):
+        cutoff = timedelta(days=RETENTION_DAYS)
+        self.add_message("just-older", cutoff + timedelta(seconds=1))
+        self.add_message("exactly-at", cutoff)
+        self.add_message("just-newer", cutoff - timedelta(seconds=1))
+
+        # Strictly older than the cutoff goes; exactly-at survives
+        self.assertEqual(self.purge(), ["just-older"])
+        self.assertEqual(self.remaining(), ["exactly-at", "just-newer"])
+
+    def test_other_orgs_are_untouched(self):
+        self.add_chat("chat-2", org="org-2")
+        self.add_message("mine-old", timedelta(days=60))
+        self.add_message("theirs-old", timedelta(days=60), org="org-2", chat="chat-2")
+
+        self.assertEqual(self.purge(), ["mine-old"])
+        self.assertEqual(self.remaining(), ["theirs-old"])
+
+    def test_batching_deletes_oldest_eligible_rows_per_pass(self):
+        for i in range(5):
+            self.add_message(f"old-{i}", timedelta(days=40 + i))
+
+        first = self.purge(batch_size=2)
+        self.assertEqual(len(first), 2)
+        second = self.purge(batch_size=2)
+        third = self.purge(batch_size=2)
+        self.assertEqual(len(second) + len(third), 3)
+        self.assertEqual(self.purge(batch_size=2), [])  # drained
+
+    def test_empty_chats_are_swept_afterwards(self):
+        self.add_chat("chat-empty")
+        self.add_message("keep", timedelta(days=1))
+
+        swept = self.db.execute(sqlite_empty_chats_sql(NOW), (ORG,)).fetchall()
+        self.db.commit()
+        self.assertEqual([r[0] for r in swept], ["chat-empty"])
+        chats = self.db.execute("SELECT id FROM chats ORDER BY id").fetchall()
+        self.assertEqual([r[0] for r in chats], ["chat-1"])
+
+    def test_fresh_empty_chats_survive_the_sweep(self):
+        # A chat row is committed before its first message is recorded, so
+        # the sweep must leave day-old-or-newer chats alone
+        self.add_chat("just-created", age=timedelta(minutes=5))
+        self.add_chat("hours-old", age=timedelta(hours=23))
+        self.add_chat("stale-empty", age=timedelta(days=1, seconds=1))
+
+        swept = self.db.execute(sqlite_empty_chats_sql(NOW), (ORG,)).fetchall()
+        self.db.commit()
+        self.assertEqual(sorted(r[0] for r in swept), ["chat-1", "stale-empty"])
+        chats = self.db.execute("SELECT id FROM chats ORDER BY id").fetchall()
+        self.assertEqual([r[0] for r in chats], ["hours-old", "just-created"])
+
+
+if __name__ == "__main__":
+    unittest.main()
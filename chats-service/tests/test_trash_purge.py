@@ -0,0 +1,127 @@
+# Run from chats-service/: python3 -m unittest discover -s tests -t .
+#
+# The purge is the destructive end of the document trash: rows soft-deleted
+# longer than the retention window go away for good, everything else stays.
+# It runs here against an in-memory SQLite documents table (the psycopg
+# placeholders and the INTERVAL arithmetic are the only rewrites), with the
+# storage delete stubbed per test.
+import asyncio
+import sqlite3
+import sys
+import types
+import unittest
+from contextlib import asynccontextmanager
+
+# trash.py imports the storage helper at module level; boto3 is not
+# installed here, so a stub satisfies the import and each test patches the
+# function it actually uses
+if "app.helpers.s3_storage" not in sys.modules:
+    _s3 = types.ModuleType("app.helpers.s3_storage")
+
+    async def _unpatched_delete(key):
+        raise AssertionError("delete_s3_object was not patched")
+
+    _s3.delete_s3_object = _unpatched_delete
+    sys.modules["app.helpers.s3_storage"] = _s3
+
+if "app.database.postgres_client" not in sys.modules:
+    _db = types.ModuleType("app.database.postgres_client")
+    _db.get_db_cursor = None
+    sys.modules["app.database.postgres_client"] = _db
+
+from app.helpers import trash  # noqa: E402
+
+
+class _Cursor:
+    def __init__(self, db):
+        self._db = db
+        self._rows = None
+
+    async def execute(self, sql, params=()):
+        sql = sql.replace("%s", "?")
+        sql = sql.replace("NOW() - (? * INTERVAL '1 day')", "datetime('now', '-' || ? || ' days')")
+        self._rows = self._db.execute(sql, params)
+
+    async def fetchall(self):
+        rows = self._rows.fetchall()
+        columns = [d[0] for d in self._rows.description]
+        return [dict(zip(columns, row)) for row in rows]
+
+
+class TrashPurgeTests(unittest.TestCase):
+    def setUp(self):
+        self.db = sqlite3.connect(":memory:")
+        self.db.execute(
+            "CREATE TABLE documents (id TEXT PRIMARY KEY, s3_key TEXT, deleted_at TEXT)"
+        )
+        self.deleted_keys = []
+
+        @asynccontextmanager
+        async def fake_cursor(commit=False):
+            yield _Cursor(self.db)
+            if commit:
+                self.db.commit()
+
+        async def fake_delete(key):
+            self.deleted_keys.append(key)
+
+        self._saved_cursor = trash.get_db_cursor
+        self._saved_delete = trash.delete_s3_object
+        trash.get_db_cursor = fake_cursor
+        trash.delete_s3_object = fake_delete
+
+    def tearDown(self):
+        trash.get_db_cursor = self._saved_cursor
+        trash.delete_s3_object = self._saved_delete
+        self.db.close()
+
+    def _add_document(self, doc_id, deleted_days_ago=None):
+        if deleted_days_ago is None:
+            deleted_at = None
+        else:
+            deleted_at = self.db.execute(
+                "SELECT datetime('now', ?)", (f"-{deleted_days_ago} days",)
+            ).fetchone()[0]
+        self.db.execute(
+            "INSERT INTO documents (id, s3_key, deleted_at) VALUES (?, ?, ?)",
+            (doc_id, f"s3/{doc_id}", deleted_at),
+        )
+        self.db.commit()
+
+    def _remaining_ids(self):
+        return {row[0] for row in self.db.execute("SELECT id FROM documents")}
+
+    def test_purges_only_trash_past_retention(self):
+        self._add_document("expired", deleted_days_ago=trash.TRASH_RETENTION_DAYS + 5)
+        self._add_document("recent-trash", deleted_days_ago=1)
+        self._add_document("live")
+
+        purged = asyncio.run(trash.purge_trashed_documents())
+
+        self.assertEqual(purged, 1)
+        self.assertEqual(self._remaining_ids(), {"recent-trash", "live"})
+        self.assertEqual(self.deleted_keys, ["s3/expired"])
+
+    def test_nothing_to_purge_is_a_no_op(self):
+        self._add_document("live")
+        purged = asyncio.run(trash.purge_trashed_documents())
+        self.assertEqual(purged, 0)
+        self.assertEqual(self.deleted_keys, [])
+
+    def test_storage_failure_does_not_strand_the_purge(self):
+        self._add_document("expired", deleted_days_ago=trash.TRASH_RETENTION_DAYS + 5)
+
+        async def failing_delete(key):
+            raise ConnectionError("s3 unavailable")
+
+        trash.delete_s3_object = failing_delete
+
+        purged = asyncio.run(trash.purge_trashed_documents())
+
+        # The row is gone regardless; an orphaned object beats a stuck purge
+        self.assertEqual(purged, 1)
+        self.assertEqual(self._remaining_ids(), set())
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -33,6 +33,27 @@ CREATE TABLE IF NOT EXISTS organizations (
     created_by UUID,
     status VARCHAR(20) DEFAULT 'pending',
     meta JSONB,
+    message_retention_days INT DEFAULT 0,
+    last_purge_at TIMESTAMPTZ,
+    prediction_quota_monthly INT DEFAULT 0,            -- 0 = unlimited
+    quota_overflow_behavior VARCHAR(20) DEFAULT 'block', -- block / degrade
+    quota_fallback_model VARCHAR(100),
+    allowed_email_domains VARCHAR(500),                -- comma-separated, empty = any
+    allowed_chat_models VARCHAR(500),                  -- comma-separated models members may pin, empty = any
+    default_locale VARCHAR(10) DEFAULT 'en',           -- email language for new members
+    member_directory_visible BOOLEAN DEFAULT true,     -- members may browse GET /members
+    default_join_role VARCHAR(50) DEFAULT 'member',    -- role for join-link signups without an explicit role
+    seat_limit INT,                                    -- NULL = unlimited; active+pending occupy seats
+    tier VARCHAR(20) DEFAULT 'free',                   -- billing tier; weights the inference queue
+    storage_quota_bytes BIGINT DEFAULT 0,              -- document storage cap (0 = deployment default)
+    storage_used_bytes BIGINT DEFAULT 0,               -- running counter; reconciled nightly
+    brand_logo_url VARCHAR(500),                       -- email branding (https only)
+    brand_primary_color VARCHAR(7),                    -- email branding (#RRGGBB)
+    email_footer_text VARCHAR(500),                    -- email branding footer
+    onboarding_state JSONB DEFAULT '{}'::jsonb,        -- checklist: step key -> completed_at, plus "dismissed"
+    enforce_2fa BOOLEAN DEFAULT FALSE,                 -- invite auto-login returns a challenge instead of a token
+    moderation_mode VARCHAR(20) DEFAULT 'standard',    -- standard / strict; auto-tightened on abuse flagging
+    abuse_flagged_at TIMESTAMPTZ,                      -- set when report volume crosses the threshold
     created_at TIMESTAMPTZ DEFAULT now(),
     updated_at TIMESTAMPTZ DEFAULT now(),
     is_deleted BOOLEAN DEFAULT FALSE,
@@ -47,29 +68,163 @@ CREATE TABLE IF NOT EXISTS users (
     organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
     name VARCHAR(255) NOT NULL,
     email VARCHAR(255) NOT NULL,
+    email_status VARCHAR(20) DEFAULT 'ok',
     password VARCHAR(255) NOT NULL,
     role VARCHAR(20) NOT NULL,
     status VARCHAR(20) DEFAULT 'pending',
+    locale VARCHAR(10) DEFAULT 'en',
     profile_image_url TEXT,
     invited_by UUID REFERENCES users(id),
     invite_token VARCHAR(255),
     expires_at TIMESTAMPTZ,
+    accepted_at TIMESTAMPTZ,                           -- invite/verification completed; feeds funnel metrics
     reactivated_at TIMESTAMPTZ,
     token_version INT DEFAULT 1,
-    is_deleted BOOLEAN DEFAULT FALSE,
     deleted_at TIMESTAMPTZ,
     deleted_by UUID,
     reset_token VARCHAR(255),
     reset_expires_at TIMESTAMPTZ,
     created_at TIMESTAMPTZ DEFAULT now(),
     updated_at TIMESTAMPTZ DEFAULT now(),
-    metadata JSONB,
-    CONSTRAINT unique_org_email UNIQUE (organization_id, email)
+    metadata JSONB
 );
 
 CREATE INDEX IF NOT EXISTS idx_users_org ON users(organization_id);
+-- emails are unique per org case-insensitively: the app lowercases on write,
+-- this index enforces it for rows written by anything else
+CREATE UNIQUE INDEX IF NOT EXISTS uq_users_org_email_lower ON users(organization_id, LOWER(email));
 CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users(LOWER(email));
 CREATE INDEX IF NOT EXISTS idx_users_status ON users(status);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at);
+-- invite funnel stats scan invited users by org and window
+CREATE INDEX IF NOT EXISTS idx_users_org_invited_created ON users(organization_id, created_at) WHERE invited_by IS NOT NULL;
+
+-- ====================================================
+-- account_id_aliases (retired account ids after regeneration)
+-- ====================================================
+CREATE TABLE IF NOT EXISTS account_id_aliases (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    account_id VARCHAR(16) UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_id_aliases_org ON account_id_aliases(organization_id);
+
+-- ====================================================
+-- organization_domains (TXT-verified corporate email domains)
+-- ====================================================
+CREATE TABLE IF NOT EXISTS organization_domains (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    domain VARCHAR(255) UNIQUE NOT NULL, -- lowercased; one org per domain
+    txt_challenge VARCHAR(64) NOT NULL,
+    verified_at TIMESTAMPTZ,             -- only verified domains enable auto-join
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_domains_org ON organization_domains(organization_id);
+
+-- ====================================================
+-- invite_links (shareable org join links)
+-- ====================================================
+CREATE TABLE IF NOT EXISTS invite_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    token VARCHAR(64) UNIQUE NOT NULL,
+    role VARCHAR(20) NOT NULL,           -- maintainer / member
+    created_by UUID,
+    max_uses INT DEFAULT 0,              -- 0 = unlimited
+    uses INT DEFAULT 0,
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_invite_links_org ON invite_links(organization_id);
+
+-- ====================================================
+-- org_roles (custom permission bundles; built-in roles never appear here)
+-- ====================================================
+CREATE TABLE IF NOT EXISTS org_roles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    permissions VARCHAR(1000),          -- comma-separated capability strings
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT uq_org_roles_org_name UNIQUE (organization_id, name)
+);
+
+-- ====================================================
+-- prompt_templates (reusable org-scoped prompts with {{placeholders}})
+-- ====================================================
+CREATE TABLE IF NOT EXISTS prompt_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    created_by UUID,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_prompt_templates_org ON prompt_templates(organization_id);
+
+-- ====================================================
+-- feature_flags (maintenance mode / per-endpoint kill switches;
+-- organization_id NULL = global, non-NULL = per-tenant override)
+-- ====================================================
+CREATE TABLE IF NOT EXISTS feature_flags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT uq_feature_flags_name_org UNIQUE (name, organization_id)
+);
+
+-- UNIQUE treats NULLs as distinct, so pin down the global row separately
+CREATE UNIQUE INDEX IF NOT EXISTS uq_feature_flags_global
+    ON feature_flags(name) WHERE organization_id IS NULL;
+
+-- ====================================================
+-- audit_logs (sensitive admin actions, incl. impersonation)
+-- ====================================================
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    actor_id UUID NOT NULL,
+    impersonator_id UUID,
+    action VARCHAR(100) NOT NULL,
+    details TEXT,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_org ON audit_logs(organization_id, created_at DESC);
+
+-- ====================================================
+-- outbox_emails (transactional outbox; dispatcher delivers at-least-once)
+-- ====================================================
+CREATE TABLE IF NOT EXISTS outbox_emails (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID,                              -- scopes owner visibility; NULL for mail without org context
+    template VARCHAR(50),                              -- verify / invite / reset
+    recipient VARCHAR(255) NOT NULL,
+    subject VARCHAR(500) NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending',              -- pending / sent / failed
+    attempts INT DEFAULT 0,
+    last_error VARCHAR(500),
+    claimed_at TIMESTAMPTZ,                            -- set on claim, cleared once the outcome is recorded
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_emails_pending ON outbox_emails(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_emails_org ON outbox_emails(organization_id, status, created_at);
 
 -- ====================================================
 -- Documents
@@ -80,8 +235,12 @@ CREATE TABLE documents (
     created_by UUID REFERENCES users(id),
     file_name VARCHAR(1024) NOT NULL,
     s3_key TEXT NOT NULL,
+    file_size BIGINT DEFAULT 0,
+    -- 'file' for uploads, 'snippet' for pasted text
+    source_type VARCHAR(20) DEFAULT 'file',
     status VARCHAR(20) DEFAULT 'pending',
     trainable BOOLEAN DEFAULT TRUE,
+    visibility VARCHAR(20) DEFAULT 'org',
     created_at TIMESTAMPTZ DEFAULT now(),
     updated_at TIMESTAMPTZ DEFAULT now(),
     last_trained_at TIMESTAMPTZ,
@@ -91,6 +250,60 @@ CREATE TABLE documents (
 CREATE INDEX idx_documents_org ON documents(organization_id);
 CREATE INDEX idx_documents_status ON documents(status);
 
+-- ====================================================
+-- Document Sources (scheduled sync from external URLs)
+-- ====================================================
+CREATE TABLE document_sources (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id),
+    document_id UUID REFERENCES documents(id) ON DELETE SET NULL,
+    url TEXT NOT NULL,
+    auth_header VARCHAR(500),                          -- optional "Name: value" sent with each fetch
+    fetch_interval_minutes INT NOT NULL DEFAULT 60,
+    content_hash VARCHAR(64),                          -- sha256 of the last synced payload
+    enabled BOOLEAN DEFAULT TRUE,
+    failure_count INT DEFAULT 0,                       -- consecutive failures; drives backoff
+    last_fetched_at TIMESTAMPTZ,
+    next_fetch_at TIMESTAMPTZ DEFAULT now(),
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_document_sources_org ON document_sources(organization_id);
+CREATE INDEX idx_document_sources_due ON document_sources(next_fetch_at) WHERE enabled;
+
+CREATE TABLE document_source_fetches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_id UUID NOT NULL REFERENCES document_sources(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,                       -- synced / unchanged / failed
+    http_status INT,
+    bytes INT DEFAULT 0,
+    duration_ms INT DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_document_source_fetches_source
+    ON document_source_fetches(source_id, created_at DESC);
+
+-- ====================================================
+-- Document Permissions (restricted-visibility ACLs)
+-- ====================================================
+CREATE TABLE IF NOT EXISTS document_permissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    document_id UUID NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20),
+    created_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT chk_document_permission_target CHECK (user_id IS NOT NULL OR role IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_document_permissions_doc ON document_permissions(document_id);
+CREATE INDEX IF NOT EXISTS idx_document_permissions_user ON document_permissions(user_id);
+
 -- ====================================================
 -- Training Jobs
 -- ====================================================
@@ -117,6 +330,8 @@ CREATE TABLE document_chunks (
     organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
     chunk_index INT NOT NULL,
     chunk_text TEXT NOT NULL,
+    char_start INT,                                    -- offsets into the normalized document text,
+    char_end INT,                                      -- for citation highlighting
     embedding VECTOR(1536) NOT NULL,
     created_at TIMESTAMPTZ DEFAULT now(),
 
@@ -140,6 +355,10 @@ CREATE TABLE chats (
     user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
     title VARCHAR(255),
     status VARCHAR(20) DEFAULT 'active',
+    -- Pinned prediction settings; NULL = deployment default
+    model VARCHAR(100),
+    temperature REAL,
+    max_tokens INT,
     created_at TIMESTAMPTZ DEFAULT now(),
     updated_at TIMESTAMPTZ DEFAULT now(),
     last_message_at TIMESTAMPTZ,
@@ -158,11 +377,83 @@ CREATE TABLE messages (
     sender_user_id UUID REFERENCES users(id),
     role VARCHAR(20) NOT NULL,
     content TEXT NOT NULL,
+    content_tsv TSVECTOR,
+    citations JSONB,                                   -- chunk-level citations on assistant messages
     created_at TIMESTAMPTZ DEFAULT now()
 );
 
 CREATE INDEX idx_messages_chat ON messages(chat_id);
 CREATE INDEX idx_messages_org ON messages(organization_id);
+CREATE INDEX IF NOT EXISTS idx_messages_content_tsv ON messages USING GIN (content_tsv);
+
+-- Keep the full-text column in sync with message content
+CREATE OR REPLACE FUNCTION messages_content_tsv_update() RETURNS trigger AS $$
+BEGIN
+    NEW.content_tsv := to_tsvector('english', COALESCE(NEW.content, ''));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_messages_content_tsv ON messages;
+CREATE TRIGGER trg_messages_content_tsv
+    BEFORE INSERT OR UPDATE OF content ON messages
+    FOR EACH ROW EXECUTE FUNCTION messages_content_tsv_update();
+
+-- ====================================================
+-- Chat Shares (read-only conversation links within an org)
+-- ====================================================
+CREATE TABLE IF NOT EXISTS chat_shares (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    chat_id UUID NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    slug VARCHAR(64) UNIQUE NOT NULL,
+    snapshot_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_shares_org ON chat_shares(organization_id);
+CREATE INDEX IF NOT EXISTS idx_chat_shares_chat ON chat_shares(chat_id);
+
+-- ====================================================
+-- Message Feedback (thumbs up/down on assistant messages)
+-- ====================================================
+CREATE TABLE IF NOT EXISTS message_feedback (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    message_id UUID NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating IN (-1, 0, 1)),
+    comment TEXT,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT uq_message_feedback UNIQUE (message_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_feedback_org ON message_feedback(organization_id);
+
+-- ====================================================
+-- Abuse Reports (user reports on problematic AI output)
+-- ====================================================
+CREATE TABLE IF NOT EXISTS abuse_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    message_id UUID NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    reporter_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category VARCHAR(30) NOT NULL,                     -- harmful / hateful / sexual / privacy / misinformation / other
+    details TEXT,
+    status VARCHAR(20) DEFAULT 'open',                 -- open / reviewed / dismissed
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    -- Re-reporting the same message updates the existing report
+    CONSTRAINT uq_abuse_reports UNIQUE (message_id, reporter_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_abuse_reports_org ON abuse_reports(organization_id, created_at);
 
 -- ====================================================
 -- Token Usage
@@ -182,6 +473,35 @@ CREATE TABLE token_usage (
 CREATE INDEX idx_token_usage_org ON token_usage(organization_id);
 CREATE INDEX idx_token_usage_user ON token_usage(user_id);
 
+-- Daily rollup (UTC buckets) backing the org usage report
+CREATE TABLE IF NOT EXISTS token_usage_daily (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    day DATE NOT NULL,
+
+    prompt_tokens BIGINT DEFAULT 0,
+    completion_tokens BIGINT DEFAULT 0,
+    requests BIGINT DEFAULT 0,
+
+    PRIMARY KEY (organization_id, user_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_usage_daily_org_day ON token_usage_daily(organization_id, day);
+
+-- One row per org per monthly quota period; the PK makes period rollover
+-- race-safe (concurrent requests upsert into the same row).
+CREATE TABLE IF NOT EXISTS org_quota_periods (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    predictions_used BIGINT DEFAULT 0,
+    warning_sent_at TIMESTAMPTZ,
+
+    PRIMARY KEY (organization_id, period_start)
+);
+
+-- Supports the usage report group-by over a year of messages
+CREATE INDEX IF NOT EXISTS idx_messages_org_created ON messages(organization_id, created_at);
+
 -- ====================================================
 -- End of Basic RAG Schema (Organizations & Users Preserved)
 -- ====================================================
@@ -0,0 +1,101 @@
+// Package apperrors carries an HTTP status with an error across the
+// service/handler boundary, so handlers stop guessing (and alerting) with
+// blanket 400/500s. Services return e.g. apperrors.Conflict("...") and
+// handlers map it with apperrors.Status; plain errors keep the handler's
+// fallback status.
+package apperrors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Error is an error with an HTTP status attached, and optionally a stable
+// machine-readable code clients (and the i18n catalog) can key on.
+type Error struct {
+	status  int
+	code    string
+	message string
+	cause   error
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// HTTPStatus returns the status this error should surface as.
+func (e *Error) HTTPStatus() int {
+	return e.status
+}
+
+// WithCode tags the error with a stable identifier ("org_name_taken", ...)
+// and returns the receiver, so it chains off the constructors below.
+func (e *Error) WithCode(code string) *Error {
+	e.code = code
+	return e
+}
+
+// New builds an error with an explicit status, for the odd case the helpers
+// below don't cover.
+func New(status int, message string) *Error {
+	return &Error{status: status, message: message}
+}
+
+// Wrap attaches a status and user-facing message while keeping the cause
+// reachable via errors.Is/As.
+func Wrap(status int, message string, cause error) *Error {
+	return &Error{status: status, message: message, cause: cause}
+}
+
+// BadRequest: the request is malformed (400).
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, message)
+}
+
+// Validation: the request parsed but its content is invalid (422).
+func Validation(message string) *Error {
+	return New(http.StatusUnprocessableEntity, message)
+}
+
+// Forbidden: the caller is authenticated but not allowed (403).
+func Forbidden(message string) *Error {
+	return New(http.StatusForbidden, message)
+}
+
+// NotFound: the referenced resource doesn't exist (404).
+func NotFound(message string) *Error {
+	return New(http.StatusNotFound, message)
+}
+
+// Conflict: the request clashes with existing state, e.g. a duplicate (409).
+func Conflict(message string) *Error {
+	return New(http.StatusConflict, message)
+}
+
+// Gone: the resource existed but no longer does, e.g. an expired token (410).
+func Gone(message string) *Error {
+	return New(http.StatusGone, message)
+}
+
+// Status extracts the HTTP status from a typed error, or returns fallback for
+// plain errors (handlers pick 500 for "anything unexpected" paths).
+func Status(err error, fallback int) int {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.HTTPStatus()
+	}
+	return fallback
+}
+
+// Code extracts the machine-readable code, or "" for plain or untagged errors.
+func Code(err error) string {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.code
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+)
+
+// authCacheTTL reads AUTH_CACHE_TTL_SECONDS (default 30). A short TTL bounds
+// staleness for anything that forgets to invalidate; explicit invalidation
+// covers the security-sensitive changes.
+func authCacheTTL() time.Duration {
+	if raw := os.Getenv("AUTH_CACHE_TTL_SECONDS"); raw != "" {
+		if seconds, err := strconv.Atoi(raw); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return 30 * time.Second
+}
+
+// Shared caches for the hot auth path. Users and Organizations are keyed by
+// UUID string; OrgRoles by OrgRoleKey (custom-role permission resolution).
+var (
+	Users         = New[models.User](authCacheTTL())
+	Organizations = New[models.Organization](authCacheTTL())
+	OrgRoles      = New[models.OrgRole](authCacheTTL())
+)
+
+// OrgRoleKey builds the OrgRoles cache key for a role name within an org.
+func OrgRoleKey(orgID, roleName string) string {
+	return orgID + ":" + roleName
+}
+
+// InvalidateUser must be called after any change to a user's status, role,
+// or token version so the next authenticated request sees it immediately.
+func InvalidateUser(userID string) {
+	Users.Invalidate(userID)
+}
+
+// InvalidateOrganization must be called after org mutations (account id
+// regeneration, deletion, settings changes).
+func InvalidateOrganization(orgID string) {
+	Organizations.Invalidate(orgID)
+}
+
+// InvalidateOrgRole must be called after a custom role's permissions change
+// or the role is deleted, so the next request resolves the new grant.
+func InvalidateOrgRole(orgID, roleName string) {
+	OrgRoles.Invalidate(OrgRoleKey(orgID, roleName))
+}
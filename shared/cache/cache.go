@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cache is a small concurrent-safe TTL cache for hot read paths (auth user
+// lookups, org lookups). Entries expire lazily on Get; writes that must be
+// visible immediately (suspend, role change, token-version bump) call
+// Invalidate so stale data never authorizes a request it shouldn't.
+type Cache[T any] struct {
+	mu      sync.RWMutex
+	entries map[string]entry[T]
+	ttl     time.Duration
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type entry[T any] struct {
+	value     T
+	expiresAt time.Time
+}
+
+// Stats is a point-in-time snapshot of cache effectiveness.
+type Stats struct {
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+	Entries int   `json:"entries"`
+}
+
+func New[T any](ttl time.Duration) *Cache[T] {
+	return &Cache[T]{
+		entries: make(map[string]entry[T]),
+		ttl:     ttl,
+	}
+}
+
+func (c *Cache[T]) Get(key string) (T, bool) {
+	c.mu.RLock()
+	e, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok || time.Now().After(e.expiresAt) {
+		if ok {
+			// Expired: drop it so Entries stays meaningful
+			c.mu.Lock()
+			if current, still := c.entries[key]; still && time.Now().After(current.expiresAt) {
+				delete(c.entries, key)
+			}
+			c.mu.Unlock()
+		}
+		c.misses.Add(1)
+		var zero T
+		return zero, false
+	}
+
+	c.hits.Add(1)
+	return e.value, true
+}
+
+func (c *Cache[T]) Set(key string, value T) {
+	c.mu.Lock()
+	c.entries[key] = entry[T]{value: value, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+}
+
+func (c *Cache[T]) Invalidate(key string) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+func (c *Cache[T]) Stats() Stats {
+	c.mu.RLock()
+	entries := len(c.entries)
+	c.mu.RUnlock()
+
+	return Stats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Entries: entries,
+	}
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheGetSetAndExpiry(t *testing.T) {
+	c := New[string](20 * time.Millisecond)
+
+	if _, ok := c.Get("k"); ok {
+		t.Fatal("empty cache returned a hit")
+	}
+
+	c.Set("k", "value")
+	if got, ok := c.Get("k"); !ok || got != "value" {
+		t.Fatalf("Get = %q, %v; want value, true", got, ok)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	if _, ok := c.Get("k"); ok {
+		t.Error("entry survived past its TTL")
+	}
+	if stats := c.Stats(); stats.Entries != 0 {
+		t.Errorf("expired entry still counted: %+v", stats)
+	}
+}
+
+func TestCacheInvalidateTakesEffectImmediately(t *testing.T) {
+	c := New[int](time.Hour)
+
+	c.Set("user", 1)
+	c.Invalidate("user")
+	if _, ok := c.Get("user"); ok {
+		t.Error("invalidated entry was still served")
+	}
+
+	// Invalidating an absent key is a no-op
+	c.Invalidate("never-set")
+}
+
+func TestCacheStatsCountHitsAndMisses(t *testing.T) {
+	c := New[string](time.Hour)
+
+	c.Get("miss")
+	c.Set("hit", "v")
+	c.Get("hit")
+	c.Get("hit")
+
+	stats := c.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("stats = %+v, want 2 hits / 1 miss / 1 entry", stats)
+	}
+}
+
+func TestCacheIsSafeUnderConcurrentUse(t *testing.T) {
+	c := New[int](time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				c.Set("shared", j)
+				c.Get("shared")
+				c.Invalidate("shared")
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,185 @@
+// Package client holds typed HTTP clients for the internal service-to-service
+// endpoints, so callers don't hand-roll URLs, headers and envelope parsing.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/cache"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/httpclient"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+)
+
+// usersCacheTTL reads USERS_CLIENT_CACHE_TTL_SECONDS (default 30). Display
+// info goes stale gracefully — a renamed user shows the old name for at most
+// the TTL — so a short window is plenty.
+func usersCacheTTL() time.Duration {
+	if raw := os.Getenv("USERS_CLIENT_CACHE_TTL_SECONDS"); raw != "" {
+		if seconds, err := strconv.Atoi(raw); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return 30 * time.Second
+}
+
+// UsersClient resolves user display info via users-service's internal bulk
+// endpoint (GET /api/v1/internal/v1/users). Hits are served from a short TTL
+// cache; concurrent identical batches collapse into one request.
+type UsersClient struct {
+	baseURL    string
+	serviceKey string
+	httpClient *httpclient.Client
+
+	users   *cache.Cache[models.UserDisplayInfo]
+	missing *cache.Cache[bool] // negative cache so unknown IDs don't refetch every call
+
+	mu       sync.Mutex
+	inflight map[string]*resolveCall
+}
+
+// resolveCall is one in-flight fetch; duplicates wait on done and share the
+// result (just enough singleflight for this one call site).
+type resolveCall struct {
+	done chan struct{}
+	resp *models.ResolveUsersResponse
+	err  error
+}
+
+// NewUsersClient reads USERS_SERVICE_URL (default http://localhost:8080) and
+// SERVICE_API_KEY — the same key ServiceKeyAuth checks on the other side.
+func NewUsersClient() *UsersClient {
+	baseURL := os.Getenv("USERS_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+
+	ttl := usersCacheTTL()
+	return &UsersClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		serviceKey: os.Getenv("SERVICE_API_KEY"),
+		httpClient: httpclient.New(httpclient.Config{}),
+		users:      cache.New[models.UserDisplayInfo](ttl),
+		missing:    cache.New[bool](ttl),
+		inflight:   make(map[string]*resolveCall),
+	}
+}
+
+// Resolve returns display info for up to 100 user IDs. Unknown IDs come back
+// in Missing rather than as an error, mirroring the endpoint itself.
+func (c *UsersClient) Resolve(ids []string) (*models.ResolveUsersResponse, error) {
+	response := &models.ResolveUsersResponse{
+		Users:   []models.UserDisplayInfo{},
+		Missing: []string{},
+	}
+
+	// 1️⃣ Serve what we can from the cache
+	var uncached []string
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if info, ok := c.users.Get(id); ok {
+			response.Users = append(response.Users, info)
+			continue
+		}
+		if _, ok := c.missing.Get(id); ok {
+			response.Missing = append(response.Missing, id)
+			continue
+		}
+		uncached = append(uncached, id)
+	}
+	if len(uncached) == 0 {
+		return response, nil
+	}
+
+	// 2️⃣ Fetch the rest in one call, collapsing concurrent identical batches
+	fetched, err := c.fetchShared(uncached)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, info := range fetched.Users {
+		c.users.Set(info.ID, info)
+		response.Users = append(response.Users, info)
+	}
+	for _, id := range fetched.Missing {
+		c.missing.Set(id, true)
+		response.Missing = append(response.Missing, id)
+	}
+
+	return response, nil
+}
+
+// fetchShared ensures only one HTTP request per identical batch is in flight;
+// latecomers wait for and share the first result.
+func (c *UsersClient) fetchShared(ids []string) (*models.ResolveUsersResponse, error) {
+	sorted := append([]string{}, ids...)
+	sort.Strings(sorted)
+	key := strings.Join(sorted, ",")
+
+	c.mu.Lock()
+	if call, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.resp, call.err
+	}
+	call := &resolveCall{done: make(chan struct{})}
+	c.inflight[key] = call
+	c.mu.Unlock()
+
+	call.resp, call.err = c.fetch(ids)
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.resp, call.err
+}
+
+func (c *UsersClient) fetch(ids []string) (*models.ResolveUsersResponse, error) {
+	endpoint := fmt.Sprintf(
+		"%s/api/v1/internal/v1/users?ids=%s",
+		c.baseURL,
+		url.QueryEscape(strings.Join(ids, ",")),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Service-Key", c.serviceKey)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("users-service unreachable: %w", err)
+	}
+	defer res.Body.Close()
+
+	// The endpoint wraps data in the standard APIResponse envelope
+	var envelope struct {
+		Error   bool                        `json:"error"`
+		Message string                      `json:"message"`
+		Data    models.ResolveUsersResponse `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode users-service response: %w", err)
+	}
+	if res.StatusCode != http.StatusOK || envelope.Error {
+		return nil, fmt.Errorf("users-service returned %d: %s", res.StatusCode, envelope.Message)
+	}
+
+	return &envelope.Data, nil
+}
@@ -0,0 +1,76 @@
+package constants
+
+// Capability is a fine-grained permission string checked by
+// middleware.RequirePermission. Roles stay coarse in the JWT; this matrix is
+// the single source of truth for what each role may do.
+type Capability string
+
+const (
+	CapOrgSettingsRead  Capability = "org.settings.read"
+	CapOrgSettingsWrite Capability = "org.settings.write"
+	CapUsersInvite      Capability = "users.invite"
+	CapUsersSuspend     Capability = "users.suspend"
+	CapDocumentsDelete  Capability = "documents.delete"
+)
+
+// RolePermissions maps each role to its capabilities. Maintainers can read
+// org settings (invite expiry, allowed domains, verified domains) but not
+// change them; writes stay owner-only.
+var RolePermissions = map[RoleEnum][]Capability{
+	RoleOwner: {
+		CapOrgSettingsRead,
+		CapOrgSettingsWrite,
+		CapUsersInvite,
+		CapUsersSuspend,
+		CapDocumentsDelete,
+	},
+	RoleMaintainer: {
+		CapOrgSettingsRead,
+		CapUsersInvite,
+		CapUsersSuspend,
+		CapDocumentsDelete,
+	},
+	RoleMember: {},
+}
+
+// AllCapabilities enumerates every known capability; custom org roles may
+// only grant capabilities from this list.
+var AllCapabilities = []Capability{
+	CapOrgSettingsRead,
+	CapOrgSettingsWrite,
+	CapUsersInvite,
+	CapUsersSuspend,
+	CapDocumentsDelete,
+}
+
+// IsBuiltInRole reports whether the name is one of the fixed roles. Built-in
+// roles are immutable; everything else resolves through org_roles.
+func IsBuiltInRole(role string) bool {
+	switch RoleEnum(role) {
+	case RoleOwner, RoleMaintainer, RoleMember:
+		return true
+	}
+	return false
+}
+
+// CapabilityExists reports whether the capability string is known.
+func CapabilityExists(cap string) bool {
+	for _, known := range AllCapabilities {
+		if known == Capability(cap) {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleHasCapability reports whether a built-in role grants the capability.
+// Unknown roles grant nothing here; custom roles resolve via org_roles in
+// AuthMiddleware.
+func RoleHasCapability(role string, cap Capability) bool {
+	for _, granted := range RolePermissions[RoleEnum(role)] {
+		if granted == cap {
+			return true
+		}
+	}
+	return false
+}
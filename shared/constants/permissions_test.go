@@ -0,0 +1,69 @@
+package constants
+
+import "testing"
+
+// The matrix is the single source of truth for role checks; these tests keep
+// it internally consistent so a new capability can't be granted without being
+// registered, and vice versa.
+
+func TestRolePermissionsOnlyGrantKnownCapabilities(t *testing.T) {
+	for role, caps := range RolePermissions {
+		seen := map[Capability]bool{}
+		for _, cap := range caps {
+			if !CapabilityExists(string(cap)) {
+				t.Errorf("role %s grants unregistered capability %q", role, cap)
+			}
+			if seen[cap] {
+				t.Errorf("role %s grants %q twice", role, cap)
+			}
+			seen[cap] = true
+		}
+	}
+}
+
+func TestEveryCapabilityIsGrantedToSomeRole(t *testing.T) {
+	for _, cap := range AllCapabilities {
+		granted := false
+		for role := range RolePermissions {
+			if RoleHasCapability(string(role), cap) {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			t.Errorf("capability %q is registered but no role grants it", cap)
+		}
+	}
+}
+
+func TestOwnerGrantsEverything(t *testing.T) {
+	for _, cap := range AllCapabilities {
+		if !RoleHasCapability(string(RoleOwner), cap) {
+			t.Errorf("owner lacks %q; owners must hold every capability", cap)
+		}
+	}
+}
+
+func TestMaintainerReadsButNeverWritesSettings(t *testing.T) {
+	if !RoleHasCapability(string(RoleMaintainer), CapOrgSettingsRead) {
+		t.Error("maintainer must be able to read org settings")
+	}
+	if RoleHasCapability(string(RoleMaintainer), CapOrgSettingsWrite) {
+		t.Error("maintainer must not be able to write org settings")
+	}
+}
+
+func TestUnknownRolesAndCapabilitiesGrantNothing(t *testing.T) {
+	if RoleHasCapability("member", CapUsersInvite) {
+		t.Error("member unexpectedly holds users.invite")
+	}
+	if RoleHasCapability("custom-analyst", CapOrgSettingsRead) {
+		t.Error("unknown roles must resolve through org_roles, not this matrix")
+	}
+	if CapabilityExists("org.settings.destroy") {
+		t.Error("CapabilityExists accepted an unregistered capability")
+	}
+	if IsBuiltInRole("custom-analyst") {
+		t.Error("custom roles must not report as built-in")
+	}
+}
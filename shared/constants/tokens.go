@@ -0,0 +1,15 @@
+package constants
+
+import "time"
+
+// Token lifetimes used across the auth flows. Keep the email copy in the
+// services in sync when changing these.
+const (
+	VerifyTokenTTL = 1 * time.Hour
+	InviteTokenTTL = 48 * time.Hour
+	ResetTokenTTL  = 1 * time.Hour
+
+	// Minimum gap between invite re-sends to the same user, so bulk resends
+	// can't spam someone who was just (re-)invited.
+	ResendInviteCooldown = 10 * time.Minute
+)
@@ -11,6 +11,7 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
 // NewDB initializes and returns a GORM DB instance.
@@ -22,8 +23,10 @@ func NewDB() (*gorm.DB, error) {
 	dbname := getEnv("DB_NAME", "ai_knowledgebase")
 	sslmode := getEnv("DB_SSL_MODE", "disable")
 
+	// TimeZone=UTC pins the session timezone so now() defaults and
+	// timestamptz round-trips don't depend on the server's locale
 	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC",
 		host, port, user, password, dbname, sslmode,
 	)
 
@@ -37,14 +40,42 @@ func NewDB() (*gorm.DB, error) {
 		},
 	)
 
-	// Initialize GORM with PostgreSQL driver
+	// Initialize GORM with PostgreSQL driver. NowFunc keeps every
+	// GORM-stamped timestamp in UTC so responses serialize as RFC3339 "Z"
+	// regardless of the host's local timezone.
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: newLogger,
+		Logger:  newLogger,
+		NowFunc: func() time.Time { return time.Now().UTC() },
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Optional read replica: reads go to the replica, writes stay on primary.
+	// Either a full DB_REPLICA_DSN or DB_REPLICA_HOST plus optional
+	// DB_REPLICA_* overrides (falling back to the primary's settings).
+	replicaDSN := os.Getenv("DB_REPLICA_DSN")
+	if replicaDSN == "" && os.Getenv("DB_REPLICA_HOST") != "" {
+		replicaDSN = fmt.Sprintf(
+			"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC",
+			getEnv("DB_REPLICA_HOST", host),
+			getEnvInt("DB_REPLICA_PORT", port),
+			getEnv("DB_REPLICA_USER", user),
+			getEnv("DB_REPLICA_PASSWORD", password),
+			getEnv("DB_REPLICA_NAME", dbname),
+			getEnv("DB_REPLICA_SSL_MODE", sslmode),
+		)
+	}
+	if replicaDSN != "" {
+		err = db.Use(dbresolver.Register(dbresolver.Config{
+			Replicas: []gorm.Dialector{postgres.Open(replicaDSN)},
+		}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to register read replica: %w", err)
+		}
+		log.Printf("✅ Read replica registered")
+	}
+
 	// Get generic SQL DB to configure pool & test connection
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -65,6 +96,13 @@ func NewDB() (*gorm.DB, error) {
 	return db, nil
 }
 
+// Primary forces the query to run against the primary database. Use it for
+// reads that must see a just-committed write (read-after-write), where
+// replication lag on the replica could return stale data.
+func Primary(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Write)
+}
+
 // getEnv retrieves a string environment variable or returns a default.
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
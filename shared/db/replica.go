@@ -0,0 +1,58 @@
+package db
+
+import (
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// Read-your-writes tracking: after a user writes, their reads stay on the
+// primary for a short window so replication lag can't hand them back stale
+// data (e.g. a profile edit that doesn't show up on the next fetch). The
+// window is DB_READ_YOUR_WRITES_SECONDS (default 5); 0 disables tracking.
+var (
+	readYourWritesWindow = time.Duration(getEnvInt("DB_READ_YOUR_WRITES_SECONDS", 5)) * time.Second
+
+	// user id -> time of their last write
+	recentWrites sync.Map
+)
+
+// MarkUserWrite records that the user just performed a write, pinning their
+// replica-eligible reads to the primary for the read-your-writes window.
+func MarkUserWrite(userID string) {
+	if userID == "" || readYourWritesWindow <= 0 {
+		return
+	}
+	recentWrites.Store(userID, time.Now().UTC())
+}
+
+func wroteRecently(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	v, ok := recentWrites.Load(userID)
+	if !ok {
+		return false
+	}
+	if time.Since(v.(time.Time)) < readYourWritesWindow {
+		return true
+	}
+	recentWrites.Delete(userID)
+	return false
+}
+
+// Replica routes a read-heavy query (stats, listings, search, org details)
+// to the read replica when one is configured. Pass the requesting user's id
+// so their reads fall back to the primary during the read-your-writes
+// window; pass "" for queries with no meaningful requester. Without a
+// registered replica the clause is a no-op and everything runs on the
+// single connection. Writes and anything inside a transaction always stay
+// on the primary regardless.
+func Replica(conn *gorm.DB, userID string) *gorm.DB {
+	if wroteRecently(userID) {
+		return conn.Clauses(dbresolver.Write)
+	}
+	return conn.Clauses(dbresolver.Read)
+}
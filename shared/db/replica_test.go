@@ -0,0 +1,83 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The dbresolver operation markers Replica stamps onto the statement; the
+// plugin routes on exactly these settings keys.
+const (
+	resolverWrite = "gorm:db_resolver:write"
+	resolverRead  = "gorm:db_resolver:read"
+)
+
+func replicaTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	return conn
+}
+
+func routedTo(t *testing.T, tx *gorm.DB) string {
+	t.Helper()
+	if _, ok := tx.Statement.Settings.Load(resolverWrite); ok {
+		return "primary"
+	}
+	if _, ok := tx.Statement.Settings.Load(resolverRead); ok {
+		return "replica"
+	}
+	return "unmarked"
+}
+
+func TestReplicaRoutesReadsToTheReplica(t *testing.T) {
+	conn := replicaTestDB(t)
+
+	if got := routedTo(t, Replica(conn.Session(&gorm.Session{}), "user-a")); got != "replica" {
+		t.Errorf("plain read routed to %s, want replica", got)
+	}
+	if got := routedTo(t, Replica(conn.Session(&gorm.Session{}), "")); got != "replica" {
+		t.Errorf("requesterless read routed to %s, want replica", got)
+	}
+}
+
+func TestReplicaPinsReadsAfterAWrite(t *testing.T) {
+	conn := replicaTestDB(t)
+
+	MarkUserWrite("writer")
+	t.Cleanup(func() { recentWrites.Delete("writer") })
+
+	if got := routedTo(t, Replica(conn.Session(&gorm.Session{}), "writer")); got != "primary" {
+		t.Errorf("read-your-writes read routed to %s, want primary", got)
+	}
+	// Another user's reads are unaffected by someone else's write
+	if got := routedTo(t, Replica(conn.Session(&gorm.Session{}), "reader")); got != "replica" {
+		t.Errorf("unrelated user's read routed to %s, want replica", got)
+	}
+}
+
+func TestReplicaPinExpiresWithTheWindow(t *testing.T) {
+	conn := replicaTestDB(t)
+
+	// A write older than the window no longer pins the user
+	recentWrites.Store("stale-writer", time.Now().UTC().Add(-readYourWritesWindow-time.Second))
+	if got := routedTo(t, Replica(conn.Session(&gorm.Session{}), "stale-writer")); got != "replica" {
+		t.Errorf("post-window read routed to %s, want replica", got)
+	}
+	if _, still := recentWrites.Load("stale-writer"); still {
+		t.Error("expired write entry was not cleaned up")
+	}
+}
+
+func TestMarkUserWriteIgnoresEmptyUser(t *testing.T) {
+	MarkUserWrite("")
+	if _, ok := recentWrites.Load(""); ok {
+		t.Error("an empty user id was tracked")
+	}
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Managed Postgres does brief failovers; every query in flight during those
+// few seconds fails with a connection error that would otherwise surface to
+// users. Retry re-runs operations that hit a transient error class with
+// bounded, jittered backoff. It is only safe for read-only queries and
+// writes the caller knows are idempotent — non-idempotent writes must never
+// go through here, since a "failed" attempt may in fact have committed.
+
+var (
+	retryAttempts  = envInt("DB_RETRY_ATTEMPTS", 3)
+	retryBaseDelay = time.Duration(envInt("DB_RETRY_BASE_DELAY_MS", 100)) * time.Millisecond
+
+	// total operations that needed at least one retry, for ops dashboards
+	retriedOps atomic.Int64
+)
+
+func envInt(key string, fallback int) int {
+	if v := os.Getenv(key); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+// Postgres SQLSTATE classes that are safe to retry: serialization/deadlock
+// conflicts, connection exhaustion and shutdown/failover states.
+var transientPgCodes = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"53300": true, // too_many_connections
+	"57P01": true, // admin_shutdown (failover kicking connections)
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now (standby still starting)
+	"08000": true, // connection_exception
+	"08006": true, // connection_failure
+}
+
+// IsTransient reports whether err looks like a short-lived infrastructure
+// failure worth retrying rather than a query or data problem.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return transientPgCodes[pgErr.Code]
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	// Driver-level failures during a failover don't always carry a SQLSTATE
+	msg := err.Error()
+	for _, marker := range []string{
+		"connection refused",
+		"connection reset",
+		"broken pipe",
+		"unexpected EOF",
+		"conn closed",
+	} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Retry runs op, re-running it on transient errors with jittered exponential
+// backoff. Use for read-only queries only; for writes see RetryIdempotent.
+func Retry(op func() error) error {
+	var err error
+	for attempt := 0; attempt < retryAttempts; attempt++ {
+		if attempt > 0 {
+			// full jitter: 0..base*2^(attempt-1), so a thundering herd after
+			// a failover spreads out
+			backoff := retryBaseDelay << (attempt - 1)
+			time.Sleep(time.Duration(rand.Int63n(int64(backoff) + 1)))
+		}
+		if err = op(); err == nil || !IsTransient(err) {
+			if attempt > 0 {
+				retriedOps.Add(1)
+			}
+			return err
+		}
+	}
+	retriedOps.Add(1)
+	return err
+}
+
+// RetryIdempotent is Retry for writes. The separate name forces the caller
+// to assert the operation is safe to re-run (e.g. an UPSERT keyed on a
+// natural id); plain INSERTs and counters must not use it.
+func RetryIdempotent(op func() error) error {
+	return Retry(op)
+}
+
+// RetriedOperations returns how many operations needed at least one retry
+// since the process started.
+func RetriedOperations() int64 {
+	return retriedOps.Load()
+}
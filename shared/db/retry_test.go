@@ -0,0 +1,95 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fastRetry drops the backoff base so failure-path tests don't sleep.
+func fastRetry(t *testing.T) {
+	t.Helper()
+	saved := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = saved })
+}
+
+func TestIsTransientClassifiesErrors(t *testing.T) {
+	transient := []error{
+		&pgconn.PgError{Code: "40001"}, // serialization_failure
+		&pgconn.PgError{Code: "53300"}, // too_many_connections
+		&pgconn.PgError{Code: "57P01"}, // failover kicking connections
+		errors.New("dial tcp 10.0.0.1:5432: connect: connection refused"),
+		errors.New("read tcp: connection reset by peer"),
+	}
+	for _, err := range transient {
+		if !IsTransient(err) {
+			t.Errorf("IsTransient(%v) = false, want true", err)
+		}
+	}
+
+	permanent := []error{
+		nil,
+		&pgconn.PgError{Code: "23505"}, // unique_violation: a data problem
+		errors.New("record not found"),
+	}
+	for _, err := range permanent {
+		if IsTransient(err) {
+			t.Errorf("IsTransient(%v) = true, want false", err)
+		}
+	}
+}
+
+func TestRetryRecoversFromTransientFailures(t *testing.T) {
+	fastRetry(t)
+	before := RetriedOperations()
+
+	calls := 0
+	err := Retry(func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Retry failed after the backend recovered: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("op ran %d times, want 3", calls)
+	}
+	if RetriedOperations() != before+1 {
+		t.Errorf("retried-operations metric did not count the recovery")
+	}
+}
+
+func TestRetryDoesNotRetryPermanentErrors(t *testing.T) {
+	fastRetry(t)
+
+	calls := 0
+	err := Retry(func() error {
+		calls++
+		return errors.New("record not found")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("permanent error: calls=%d err=%v, want a single failed attempt", calls, err)
+	}
+}
+
+func TestRetryGivesUpAfterBoundedAttempts(t *testing.T) {
+	fastRetry(t)
+
+	calls := 0
+	err := Retry(func() error {
+		calls++
+		return errors.New("connection reset")
+	})
+	if err == nil {
+		t.Fatal("a persistent outage was reported as success")
+	}
+	if calls != retryAttempts {
+		t.Errorf("op ran %d times, want the %d-attempt bound", calls, retryAttempts)
+	}
+}
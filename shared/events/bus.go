@@ -0,0 +1,83 @@
+package events
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"sync"
+)
+
+var (
+	defaultBus  Bus
+	defaultOnce sync.Once
+
+	dbMu     sync.Mutex
+	sharedDB *sql.DB
+)
+
+// openDB lazily opens one plain database/sql handle for NOTIFY publishes.
+func openDB(dsn string) (*sql.DB, error) {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+
+	if sharedDB != nil {
+		return sharedDB, nil
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	sharedDB = db
+	return sharedDB, nil
+}
+
+// postgresDSN mirrors the env vars NewDB reads, in lib/pq key=value form.
+func postgresDSN() string {
+	get := func(key, fallback string) string {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+		return fallback
+	}
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		get("DB_HOST", "localhost"),
+		get("DB_PORT", "5432"),
+		get("DB_USER", "postgres"),
+		get("DB_PASSWORD", "root"),
+		get("DB_NAME", "ai_knowledgebase"),
+		get("DB_SSL_MODE", "disable"),
+	)
+}
+
+// Default returns the process-wide bus, selected by EVENT_BUS:
+// "postgres" → LISTEN/NOTIFY (cross-service), anything else → in-process.
+// A failed Postgres setup falls back to in-process with a warning rather
+// than taking the service down.
+func Default() Bus {
+	defaultOnce.Do(func() {
+		if os.Getenv("EVENT_BUS") == "postgres" {
+			bus, err := NewPostgresBus(postgresDSN())
+			if err == nil {
+				defaultBus = bus
+				return
+			}
+			fmt.Printf("[WARN] postgres event bus unavailable, falling back to in-process: %v\n", err)
+		}
+		defaultBus = NewInProcessBus()
+	})
+	return defaultBus
+}
+
+// Publish emits on the default bus; publishing never fails the caller's
+// request — errors are logged.
+func Publish(e Event) {
+	if err := Default().Publish(e); err != nil {
+		fmt.Printf("[WARN] failed to publish event %s: %v\n", e.Type, err)
+	}
+}
+
+// Subscribe registers a handler on the default bus.
+func Subscribe(eventType string, handler Handler) {
+	Default().Subscribe(eventType, handler)
+}
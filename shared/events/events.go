@@ -0,0 +1,63 @@
+// Package events is the async "something happened" channel between features:
+// webhook dispatch, audit, usage counters and cache invalidation subscribe
+// here instead of polling the DB or being bolted inline into services.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Event is the envelope every publisher emits. Payload carries event-specific
+// details; keep it JSON-serializable so the Postgres transport can carry it.
+type Event struct {
+	Type       string                 `json:"type"` // e.g. user.invited
+	OrgID      string                 `json:"org_id,omitempty"`
+	ActorID    string                 `json:"actor_id,omitempty"`
+	Payload    map[string]interface{} `json:"payload,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
+
+// Handler processes one event. Returning an error triggers a retry
+// (at-least-once delivery); handlers must therefore be idempotent.
+type Handler func(Event) error
+
+type Publisher interface {
+	Publish(Event) error
+}
+
+type Subscriber interface {
+	// Subscribe registers a handler for an event type. "*" receives every
+	// event (webhook dispatchers, audit sinks).
+	Subscribe(eventType string, handler Handler)
+}
+
+type Bus interface {
+	Publisher
+	Subscriber
+}
+
+// Delivery policy: each handler gets maxDeliveryAttempts tries with linear
+// backoff; after that the event goes to the dead-letter log so an operator
+// can replay it manually.
+const maxDeliveryAttempts = 3
+
+var retryBackoff = 100 * time.Millisecond
+
+// deliver runs one handler with retries and dead-letters on final failure.
+func deliver(handler Handler, e Event) {
+	var err error
+	for attempt := 1; attempt <= maxDeliveryAttempts; attempt++ {
+		if err = handler(e); err == nil {
+			return
+		}
+		if attempt < maxDeliveryAttempts {
+			time.Sleep(time.Duration(attempt) * retryBackoff)
+		}
+	}
+
+	payload, _ := json.Marshal(e)
+	fmt.Printf("[DEAD-LETTER] event delivery failed after %d attempts: type=%s err=%v event=%s\n",
+		maxDeliveryAttempts, e.Type, err, payload)
+}
@@ -0,0 +1,40 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// InProcessBus dispatches events to subscribers in the same binary. Handlers
+// run on their own goroutines, so a slow webhook can't block the request that
+// published the event.
+type InProcessBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewInProcessBus() *InProcessBus {
+	return &InProcessBus{handlers: make(map[string][]Handler)}
+}
+
+func (b *InProcessBus) Publish(e Event) error {
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	handlers := append([]Handler{}, b.handlers[e.Type]...)
+	handlers = append(handlers, b.handlers["*"]...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		go deliver(handler, e)
+	}
+	return nil
+}
+
+func (b *InProcessBus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+	b.mu.Unlock()
+}
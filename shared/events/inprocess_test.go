@@ -0,0 +1,156 @@
+package events
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// captureStdout runs fn with os.Stdout redirected and returns what it printed;
+// the bus logs retries and dead-letters with fmt.Printf like the rest of the
+// shared packages.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe failed: %v", err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	fn()
+
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+func fastBackoff(t *testing.T) {
+	t.Helper()
+	orig := retryBackoff
+	retryBackoff = time.Millisecond
+	t.Cleanup(func() { retryBackoff = orig })
+}
+
+func TestInProcessBusDeliversToMatchingAndWildcardHandlers(t *testing.T) {
+	bus := NewInProcessBus()
+
+	invited := make(chan Event, 1)
+	everything := make(chan Event, 1)
+	other := make(chan Event, 1)
+
+	bus.Subscribe("user.invited", func(e Event) error { invited <- e; return nil })
+	bus.Subscribe("*", func(e Event) error { everything <- e; return nil })
+	bus.Subscribe("password.reset", func(e Event) error { other <- e; return nil })
+
+	if err := bus.Publish(Event{Type: "user.invited", OrgID: "org-1"}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+
+	select {
+	case e := <-invited:
+		if e.OrgID != "org-1" {
+			t.Errorf("handler saw org %q, want org-1", e.OrgID)
+		}
+		if e.OccurredAt.IsZero() {
+			t.Error("OccurredAt was not stamped on publish")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("typed handler never received the event")
+	}
+	select {
+	case <-everything:
+	case <-time.After(time.Second):
+		t.Fatal("wildcard handler never received the event")
+	}
+	select {
+	case <-other:
+		t.Fatal("handler for a different type received the event")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestDeliveryRetriesUntilTheHandlerSucceeds(t *testing.T) {
+	fastBackoff(t)
+	bus := NewInProcessBus()
+
+	var attempts atomic.Int32
+	done := make(chan struct{})
+	bus.Subscribe("user.verified", func(e Event) error {
+		if attempts.Add(1) < 2 {
+			return io.ErrUnexpectedEOF
+		}
+		close(done)
+		return nil
+	})
+
+	if err := bus.Publish(Event{Type: "user.verified"}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handler never succeeded")
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("handler ran %d times, want 2 (one failure, one success)", got)
+	}
+}
+
+func TestDeliveryDeadLettersAfterMaxAttempts(t *testing.T) {
+	fastBackoff(t)
+	bus := NewInProcessBus()
+
+	var attempts atomic.Int32
+	finished := make(chan struct{})
+	bus.Subscribe("password.reset", func(e Event) error {
+		if attempts.Add(1) == maxDeliveryAttempts {
+			defer close(finished)
+		}
+		return io.ErrUnexpectedEOF
+	})
+
+	out := captureStdout(t, func() {
+		if err := bus.Publish(Event{Type: "password.reset", OrgID: "org-dead"}); err != nil {
+			t.Errorf("publish failed: %v", err)
+		}
+		select {
+		case <-finished:
+		case <-time.After(time.Second):
+			t.Error("handler never exhausted its attempts")
+		}
+		// The dead-letter line is printed after the final attempt returns
+		time.Sleep(20 * time.Millisecond)
+	})
+
+	if got := attempts.Load(); got != maxDeliveryAttempts {
+		t.Errorf("handler ran %d times, want %d", got, maxDeliveryAttempts)
+	}
+	if !strings.Contains(out, "[DEAD-LETTER]") || !strings.Contains(out, "password.reset") {
+		t.Errorf("dead-letter log missing or incomplete: %q", out)
+	}
+	if !strings.Contains(out, "org-dead") {
+		t.Errorf("dead-letter log does not carry the event payload: %q", out)
+	}
+}
+
+func TestPublishDoesNotBlockOnSlowHandlers(t *testing.T) {
+	bus := NewInProcessBus()
+
+	release := make(chan struct{})
+	bus.Subscribe("user.invited", func(e Event) error { <-release; return nil })
+	t.Cleanup(func() { close(release) })
+
+	start := time.Now()
+	if err := bus.Publish(Event{Type: "user.invited"}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("publish blocked for %s on a stalled handler", elapsed)
+	}
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// eventChannel is the pg_notify channel every service shares.
+const eventChannel = "app_events"
+
+// PostgresBus carries events over Postgres LISTEN/NOTIFY, so multi-binary
+// deployments (users-service + chats-service) see each other's events without
+// a separate broker. NOTIFY payloads are capped at ~8KB; keep Payload small.
+type PostgresBus struct {
+	dsn      string
+	listener *pq.Listener
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewPostgresBus(dsn string) (*PostgresBus, error) {
+	bus := &PostgresBus{
+		dsn:      dsn,
+		handlers: make(map[string][]Handler),
+	}
+
+	bus.listener = pq.NewListener(dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			fmt.Printf("[WARN] event listener state change: %v\n", err)
+		}
+	})
+	if err := bus.listener.Listen(eventChannel); err != nil {
+		return nil, fmt.Errorf("failed to LISTEN on %s: %w", eventChannel, err)
+	}
+
+	go bus.receive()
+	return bus, nil
+}
+
+func (b *PostgresBus) Publish(e Event) error {
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now().UTC()
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	// Reuse the listener's connection pool via a plain exec
+	db, err := openDB(b.dsn)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("SELECT pg_notify($1, $2)", eventChannel, string(payload))
+	return err
+}
+
+func (b *PostgresBus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+	b.mu.Unlock()
+}
+
+// receive dispatches incoming notifications to local subscribers with the
+// shared retry/dead-letter policy.
+func (b *PostgresBus) receive() {
+	for notification := range b.listener.Notify {
+		if notification == nil {
+			// nil signals a reconnect; missed events are the price of
+			// NOTIFY (no persistence) — subscribers needing durability
+			// should also reconcile from the DB
+			continue
+		}
+
+		var e Event
+		if err := json.Unmarshal([]byte(notification.Extra), &e); err != nil {
+			fmt.Printf("[WARN] dropping malformed event payload: %v\n", err)
+			continue
+		}
+
+		b.mu.RLock()
+		handlers := append([]Handler{}, b.handlers[e.Type]...)
+		handlers = append(handlers, b.handlers["*"]...)
+		b.mu.RUnlock()
+
+		for _, handler := range handlers {
+			go deliver(handler, e)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Package features holds deployment-level feature toggles read from the
+// environment once at startup. Unlike shared/flags — runtime kill switches
+// that are DB-backed, per-org and answer 503 — a disabled feature simply
+// doesn't exist in this deployment: its routes answer 404 as if they were
+// never registered. Flip a FEATURE_* env var and restart to change the set.
+package features
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// Feature names. The matching env var is FEATURE_<NAME uppercased>, e.g.
+// FEATURE_INVITE_LINKS=false. Everything defaults to enabled.
+const (
+	InviteLinks    = "invite_links"     // shareable join links (/join, /invite-links)
+	DomainAutoJoin = "domain_auto_join" // verified-domain auto-join (/join-by-domain)
+	Impersonation  = "impersonation"    // support impersonation (/admin/impersonate)
+)
+
+var known = []string{InviteLinks, DomainAutoJoin, Impersonation}
+
+var (
+	loadOnce sync.Once
+	active   map[string]bool
+)
+
+// Load reads the FEATURE_* env vars into the active set. Called once at
+// startup (Enabled falls back to a lazy load, so ordering mistakes degrade
+// gracefully rather than panic).
+func Load() {
+	loadOnce.Do(func() {
+		active = make(map[string]bool, len(known))
+		for _, name := range known {
+			active[name] = envEnabled(name)
+		}
+	})
+}
+
+// envEnabled reads FEATURE_<NAME>; only an explicit false disables.
+func envEnabled(name string) bool {
+	v := strings.ToLower(os.Getenv("FEATURE_" + strings.ToUpper(name)))
+	return v != "false" && v != "0"
+}
+
+// Enabled reports whether a feature exists in this deployment. Unknown names
+// are enabled, so a stale gate fails open rather than blanking a route.
+func Enabled(name string) bool {
+	Load()
+	if enabled, ok := active[name]; ok {
+		return enabled
+	}
+	return true
+}
+
+// Active returns a copy of the flag set, for the debug endpoint.
+func Active() map[string]bool {
+	Load()
+	out := make(map[string]bool, len(active))
+	for name, enabled := range active {
+		out[name] = enabled
+	}
+	return out
+}
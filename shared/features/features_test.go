@@ -0,0 +1,60 @@
+package features
+
+import (
+	"sync"
+	"testing"
+)
+
+// reload clears the once-loaded flag set so each test reads its own env.
+func reload() {
+	loadOnce = sync.Once{}
+	active = nil
+}
+
+func TestEnabledDefaultsToOn(t *testing.T) {
+	reload()
+	t.Cleanup(reload)
+
+	for _, name := range known {
+		if !Enabled(name) {
+			t.Errorf("feature %s is off without any env override", name)
+		}
+	}
+}
+
+func TestEnabledHonorsExplicitDisable(t *testing.T) {
+	t.Setenv("FEATURE_IMPERSONATION", "false")
+	t.Setenv("FEATURE_DOMAIN_AUTO_JOIN", "0")
+	reload()
+	t.Cleanup(reload)
+
+	if Enabled(Impersonation) {
+		t.Error("FEATURE_IMPERSONATION=false left the feature on")
+	}
+	if Enabled(DomainAutoJoin) {
+		t.Error("FEATURE_DOMAIN_AUTO_JOIN=0 left the feature on")
+	}
+	if !Enabled(InviteLinks) {
+		t.Error("an untouched feature was switched off")
+	}
+}
+
+func TestEnabledFailsOpenForUnknownNames(t *testing.T) {
+	reload()
+	t.Cleanup(reload)
+
+	if !Enabled("retired_feature_gate") {
+		t.Error("an unknown feature name must fail open")
+	}
+}
+
+func TestActiveReturnsACopy(t *testing.T) {
+	reload()
+	t.Cleanup(reload)
+
+	snapshot := Active()
+	snapshot[InviteLinks] = false
+	if !Enabled(InviteLinks) {
+		t.Error("mutating the Active snapshot changed the live flag set")
+	}
+}
@@ -0,0 +1,155 @@
+// Package flags is a lightweight feature-flag facility for maintenance mode
+// and per-endpoint kill switches. Values live in the feature_flags table
+// (global row + optional per-org overrides) with FLAG_* env vars as the
+// fallback when no row exists; evaluations are cached with a short TTL so
+// the DB isn't hit per request and flips take effect without a restart.
+package flags
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/cache"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Known flags. Anything can be flagged, but these are the ones middleware
+// and services actually check.
+const (
+	SignupEnabled  = "signup_enabled"
+	InvitesEnabled = "invites_enabled"
+	PredictEnabled = "predict_enabled"
+)
+
+// flagCacheTTL reads FLAG_CACHE_TTL_SECONDS (default 10). Short on purpose —
+// it bounds how long a flip takes to propagate.
+func flagCacheTTL() time.Duration {
+	if raw := os.Getenv("FLAG_CACHE_TTL_SECONDS"); raw != "" {
+		if seconds, err := strconv.Atoi(raw); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return 10 * time.Second
+}
+
+var (
+	db          *gorm.DB
+	evaluations = cache.New[bool](flagCacheTTL())
+)
+
+// Init hands the package its DB handle; call once at startup. Before Init
+// (and in processes without a DB) only the env fallback applies.
+func Init(database *gorm.DB) {
+	db = database
+}
+
+// envDefault resolves a flag with no DB row: FLAG_SIGNUP_ENABLED=false style
+// env vars win, otherwise flags default to enabled.
+func envDefault(name string) bool {
+	if raw := os.Getenv("FLAG_" + strings.ToUpper(name)); raw != "" {
+		if enabled, err := strconv.ParseBool(raw); err == nil {
+			return enabled
+		}
+	}
+	return true
+}
+
+func evaluationKey(name, orgID string) string {
+	return name + "|" + orgID
+}
+
+// Enabled evaluates a flag for an organization ("" = no org context, e.g.
+// public signup). Per-org overrides beat the global row, which beats the env
+// default. Results are cached for flagCacheTTL.
+func Enabled(name, orgID string) bool {
+	key := evaluationKey(name, orgID)
+	if enabled, ok := evaluations.Get(key); ok {
+		return enabled
+	}
+
+	enabled := evaluate(name, orgID)
+	evaluations.Set(key, enabled)
+	return enabled
+}
+
+func evaluate(name, orgID string) bool {
+	if db == nil {
+		return envDefault(name)
+	}
+
+	// 1️⃣ Per-org override
+	if orgID != "" {
+		var flag models.FeatureFlag
+		err := db.Where("name = ? AND organization_id = ?", name, orgID).First(&flag).Error
+		if err == nil {
+			return flag.Enabled
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			// Fail open on DB errors: a flaky flags table must not take
+			// every gated endpoint down with it
+			return envDefault(name)
+		}
+	}
+
+	// 2️⃣ Global row
+	var flag models.FeatureFlag
+	err := db.Where("name = ? AND organization_id IS NULL", name).First(&flag).Error
+	if err == nil {
+		return flag.Enabled
+	}
+
+	// 3️⃣ Env fallback
+	return envDefault(name)
+}
+
+// Set upserts a flag (orgID nil = global) and drops the cached evaluation so
+// the flip is visible locally right away; other instances converge within the
+// cache TTL.
+func Set(name string, orgID *uuid.UUID, enabled bool) error {
+	if db == nil {
+		return errors.New("feature flags not initialized with a database")
+	}
+
+	query := db.Model(&models.FeatureFlag{}).Where("name = ?", name)
+	cacheOrgID := ""
+	if orgID == nil {
+		query = query.Where("organization_id IS NULL")
+	} else {
+		query = query.Where("organization_id = ?", *orgID)
+		cacheOrgID = orgID.String()
+	}
+
+	result := query.Update("enabled", enabled)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		flag := models.FeatureFlag{Name: name, OrganizationID: orgID, Enabled: enabled}
+		if err := db.Create(&flag).Error; err != nil {
+			return err
+		}
+	}
+
+	evaluations.Invalidate(evaluationKey(name, cacheOrgID))
+	// A global flip also affects orgs without overrides; their cached
+	// evaluations age out within the TTL
+	return nil
+}
+
+// List returns every flag row, overrides included, for the admin endpoint.
+func List() ([]models.FeatureFlag, error) {
+	if db == nil {
+		return nil, errors.New("feature flags not initialized with a database")
+	}
+
+	var all []models.FeatureFlag
+	if err := db.Order("name ASC, organization_id ASC NULLS FIRST").Find(&all).Error; err != nil {
+		return nil, err
+	}
+	return all, nil
+}
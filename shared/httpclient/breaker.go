@@ -0,0 +1,132 @@
+package httpclient
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Circuit breaker states.
+const (
+	stateClosed   = "closed"
+	stateOpen     = "open"
+	stateHalfOpen = "half-open"
+)
+
+// breakerFailureThreshold consecutive failures open a host's circuit;
+// breakerOpenDuration later one probe request is let through (half-open).
+const (
+	breakerFailureThreshold = 5
+	breakerOpenDuration     = 30 * time.Second
+)
+
+// BreakerStats is a point-in-time snapshot of one host's circuit.
+type BreakerStats struct {
+	State     string `json:"state"`
+	Successes int64  `json:"successes"`
+	Failures  int64  `json:"failures"`
+	Rejected  int64  `json:"rejected"` // requests short-circuited while open
+}
+
+// hostBreaker tracks consecutive failures for a single host.
+type hostBreaker struct {
+	consecutiveFailures int
+	openedAt            time.Time
+	probing             bool // a half-open probe is in flight
+
+	successes int64
+	failures  int64
+	rejected  int64
+}
+
+func (b *hostBreaker) state(now time.Time) string {
+	if b.consecutiveFailures < breakerFailureThreshold {
+		return stateClosed
+	}
+	if now.Sub(b.openedAt) >= breakerOpenDuration {
+		return stateHalfOpen
+	}
+	return stateOpen
+}
+
+// breakerGroup holds one breaker per destination host.
+type breakerGroup struct {
+	mu       sync.Mutex
+	breakers map[string]*hostBreaker
+}
+
+func newBreakerGroup() *breakerGroup {
+	return &breakerGroup{breakers: make(map[string]*hostBreaker)}
+}
+
+func (g *breakerGroup) get(host string) *hostBreaker {
+	if b, ok := g.breakers[host]; ok {
+		return b
+	}
+	b := &hostBreaker{}
+	g.breakers[host] = b
+	return b
+}
+
+// allow reports whether a request to host may proceed. While open, everything
+// is rejected; half-open lets exactly one probe through at a time.
+func (g *breakerGroup) allow(host string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	b := g.get(host)
+	switch b.state(time.Now()) {
+	case stateOpen:
+		b.rejected++
+		return fmt.Errorf("circuit open for %s (retry after %s)", host, breakerOpenDuration)
+	case stateHalfOpen:
+		if b.probing {
+			b.rejected++
+			return fmt.Errorf("circuit half-open for %s, probe in flight", host)
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record feeds a request outcome back into host's breaker.
+func (g *breakerGroup) record(host string, success bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	b := g.get(host)
+	b.probing = false
+	if success {
+		b.successes++
+		b.consecutiveFailures = 0
+		return
+	}
+
+	b.failures++
+	b.consecutiveFailures++
+	if b.consecutiveFailures == breakerFailureThreshold {
+		b.openedAt = time.Now()
+	} else if b.consecutiveFailures > breakerFailureThreshold {
+		// Failed probe: restart the open window
+		b.openedAt = time.Now()
+		b.consecutiveFailures = breakerFailureThreshold
+	}
+}
+
+// Stats snapshots every host's breaker, for debug endpoints.
+func (g *breakerGroup) Stats() map[string]BreakerStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	stats := make(map[string]BreakerStats, len(g.breakers))
+	for host, b := range g.breakers {
+		stats[host] = BreakerStats{
+			State:     b.state(now),
+			Successes: b.successes,
+			Failures:  b.failures,
+			Rejected:  b.rejected,
+		}
+	}
+	return stats
+}
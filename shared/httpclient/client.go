@@ -0,0 +1,198 @@
+// Package httpclient is the standard HTTP client for inter-service calls
+// (introspection, user lookup, webhooks). It bakes in what every hand-rolled
+// http.Client kept forgetting: real timeouts, keep-alive tuning, request-ID
+// propagation, retry with jittered backoff on transient failures, and a
+// per-host circuit breaker so one dead dependency doesn't tie up every
+// goroutine in the process.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+// requestIDKey carries the inbound request ID through context so outbound
+// calls tag the same X-Request-ID and traces line up across services.
+type contextKey string
+
+const requestIDKey contextKey = "request_id"
+
+// WithRequestID returns a context carrying the request ID for propagation.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFrom extracts a propagated request ID, if any.
+func RequestIDFrom(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// Config tunes a Client; zero values get sane defaults from New.
+type Config struct {
+	// Timeout bounds one attempt end-to-end (default 10s).
+	Timeout time.Duration
+	// MaxRetries is extra attempts after the first (default 2).
+	MaxRetries int
+	// RetryBackoff is the base backoff, multiplied per attempt and jittered
+	// (default 200ms).
+	RetryBackoff time.Duration
+	// RetryAllMethods opts non-idempotent methods (POST, PATCH) into retries.
+	// Leave false unless the receiving endpoint deduplicates.
+	RetryAllMethods bool
+}
+
+// Client wraps http.Client with the shared retry/breaker policy.
+type Client struct {
+	httpClient *http.Client
+	config     Config
+	breakers   *breakerGroup
+}
+
+// New builds a Client. Connection timeouts live in the transport; Config
+// covers the per-attempt deadline and retry policy.
+func New(cfg Config) *Client {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 2
+	}
+	if cfg.RetryBackoff == 0 {
+		cfg.RetryBackoff = 200 * time.Millisecond
+	}
+
+	transport := &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: cfg.Timeout,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+	}
+
+	return &Client{
+		httpClient: &http.Client{Timeout: cfg.Timeout, Transport: transport},
+		config:     cfg,
+		breakers:   newBreakerGroup(),
+	}
+}
+
+// idempotent per RFC 9110 §9.2.2 — safe to retry without coordination.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryableStatus marks responses worth another attempt: the dependency (or
+// its load balancer) said "not right now", not "you are wrong".
+func retryableStatus(code int) bool {
+	return code == http.StatusBadGateway || code == http.StatusServiceUnavailable
+}
+
+// retryableError covers transport-level failures (connection reset, refused,
+// timeouts) where the request may never have been processed.
+func retryableError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	// Connection resets surface as *net.OpError wrapped in *url.Error, which
+	// implements net.Error; syscall-level resets that don't are still worth
+	// one more try
+	return errors.Is(err, net.ErrClosed)
+}
+
+// Do sends the request with request-ID propagation, the circuit breaker and
+// the retry policy applied. The caller owns the response body as usual.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if id := RequestIDFrom(req.Context()); id != "" && req.Header.Get("X-Request-ID") == "" {
+		req.Header.Set("X-Request-ID", id)
+	}
+
+	host := req.URL.Host
+	canRetry := idempotent(req.Method) || c.config.RetryAllMethods
+	// Retrying needs a rewindable body; GetBody is set automatically for
+	// common body types (bytes, strings readers)
+	if req.Body != nil && req.GetBody == nil {
+		canRetry = false
+	}
+
+	attempts := 1
+	if canRetry {
+		attempts += c.config.MaxRetries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			// Linear backoff with up-to-50% jitter so synchronized callers
+			// don't stampede a recovering host
+			backoff := time.Duration(attempt) * c.config.RetryBackoff
+			backoff += time.Duration(rand.Int63n(int64(backoff) / 2))
+			select {
+			case <-time.After(backoff):
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+
+			if req.GetBody != nil {
+				rewound, err := req.GetBody()
+				if err != nil {
+					return nil, fmt.Errorf("failed to rewind request body for retry: %w", err)
+				}
+				req.Body = rewound
+			}
+		}
+
+		if err := c.breakers.allow(host); err != nil {
+			return nil, err
+		}
+
+		res, err := c.httpClient.Do(req)
+		if err != nil {
+			c.breakers.record(host, false)
+			lastErr = err
+			if canRetry && retryableError(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if retryableStatus(res.StatusCode) {
+			c.breakers.record(host, false)
+			lastErr = fmt.Errorf("%s %s returned %d", req.Method, req.URL, res.StatusCode)
+			if canRetry && attempt < attempts-1 {
+				res.Body.Close()
+				continue
+			}
+			// Out of attempts: hand the response back rather than swallow it
+			return res, nil
+		}
+
+		// 4xx/5xx other than 502/503 are the dependency answering, not dying —
+		// they count as breaker successes
+		c.breakers.record(host, true)
+		return res, nil
+	}
+
+	return nil, lastErr
+}
+
+// BreakerStats snapshots the per-host circuit breakers, for debug endpoints.
+func (c *Client) BreakerStats() map[string]BreakerStats {
+	return c.breakers.Stats()
+}
@@ -0,0 +1,198 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fastClient() *Client {
+	return New(Config{Timeout: time.Second, MaxRetries: 2, RetryBackoff: time.Millisecond})
+}
+
+// flakyServer fails the first n requests with status, then answers 200.
+func flakyServer(t *testing.T, n int, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= int32(n) {
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestDoRetriesTransient5xxOnIdempotentRequests(t *testing.T) {
+	server, calls := flakyServer(t, 2, http.StatusServiceUnavailable)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	res, err := fastClient().Do(req)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200 after retries", res.StatusCode)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("server saw %d attempts, want 3", got)
+	}
+}
+
+func TestDoDoesNotRetryNonIdempotentMethods(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusBadGateway)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{}`))
+	res, err := fastClient().Do(req)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	// The 502 comes back to the caller instead of being retried
+	if res.StatusCode != http.StatusBadGateway {
+		t.Errorf("status = %d, want the original 502", res.StatusCode)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("server saw %d attempts, want 1 for a POST", got)
+	}
+}
+
+func TestDoRetriesPostWhenExplicitlyOptedIn(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusServiceUnavailable)
+
+	client := New(Config{Timeout: time.Second, MaxRetries: 2, RetryBackoff: time.Millisecond, RetryAllMethods: true})
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"idempotency_key":"k"}`))
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200 after an opted-in retry", res.StatusCode)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server saw %d attempts, want 2", got)
+	}
+}
+
+func TestDoDoesNotRetryPlainClientErrors(t *testing.T) {
+	server, calls := flakyServer(t, 5, http.StatusNotFound)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	res, err := fastClient().Do(req)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404 passed through", res.StatusCode)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("server saw %d attempts, want 1 — a 404 is an answer, not an outage", got)
+	}
+}
+
+func TestDoPropagatesRequestIDFromContext(t *testing.T) {
+	var seen atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen.Store(r.Header.Get("X-Request-ID"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := WithRequestID(context.Background(), "req-abc-123")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	res, err := fastClient().Do(req)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	res.Body.Close()
+
+	if got, _ := seen.Load().(string); got != "req-abc-123" {
+		t.Errorf("X-Request-ID = %q, want req-abc-123", got)
+	}
+}
+
+func TestBreakerOpensAfterConsecutiveFailuresAndRejects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	client := New(Config{Timeout: time.Second, RetryBackoff: time.Millisecond})
+	// Zero MaxRetries means "default" to New, so disable retries directly:
+	// each request should be a single breaker-visible attempt
+	client.config.MaxRetries = 0
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	for i := 0; i < breakerFailureThreshold; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		if res, err := client.Do(req); err == nil {
+			res.Body.Close()
+		}
+	}
+
+	stats := client.BreakerStats()[host]
+	if stats.State != stateOpen {
+		t.Fatalf("breaker state = %s after %d failures, want open", stats.State, breakerFailureThreshold)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, err := client.Do(req); err == nil || !strings.Contains(err.Error(), "circuit open") {
+		t.Errorf("open circuit did not short-circuit the request: %v", err)
+	}
+	if got := client.BreakerStats()[host].Rejected; got != 1 {
+		t.Errorf("rejected counter = %d, want 1", got)
+	}
+}
+
+func TestBreakerRecoversAfterASuccessfulProbe(t *testing.T) {
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if healthy.Load() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	client := New(Config{Timeout: time.Second, RetryBackoff: time.Millisecond})
+	client.config.MaxRetries = 0
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	for i := 0; i < breakerFailureThreshold; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		if res, err := client.Do(req); err == nil {
+			res.Body.Close()
+		}
+	}
+
+	// Age the open window so the next request is the half-open probe
+	client.breakers.mu.Lock()
+	client.breakers.get(host).openedAt = time.Now().Add(-breakerOpenDuration - time.Second)
+	client.breakers.mu.Unlock()
+	healthy.Store(true)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("half-open probe failed: %v", err)
+	}
+	res.Body.Close()
+
+	if state := client.BreakerStats()[host].State; state != stateClosed {
+		t.Errorf("breaker state = %s after a successful probe, want closed", state)
+	}
+}
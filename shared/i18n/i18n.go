@@ -0,0 +1,97 @@
+// Package i18n holds the message catalog for client-facing API strings.
+// English is the reference locale; other locales translate a subset, and a
+// missing entry falls back to the English one — never to an empty string.
+// Error messages are keyed by the stable codes attached via apperrors, so
+// API clients can also run their own translations off the same codes.
+package i18n
+
+import (
+	"strings"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+)
+
+// DefaultLocale is the reference language every key is guaranteed to have.
+const DefaultLocale = "en"
+
+// catalog maps locale → error code → translated message. The English entries
+// mirror the literal strings the services return, so untranslated responses
+// read identically with or without the catalog in the path.
+var catalog = map[string]map[string]string{
+	"en": {
+		"org_name_taken":       "organization with this name already exists",
+		"invalid_token":        "invalid token",
+		"token_expired":        "token has expired",
+		"link_corrupted":       "this link is corrupted or incomplete, request a new one",
+		"user_already_active":  "user already exists and is active",
+		"user_already_invited": "user has already been invited",
+	},
+	"fr": {
+		"org_name_taken":       "une organisation portant ce nom existe déjà",
+		"invalid_token":        "jeton invalide",
+		"token_expired":        "le jeton a expiré",
+		"link_corrupted":       "ce lien est corrompu ou incomplet, veuillez en demander un nouveau",
+		"user_already_active":  "cet utilisateur existe déjà et est actif",
+		"user_already_invited": "cet utilisateur a déjà été invité",
+	},
+}
+
+// Supported reports whether a locale has a catalog (and email templates).
+func Supported(locale string) bool {
+	_, ok := catalog[Normalize(locale)]
+	return ok
+}
+
+// Normalize lowercases a locale tag, strips any region suffix ("fr-CA" →
+// "fr") and maps empty or unknown values to the default.
+func Normalize(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	if _, ok := catalog[locale]; ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// FromAcceptLanguage picks the first supported locale from an Accept-Language
+// header, honoring the client's listed order (quality weights are ignored —
+// browsers already sort by preference). Empty or all-unsupported → default.
+func FromAcceptLanguage(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		tag := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		if i := strings.IndexAny(tag, "-_"); i > 0 {
+			tag = tag[:i]
+		}
+		if _, ok := catalog[strings.ToLower(tag)]; ok {
+			return strings.ToLower(tag)
+		}
+	}
+	return DefaultLocale
+}
+
+// Message looks up a code in the given locale, falling back to English. The
+// second return is false only when the code is unknown in every locale.
+func Message(locale, code string) (string, bool) {
+	if msg, ok := catalog[Normalize(locale)][code]; ok {
+		return msg, true
+	}
+	msg, ok := catalog[DefaultLocale][code]
+	return msg, ok
+}
+
+// ErrorMessage translates a service error for the client's Accept-Language
+// header. Errors without a code (or with a code missing from the catalog)
+// keep their original English message.
+func ErrorMessage(acceptLanguage string, err error) string {
+	if code := apperrors.Code(err); code != "" {
+		if msg, ok := Message(FromAcceptLanguage(acceptLanguage), code); ok {
+			return msg
+		}
+	}
+	return err.Error()
+}
@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+)
+
+// English is the reference locale: every other locale may only translate
+// codes that exist there, and no entry anywhere may be empty. This walk keeps
+// the catalog honest as locales and codes get added.
+func TestCatalogParity(t *testing.T) {
+	reference, ok := catalog[DefaultLocale]
+	if !ok || len(reference) == 0 {
+		t.Fatalf("catalog has no %s reference locale", DefaultLocale)
+	}
+
+	for locale, messages := range catalog {
+		for code, msg := range messages {
+			if msg == "" {
+				t.Errorf("catalog[%s][%s] is empty; fall back to English instead", locale, code)
+			}
+			if _, ok := reference[code]; !ok {
+				t.Errorf("catalog[%s] translates %q, which %s doesn't define", locale, code, DefaultLocale)
+			}
+		}
+	}
+}
+
+func TestMessageFallsBackToEnglishNeverEmpty(t *testing.T) {
+	for code, want := range catalog[DefaultLocale] {
+		for _, locale := range []string{"fr", "de", "fr-CA", ""} {
+			msg, ok := Message(locale, code)
+			if !ok || msg == "" {
+				t.Errorf("Message(%q, %q) = %q, %v; want a non-empty message", locale, code, msg, ok)
+			}
+		}
+		if msg, _ := Message("de", code); msg != want {
+			t.Errorf("Message(de, %q) = %q, want the English %q", code, msg, want)
+		}
+	}
+
+	if _, ok := Message("en", "no_such_code"); ok {
+		t.Error("Message reported an unknown code as found")
+	}
+}
+
+func TestNormalizeAndAcceptLanguage(t *testing.T) {
+	cases := map[string]string{"fr": "fr", "FR-ca": "fr", "de": "en", "": "en"}
+	for in, want := range cases {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	headers := map[string]string{
+		"fr-CH, fr;q=0.9, en;q=0.8": "fr",
+		"de-DE, de;q=0.9":           "en",
+		"*":                         "en",
+		"":                          "en",
+	}
+	for header, want := range headers {
+		if got := FromAcceptLanguage(header); got != want {
+			t.Errorf("FromAcceptLanguage(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestErrorMessageTranslatesByCode(t *testing.T) {
+	err := apperrors.Gone("token has expired").WithCode("token_expired")
+	if got := ErrorMessage("fr", err); got != catalog["fr"]["token_expired"] {
+		t.Errorf("ErrorMessage(fr) = %q, want the French translation", got)
+	}
+	if got := ErrorMessage("de", err); got != catalog["en"]["token_expired"] {
+		t.Errorf("ErrorMessage(de) = %q, want the English fallback", got)
+	}
+
+	plain := errors.New("something exploded")
+	if got := ErrorMessage("fr", plain); got != "something exploded" {
+		t.Errorf("ErrorMessage on an uncoded error = %q, want the original message", got)
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/cache"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+// The auth cache trades a bounded TTL of staleness for one DB read per
+// request; suspend must therefore pair the status change with an explicit
+// InvalidateUser so the very next request is rejected.
+func TestSuspendWithInvalidationRejectsImmediately(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	org := testutil.NewOrganization(db).Active().Build()
+	user := testutil.NewUser(db, org).Active().Build()
+	token := testutil.MintJWT(user, org)
+	t.Cleanup(func() { cache.InvalidateUser(user.ID.String()) })
+
+	if _, err := ValidateJWT(token, db); err != nil {
+		t.Fatalf("initial validation failed: %v", err)
+	}
+
+	if err := db.Model(&models.User{}).Where("id = ?", user.ID).
+		Update("status", "suspended").Error; err != nil {
+		t.Fatalf("suspend failed: %v", err)
+	}
+
+	// Within the TTL the cached copy still authorizes: this is the bounded
+	// staleness the cache accepts when nobody invalidates
+	if _, err := ValidateJWT(token, db); err != nil {
+		t.Fatalf("cached validation failed before invalidation: %v", err)
+	}
+
+	cache.InvalidateUser(user.ID.String())
+	if _, err := ValidateJWT(token, db); err == nil {
+		t.Fatal("a suspended user passed validation after invalidation")
+	}
+}
+
+func TestTokenVersionBumpWithInvalidationRejectsOldTokens(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	org := testutil.NewOrganization(db).Active().Build()
+	user := testutil.NewUser(db, org).Active().Build()
+	token := testutil.MintJWT(user, org)
+	t.Cleanup(func() { cache.InvalidateUser(user.ID.String()) })
+
+	if _, err := ValidateJWT(token, db); err != nil {
+		t.Fatalf("initial validation failed: %v", err)
+	}
+
+	if err := db.Model(&models.User{}).Where("id = ?", user.ID).
+		Update("token_version", user.TokenVersion+1).Error; err != nil {
+		t.Fatalf("token version bump failed: %v", err)
+	}
+	cache.InvalidateUser(user.ID.String())
+
+	if _, err := ValidateJWT(token, db); err == nil {
+		t.Fatal("a token minted before a version bump passed validation")
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/features"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireFeature hides an endpoint when the deployment-level feature toggle
+// is off. Responds 404 (not 403/503) so a disabled feature is
+// indistinguishable from a route that was never registered.
+func RequireFeature(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !features.Enabled(name) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/features"
+	"github.com/gin-gonic/gin"
+)
+
+// The features package loads its env once per process, so both sides of the
+// gate are asserted in one test: the env is set before the first
+// features.Enabled call this binary makes.
+func TestRequireFeatureGatesRoutes(t *testing.T) {
+	t.Setenv("FEATURE_IMPERSONATION", "false")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/impersonate", RequireFeature(features.Impersonation), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.GET("/join", RequireFeature(features.InviteLinks), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/impersonate", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("disabled feature route = %d, want 404", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/join", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("enabled feature route = %d, want 200", rec.Code)
+	}
+}
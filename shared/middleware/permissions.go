@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/constants"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission gates a route on a capability from the role permission
+// matrix in shared/constants. Prefer it over RoleAuthorization where read and
+// write access diverge for the same role (e.g. maintainers may view org
+// settings but not change them).
+func RequirePermission(cap constants.Capability) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claimsVal, exists := c.Get("userClaims")
+		if !exists {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing user claims"})
+			return
+		}
+
+		claims, ok := claimsVal.(*utils.JWTClaims)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid user claims"})
+			return
+		}
+
+		if !hasPermission(c, claims.Role, cap) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "unauthorized: missing permission " + string(cap)})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// hasPermission checks the built-in matrix first, then falls back to the
+// custom-role capability set AuthMiddleware resolved into the context.
+func hasPermission(c *gin.Context, role string, cap constants.Capability) bool {
+	if constants.IsBuiltInRole(role) {
+		return constants.RoleHasCapability(role, cap)
+	}
+
+	permsVal, exists := c.Get("userPermissions")
+	if !exists {
+		return false
+	}
+	permissions, ok := permsVal.([]string)
+	if !ok {
+		return false
+	}
+	for _, granted := range permissions {
+		if granted == string(cap) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// rateBucket tracks requests for one key within the current fixed window.
+type rateBucket struct {
+	count       int
+	windowStart time.Time
+}
+
+// RateLimiter applies a per-key fixed-window limit and exposes the bucket
+// state via X-RateLimit-* headers so well-behaved clients can self-throttle.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	buckets   map[string]*rateBucket
+	lastSweep time.Time
+}
+
+// NewRateLimiter creates a limiter allowing `limit` requests per window.
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		window:  window,
+		buckets: make(map[string]*rateBucket),
+	}
+}
+
+// take consumes one request for key and returns remaining quota and the
+// window reset time. The bucket state is updated atomically under the lock
+// so the headers stay accurate under concurrency.
+func (rl *RateLimiter) take(key string) (allowed bool, remaining int, reset time.Time) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+
+	// Drop buckets whose window has fully elapsed, at most once per window.
+	// Without this the map grows one entry per distinct key forever — the
+	// limiter also fronts unauthenticated routes keyed by client IP, where
+	// the key space is attacker-controlled.
+	if now.Sub(rl.lastSweep) >= rl.window {
+		for k, bkt := range rl.buckets {
+			if now.Sub(bkt.windowStart) >= rl.window {
+				delete(rl.buckets, k)
+			}
+		}
+		rl.lastSweep = now
+	}
+
+	b, ok := rl.buckets[key]
+	if !ok || now.Sub(b.windowStart) >= rl.window {
+		b = &rateBucket{windowStart: now}
+		rl.buckets[key] = b
+	}
+
+	reset = b.windowStart.Add(rl.window)
+	if b.count >= rl.limit {
+		return false, 0, reset
+	}
+
+	b.count++
+	return true, rl.limit - b.count, reset
+}
+
+// Middleware rate-limits by user ID when authenticated, client IP otherwise.
+func (rl *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.ClientIP()
+		if claimsVal, exists := c.Get("userClaims"); exists {
+			if claims, ok := claimsVal.(*utils.JWTClaims); ok {
+				key = claims.UserID
+			}
+		}
+
+		allowed, remaining, reset := rl.take(key)
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(rl.limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+		if !allowed {
+			c.Header("Retry-After", fmt.Sprintf("%d", int(time.Until(reset).Seconds())+1))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests,
+				utils.APIResponse(true, "rate limit exceeded", nil, http.StatusTooManyRequests))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RateLimitMiddleware builds a limiter from RATE_LIMIT_PER_MINUTE (default 60).
+func RateLimitMiddleware() gin.HandlerFunc {
+	limit := 60
+	if v := os.Getenv("RATE_LIMIT_PER_MINUTE"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	return NewRateLimiter(limit, time.Minute).Middleware()
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func TestTakeEnforcesLimitAndCountsDown(t *testing.T) {
+	rl := NewRateLimiter(3, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		allowed, remaining, _ := rl.take("user-a")
+		if !allowed {
+			t.Fatalf("request %d: expected to be allowed", i+1)
+		}
+		if want := 3 - (i + 1); remaining != want {
+			t.Errorf("request %d: remaining = %d, want %d", i+1, remaining, want)
+		}
+	}
+
+	allowed, remaining, reset := rl.take("user-a")
+	if allowed {
+		t.Fatal("4th request: expected to be rejected")
+	}
+	if remaining != 0 {
+		t.Errorf("rejected request: remaining = %d, want 0", remaining)
+	}
+	if !reset.After(time.Now()) {
+		t.Errorf("reset %v is not in the future", reset)
+	}
+}
+
+func TestTakeKeysAreIndependent(t *testing.T) {
+	rl := NewRateLimiter(1, time.Minute)
+
+	if allowed, _, _ := rl.take("user-a"); !allowed {
+		t.Fatal("user-a's first request should be allowed")
+	}
+	if allowed, _, _ := rl.take("user-b"); !allowed {
+		t.Fatal("user-b must not be throttled by user-a's traffic")
+	}
+	if allowed, _, _ := rl.take("user-a"); allowed {
+		t.Fatal("user-a's second request should be rejected")
+	}
+}
+
+func TestTakeWindowResets(t *testing.T) {
+	rl := NewRateLimiter(1, 20*time.Millisecond)
+
+	rl.take("user-a")
+	if allowed, _, _ := rl.take("user-a"); allowed {
+		t.Fatal("expected the second request inside the window to be rejected")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if allowed, _, _ := rl.take("user-a"); !allowed {
+		t.Fatal("expected quota back after the window elapsed")
+	}
+}
+
+func TestTakeEvictsStaleBuckets(t *testing.T) {
+	rl := NewRateLimiter(1, 20*time.Millisecond)
+
+	// Many one-off keys, like an attacker cycling source IPs
+	for i := 0; i < 100; i++ {
+		rl.take("ip-" + strconv.Itoa(i))
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	rl.take("ip-fresh")
+
+	rl.mu.Lock()
+	size := len(rl.buckets)
+	rl.mu.Unlock()
+	if size != 1 {
+		t.Errorf("buckets = %d entries after the windows elapsed, want only the fresh key", size)
+	}
+}
+
+// limitedRouter wires the limiter behind a stub that authenticates every
+// request as userID, mirroring how routes mount it after AuthMiddleware.
+func limitedRouter(rl *RateLimiter, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("userClaims", &utils.JWTClaims{UserID: userID})
+	})
+	r.Use(rl.Middleware())
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func TestMiddlewareHeadersAndTooManyRequests(t *testing.T) {
+	r := limitedRouter(NewRateLimiter(2, time.Minute), "user-a")
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		w := get()
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i+1, w.Code)
+		}
+		if got := w.Header().Get("X-RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d: X-RateLimit-Limit = %q, want 2", i+1, got)
+		}
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != wantRemaining {
+			t.Errorf("request %d: X-RateLimit-Remaining = %q, want %q", i+1, got, wantRemaining)
+		}
+	}
+
+	w := get()
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("over-limit request: status = %d, want 429", w.Code)
+	}
+	if got := w.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("over-limit request: X-RateLimit-Remaining = %q, want 0", got)
+	}
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 1 {
+		t.Errorf("Retry-After = %q, want a positive integer of seconds", w.Header().Get("Retry-After"))
+	}
+	if reset, err := strconv.ParseInt(w.Header().Get("X-RateLimit-Reset"), 10, 64); err != nil || reset < time.Now().Unix() {
+		t.Errorf("X-RateLimit-Reset = %q, want a future unix timestamp", w.Header().Get("X-RateLimit-Reset"))
+	}
+}
+
+func TestMiddlewareFallsBackToClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(NewRateLimiter(1, time.Minute).Middleware())
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	get := func(ip string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.RemoteAddr = ip + ":1234"
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := get("10.0.0.1"); code != http.StatusOK {
+		t.Fatalf("first request from 10.0.0.1: status = %d, want 200", code)
+	}
+	if code := get("10.0.0.1"); code != http.StatusTooManyRequests {
+		t.Fatalf("second request from 10.0.0.1: status = %d, want 429", code)
+	}
+	if code := get("10.0.0.2"); code != http.StatusOK {
+		t.Fatalf("request from 10.0.0.2: status = %d, want 200", code)
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RecoveryMiddleware recovers from handler panics, logs the stack with the
+// request details, and returns the standard GenericResponse JSON envelope
+// instead of gin's bare 500. The stack is never leaked to the client.
+func RecoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[PANIC] %s %s request_id=%s: %v\n%s",
+					c.Request.Method, c.Request.URL.Path, c.GetHeader("X-Request-ID"), r, debug.Stack())
+
+				c.AbortWithStatusJSON(http.StatusInternalServerError,
+					utils.APIResponse(true, "internal server error", nil, http.StatusInternalServerError))
+			}
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRecoveryMiddlewareTurnsPanicsIntoEnvelope(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(log.Writer())
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RecoveryMiddleware())
+	r.GET("/boom", func(c *gin.Context) { panic("database exploded: secret dsn") })
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/boom", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+
+	// The client gets the standard envelope, never the panic or stack
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not JSON: %v", err)
+	}
+	if body["error"] != true || body["message"] != "internal server error" {
+		t.Errorf("unexpected envelope: %v", body)
+	}
+	if strings.Contains(w.Body.String(), "exploded") {
+		t.Error("panic detail leaked into the response body")
+	}
+
+	// The log carries the request details and the stack
+	out := logged.String()
+	for _, want := range []string{"[PANIC]", "GET", "/boom", "req-123", "database exploded", "goroutine"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output is missing %q", want)
+		}
+	}
+}
+
+func TestRecoveryMiddlewareStaysOutOfTheWay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RecoveryMiddleware())
+	r.GET("/ok", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"fine": true}) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok", nil))
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "fine") {
+		t.Errorf("healthy handler was disturbed: %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceKeyAuth protects internal/service-to-service endpoints with a shared
+// API key passed in the X-Service-Key header (configured via SERVICE_API_KEY).
+func ServiceKeyAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		expected := os.Getenv("SERVICE_API_KEY")
+		if expected == "" {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "service API key not configured"})
+			return
+		}
+
+		provided := c.GetHeader("X-Service-Key")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid service API key"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// EmailBounceAuth protects the inbound email-bounce webhook with a shared
+// secret passed in the X-Bounce-Secret header (configured via
+// EMAIL_BOUNCE_SECRET).
+func EmailBounceAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		expected := os.Getenv("EMAIL_BOUNCE_SECRET")
+		if expected == "" {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "email bounce secret not configured"})
+			return
+		}
+
+		provided := c.GetHeader("X-Bounce-Secret")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid bounce secret"})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// slowRequestThreshold reads SLOW_REQUEST_THRESHOLD_MS (default 1000),
+// matching the DB slow-query threshold configured in NewDB.
+func slowRequestThreshold() time.Duration {
+	if raw := os.Getenv("SLOW_REQUEST_THRESHOLD_MS"); raw != "" {
+		if ms, err := strconv.Atoi(raw); err == nil && ms > 0 {
+			return time.Duration(ms) * time.Millisecond
+		}
+	}
+	return time.Second
+}
+
+var (
+	slowRequestsMu sync.Mutex
+	// route ("METHOD /path") → count of requests over the threshold
+	slowRequestCounts = make(map[string]int64)
+)
+
+// SlowRequestLogger warns about handlers exceeding the slow threshold and
+// counts them per route, so chronically slow endpoints stand out without
+// tracing every request.
+func SlowRequestLogger() gin.HandlerFunc {
+	threshold := slowRequestThreshold()
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		duration := time.Since(start)
+
+		if duration < threshold {
+			return
+		}
+
+		// FullPath keeps the route template (e.g. /users/:id), so counters
+		// don't explode per concrete id
+		route := c.Request.Method + " " + c.FullPath()
+		if c.FullPath() == "" {
+			route = c.Request.Method + " " + c.Request.URL.Path
+		}
+
+		slowRequestsMu.Lock()
+		slowRequestCounts[route]++
+		slowRequestsMu.Unlock()
+
+		fmt.Printf("[WARN] slow request: %s status=%d duration=%s threshold=%s\n",
+			route, c.Writer.Status(), duration.Round(time.Millisecond), threshold)
+	}
+}
+
+// SlowRequestStats returns a copy of the per-route slow-request counters.
+func SlowRequestStats() map[string]int64 {
+	slowRequestsMu.Lock()
+	defer slowRequestsMu.Unlock()
+
+	stats := make(map[string]int64, len(slowRequestCounts))
+	for route, count := range slowRequestCounts {
+		stats[route] = count
+	}
+	return stats
+}
@@ -2,18 +2,19 @@ package middleware
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
-	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/cache"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/constants"
+	shareddb "github.com/Bipul-Dubey/ai-knowledgebase/shared/db"
 	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 )
 
-var jwtSecret = []byte("YOUR_SUPER_SECRET_KEY")
-
 func AuthMiddleware(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -28,7 +29,7 @@ func AuthMiddleware(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
-		claims, err := validateJWT(tokenStr, db)
+		claims, err := ValidateJWT(tokenStr, db)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
@@ -36,13 +37,40 @@ func AuthMiddleware(db *gorm.DB) gin.HandlerFunc {
 
 		// Attach claims to context
 		c.Set("userClaims", claims)
+		if claims.ImpersonatorID != "" {
+			c.Set("impersonatorID", claims.ImpersonatorID)
+		}
+
+		// Custom roles resolve their capability set here (cached) so
+		// RequirePermission stays DB-free
+		if !constants.IsBuiltInRole(claims.Role) {
+			c.Set("userPermissions", resolveCustomRolePermissions(db, claims.OrganizationID, claims.Role))
+		}
+
 		c.Next()
 	}
 }
 
-func validateJWT(tokenStr string, db *gorm.DB) (*utils.JWTClaims, error) {
+// ValidateJWT verifies a token's signature and claims and checks the backing
+// user record (active status, token version). It is shared by AuthMiddleware
+// and the token introspection endpoint.
+func ValidateJWT(tokenStr string, db *gorm.DB) (*utils.JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenStr, &utils.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
+		// Pin the algorithm to the configured HMAC variant before handing
+		// out the key: without this, `alg: none` or an RS256 token
+		// "verified" against the HMAC secret could slip through (algorithm
+		// confusion)
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || token.Method.Alg() != utils.JWTSigningMethod().Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		// Select the verification key by kid so tokens issued under a key
+		// still in the set keep validating through a rotation window
+		kid, _ := token.Header["kid"].(string)
+		key, ok := utils.JWTVerificationKey(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		return key, nil
 	})
 
 	if err != nil || !token.Valid {
@@ -54,12 +82,21 @@ func validateJWT(tokenStr string, db *gorm.DB) (*utils.JWTClaims, error) {
 		return nil, errors.New("invalid claims")
 	}
 
-	var user models.User
-	if err := db.First(&user, "id = ?", claims.UserID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+	// TTL cache keeps the per-request DB load off the hot path; suspend /
+	// role / token-version changes invalidate explicitly
+	user, cached := cache.Users.Get(claims.UserID)
+	if !cached {
+		// retried: this read is on every authenticated request, so a
+		// failover blip would otherwise 401 live sessions
+		if err := shareddb.Retry(func() error {
+			return db.First(&user, "id = ?", claims.UserID).Error
+		}); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("user not found")
+			}
+			return nil, err
 		}
-		return nil, err
+		cache.Users.Set(claims.UserID, user)
 	}
 
 	if user.Status != "active" {
@@ -72,3 +109,19 @@ func validateJWT(tokenStr string, db *gorm.DB) (*utils.JWTClaims, error) {
 
 	return claims, nil
 }
+
+// resolveCustomRolePermissions loads an org-defined role's capability set. A
+// role that has been deleted (or never existed) grants nothing, which fails
+// closed at RequirePermission.
+func resolveCustomRolePermissions(db *gorm.DB, orgID, roleName string) []string {
+	role, found := cache.OrgRoles.Get(cache.OrgRoleKey(orgID, roleName))
+	if !found {
+		if err := db.
+			Where("organization_id = ? AND name = ?", orgID, roleName).
+			First(&role).Error; err != nil {
+			return []string{}
+		}
+		cache.OrgRoles.Set(cache.OrgRoleKey(orgID, roleName), role)
+	}
+	return role.PermissionList()
+}
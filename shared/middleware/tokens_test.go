@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// forgedClaims builds otherwise-plausible claims for the given fixture user,
+// so the only thing wrong with each forged token is its signature scheme.
+func forgedClaims(userID, orgID string) utils.JWTClaims {
+	return utils.JWTClaims{
+		UserID:         userID,
+		OrganizationID: orgID,
+		Role:           "owner",
+		TokenVersion:   1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+		},
+	}
+}
+
+func validationFixture(t *testing.T) (*gorm.DB, utils.JWTClaims, string) {
+	t.Helper()
+	db := testutil.NewTestDB(t)
+	org := testutil.NewOrganization(db).Active().Build()
+	user := testutil.NewUser(db, org).Owner().Active().Build()
+	return db, forgedClaims(user.ID.String(), org.ID.String()), testutil.MintJWT(user, org)
+}
+
+func TestValidateJWTAcceptsProperlySignedToken(t *testing.T) {
+	db, _, token := validationFixture(t)
+
+	claims, err := ValidateJWT(token, db)
+	if err != nil {
+		t.Fatalf("a properly signed token failed validation: %v", err)
+	}
+	if claims.Role != "owner" {
+		t.Errorf("claims role = %q, want owner", claims.Role)
+	}
+}
+
+func TestValidateJWTRejectsAlgNone(t *testing.T) {
+	db, claims, _ := validationFixture(t)
+
+	// An unsigned token claiming alg "none" must never pass, whatever the
+	// payload says
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("failed to build alg=none token: %v", err)
+	}
+
+	if _, err := ValidateJWT(forged, db); err == nil {
+		t.Fatal("ValidateJWT accepted an alg=none token")
+	}
+}
+
+func TestValidateJWTRejectsRS256(t *testing.T) {
+	db, claims, _ := validationFixture(t)
+
+	// Classic algorithm confusion: an RS256 token that a sloppy validator
+	// would verify against the HMAC secret as if it were a public key
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to build RS256 token: %v", err)
+	}
+
+	if _, err := ValidateJWT(forged, db); err == nil {
+		t.Fatal("ValidateJWT accepted an RS256 token")
+	}
+}
+
+func TestValidateJWTRejectsWrongSecret(t *testing.T) {
+	db, claims, _ := validationFixture(t)
+
+	forged, err := jwt.NewWithClaims(utils.JWTSigningMethod(), claims).
+		SignedString([]byte("not-the-real-secret"))
+	if err != nil {
+		t.Fatalf("failed to build wrongly keyed token: %v", err)
+	}
+
+	if _, err := ValidateJWT(forged, db); err == nil {
+		t.Fatal("ValidateJWT accepted a token signed with the wrong secret")
+	}
+}
+
+func TestValidateJWTRejectsUnknownKid(t *testing.T) {
+	db, claims, _ := validationFixture(t)
+
+	token := jwt.NewWithClaims(utils.JWTSigningMethod(), claims)
+	token.Header["kid"] = "rotated-out"
+	forged, err := token.SignedString([]byte("whatever"))
+	if err != nil {
+		t.Fatalf("failed to build token with unknown kid: %v", err)
+	}
+
+	if _, err := ValidateJWT(forged, db); err == nil {
+		t.Fatal("ValidateJWT accepted a token with an unknown kid")
+	}
+}
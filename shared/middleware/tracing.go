@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// Tracing starts a server span per request, continuing the trace from an
+// incoming W3C traceparent header when one is present. The trace id is
+// echoed in X-Trace-Id so users can quote it in bug reports.
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if traceID, spanID, ok := tracing.ParseTraceparent(c.GetHeader("traceparent")); ok {
+			ctx = tracing.ContextWithRemoteParent(ctx, traceID, spanID)
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		ctx, span := tracing.StartSpan(ctx, c.Request.Method+" "+route, tracing.KindServer)
+		c.Request = c.Request.WithContext(ctx)
+		c.Header("X-Trace-Id", span.TraceID)
+
+		c.Next()
+
+		span.SetAttr("http.method", c.Request.Method)
+		span.SetAttr("http.route", route)
+		span.SetAttr("http.status_code", strconv.Itoa(c.Writer.Status()))
+		if c.Writer.Status() >= 500 {
+			span.Failed = true
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// tracingRouter captures the span the middleware attached to the request
+// context, so each test can inspect it after the response.
+func tracingRouter(captured **tracing.Span) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Tracing())
+	router.GET("/ping/:id", func(c *gin.Context) {
+		*captured = tracing.FromContext(c.Request.Context())
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func TestTracingStartsAServerSpanPerRequest(t *testing.T) {
+	var span *tracing.Span
+	router := tracingRouter(&span)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping/42", nil))
+
+	if span == nil {
+		t.Fatal("no span reached the handler")
+	}
+	if span.Name != "GET /ping/:id" || span.Kind != tracing.KindServer {
+		t.Errorf("span = %q kind %d, want the route template as a server span", span.Name, span.Kind)
+	}
+	if got := rec.Header().Get("X-Trace-Id"); got != span.TraceID {
+		t.Errorf("X-Trace-Id = %q, want the span's trace id %q", got, span.TraceID)
+	}
+
+	// A second request gets its own trace
+	first := span.TraceID
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping/43", nil))
+	if span.TraceID == first {
+		t.Error("two requests shared one trace id")
+	}
+}
+
+func TestTracingContinuesAnIncomingTraceparent(t *testing.T) {
+	var span *tracing.Span
+	router := tracingRouter(&span)
+
+	traceID := strings.Repeat("ab", 16)
+	parentID := strings.Repeat("cd", 8)
+	req := httptest.NewRequest(http.MethodGet, "/ping/42", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentID+"-01")
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if span == nil {
+		t.Fatal("no span reached the handler")
+	}
+	if span.TraceID != traceID || span.ParentID != parentID {
+		t.Errorf("span = trace %q parent %q, want the upstream %q/%q",
+			span.TraceID, span.ParentID, traceID, parentID)
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===============================
+// AuditLog
+// ===============================
+// Sensitive admin actions are appended here. ImpersonatorID records the
+// real admin whenever the action was taken under impersonation.
+type AuditLog struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	ActorID        uuid.UUID  `gorm:"type:uuid;not null"`
+	ImpersonatorID *uuid.UUID `gorm:"type:uuid"`
+	Action         string     `gorm:"type:varchar(100);not null"`
+	Details        string     `gorm:"type:text"`
+	CreatedAt      time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+}
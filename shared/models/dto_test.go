@@ -0,0 +1,81 @@
+package models_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/google/uuid"
+)
+
+// A user with every sensitive field deliberately populated; both the DTO and
+// the raw-model backstop must keep them out of the wire format.
+func loadedUser() models.User {
+	token := "invite-token-should-never-leak"
+	expires := time.Now().UTC().Add(72 * time.Hour)
+	return models.User{
+		ID:             uuid.New(),
+		OrganizationID: uuid.New(),
+		Name:           "Sensitive Sam",
+		Email:          "sam@example.com",
+		EmailStatus:    "ok",
+		Password:       "$2a$12$bcrypt-hash-should-never-leak",
+		Role:           "member",
+		Status:         "pending",
+		Locale:         "en",
+		InviteToken:    &token,
+		ExpiresAt:      &expires,
+		TokenVersion:   7,
+	}
+}
+
+func TestUserResponseOmitsSensitiveFields(t *testing.T) {
+	raw, err := json.Marshal(models.ToUserResponse(loadedUser()))
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	body := string(raw)
+
+	for _, leak := range []string{"password", "bcrypt-hash", "invite_token", "invite-token", "token_version"} {
+		if strings.Contains(body, leak) {
+			t.Errorf("DTO JSON contains %q: %s", leak, body)
+		}
+	}
+	// The public shape is still intact
+	for _, want := range []string{`"id"`, `"organization_id"`, `"email":"sam@example.com"`, `"role":"member"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("DTO JSON missing %s: %s", want, body)
+		}
+	}
+}
+
+func TestRawModelBackstopHidesSensitiveFields(t *testing.T) {
+	// Even if a handler mistakenly serializes the GORM model directly, the
+	// json:"-" tags must keep credentials out of the response.
+	raw, err := json.Marshal(loadedUser())
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	body := string(raw)
+
+	for _, leak := range []string{"bcrypt-hash", "invite-token-should-never-leak", "Password", "InviteToken"} {
+		if strings.Contains(body, leak) {
+			t.Errorf("raw model JSON contains %q: %s", leak, body)
+		}
+	}
+}
+
+func TestToUserResponsesMapsEveryUser(t *testing.T) {
+	users := []models.User{loadedUser(), loadedUser()}
+	got := models.ToUserResponses(users)
+	if len(got) != len(users) {
+		t.Fatalf("mapped %d users, want %d", len(got), len(users))
+	}
+	for i := range got {
+		if got[i].ID != users[i].ID || got[i].Email != users[i].Email {
+			t.Errorf("user %d mapped incorrectly: %+v", i, got[i])
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===============================
+// FeatureFlag
+// ===============================
+// A runtime kill switch for one feature (e.g. "signup_enabled"). A row with
+// OrganizationID nil is the global value; a row with an org set overrides the
+// global one for that tenant only. No row at all means enabled (or whatever
+// the FLAG_* env default says) — flags exist to turn things off.
+type FeatureFlag struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	Name           string     `gorm:"type:varchar(100);not null;uniqueIndex:uq_feature_flags_name_org"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid;uniqueIndex:uq_feature_flags_name_org"`
+	Enabled        bool       `gorm:"not null;default:true"`
+	UpdatedAt      time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// BeforeCreate assigns the UUID primary key when unset and stamps the
+// timestamp, so service code doesn't do it inline.
+func (f *FeatureFlag) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	f.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// BeforeUpdate keeps UpdatedAt accurate on every write path, including
+// map-based Updates.
+func (f *FeatureFlag) BeforeUpdate(tx *gorm.DB) error {
+	tx.Statement.SetColumn("updated_at", time.Now().UTC())
+	return nil
+}
+
+// SetFeatureFlagRequest flips one flag; OrganizationID nil targets the
+// global value, non-nil a per-tenant override.
+type SetFeatureFlagRequest struct {
+	Name           string  `json:"name" binding:"required"`
+	OrganizationID *string `json:"organization_id"`
+	Enabled        *bool   `json:"enabled" binding:"required"`
+}
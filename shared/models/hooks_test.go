@@ -0,0 +1,70 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	"github.com/google/uuid"
+)
+
+func TestHooksAssignUUIDExactlyOnce(t *testing.T) {
+	db := testutil.NewTestDB(t)
+
+	org := models.Organization{Name: "Acme", AccountID: "1100000000000042", Status: "pending"}
+	if err := db.Create(&org).Error; err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if org.ID == uuid.Nil {
+		t.Fatal("BeforeCreate did not assign an id")
+	}
+
+	created := org.ID
+	org.Name = "Acme Renamed"
+	if err := db.Save(&org).Error; err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if org.ID != created {
+		t.Errorf("id changed on update: %s -> %s", created, org.ID)
+	}
+
+	// An explicitly chosen id is respected, not overwritten
+	chosen := uuid.New()
+	pinned := models.Organization{ID: chosen, Name: "Pinned", AccountID: "1100000000000034", Status: "pending"}
+	if err := db.Create(&pinned).Error; err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if pinned.ID != chosen {
+		t.Errorf("preset id %s was replaced with %s", chosen, pinned.ID)
+	}
+}
+
+func TestHooksKeepUpdatedAtAccurate(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	org := testutil.NewOrganization(db).Build()
+
+	if org.CreatedAt.IsZero() || org.UpdatedAt.IsZero() {
+		t.Fatal("timestamps were not stamped on create")
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	// Map-based Updates bypasses gorm's struct tracking; the BeforeUpdate
+	// hook must stamp updated_at anyway
+	if err := db.Model(&models.Organization{}).Where("id = ?", org.ID).
+		Update("name", "Updated Name").Error; err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+
+	var after models.Organization
+	if err := db.First(&after, "id = ?", org.ID).Error; err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	if !after.UpdatedAt.After(org.UpdatedAt) {
+		t.Errorf("updated_at did not advance: %v -> %v", org.UpdatedAt, after.UpdatedAt)
+	}
+	if !after.CreatedAt.Equal(org.CreatedAt) {
+		t.Errorf("created_at drifted on update: %v -> %v", org.CreatedAt, after.CreatedAt)
+	}
+}
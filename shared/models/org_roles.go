@@ -0,0 +1,86 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===============================
+// OrgRole
+// ===============================
+// A custom role defined by an organization (e.g. "billing-admin"), granting a
+// subset of the capability strings from shared/constants. The built-in
+// owner/maintainer/member roles never appear here and stay immutable.
+// User.Role stores either a built-in name or an OrgRole.Name.
+type OrgRole struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:uq_org_roles_org_name"`
+	Name           string    `gorm:"type:varchar(50);not null;uniqueIndex:uq_org_roles_org_name"`
+	// Comma-separated capability list (same storage style as
+	// Organization.AllowedEmailDomains)
+	Permissions string    `gorm:"type:varchar(1000)"`
+	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// BeforeCreate assigns the UUID primary key when unset and stamps both
+// timestamps, so service code doesn't do it inline.
+func (r *OrgRole) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = now
+	}
+	r.UpdatedAt = now
+	return nil
+}
+
+// BeforeUpdate keeps UpdatedAt accurate on every write path, including
+// map-based Updates.
+func (r *OrgRole) BeforeUpdate(tx *gorm.DB) error {
+	tx.Statement.SetColumn("updated_at", time.Now().UTC())
+	return nil
+}
+
+// PermissionList splits the stored capability string.
+func (r OrgRole) PermissionList() []string {
+	if strings.TrimSpace(r.Permissions) == "" {
+		return []string{}
+	}
+	parts := strings.Split(r.Permissions, ",")
+	permissions := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if trimmed := strings.TrimSpace(p); trimmed != "" {
+			permissions = append(permissions, trimmed)
+		}
+	}
+	return permissions
+}
+
+type CreateOrgRoleRequest struct {
+	// Name is normalized (trim + lowercase) and slug-checked by the service,
+	// so raw input like " Admin-Ops " still passes binding.
+	Name        string   `json:"name" binding:"required"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// UpdateOrgRoleRequest: nil Permissions leaves the grant untouched. Names are
+// identity (users reference roles by name) and cannot be changed.
+type UpdateOrgRoleRequest struct {
+	Permissions *[]string `json:"permissions"`
+}
+
+type OrgRoleResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Permissions []string  `json:"permissions"`
+	// Number of users currently holding the role
+	UserCount int64     `json:"user_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
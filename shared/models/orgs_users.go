@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // ===============================
@@ -15,8 +16,98 @@ type Organization struct {
 	AccountID string     `gorm:"type:varchar(16);unique;not null"`
 	CreatedBy *uuid.UUID `gorm:"type:uuid"`
 	Status    string     `gorm:"type:varchar(20);default:'pending'"` // pending / active
-	CreatedAt time.Time  `gorm:"default:now()"`
-	UpdatedAt time.Time  `gorm:"default:now()"`
+
+	// Message retention policy (0 = keep forever); purge runs in chats-service
+	MessageRetentionDays int        `gorm:"default:0"`
+	LastPurgeAt          *time.Time `gorm:"column:last_purge_at"`
+
+	// Comma-separated email domains allowed to join (empty = any domain)
+	AllowedEmailDomains string `gorm:"type:varchar(500)"`
+
+	// Email/UI language for new members who don't pick one themselves
+	DefaultLocale string `gorm:"type:varchar(10);default:'en'"`
+
+	// When false, regular members can't browse the member directory
+	// (GET /members); owners and maintainers always can
+	MemberDirectoryVisible bool `gorm:"default:true"`
+
+	// Role granted to self-service signups through a shareable join link
+	// created without an explicit role (member / maintainer)
+	DefaultJoinRole string `gorm:"type:varchar(50);default:'member'"`
+
+	// Max active + pending members (nil = unlimited); set via the internal
+	// billing API only. Suspended and deleted users don't occupy seats.
+	SeatLimit *int
+
+	// Billing tier (free / pro / enterprise); set via the internal billing
+	// API. Drives the inference queue weight in chats-service.
+	Tier string `gorm:"type:varchar(20);default:'free'"`
+
+	// Document storage quota in bytes (0 = deployment default from env,
+	// which may itself be unlimited) and the running usage counter, kept in
+	// step transactionally by chats-service on upload/delete/restore and
+	// reconciled nightly against the documents table.
+	StorageQuotaBytes int64 `gorm:"default:0"`
+	StorageUsedBytes  int64 `gorm:"default:0"`
+
+	// Email branding (empty = platform defaults). Validated on write
+	// (https URL, hex color, length caps) and escaped again at render time.
+	BrandLogoURL      string `gorm:"type:varchar(500)"`
+	BrandPrimaryColor string `gorm:"type:varchar(7)"`
+	EmailFooterText   string `gorm:"type:varchar(500)"`
+
+	// Getting-started checklist state: step key → completion timestamp,
+	// plus a "dismissed" flag. Written only through jsonb_set (see
+	// users-service services/onboarding.go) so updates stay idempotent.
+	OnboardingState string `gorm:"type:jsonb;default:'{}'"`
+
+	// When true, session issuance outside the regular login flow (invite
+	// auto-login) returns a 2FA challenge marker instead of a token. Set via
+	// the org settings endpoint; the challenge flow itself (prompting for and
+	// verifying a second factor at login) is deliberately out of scope here.
+	Enforce2FA bool `gorm:"column:enforce_2fa;default:false"`
+
+	// Abuse moderation: chats-service sets the flag (and, when configured,
+	// strict mode) once report volume crosses the threshold; surfaced in
+	// the internal moderation listing.
+	ModerationMode string     `gorm:"type:varchar(20);default:'standard'"` // standard / strict
+	AbuseFlaggedAt *time.Time `gorm:"column:abuse_flagged_at"`
+
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// BeforeCreate assigns the UUID primary key when unset and stamps both
+// timestamps, so service code doesn't do it inline.
+func (o *Organization) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = now
+	}
+	o.UpdatedAt = now
+	return nil
+}
+
+// BeforeUpdate keeps UpdatedAt accurate on every write path, including
+// map-based Updates.
+func (o *Organization) BeforeUpdate(tx *gorm.DB) error {
+	tx.Statement.SetColumn("updated_at", time.Now().UTC())
+	return nil
+}
+
+// ===============================
+// AccountIDAlias
+// ===============================
+// Retired account ids kept after an admin regenerates an organization's
+// account id, so stale login attempts can get an "account ID changed" hint.
+type AccountIDAlias struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;index"`
+	AccountID      string    `gorm:"type:varchar(16);uniqueIndex;not null"` // the retired id
+	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP"`
 }
 
 // ===============================
@@ -27,21 +118,78 @@ type User struct {
 	OrganizationID uuid.UUID  `gorm:"type:uuid;not null;index"`
 	Name           string     `gorm:"type:varchar(255);not null"`
 	Email          string     `gorm:"type:varchar(255);not null"`         // unique per org
-	Password       string     `gorm:"type:varchar(255)"`                  // hashed
+	EmailStatus    string     `gorm:"type:varchar(20);default:'ok'"`      // ok / bounced
+	Password       string     `gorm:"type:varchar(255)" json:"-"`         // hashed; never serialized
 	Role           string     `gorm:"type:varchar(20);not null"`          // owner / maintainer / member
-	Status         string     `gorm:"type:varchar(20);default:'pending'"` // pending / active / suspended
+	Status         string     `gorm:"type:varchar(20);default:'pending'"` // pending / active / suspended / anonymized
+	Locale         string     `gorm:"type:varchar(10);default:'en'"`      // email template language
 	InvitedBy      *uuid.UUID `gorm:"type:uuid"`
-	InviteToken    *string    `gorm:"type:varchar(255)"`
-	ExpiresAt      *time.Time
+	InviteToken    *string    `gorm:"type:varchar(255)" json:"-"`
+	ExpiresAt      *time.Time `json:"-"`
+	AcceptedAt     *time.Time // when the invite/verification was completed; feeds funnel metrics
 	ReactivatedAt  *time.Time
-	TokenVersion   int  `gorm:"default:1"`
-	IsDeleted      bool `gorm:"default:false"`
-	DeletedAt      *time.Time
+	TokenVersion   int            `gorm:"default:1"`
+	DeletedAt      gorm.DeletedAt `gorm:"index"` // soft delete: queries skip deleted users automatically
 	DeletedBy      *uuid.UUID
-	CreatedAt      time.Time `gorm:"default:now()"`
-	UpdatedAt      time.Time `gorm:"default:now()"`
+	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP"`
 
 	// ⚡️ Send email on invite (InviteToken)
 	// ⚡️ Send email on verify (InviteToken)
-	// ⚡️ Return this model (without Password, InviteToken) in API responses
+	// ⚡️ API responses use ToUserResponse; sensitive fields are json:"-" as a backstop
+}
+
+// BeforeCreate assigns the UUID primary key when unset and stamps both
+// timestamps, so service code doesn't do it inline.
+func (u *User) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = now
+	}
+	u.UpdatedAt = now
+	return nil
+}
+
+// BeforeUpdate keeps UpdatedAt accurate on every write path, including
+// map-based Updates.
+func (u *User) BeforeUpdate(tx *gorm.DB) error {
+	tx.Statement.SetColumn("updated_at", time.Now().UTC())
+	return nil
+}
+
+// ===============================
+// OrganizationDomain
+// ===============================
+// A corporate email domain claimed by an org. Ownership is proven via a DNS
+// TXT challenge; only verified domains enable auto-join, and the unique index
+// on domain rejects a second org claiming the same one. Removing a row only
+// disables future auto-joins — existing members are untouched.
+type OrganizationDomain struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Domain         string    `gorm:"type:varchar(255);uniqueIndex;not null"` // lowercased
+	TxtChallenge   string    `gorm:"type:varchar(64);not null"`
+	VerifiedAt     *time.Time
+	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// ===============================
+// InviteLink
+// ===============================
+// Shareable join link (e.g. dropped in Slack). Anyone with the token can
+// request a pending account in the org, still gated by email verification.
+type InviteLink struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Token          string    `gorm:"type:varchar(64);uniqueIndex;not null"`
+	Role           string    `gorm:"type:varchar(20);not null"` // maintainer / member
+	CreatedBy      uuid.UUID `gorm:"type:uuid"`
+	MaxUses        int       `gorm:"default:0"` // 0 = unlimited
+	Uses           int       `gorm:"default:0"`
+	ExpiresAt      *time.Time
+	RevokedAt      *time.Time
+	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP"`
 }
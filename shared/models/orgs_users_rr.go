@@ -11,6 +11,7 @@ type SignupRequest struct {
 	OwnerName        string `json:"owner_name" validate:"required"`
 	Email            string `json:"email" validate:"required,email"`
 	Password         string `json:"password" validate:"required,min=8"`
+	Locale           string `json:"locale"` // email language, defaults to en
 }
 
 type SignupResponse struct {
@@ -22,8 +23,8 @@ type SignupResponse struct {
 	Role           string    `json:"role"`
 	Status         string    `json:"status"`
 	// ⚡️ Send verification mail to Email
-	// TODO: temp use until email feature implemented
-	InviteToken string     `json:"invite_token"`         // for verification email
+	// Only populated when DEV_EXPOSE_TOKENS=true (local testing; insecure)
+	InviteToken string     `json:"invite_token,omitempty"`
 	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // token expiry
 }
 
@@ -44,6 +45,27 @@ type LoginResponse struct {
 	OrganizationName string    `json:"organization_name"`
 }
 
+// ForgotPasswordResponse acknowledges a password-reset request. ResetLink
+// is only populated when DEV_EXPOSE_TOKENS is on (local testing without an
+// inbox).
+type ForgotPasswordResponse struct {
+	Email     string    `json:"email"`
+	AccountID string    `json:"account_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	ResetLink string    `json:"reset_link,omitempty"`
+}
+
+// ResetPasswordResponse reports the account state after a password change,
+// for both the logged-in flow and the emailed-link flow (Role is empty in
+// the latter).
+type ResetPasswordResponse struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role,omitempty"`
+	Status   string    `json:"status"`
+	Verified bool      `json:"verified"`
+}
+
 type VerifyAccountRequest struct {
 	Token string `json:"token" validate:"required"`
 }
@@ -58,28 +80,36 @@ type VerifyAccountResponse struct {
 }
 
 type InviteUserRequest struct {
-	Email string `json:"email" validate:"required,email"`
-	Role  string `json:"role" validate:"required,oneof=maintainer member"`
-	Name  string `json:"name"`
+	Email  string `json:"email" validate:"required,email"`
+	Role   string `json:"role" validate:"required"` // maintainer / member / custom org role
+	Name   string `json:"name"`
+	Locale string `json:"locale"` // email language, defaults to en
 }
 
 type InviteUserResponse struct {
-	UserID     uuid.UUID  `json:"user_id"`
-	Email      string     `json:"email"`
-	Name       string     `json:"name"`
-	Role       string     `json:"role"`
-	Status     string     `json:"status"`
-	ExpiresAt  *time.Time `json:"expires_at"`
-	InviteLink string     `json:"invite_link"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Email     string     `json:"email"`
+	Name      string     `json:"name"`
+	Role      string     `json:"role"`
+	Status    string     `json:"status"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	// Only populated when DEV_EXPOSE_TOKENS=true (local testing; insecure)
+	InviteLink string `json:"invite_link,omitempty"`
+	// Present when recent outbox deliveries for this org keep failing, so the
+	// UI can warn that the invite email may not arrive.
+	EmailWarning *EmailHealthWarning `json:"email_warning,omitempty"`
 	// ⚡️ Send invite email to Email with InviteToken link
 }
 
 type AcceptInviteRequest struct {
 	Name      string `json:"name" binding:"required"`
 	Email     string `json:"email" binding:"required,email"`
-	Password  string `json:"password" binding:"required,min=6"`
+	Password  string `json:"password" binding:"required,strongpassword"`
 	AccountID string `json:"account_id" binding:"required"`
 	Token     string `json:"token" binding:"required"`
+	// Issue a session token in the response so the new member skips the
+	// separate login step (unless the org enforces 2FA)
+	AutoLogin bool `json:"auto_login"`
 }
 
 type AcceptInviteResponse struct {
@@ -90,31 +120,261 @@ type AcceptInviteResponse struct {
 	Role           string    `json:"role"`
 	Status         string    `json:"status"`
 	IsVerified     bool      `json:"is_verified"`
+	// Auto-login outcome (auto_login=true only): a full session token, or a
+	// challenge marker when the org enforces 2FA and the client must go
+	// through the regular login flow instead
+	AccessToken       string `json:"access_token,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+}
+
+// UpdateUserRequest carries a combined admin edit; nil fields are untouched
+// and at least one field must be provided.
+type UpdateUserRequest struct {
+	Name   *string `json:"name"`
+	Role   *string `json:"role"`   // maintainer / member / custom org role
+	Status *string `json:"status"` // active / suspended
+}
+
+// InviteStatsResponse is the invitation funnel for one organization over a
+// window. Resent invites reuse the user row, so a chain counts once; deleted
+// users stay in Sent (the invite did go out) and a deletion while still
+// pending counts as revoked.
+type InviteStatsResponse struct {
+	WindowDays int   `json:"window_days"`
+	Sent       int64 `json:"sent"`
+	Accepted   int64 `json:"accepted"`
+	Expired    int64 `json:"expired"`
+	Revoked    int64 `json:"revoked"`
+	// nil when nothing was accepted in the window
+	MedianSecondsToAccept *float64 `json:"median_seconds_to_accept"`
+}
+
+// MemberDirectoryEntry is the privacy-trimmed projection every active user
+// may see: no email, and only active users are listed at all.
+type MemberDirectoryEntry struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Role string    `json:"role"`
+}
+
+type MemberDirectoryResponse struct {
+	Members []MemberDirectoryEntry `json:"members"`
+	Total   int64                  `json:"total"`
+	Page    int                    `json:"page"`
+	Limit   int                    `json:"limit"`
+}
+
+// UpdateProfileRequest carries a self-service edit (PATCH /users/me); nil
+// fields are untouched.
+type UpdateProfileRequest struct {
+	Name   *string `json:"name"`
+	Locale *string `json:"locale"` // must be a supported locale (en / fr)
+}
+
+// UpdateOrgSettingsRequest carries owner-editable org settings; nil fields
+// are untouched.
+type UpdateOrgSettingsRequest struct {
+	DefaultLocale *string `json:"default_locale"` // email language for new members
+	// false hides GET /members from regular members (owners/maintainers
+	// keep their full user listing either way)
+	MemberDirectoryVisible *bool `json:"member_directory_visible"`
+	// role assigned to join-link signups when the link doesn't set one
+	// (member / maintainer)
+	DefaultJoinRole *string `json:"default_join_role"`
+	// document storage cap in bytes (0 = deployment default)
+	StorageQuotaBytes *int64 `json:"storage_quota_bytes"`
+	// when true, session issuance outside the regular login flow (invite
+	// auto-login) returns a 2FA challenge marker instead of a token
+	Enforce2FA *bool `json:"enforce_2fa"`
+}
+
+type CreateInviteLinkRequest struct {
+	// Empty falls back to the organization's default join role
+	Role           string `json:"role" binding:"omitempty,oneof=maintainer member"`
+	MaxUses        int    `json:"max_uses"`         // 0 = unlimited
+	ExpiresInHours int    `json:"expires_in_hours"` // 0 = never expires
+}
+
+type InviteLinkResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	Token     string     `json:"token"`
+	Role      string     `json:"role"`
+	MaxUses   int        `json:"max_uses"`
+	Uses      int        `json:"uses"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	// ⚡️ Share JoinLink directly (e.g. in Slack)
+	JoinLink string `json:"join_link"`
+}
+
+type JoinRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,strongpassword"`
+}
+
+type JoinResponse struct {
+	UserID         uuid.UUID `json:"user_id"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Email          string    `json:"email"`
+	Role           string    `json:"role"`
+	Status         string    `json:"status"`
+	// ⚡️ Verification mail is sent to Email before the account activates
+}
+
+type AddOrganizationDomainRequest struct {
+	Domain string `json:"domain" binding:"required"`
+}
+
+type OrganizationDomainResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Domain     string     `json:"domain"`
+	Verified   bool       `json:"verified"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	// ⚡️ Publish TxtRecord in the domain's DNS, then call the verify endpoint
+	TxtRecord string `json:"txt_record"`
+}
+
+type JoinByDomainRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,strongpassword"`
 }
 
 type ResetPasswordRequest struct {
 	OldPassword string `json:"old_password" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=8"`
+	NewPassword string `json:"new_password" binding:"required,strongpassword"`
 }
 
 type ResetPasswordByEmailRequest struct {
 	Token       string `json:"token" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=8"`
+	NewPassword string `json:"new_password" binding:"required,strongpassword"`
 }
 
 type OrganizationDetailsResponse struct {
-	OrganizationID    string    `json:"organization_id"`
-	AccountID         int64     `json:"account_id"`
-	Name              string    `json:"name"`
-	Status            string    `json:"status"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
-	TotalUsers        int       `json:"total_users"`
-	TotalMaintainers  int       `json:"total_maintainers"`
-	TotalMembers      int       `json:"total_members"`
-	OwnerEmail        *string   `json:"owner_email,omitempty"`
-	CreatedByUserID   *string   `json:"created_by_user_id,omitempty"`
-	CreatedByUserName *string   `json:"created_by_user_name,omitempty"`
+	OrganizationID   string    `json:"organization_id"`
+	AccountID        string    `json:"account_id"` // checksummed id, kept as string
+	Name             string    `json:"name"`
+	Status           string    `json:"status"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	DefaultLocale    string    `json:"default_locale"` // email language for new members
+	TotalUsers       int       `json:"total_users"`
+	TotalMaintainers int       `json:"total_maintainers"`
+	TotalMembers     int       `json:"total_members"`
+	// Breakdown visible to owner/maintainer only
+	UsersByStatus  map[string]int `json:"users_by_status,omitempty"`
+	UsersByRole    map[string]int `json:"users_by_role,omitempty"`
+	PendingInvites int            `json:"pending_invites"`
+	// Seats used (active + pending) vs the billing limit (nil = unlimited)
+	SeatsUsed         int     `json:"seats_used"`
+	SeatLimit         *int    `json:"seat_limit,omitempty"`
+	OwnerEmail        *string `json:"owner_email,omitempty"`
+	CreatedByUserID   *string `json:"created_by_user_id,omitempty"`
+	CreatedByUserName *string `json:"created_by_user_name,omitempty"`
+
+	// Message retention policy (0 = keep forever)
+	MessageRetentionDays int        `json:"message_retention_days"`
+	LastPurgeAt          *time.Time `json:"last_purge_at,omitempty"`
+}
+
+// SetSeatLimitRequest comes from the internal billing API; null = unlimited.
+type SetSeatLimitRequest struct {
+	SeatLimit *int `json:"seat_limit"`
+}
+
+// SetTierRequest comes from the internal billing API.
+type SetTierRequest struct {
+	Tier string `json:"tier" binding:"required,oneof=free pro enterprise"`
+}
+
+// ResendInviteResult is one user's outcome in a bulk invite resend.
+// Status is "queued", "skipped" (cooldown) or "failed".
+type ResendInviteResult struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type ResendAllInvitesResponse struct {
+	Total   int                  `json:"total"`
+	Queued  int                  `json:"queued"`
+	Skipped int                  `json:"skipped"`
+	Failed  int                  `json:"failed"`
+	Results []ResendInviteResult `json:"results"`
+	// Present when recent outbox deliveries for this org keep failing.
+	EmailWarning *EmailHealthWarning `json:"email_warning,omitempty"`
+}
+
+// EmailHealthWarning tells the caller that the org's last few emails all
+// failed to deliver, so whatever was just queued will likely fail too.
+type EmailHealthWarning struct {
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	Message             string `json:"message"`
+}
+
+// OutboxEmailSummary is the owner-facing view of an outbox row for the email
+// delivery dashboard — delivery state only, never the rendered body.
+type OutboxEmailSummary struct {
+	ID        uuid.UUID  `json:"id"`
+	Recipient string     `json:"recipient"`
+	Template  string     `json:"template"`
+	Status    string     `json:"status"`
+	Attempts  int        `json:"attempts"`
+	LastError string     `json:"last_error,omitempty"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// AbuseReportAdminRow is one abuse report in the internal moderation
+// listing, joined with the reported org's current flag state so abusive
+// tenants stand out without a second lookup.
+type AbuseReportAdminRow struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id"`
+	MessageID      uuid.UUID  `json:"message_id"`
+	ReporterUserID uuid.UUID  `json:"reporter_user_id"`
+	Category       string     `json:"category"`
+	Details        string     `json:"details,omitempty"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ModerationMode string     `json:"moderation_mode"`
+	AbuseFlaggedAt *time.Time `json:"abuse_flagged_at,omitempty"`
+}
+
+// UserDisplayInfo is the sanitized cross-service view of a user — just what
+// another service needs to render a participant, never password/token fields.
+type UserDisplayInfo struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Role   string `json:"role"`
+	Status string `json:"status"`
+}
+
+// ResolveUsersResponse answers the internal bulk lookup; IDs that don't
+// resolve land in Missing instead of failing the whole call.
+type ResolveUsersResponse struct {
+	Users   []UserDisplayInfo `json:"users"`
+	Missing []string          `json:"missing"`
+}
+
+type TokenIntrospectionRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// TokenIntrospectionResponse is modeled loosely on RFC 7662: inactive tokens
+// only carry `active: false`, without hinting why validation failed.
+type TokenIntrospectionResponse struct {
+	Active         bool   `json:"active"`
+	UserID         string `json:"user_id,omitempty"`
+	OrganizationID string `json:"organization_id,omitempty"`
+	Role           string `json:"role,omitempty"`
+	Exp            int64  `json:"exp,omitempty"`
 }
 
 type AccountVerificationRequest struct {
@@ -122,6 +382,42 @@ type AccountVerificationRequest struct {
 	Email     string `json:"email" binding:"required,email"`
 }
 
+// UpdateBrandingRequest edits email branding; nil fields stay untouched,
+// empty strings clear back to the platform default.
+type UpdateBrandingRequest struct {
+	BrandLogoURL      *string `json:"brand_logo_url"`
+	BrandPrimaryColor *string `json:"brand_primary_color"`
+	EmailFooterText   *string `json:"email_footer_text"`
+}
+
+type BrandingResponse struct {
+	BrandLogoURL      string `json:"brand_logo_url"`
+	BrandPrimaryColor string `json:"brand_primary_color"`
+	EmailFooterText   string `json:"email_footer_text"`
+}
+
+// OnboardingStep is one item of the getting-started checklist.
+type OnboardingStep struct {
+	Key         string     `json:"key"`
+	Label       string     `json:"label"`
+	Complete    bool       `json:"complete"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// OnboardingProgressResponse powers the frontend getting-started widget;
+// served from the organization's stored onboarding_state.
+type OnboardingProgressResponse struct {
+	Steps     []OnboardingStep `json:"steps"`
+	Completed int              `json:"completed"`
+	Total     int              `json:"total"`
+	Dismissed bool             `json:"dismissed"`
+}
+
+// DismissOnboardingRequest hides or re-shows the checklist (owners only).
+type DismissOnboardingRequest struct {
+	Dismissed *bool `json:"dismissed" binding:"required"`
+}
+
 type DailyActivity struct {
 	Date          string `json:"date"`
 	TotalChats    int64  `json:"total_chats"`
@@ -150,19 +446,106 @@ type DashboardStatsResponse struct {
 	TotalMessages int64   `json:"total_messages"`
 	TotalCost     float64 `json:"total_cost"`
 
+	// Document storage (quota 0 = no org-level cap)
+	StorageUsedBytes  int64 `json:"storage_used_bytes"`
+	StorageQuotaBytes int64 `json:"storage_quota_bytes"`
+
 	Last30Days []DailyActivity `json:"last_30_days"`
 }
 
+// UsageReportBucket is one UTC time bucket of the org usage report.
+type UsageReportBucket struct {
+	Bucket           time.Time `json:"bucket"`
+	Predictions      int64     `json:"predictions"`
+	PromptTokens     int64     `json:"prompt_tokens"`
+	CompletionTokens int64     `json:"completion_tokens"`
+	ActiveUsers      int64     `json:"active_users"`
+}
+
+// UserResponse is the sanitized user DTO for API responses: it enumerates
+// fields explicitly, so Password and InviteToken can never leak through a
+// stray c.JSON(user).
 type UserResponse struct {
 	ID             uuid.UUID  `json:"id"`
 	OrganizationID uuid.UUID  `json:"organization_id"`
 	Name           string     `json:"name"`
 	Email          string     `json:"email"`
+	EmailStatus    string     `json:"email_status"` // ok / bounced (invite never arrived)
 	Role           string     `json:"role"`
 	Status         string     `json:"status"`
+	Locale         string     `json:"locale"` // email language, defaults to en
 	InvitedBy      *uuid.UUID `json:"invited_by,omitempty"`
 	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
 	ReactivatedAt  *time.Time `json:"reactivated_at,omitempty"`
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
 }
+
+// ToUserResponse maps the GORM model to its sanitized API shape.
+func ToUserResponse(u User) UserResponse {
+	return UserResponse{
+		ID:             u.ID,
+		OrganizationID: u.OrganizationID,
+		Name:           u.Name,
+		Email:          u.Email,
+		EmailStatus:    u.EmailStatus,
+		Role:           u.Role,
+		Status:         u.Status,
+		Locale:         u.Locale,
+		InvitedBy:      u.InvitedBy,
+		ExpiresAt:      u.ExpiresAt,
+		ReactivatedAt:  u.ReactivatedAt,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
+	}
+}
+
+// PersonalDataExport is the GDPR data-portability bundle served by
+// GET /me/export. Assembled exclusively from the caller's own rows and
+// delivered as a downloadable file rather than the usual envelope.
+type PersonalDataExport struct {
+	GeneratedAt time.Time          `json:"generated_at"`
+	Profile     UserResponse       `json:"profile"`
+	Membership  ExportMembership   `json:"membership"`
+	Chats       []ExportChat       `json:"chats"`
+	AuditTrail  []ExportAuditEntry `json:"audit_trail"`
+}
+
+// ExportMembership describes the caller's place in their organization.
+type ExportMembership struct {
+	OrganizationID   uuid.UUID  `json:"organization_id"`
+	OrganizationName string     `json:"organization_name"`
+	AccountID        string     `json:"account_id"`
+	Role             string     `json:"role"`
+	JoinedAt         *time.Time `json:"joined_at,omitempty"`
+}
+
+// ExportChat is one conversation with its messages, oldest first.
+type ExportChat struct {
+	ID        uuid.UUID       `json:"id"`
+	Title     string          `json:"title"`
+	CreatedAt time.Time       `json:"created_at"`
+	Messages  []ExportMessage `json:"messages" gorm:"-"` // filled per chat, not a relation
+}
+
+type ExportMessage struct {
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportAuditEntry is an audit row where the caller was the actor.
+type ExportAuditEntry struct {
+	Action    string    `json:"action"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ToUserResponses converts a slice of users in listing order.
+func ToUserResponses(users []User) []UserResponse {
+	responses := make([]UserResponse, 0, len(users))
+	for _, u := range users {
+		responses = append(responses, ToUserResponse(u))
+	}
+	return responses
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===============================
+// OutboxEmail
+// ===============================
+// A notification written in the same transaction as the business change it
+// announces (signup verification, invites). The dispatcher polls pending
+// rows and delivers them with at-least-once semantics, so a crash between
+// commit and send no longer loses the email.
+type OutboxEmail struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid;index"`          // scopes owner visibility; nil for mail without org context
+	Template       string     `gorm:"type:varchar(50)"`         // which RenderEmail template produced it (verify / invite / reset)
+	Recipient      string     `gorm:"type:varchar(255);not null"`
+	Subject        string     `gorm:"type:varchar(500);not null"`
+	Body           string     `gorm:"type:text;not null"`
+	Status         string     `gorm:"type:varchar(20);default:'pending';index"` // pending / sent / failed
+	Attempts       int        `gorm:"default:0"`
+	LastError      string     `gorm:"type:varchar(500)"`
+	ClaimedAt      *time.Time // set when a dispatcher claims the row; cleared once the outcome is recorded
+	SentAt         *time.Time
+	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// BeforeCreate assigns the UUID primary key when unset and stamps the
+// creation time, so service code doesn't do it inline.
+func (m *OutboxEmail) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now().UTC()
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===============================
+// PromptTemplate
+// ===============================
+// Reusable org-scoped prompt with {{placeholder}} variables. Templates are
+// rendered server-side at query time in chats-service; the id + variables
+// travel with the query request, never the raw body.
+type PromptTemplate struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name           string    `gorm:"type:varchar(255);not null"`
+	Body           string    `gorm:"type:text;not null"`
+	CreatedBy      uuid.UUID `gorm:"type:uuid"`
+	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// BeforeCreate assigns the UUID primary key when unset and stamps both
+// timestamps, so service code doesn't do it inline.
+func (p *PromptTemplate) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = now
+	}
+	p.UpdatedAt = now
+	return nil
+}
+
+// BeforeUpdate keeps UpdatedAt accurate on every write path, including
+// map-based Updates.
+func (p *PromptTemplate) BeforeUpdate(tx *gorm.DB) error {
+	tx.Statement.SetColumn("updated_at", time.Now().UTC())
+	return nil
+}
+
+type CreatePromptTemplateRequest struct {
+	Name string `json:"name" binding:"required"`
+	Body string `json:"body" binding:"required"`
+}
+
+// UpdatePromptTemplateRequest: nil fields are untouched.
+type UpdatePromptTemplateRequest struct {
+	Name *string `json:"name"`
+	Body *string `json:"body"`
+}
+
+type PromptTemplateResponse struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Body string    `json:"body"`
+	// Placeholder names parsed from Body, so the UI can build a variables form
+	Variables []string  `json:"variables"`
+	CreatedBy uuid.UUID `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withLocalZone pins the process-local zone for the duration of a test, so
+// UTC stamping is asserted against a decidedly non-UTC server timezone.
+func withLocalZone(t *testing.T, name string) {
+	t.Helper()
+	zone, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("zone %s unavailable: %v", name, err)
+	}
+	saved := time.Local
+	time.Local = zone
+	t.Cleanup(func() { time.Local = saved })
+}
+
+func TestBeforeCreateStampsUTCRegardlessOfLocalZone(t *testing.T) {
+	withLocalZone(t, "Asia/Kolkata")
+
+	org := Organization{}
+	if err := org.BeforeCreate(nil); err != nil {
+		t.Fatalf("BeforeCreate failed: %v", err)
+	}
+	user := User{}
+	if err := user.BeforeCreate(nil); err != nil {
+		t.Fatalf("BeforeCreate failed: %v", err)
+	}
+
+	for name, ts := range map[string]time.Time{
+		"organization CreatedAt": org.CreatedAt,
+		"organization UpdatedAt": org.UpdatedAt,
+		"user CreatedAt":         user.CreatedAt,
+		"user UpdatedAt":         user.UpdatedAt,
+	} {
+		if _, offset := ts.Zone(); offset != 0 {
+			t.Errorf("%s stamped with offset %d, want UTC", name, offset)
+		}
+	}
+}
+
+func TestTimestampsSerializeAsRFC3339UTC(t *testing.T) {
+	withLocalZone(t, "Asia/Kolkata")
+
+	user := User{}
+	if err := user.BeforeCreate(nil); err != nil {
+		t.Fatalf("BeforeCreate failed: %v", err)
+	}
+	raw, err := json.Marshal(ToUserResponse(user))
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	created, _ := payload["created_at"].(string)
+	if !strings.HasSuffix(created, "Z") {
+		t.Errorf("created_at = %q, want an RFC3339 UTC timestamp", created)
+	}
+	if _, err := time.Parse(time.RFC3339, created); err != nil {
+		t.Errorf("created_at %q is not RFC3339: %v", created, err)
+	}
+}
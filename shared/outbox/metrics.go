@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"strings"
+	"sync"
+)
+
+// In-process delivery-failure counters labeled by template and failure
+// class, reset on restart like the other debug counters. Surfaced through
+// the admin email-metrics endpoint in users-service.
+
+var (
+	failureMu     sync.Mutex
+	failureCounts = map[string]map[string]int64{} // template → class → count
+)
+
+// classifyError buckets a send error into a coarse failure class so the
+// counters stay low-cardinality no matter what the SMTP server says.
+func classifyError(err error) string {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "auth"):
+		return "auth"
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "deadline"):
+		return "timeout"
+	case strings.Contains(msg, "connect") || strings.Contains(msg, "dial") || strings.Contains(msg, "refused"):
+		return "connect"
+	case strings.Contains(msg, "550") || strings.Contains(msg, "recipient"):
+		return "rejected"
+	default:
+		return "other"
+	}
+}
+
+func countFailure(template, class string) {
+	if template == "" {
+		template = "unknown"
+	}
+	failureMu.Lock()
+	defer failureMu.Unlock()
+	if failureCounts[template] == nil {
+		failureCounts[template] = map[string]int64{}
+	}
+	failureCounts[template][class]++
+}
+
+// FailureCounts returns a snapshot of the counters for reporting.
+func FailureCounts() map[string]map[string]int64 {
+	failureMu.Lock()
+	defer failureMu.Unlock()
+	out := make(map[string]map[string]int64, len(failureCounts))
+	for template, classes := range failureCounts {
+		copied := make(map[string]int64, len(classes))
+		for class, n := range classes {
+			copied[class] = n
+		}
+		out[template] = copied
+	}
+	return out
+}
@@ -0,0 +1,180 @@
+package outbox
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Transactional outbox for emails. Business writes insert an outbox row in
+// their own transaction via Enqueue; a Dispatcher polls pending rows and
+// delivers them. Delivery is at-least-once: a crash after send but before
+// the row is marked sent re-sends on the next poll, which is acceptable for
+// verification and invite mail.
+
+// Enqueue records an email inside the caller's transaction. The message only
+// becomes visible to the dispatcher once that transaction commits, and rolls
+// back with it on failure. orgID and template drive the owner-facing delivery
+// dashboard and the failure metrics; pass nil/"" for mail with no org context.
+func Enqueue(tx *gorm.DB, orgID *uuid.UUID, template, recipient, subject, body string) error {
+	return tx.Create(&models.OutboxEmail{
+		OrganizationID: orgID,
+		Template:       template,
+		Recipient:      recipient,
+		Subject:        subject,
+		Body:           body,
+		Status:         "pending",
+	}).Error
+}
+
+// Dispatcher polls the outbox and delivers pending emails through the given
+// provider. Rows are claimed with FOR UPDATE SKIP LOCKED so several service
+// replicas can dispatch concurrently without double-claiming a batch.
+type Dispatcher struct {
+	db           *gorm.DB
+	provider     utils.EmailProvider
+	interval     time.Duration
+	batchSize    int
+	maxAttempts  int
+	claimTimeout time.Duration
+	stop         chan struct{}
+	done         chan struct{}
+}
+
+// NewDispatcher builds a dispatcher with env-tunable polling. Pass nil for
+// the provider to use the SMTP sender.
+func NewDispatcher(db *gorm.DB, provider utils.EmailProvider) *Dispatcher {
+	if provider == nil {
+		provider = utils.NewEmailSender()
+	}
+	return &Dispatcher{
+		db:           db,
+		provider:     provider,
+		interval:     time.Duration(envInt("OUTBOX_POLL_INTERVAL_SECONDS", 5)) * time.Second,
+		batchSize:    envInt("OUTBOX_BATCH_SIZE", 20),
+		maxAttempts:  envInt("OUTBOX_MAX_ATTEMPTS", 5),
+		claimTimeout: time.Duration(envInt("OUTBOX_CLAIM_TIMEOUT_SECONDS", 60)) * time.Second,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+func envInt(key string, fallback int) int {
+	if v := os.Getenv(key); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+// Start launches the polling loop in a goroutine.
+func (d *Dispatcher) Start() {
+	go func() {
+		defer close(d.done)
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				if err := d.DispatchOnce(); err != nil {
+					fmt.Printf("[OUTBOX WARN] dispatch failed: %v\n", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the polling loop and waits for the in-flight batch to finish.
+func (d *Dispatcher) Stop() {
+	close(d.stop)
+	<-d.done
+}
+
+// DispatchOnce claims one batch of pending rows and delivers them. Exposed
+// for tests and for draining on shutdown.
+//
+// The claim commits before any SMTP traffic so row locks are never held
+// across network sends. Claimed rows are marked with claimed_at (and an
+// attempt bump); other replicas skip them until the claim goes stale, which
+// bounds the duplicate-send window after a crash between send and record —
+// the at-least-once semantics already accepted above.
+func (d *Dispatcher) DispatchOnce() error {
+	var batch []models.OutboxEmail
+
+	// Phase 1: claim a batch and commit, releasing the row locks.
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		staleBefore := time.Now().UTC().Add(-d.claimTimeout)
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND attempts < ? AND (claimed_at IS NULL OR claimed_at < ?)",
+				"pending", d.maxAttempts, staleBefore).
+			Order("created_at").
+			Limit(d.batchSize).
+			Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		ids := make([]uuid.UUID, len(batch))
+		for i := range batch {
+			ids[i] = batch[i].ID
+			batch[i].Attempts++ // mirror the bump below for the max-attempts check
+		}
+		return tx.Model(&models.OutboxEmail{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"attempts":   gorm.Expr("attempts + 1"),
+				"claimed_at": now,
+			}).Error
+	})
+	if err != nil || len(batch) == 0 {
+		return err
+	}
+
+	// Phase 2: send outside any transaction, recording each outcome in its
+	// own short write.
+	for i := range batch {
+		msg := &batch[i]
+		updates := map[string]interface{}{"claimed_at": nil}
+
+		if sendErr := d.provider.SendEmail(msg.Recipient, msg.Subject, msg.Body); sendErr != nil {
+			countFailure(msg.Template, classifyError(sendErr))
+			updates["last_error"] = truncate(sendErr.Error(), 500)
+			if msg.Attempts >= d.maxAttempts {
+				updates["status"] = "failed"
+			}
+		} else {
+			now := time.Now().UTC()
+			updates["status"] = "sent"
+			updates["sent_at"] = &now
+			updates["last_error"] = ""
+		}
+
+		if err := d.db.Model(&models.OutboxEmail{}).
+			Where("id = ?", msg.ID).
+			Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
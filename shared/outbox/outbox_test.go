@@ -0,0 +1,114 @@
+package outbox
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	utils "github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"gorm.io/gorm"
+)
+
+// failingProvider refuses every send, for the retry/at-least-once paths.
+type failingProvider struct{ sends int }
+
+func (p *failingProvider) SendEmail(to, subject, body string) error {
+	p.sends++
+	return errors.New("smtp unavailable")
+}
+
+func pendingCount(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(&models.OutboxEmail{}).Where("status = ?", "pending").Count(&count).Error; err != nil {
+		t.Fatalf("outbox count failed: %v", err)
+	}
+	return count
+}
+
+func TestEnqueueRollsBackWithTheBusinessTransaction(t *testing.T) {
+	db := testutil.NewTestDB(t)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := Enqueue(tx, nil, "verify", "user@example.com", "Verify", "body"); err != nil {
+			return err
+		}
+		return errors.New("business write failed")
+	})
+	if err == nil {
+		t.Fatal("transaction unexpectedly committed")
+	}
+
+	if count := pendingCount(t, db); count != 0 {
+		t.Errorf("%d outbox rows survived a rolled-back transaction, want 0", count)
+	}
+}
+
+func TestEnqueueCommitsWithTheBusinessTransaction(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	org := testutil.NewOrganization(db).Build()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return Enqueue(tx, &org.ID, "invite", "invitee@example.com", "You're invited", "body")
+	})
+	if err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+
+	var row models.OutboxEmail
+	if err := db.First(&row, "recipient = ?", "invitee@example.com").Error; err != nil {
+		t.Fatalf("outbox row missing after commit: %v", err)
+	}
+	if row.Status != "pending" || row.Template != "invite" || row.OrganizationID == nil {
+		t.Errorf("outbox row = %+v, want a pending invite scoped to the org", row)
+	}
+}
+
+func TestDispatchOnceDeliversAndMarksSent(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	emails := utils.NewMemoryEmailProvider()
+
+	if err := Enqueue(db, nil, "verify", "user@example.com", "Verify", "the body"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if err := NewDispatcher(db, emails).DispatchOnce(); err != nil {
+		t.Fatalf("DispatchOnce failed: %v", err)
+	}
+
+	last, ok := emails.Last()
+	if !ok || last.To != "user@example.com" || last.Body != "the body" {
+		t.Fatalf("delivered mail = %+v ok=%v, want the enqueued message", last, ok)
+	}
+
+	var row models.OutboxEmail
+	if err := db.First(&row, "recipient = ?", "user@example.com").Error; err != nil {
+		t.Fatalf("outbox row lookup failed: %v", err)
+	}
+	if row.Status != "sent" || row.SentAt == nil {
+		t.Errorf("row after delivery: status=%s sent_at=%v, want sent with a timestamp", row.Status, row.SentAt)
+	}
+}
+
+func TestFailedSendStaysPendingForRetry(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	provider := &failingProvider{}
+
+	if err := Enqueue(db, nil, "verify", "user@example.com", "Verify", "body"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if err := NewDispatcher(db, provider).DispatchOnce(); err != nil {
+		t.Fatalf("DispatchOnce failed: %v", err)
+	}
+
+	var row models.OutboxEmail
+	if err := db.First(&row, "recipient = ?", "user@example.com").Error; err != nil {
+		t.Fatalf("outbox row lookup failed: %v", err)
+	}
+	if row.Status != "pending" || row.Attempts != 1 || row.LastError == "" {
+		t.Errorf("row after a failed send: %+v, want pending with one recorded attempt", row)
+	}
+	if provider.sends != 1 {
+		t.Errorf("provider saw %d sends, want 1", provider.sends)
+	}
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// GormPlugin wraps every GORM operation in a client span so slow queries
+// show up under the request that issued them. Register with db.Use.
+func GormPlugin() gorm.Plugin {
+	return gormPlugin{}
+}
+
+type gormPlugin struct{}
+
+func (gormPlugin) Name() string { return "tracing" }
+
+func (gormPlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("tracing:before_create", beforeOp("db.create")); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().After("gorm:create").Register("tracing:after_create", afterOp); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("tracing:before_query", beforeOp("db.query")); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("tracing:after_query", afterOp); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("tracing:before_update", beforeOp("db.update")); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("tracing:after_update", afterOp); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("tracing:before_delete", beforeOp("db.delete")); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().After("gorm:delete").Register("tracing:after_delete", afterOp); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("tracing:before_row", beforeOp("db.row")); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().After("gorm:row").Register("tracing:after_row", afterOp); err != nil {
+		return err
+	}
+	if err := db.Callback().Raw().Before("gorm:raw").Register("tracing:before_raw", beforeOp("db.raw")); err != nil {
+		return err
+	}
+	return db.Callback().Raw().After("gorm:raw").Register("tracing:after_raw", afterOp)
+}
+
+// beforeOp starts a client span as a child of the request span carried in
+// Statement.Context; statements outside any trace get none.
+func beforeOp(name string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if FromContext(db.Statement.Context) == nil {
+			return
+		}
+		ctx, span := StartSpan(db.Statement.Context, name, KindClient)
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func afterOp(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(*Span)
+	if !ok {
+		return
+	}
+	if db.Statement.Table != "" {
+		span.SetAttr("db.table", db.Statement.Table)
+	}
+	if db.Error != nil && db.Error != gorm.ErrRecordNotFound {
+		span.Failed = true
+	}
+	span.End()
+}
@@ -0,0 +1,265 @@
+// Package tracing implements the slice of distributed tracing this project
+// actually needs: W3C traceparent propagation between services and a
+// minimal OTLP/HTTP JSON exporter, with no SDK dependency. Spans are only
+// exported when OTEL_EXPORTER_OTLP_ENDPOINT is set; without it the package
+// still generates and propagates trace ids so logs stay correlatable.
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Span kinds, numbered as in the OTLP protocol.
+const (
+	KindInternal = 1
+	KindServer   = 2
+	KindClient   = 3
+)
+
+// Span is one timed operation within a trace. End() must be called exactly
+// once; it hands the span to the exporter (a no-op when none is configured).
+type Span struct {
+	TraceID  string
+	SpanID   string
+	ParentID string
+	Name     string
+	Kind     int
+	Start    time.Time
+	Finish   time.Time
+	Failed   bool
+
+	mu    sync.Mutex
+	attrs map[string]string
+}
+
+type ctxKey struct{}
+
+// newID returns n random bytes hex-encoded (16 for trace ids, 8 for spans).
+func newID(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return strings.Repeat("0", n*2)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// FromContext returns the active span, or nil outside any trace.
+func FromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(ctxKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemoteParent seeds ctx with trace context received from an
+// upstream service, so the next StartSpan continues that trace.
+func ContextWithRemoteParent(ctx context.Context, traceID, spanID string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &Span{TraceID: traceID, SpanID: spanID})
+}
+
+// StartSpan begins a span as a child of whatever is in ctx (a fresh trace
+// when nothing is), and returns a ctx carrying the new span.
+func StartSpan(ctx context.Context, name string, kind int) (context.Context, *Span) {
+	span := &Span{
+		Name:  name,
+		Kind:  kind,
+		Start: time.Now(),
+		SpanID: newID(8),
+	}
+	if parent := FromContext(ctx); parent != nil {
+		span.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	} else {
+		span.TraceID = newID(16)
+	}
+	return context.WithValue(ctx, ctxKey{}, span), span
+}
+
+// SetAttr records a string attribute on the span.
+func (s *Span) SetAttr(key, value string) {
+	s.mu.Lock()
+	if s.attrs == nil {
+		s.attrs = map[string]string{}
+	}
+	s.attrs[key] = value
+	s.mu.Unlock()
+}
+
+// End stamps the span and queues it for export. Safe to call on a nil span.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.Finish = time.Now()
+	exportSpan(s)
+}
+
+// Traceparent renders the W3C header for the span in ctx ("" outside any
+// trace), for injection into outgoing requests.
+func Traceparent(ctx context.Context) string {
+	span := FromContext(ctx)
+	if span == nil {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", span.TraceID, span.SpanID)
+}
+
+// ParseTraceparent extracts trace and span ids from an incoming header.
+func ParseTraceparent(header string) (traceID, spanID string, ok bool) {
+	parts := strings.Split(header, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return "", "", false
+	}
+	if parts[1] == strings.Repeat("0", 32) || parts[2] == strings.Repeat("0", 16) {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// ---------------------------------------------------------------------------
+// OTLP/HTTP JSON export
+// ---------------------------------------------------------------------------
+
+const (
+	exportBatchSize = 100
+	exportInterval  = 5 * time.Second
+)
+
+var (
+	exportOnce sync.Once
+	exportCh   chan *Span
+)
+
+// exportSpan queues a finished span; drops on a full buffer rather than
+// blocking request handling.
+func exportSpan(s *Span) {
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if endpoint == "" {
+		return
+	}
+	exportOnce.Do(func() {
+		exportCh = make(chan *Span, 1024)
+		go exportLoop(strings.TrimSuffix(endpoint, "/") + "/v1/traces")
+	})
+	select {
+	case exportCh <- s:
+	default:
+	}
+}
+
+// exportLoop batches spans and posts them as OTLP/HTTP JSON. Export is
+// best-effort: a collector outage costs spans, never requests.
+func exportLoop(url string) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, exportBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		body, err := json.Marshal(otlpPayload(batch))
+		batch = batch[:0]
+		if err != nil {
+			return
+		}
+		resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			fmt.Printf("[WARN] trace export failed: %v\n", err)
+			return
+		}
+		resp.Body.Close()
+	}
+
+	for {
+		select {
+		case span := <-exportCh:
+			batch = append(batch, span)
+			if len(batch) >= exportBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+type otlpKeyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+func otlpAttr(key, value string) otlpKeyValue {
+	kv := otlpKeyValue{Key: key}
+	kv.Value.StringValue = value
+	return kv
+}
+
+type otlpSpan struct {
+	TraceID      string         `json:"traceId"`
+	SpanID       string         `json:"spanId"`
+	ParentSpanID string         `json:"parentSpanId,omitempty"`
+	Name         string         `json:"name"`
+	Kind         int            `json:"kind"`
+	StartNano    string         `json:"startTimeUnixNano"`
+	EndNano      string         `json:"endTimeUnixNano"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+	Status       struct {
+		Code int `json:"code"`
+	} `json:"status"`
+}
+
+// otlpPayload shapes a batch into the OTLP ExportTraceServiceRequest JSON
+// encoding, which any OpenTelemetry collector accepts on /v1/traces.
+func otlpPayload(batch []*Span) map[string]any {
+	spans := make([]otlpSpan, 0, len(batch))
+	for _, s := range batch {
+		out := otlpSpan{
+			TraceID:      s.TraceID,
+			SpanID:       s.SpanID,
+			ParentSpanID: s.ParentID,
+			Name:         s.Name,
+			Kind:         s.Kind,
+			StartNano:    strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndNano:      strconv.FormatInt(s.Finish.UnixNano(), 10),
+		}
+		s.mu.Lock()
+		for k, v := range s.attrs {
+			out.Attributes = append(out.Attributes, otlpAttr(k, v))
+		}
+		s.mu.Unlock()
+		if s.Failed {
+			out.Status.Code = 2 // STATUS_CODE_ERROR
+		}
+		spans = append(spans, out)
+	}
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "users-service"
+	}
+
+	return map[string]any{
+		"resourceSpans": []map[string]any{{
+			"resource": map[string]any{
+				"attributes": []otlpKeyValue{otlpAttr("service.name", serviceName)},
+			},
+			"scopeSpans": []map[string]any{{
+				"scope": map[string]string{"name": "shared/tracing"},
+				"spans": spans,
+			}},
+		}},
+	}
+}
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStartSpanBeginsAFreshTrace(t *testing.T) {
+	_, span := StartSpan(context.Background(), "GET /ping", KindServer)
+	defer span.End()
+
+	if len(span.TraceID) != 32 || len(span.SpanID) != 16 {
+		t.Errorf("ids = %q/%q, want 16-byte trace and 8-byte span hex ids", span.TraceID, span.SpanID)
+	}
+	if span.ParentID != "" {
+		t.Errorf("fresh trace has parent %q, want none", span.ParentID)
+	}
+}
+
+func TestChildSpanStaysInTheParentsTrace(t *testing.T) {
+	ctx, parent := StartSpan(context.Background(), "GET /ping", KindServer)
+	_, child := StartSpan(ctx, "db.query", KindInternal)
+	defer parent.End()
+	defer child.End()
+
+	if child.TraceID != parent.TraceID {
+		t.Errorf("child trace %q != parent trace %q", child.TraceID, parent.TraceID)
+	}
+	if child.ParentID != parent.SpanID {
+		t.Errorf("child parent %q, want %q", child.ParentID, parent.SpanID)
+	}
+}
+
+func TestRemoteParentContinuesTheUpstreamTrace(t *testing.T) {
+	traceID := strings.Repeat("ab", 16)
+	spanID := strings.Repeat("cd", 8)
+
+	ctx := ContextWithRemoteParent(context.Background(), traceID, spanID)
+	_, span := StartSpan(ctx, "grpc.Predict", KindClient)
+	defer span.End()
+
+	if span.TraceID != traceID || span.ParentID != spanID {
+		t.Errorf("span = trace %q parent %q, want the upstream %q/%q",
+			span.TraceID, span.ParentID, traceID, spanID)
+	}
+}
+
+func TestTraceparentRoundTrips(t *testing.T) {
+	ctx, span := StartSpan(context.Background(), "GET /ping", KindServer)
+	defer span.End()
+
+	header := Traceparent(ctx)
+	traceID, spanID, ok := ParseTraceparent(header)
+	if !ok || traceID != span.TraceID || spanID != span.SpanID {
+		t.Errorf("ParseTraceparent(%q) = %q, %q, %v; want the span's own ids", header, traceID, spanID, ok)
+	}
+
+	if Traceparent(context.Background()) != "" {
+		t.Error("Traceparent outside any trace should be empty")
+	}
+}
+
+func TestParseTraceparentRejectsMalformedHeaders(t *testing.T) {
+	bad := []string{
+		"",
+		"not-a-header",
+		"00-short-short-01",
+		"00-" + strings.Repeat("0", 32) + "-" + strings.Repeat("cd", 8) + "-01", // all-zero trace id
+		"00-" + strings.Repeat("ab", 16) + "-" + strings.Repeat("0", 16) + "-01", // all-zero span id
+	}
+	for _, header := range bad {
+		if _, _, ok := ParseTraceparent(header); ok {
+			t.Errorf("ParseTraceparent(%q) accepted a malformed header", header)
+		}
+	}
+}
+
+func TestEndOnNilSpanIsSafe(t *testing.T) {
+	var span *Span
+	span.End() // must not panic
+}
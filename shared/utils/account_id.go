@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// Account ids are opaque 16-digit strings: 15 random digits plus a Luhn
+// check digit, so valid tenants cannot be enumerated and typos are caught
+// before the database is hit. Legacy sequential ids (the "1100..." range)
+// remain accepted.
+//
+// The random scheme superseded the short-lived ACCOUNT_ID_BASE knob (a
+// configurable base for sequential ids): a deployment-chosen base defeats
+// the non-enumerability that random ids exist to provide, so the env var
+// was removed rather than combined with them. WarnDeprecatedAccountIDBase
+// flags deployments still setting it.
+const accountIDLength = 16
+
+const legacyAccountIDPrefix = "1100"
+
+// ErrInvalidAccountID is returned for malformed account ids.
+var ErrInvalidAccountID = errors.New("invalid account ID")
+
+// WarnDeprecatedAccountIDBase logs when the removed ACCOUNT_ID_BASE env var
+// is still set, so stale deployment config gets noticed at boot instead of
+// being silently ignored.
+func WarnDeprecatedAccountIDBase() {
+	if os.Getenv("ACCOUNT_ID_BASE") != "" {
+		fmt.Println("⚠️ ACCOUNT_ID_BASE is set but no longer used (account ids are random and checksummed); remove it from the deployment config")
+	}
+}
+
+// NormalizeAccountID strips the whitespace that rides along with copy-pasted
+// account ids; run it before ValidateAccountID.
+func NormalizeAccountID(id string) string {
+	return strings.TrimSpace(id)
+}
+
+// GenerateAccountID returns a new random, checksummed 16-digit account id.
+func GenerateAccountID() (string, error) {
+	var b strings.Builder
+	for i := 0; i < accountIDLength-1; i++ {
+		max := int64(10)
+		if i == 0 {
+			max = 9 // leading digit 1-9 keeps the id 16 digits long
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(max))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate account ID: %w", err)
+		}
+		digit := n.Int64()
+		if i == 0 {
+			digit++
+		}
+		b.WriteByte(byte('0' + digit))
+	}
+	body := b.String()
+	return body + string(byte('0'+luhnCheckDigit(body))), nil
+}
+
+// ValidateAccountID rejects malformed account ids before any database
+// lookup. It accepts checksummed ids and legacy sequential ids.
+func ValidateAccountID(id string) error {
+	if len(id) != accountIDLength {
+		return ErrInvalidAccountID
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '0' || id[i] > '9' {
+			return ErrInvalidAccountID
+		}
+	}
+	if strings.HasPrefix(id, legacyAccountIDPrefix) {
+		return nil
+	}
+	if luhnCheckDigit(id[:accountIDLength-1]) != int(id[accountIDLength-1]-'0') {
+		return ErrInvalidAccountID
+	}
+	return nil
+}
+
+// luhnCheckDigit computes the Luhn check digit for a string of digits.
+func luhnCheckDigit(digits string) int {
+	sum := 0
+	// Walk right to left; the check digit will sit at the (doubled) position 0.
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenerateAccountIDShape(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 200; i++ {
+		id, err := GenerateAccountID()
+		if err != nil {
+			t.Fatalf("GenerateAccountID failed: %v", err)
+		}
+		if len(id) != 16 {
+			t.Fatalf("id %q is %d digits, want 16", id, len(id))
+		}
+		if id[0] == '0' {
+			t.Errorf("id %q has a leading zero", id)
+		}
+		if err := ValidateAccountID(id); err != nil {
+			t.Errorf("generated id %q fails validation: %v", id, err)
+		}
+		if seen[id] {
+			t.Errorf("id %q generated twice in 200 draws", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestValidateAccountIDCatchesTypos(t *testing.T) {
+	id, err := GenerateAccountID()
+	if err != nil {
+		t.Fatalf("GenerateAccountID failed: %v", err)
+	}
+
+	// A single mistyped digit must fail the Luhn check
+	mutated := []byte(id)
+	if mutated[3] == '9' {
+		mutated[3] = '0'
+	} else {
+		mutated[3]++
+	}
+	if err := ValidateAccountID(string(mutated)); !errors.Is(err, ErrInvalidAccountID) {
+		t.Errorf("ValidateAccountID(%q) = %v, want ErrInvalidAccountID", mutated, err)
+	}
+
+	for _, bad := range []string{"", "123", "123456789012345a", "12345678901234567"} {
+		if err := ValidateAccountID(bad); !errors.Is(err, ErrInvalidAccountID) {
+			t.Errorf("ValidateAccountID(%q) = %v, want ErrInvalidAccountID", bad, err)
+		}
+	}
+}
+
+func TestValidateAccountIDAcceptsLegacyRange(t *testing.T) {
+	// Pre-checksum sequential ids stay valid regardless of their last digit
+	if err := ValidateAccountID("1100000000000042"); err != nil {
+		t.Errorf("legacy id rejected: %v", err)
+	}
+}
+
+func TestNormalizeAccountID(t *testing.T) {
+	if got := NormalizeAccountID("  1100000000000042\n"); got != "1100000000000042" {
+		t.Errorf("NormalizeAccountID = %q", got)
+	}
+}
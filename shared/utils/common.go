@@ -4,8 +4,16 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/hex"
+	"strings"
 )
 
+// NormalizeEmail lowercases and trims an address so "User@X.com" and
+// "user@x.com" are the same account everywhere. Writes store the normalized
+// form; reads still compare LOWER(email) to cover pre-normalization rows.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
 func GenerateSecureToken(n int) (string, error) {
 	bytes := make([]byte, n)
 	if _, err := rand.Read(bytes); err != nil {
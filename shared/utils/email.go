@@ -6,6 +6,12 @@ import (
 	"os"
 )
 
+// EmailProvider abstracts the outgoing email transport so services can be
+// wired against SMTP in production and an in-memory recorder in tests.
+type EmailProvider interface {
+	SendEmail(to, subject, body string) error
+}
+
 // EmailSender handles sending emails through SMTP
 type EmailSender struct {
 	from     string
@@ -24,9 +30,16 @@ func NewEmailSender() *EmailSender {
 	}
 }
 
-// SendEmail sends an HTML email with subject and body
+// SendEmail sends an HTML email with subject and body.
+// When SMTP is unconfigured outside production (APP_ENV != "production"),
+// the email is logged instead of sent so flows like ForgotPassword stay
+// exercisable during local development; production still hard-fails.
 func (s *EmailSender) SendEmail(to, subject, body string) error {
 	if s.host == "" || s.port == "" || s.from == "" || s.password == "" {
+		if os.Getenv("APP_ENV") != "production" {
+			fmt.Printf("📧 [DEV EMAIL] SMTP unconfigured, logging instead of sending\nTo: %s\nSubject: %s\nBody:\n%s\n", to, subject, body)
+			return nil
+		}
 		return fmt.Errorf("missing SMTP configuration")
 	}
 
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func renderInviteWith(t *testing.T, branding OrgBranding) string {
+	t.Helper()
+	_, body, err := RenderEmail("invite", "en", EmailTemplateData{
+		Name:             "Pat Example",
+		OrganizationName: "Acme",
+		Link:             "https://app.example.com/pl/accept-invite?token=x",
+		Branding:         branding,
+	})
+	if err != nil {
+		t.Fatalf("RenderEmail failed: %v", err)
+	}
+	return body
+}
+
+func TestApplyBrandingUsesConfiguredColorAndLogo(t *testing.T) {
+	body := renderInviteWith(t, OrgBranding{
+		LogoURL:      "https://cdn.example.com/logo.png",
+		PrimaryColor: "#AB12CD",
+		FooterText:   "Sent by Acme",
+	})
+
+	if !strings.Contains(body, "#AB12CD") || strings.Contains(body, "#4F46E5") {
+		t.Error("branded color did not replace the default accent")
+	}
+	if !strings.Contains(body, `src="https://cdn.example.com/logo.png"`) {
+		t.Error("logo is missing from the branded body")
+	}
+	if !strings.Contains(body, "Sent by Acme") {
+		t.Error("footer text is missing from the branded body")
+	}
+}
+
+func TestApplyBrandingIgnoresInvalidValues(t *testing.T) {
+	// A bad logo URL or color must not block the email — the render falls
+	// back to defaults instead
+	body := renderInviteWith(t, OrgBranding{
+		LogoURL:      "javascript:alert(1)",
+		PrimaryColor: "purple",
+	})
+
+	if !strings.Contains(body, "#4F46E5") {
+		t.Error("invalid color did not fall back to the default accent")
+	}
+	if strings.Contains(body, "<img") || strings.Contains(body, "javascript:") {
+		t.Error("an invalid logo URL made it into the body")
+	}
+}
+
+func TestApplyBrandingEscapesFooterHTML(t *testing.T) {
+	body := renderInviteWith(t, OrgBranding{
+		FooterText: `<script>alert("x")</script>`,
+	})
+
+	if strings.Contains(body, "<script>") {
+		t.Error("footer HTML was injected unescaped")
+	}
+	if !strings.Contains(body, "&lt;script&gt;") {
+		t.Error("escaped footer text is missing")
+	}
+}
+
+func TestEmptyBrandingKeepsDefaults(t *testing.T) {
+	body := renderInviteWith(t, OrgBranding{})
+
+	if !strings.Contains(body, "#4F46E5") {
+		t.Error("unbranded body does not use the default accent")
+	}
+	if strings.Contains(body, "<img") || strings.Contains(body, "<hr") {
+		t.Error("unbranded body contains branding chrome")
+	}
+}
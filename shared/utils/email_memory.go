@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"net/url"
+	"regexp"
+	"sync"
+)
+
+// SentEmail is a single message recorded by MemoryEmailProvider.
+type SentEmail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+var hrefPattern = regexp.MustCompile(`href="([^"]+)"`)
+
+// Links extracts every href target from the HTML body, in order.
+func (e SentEmail) Links() []string {
+	var links []string
+	for _, m := range hrefPattern.FindAllStringSubmatch(e.Body, -1) {
+		links = append(links, m[1])
+	}
+	return links
+}
+
+// Token extracts the named query parameter (e.g. "token") from the first
+// link that carries it, or "" when no link does. This is how tests pull
+// verification/invite/reset tokens out of generated email bodies.
+func (e SentEmail) Token(param string) string {
+	for _, link := range e.Links() {
+		u, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+		if v := u.Query().Get(param); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// MemoryEmailProvider is an EmailProvider that records messages instead of
+// sending them, so auth flows can be exercised end to end without SMTP.
+// It is safe for concurrent use.
+type MemoryEmailProvider struct {
+	mu       sync.Mutex
+	messages []SentEmail
+}
+
+// NewMemoryEmailProvider creates an empty in-memory provider.
+func NewMemoryEmailProvider() *MemoryEmailProvider {
+	return &MemoryEmailProvider{}
+}
+
+// SendEmail records the message; it never fails.
+func (p *MemoryEmailProvider) SendEmail(to, subject, body string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = append(p.messages, SentEmail{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+// Messages returns a copy of everything recorded so far.
+func (p *MemoryEmailProvider) Messages() []SentEmail {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]SentEmail, len(p.messages))
+	copy(out, p.messages)
+	return out
+}
+
+// Last returns the most recently recorded message, or false when empty.
+func (p *MemoryEmailProvider) Last() (SentEmail, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.messages) == 0 {
+		return SentEmail{}, false
+	}
+	return p.messages[len(p.messages)-1], true
+}
+
+// Reset discards all recorded messages.
+func (p *MemoryEmailProvider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = nil
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// queuedEmail is a single email waiting to be sent by the queue worker.
+type queuedEmail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailQueue sends emails asynchronously through a single background worker.
+// Shutdown drains queued and in-flight emails within a context deadline.
+type EmailQueue struct {
+	provider EmailProvider
+	jobs     chan queuedEmail
+	done     chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// ErrEmailQueueClosed is returned by Enqueue after Shutdown has started.
+var ErrEmailQueueClosed = errors.New("email queue is shut down")
+
+// NewEmailQueue creates a queue with the given buffer size and starts its worker.
+func NewEmailQueue(provider EmailProvider, buffer int) *EmailQueue {
+	q := &EmailQueue{
+		provider: provider,
+		jobs:     make(chan queuedEmail, buffer),
+		done:     make(chan struct{}),
+	}
+	go q.worker()
+	return q
+}
+
+func (q *EmailQueue) worker() {
+	defer close(q.done)
+	for job := range q.jobs {
+		if err := q.provider.SendEmail(job.To, job.Subject, job.Body); err != nil {
+			fmt.Printf("[WARN] Failed to send email to %s: %v\n", job.To, err)
+		}
+	}
+}
+
+// Enqueue adds an email to the queue. It fails once shutdown has started.
+func (q *EmailQueue) Enqueue(to, subject, body string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrEmailQueueClosed
+	}
+	q.jobs <- queuedEmail{To: to, Subject: subject, Body: body}
+	return nil
+}
+
+// Shutdown stops accepting new emails and waits for queued and in-flight
+// messages to be sent, or for the context deadline. When the deadline hits
+// first, it reports how many emails were dropped.
+func (q *EmailQueue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		dropped := len(q.jobs)
+		return fmt.Errorf("email queue shutdown: dropped %d pending emails: %w", dropped, ctx.Err())
+	}
+}
+
+// ----------------------------------------------------------------------
+// Default process-wide queue used by the services for async email sends.
+// ----------------------------------------------------------------------
+
+var (
+	defaultEmailQueue     *EmailQueue
+	defaultEmailQueueOnce sync.Once
+	defaultEmailProvider  EmailProvider
+)
+
+// SetDefaultEmailProvider overrides the transport used by the default queue
+// (e.g. a MemoryEmailProvider in tests). It must be called before the first
+// QueueEmail; later calls have no effect.
+func SetDefaultEmailProvider(p EmailProvider) {
+	defaultEmailProvider = p
+}
+
+// QueueEmail enqueues an email on the process-wide default queue.
+func QueueEmail(to, subject, body string) error {
+	defaultEmailQueueOnce.Do(func() {
+		provider := defaultEmailProvider
+		if provider == nil {
+			provider = NewEmailSender()
+		}
+		defaultEmailQueue = NewEmailQueue(provider, 100)
+	})
+	return defaultEmailQueue.Enqueue(to, subject, body)
+}
+
+// ShutdownEmailQueue drains the default queue during graceful shutdown.
+// It is a no-op when no email was ever queued.
+func ShutdownEmailQueue(ctx context.Context) error {
+	if defaultEmailQueue == nil {
+		return nil
+	}
+	return defaultEmailQueue.Shutdown(ctx)
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// stallingProvider blocks every send until released, to pin jobs in the
+// queue while a shutdown deadline runs out.
+type stallingProvider struct {
+	release chan struct{}
+}
+
+func (p *stallingProvider) SendEmail(to, subject, body string) error {
+	<-p.release
+	return nil
+}
+
+func TestEmailQueueShutdownFlushesPendingSends(t *testing.T) {
+	provider := NewMemoryEmailProvider()
+	q := NewEmailQueue(provider, 10)
+
+	for i := 0; i < 5; i++ {
+		if err := q.Enqueue(fmt.Sprintf("user%d@example.com", i), "subject", "body"); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := q.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	if got := len(provider.Messages()); got != 5 {
+		t.Errorf("%d emails delivered by shutdown, want 5", got)
+	}
+}
+
+func TestEmailQueueRejectsEnqueueAfterShutdown(t *testing.T) {
+	q := NewEmailQueue(NewMemoryEmailProvider(), 10)
+
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if err := q.Enqueue("late@example.com", "subject", "body"); !errors.Is(err, ErrEmailQueueClosed) {
+		t.Errorf("Enqueue after shutdown: err = %v, want ErrEmailQueueClosed", err)
+	}
+
+	// A second shutdown is a harmless no-op
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Errorf("repeated Shutdown failed: %v", err)
+	}
+}
+
+func TestEmailQueueShutdownHonorsDeadline(t *testing.T) {
+	provider := &stallingProvider{release: make(chan struct{})}
+	q := NewEmailQueue(provider, 10)
+
+	for i := 0; i < 3; i++ {
+		if err := q.Enqueue(fmt.Sprintf("user%d@example.com", i), "subject", "body"); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	err := q.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown with a stalled transport: err = %v, want a deadline error", err)
+	}
+
+	close(provider.release) // unblock the worker so the test goroutine exits
+}
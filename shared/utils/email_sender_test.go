@@ -0,0 +1,28 @@
+package utils
+
+import "testing"
+
+func unsetSMTP(t *testing.T) {
+	t.Helper()
+	for _, v := range []string{"SMTP_USER", "SMTP_PASSWORD", "SMTP_HOST", "SMTP_PORT"} {
+		t.Setenv(v, "")
+	}
+}
+
+func TestSendEmailLogsInsteadOfFailingInDev(t *testing.T) {
+	unsetSMTP(t)
+	t.Setenv("APP_ENV", "development")
+
+	if err := NewEmailSender().SendEmail("dev@example.com", "subject", "body"); err != nil {
+		t.Errorf("unconfigured SMTP outside production must degrade to logging, got %v", err)
+	}
+}
+
+func TestSendEmailHardFailsInProduction(t *testing.T) {
+	unsetSMTP(t)
+	t.Setenv("APP_ENV", "production")
+
+	if err := NewEmailSender().SendEmail("user@example.com", "subject", "body"); err == nil {
+		t.Error("unconfigured SMTP in production must fail, not silently log")
+	}
+}
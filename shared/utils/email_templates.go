@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// OrgBranding customizes outgoing emails with the organization's identity.
+// Zero values mean the platform defaults. Values are validated at write time
+// (organization settings) and defensively re-checked/escaped here, since the
+// template bodies are raw HTML.
+type OrgBranding struct {
+	LogoURL      string
+	PrimaryColor string
+	FooterText   string
+}
+
+// EmailTemplateData carries the values interpolated into email templates.
+type EmailTemplateData struct {
+	Name             string
+	OrganizationName string
+	AccountID        string
+	Link             string
+	InviterName      string
+	Branding         OrgBranding
+}
+
+// defaultButtonColor is the accent used when an org has no branding; it is
+// also the string applyBranding swaps for the org's color.
+const defaultButtonColor = "#4F46E5"
+
+// HexColorPattern matches the only color format branding accepts (#RRGGBB).
+var HexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// ValidLogoURL reports whether raw is an absolute https URL — http or
+// anything fancier (javascript:, data:) is rejected outright.
+func ValidLogoURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	return err == nil && parsed.Scheme == "https" && parsed.Host != ""
+}
+
+// applyBranding decorates a rendered body with the org's identity: logo on
+// top, accent color on the button, footer underneath. Invalid values are
+// ignored rather than rejected — a bad logo URL must not block a password
+// reset — and the footer is HTML-escaped because it is free-form tenant text.
+func applyBranding(body string, b OrgBranding) string {
+	if b.PrimaryColor != "" && HexColorPattern.MatchString(b.PrimaryColor) {
+		body = strings.ReplaceAll(body, defaultButtonColor, b.PrimaryColor)
+	}
+	if b.LogoURL != "" && ValidLogoURL(b.LogoURL) {
+		logo := fmt.Sprintf(
+			`<img src="%s" alt="logo" style="max-height:48px;margin-bottom:16px;">`,
+			html.EscapeString(b.LogoURL),
+		)
+		body = logo + "\n" + body
+	}
+	if b.FooterText != "" {
+		footer := fmt.Sprintf(
+			`<hr style="border:none;border-top:1px solid #E5E7EB;margin-top:24px;"><p style="color:#6B7280;font-size:12px;">%s</p>`,
+			html.EscapeString(b.FooterText),
+		)
+		body = body + "\n" + footer
+	}
+	return body
+}
+
+// DefaultLocale is used when a user has no locale set or no translation exists.
+const DefaultLocale = "en"
+
+const emailButtonStyle = `style="background:#4F46E5;color:white;padding:10px 20px;border-radius:6px;text-decoration:none;"`
+
+type emailTemplate struct {
+	subject func(d EmailTemplateData) string
+	body    func(d EmailTemplateData) string
+}
+
+// emailTemplates maps template kind ("verify", "invite", "reset") to
+// locale-specific variants. English is the reference; other locales fall
+// back to it per-kind when a translation is missing.
+var emailTemplates = map[string]map[string]emailTemplate{
+	"verify": {
+		"en": {
+			subject: func(d EmailTemplateData) string { return "Verify Your Account" },
+			body: func(d EmailTemplateData) string {
+				return fmt.Sprintf(`
+			<h2>Welcome to %s!</h2>
+			<p>Hi %s,</p>
+			<b>Account ID: %s</b>
+			<p>Please verify your account by clicking the button below:</p>
+			<a href="%s" %s>Verify Account</a>
+			<p>This link will expire in 1 hour.</p>
+		`, d.OrganizationName, d.Name, d.AccountID, d.Link, emailButtonStyle)
+			},
+		},
+		"fr": {
+			subject: func(d EmailTemplateData) string { return "Vérifiez votre compte" },
+			body: func(d EmailTemplateData) string {
+				return fmt.Sprintf(`
+			<h2>Bienvenue chez %s !</h2>
+			<p>Bonjour %s,</p>
+			<b>Identifiant de compte : %s</b>
+			<p>Veuillez vérifier votre compte en cliquant sur le bouton ci-dessous :</p>
+			<a href="%s" %s>Vérifier le compte</a>
+			<p>Ce lien expirera dans 1 heure.</p>
+		`, d.OrganizationName, d.Name, d.AccountID, d.Link, emailButtonStyle)
+			},
+		},
+	},
+	"invite": {
+		"en": {
+			subject: func(d EmailTemplateData) string { return "You're invited to join " + d.OrganizationName },
+			body: func(d EmailTemplateData) string {
+				return fmt.Sprintf(`
+		<h2>You're invited to join %s!</h2>
+		<p>Hi %s,</p>
+		<p>%s has invited you to join the organization <strong>%s</strong>.</p>
+		<p>Click the button below to accept the invitation and set your password:</p>
+		<a href="%s" %s>Accept Invitation</a>
+		<p>This link will expire in 48 hours.</p>
+		<p><strong>Note:</strong> When logging in, please use the following account ID: <code>%s</code></p>
+	`, d.OrganizationName, d.Name, d.InviterName, d.OrganizationName, d.Link, emailButtonStyle, d.AccountID)
+			},
+		},
+		"fr": {
+			subject: func(d EmailTemplateData) string { return "Vous êtes invité à rejoindre " + d.OrganizationName },
+			body: func(d EmailTemplateData) string {
+				return fmt.Sprintf(`
+		<h2>Vous êtes invité à rejoindre %s !</h2>
+		<p>Bonjour %s,</p>
+		<p>%s vous a invité à rejoindre l'organisation <strong>%s</strong>.</p>
+		<p>Cliquez sur le bouton ci-dessous pour accepter l'invitation et définir votre mot de passe :</p>
+		<a href="%s" %s>Accepter l'invitation</a>
+		<p>Ce lien expirera dans 48 heures.</p>
+		<p><strong>Remarque :</strong> pour vous connecter, utilisez l'identifiant de compte suivant : <code>%s</code></p>
+	`, d.OrganizationName, d.Name, d.InviterName, d.OrganizationName, d.Link, emailButtonStyle, d.AccountID)
+			},
+		},
+	},
+	"reset": {
+		"en": {
+			subject: func(d EmailTemplateData) string { return "Reset Your Password" },
+			body: func(d EmailTemplateData) string {
+				return fmt.Sprintf(`
+		<h2>Password Reset Request</h2>
+		<p>Hello %s,</p>
+		<p>We received a request to reset your password. Click below to set a new password:</p>
+		<a href="%s" %s>Reset Password</a>
+		<p>This link will expire in 1 hour. If you didn’t request a password reset, you can safely ignore this email.</p>
+	`, d.Name, d.Link, emailButtonStyle)
+			},
+		},
+		"fr": {
+			subject: func(d EmailTemplateData) string { return "Réinitialisez votre mot de passe" },
+			body: func(d EmailTemplateData) string {
+				return fmt.Sprintf(`
+		<h2>Demande de réinitialisation du mot de passe</h2>
+		<p>Bonjour %s,</p>
+		<p>Nous avons reçu une demande de réinitialisation de votre mot de passe. Cliquez ci-dessous pour en définir un nouveau :</p>
+		<a href="%s" %s>Réinitialiser le mot de passe</a>
+		<p>Ce lien expirera dans 1 heure. Si vous n'êtes pas à l'origine de cette demande, vous pouvez ignorer cet email.</p>
+	`, d.Name, d.Link, emailButtonStyle)
+			},
+		},
+	},
+}
+
+// NormalizeLocale maps empty/unknown locales to the default.
+func NormalizeLocale(locale string) string {
+	if locale == "" {
+		return DefaultLocale
+	}
+	return locale
+}
+
+// RenderEmail renders the named template ("verify", "invite", "reset") in
+// the recipient's locale, falling back to English when the locale has no
+// translation. It returns the subject and HTML body.
+func RenderEmail(kind, locale string, d EmailTemplateData) (string, string, error) {
+	variants, ok := emailTemplates[kind]
+	if !ok {
+		return "", "", fmt.Errorf("unknown email template %q", kind)
+	}
+	tpl, ok := variants[NormalizeLocale(locale)]
+	if !ok {
+		tpl = variants[DefaultLocale]
+	}
+	return tpl.subject(d), applyBranding(tpl.body(d), d.Branding), nil
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderEmailPicksRecipientLocale(t *testing.T) {
+	data := EmailTemplateData{Name: "Ada", OrganizationName: "Acme", Link: "https://app.example.com/verify"}
+
+	subject, body, err := RenderEmail("verify", "fr", data)
+	if err != nil {
+		t.Fatalf("RenderEmail failed: %v", err)
+	}
+	if subject != "Vérifiez votre compte" {
+		t.Errorf("fr subject = %q, want the French variant", subject)
+	}
+	if !strings.Contains(body, data.Link) {
+		t.Error("rendered body lost the action link")
+	}
+}
+
+func TestRenderEmailFallsBackToEnglish(t *testing.T) {
+	data := EmailTemplateData{Name: "Ada", OrganizationName: "Acme", Link: "https://app.example.com/verify"}
+
+	english, _, err := RenderEmail("verify", "en", data)
+	if err != nil {
+		t.Fatalf("RenderEmail failed: %v", err)
+	}
+	for _, locale := range []string{"", "de", "xx"} {
+		subject, _, err := RenderEmail("verify", locale, data)
+		if err != nil {
+			t.Fatalf("RenderEmail(%q) failed: %v", locale, err)
+		}
+		if subject != english {
+			t.Errorf("locale %q subject = %q, want the English fallback %q", locale, subject, english)
+		}
+	}
+}
+
+func TestRenderEmailEveryKindAndLocale(t *testing.T) {
+	data := EmailTemplateData{
+		Name:             "Ada",
+		OrganizationName: "Acme",
+		AccountID:        "1100000000000042",
+		InviterName:      "Grace",
+		Link:             "https://app.example.com/action",
+	}
+
+	for _, kind := range []string{"verify", "invite", "reset"} {
+		for _, locale := range []string{"en", "fr"} {
+			subject, body, err := RenderEmail(kind, locale, data)
+			if err != nil {
+				t.Fatalf("RenderEmail(%s, %s) failed: %v", kind, locale, err)
+			}
+			if subject == "" || body == "" {
+				t.Errorf("RenderEmail(%s, %s) produced an empty subject or body", kind, locale)
+			}
+			if !strings.Contains(body, data.Link) {
+				t.Errorf("RenderEmail(%s, %s) body lacks the action link", kind, locale)
+			}
+		}
+	}
+
+	if _, _, err := RenderEmail("newsletter", "en", data); err == nil {
+		t.Error("RenderEmail accepted an unknown template kind")
+	}
+}
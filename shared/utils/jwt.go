@@ -1,12 +1,75 @@
 package utils
 
 import (
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte("YOUR_SUPER_SECRET_KEY")
+// jwtSecret is the legacy single signing secret, read from JWT_SECRET for
+// parity with chats-service. The checked-in default is for local development
+// only; deployments must set their own (and should migrate to a JWT_KEYS
+// keyset, which lets this key be retired — see jwt_keys.go).
+var jwtSecret = []byte(legacySecretFromEnv())
+
+func legacySecretFromEnv() string {
+	if s := os.Getenv("JWT_SECRET"); s != "" {
+		return s
+	}
+	return "YOUR_SUPER_SECRET_KEY"
+}
+
+// defaultTokenTTL applies when no role-specific or default override is set.
+const defaultTokenTTL = 24 * time.Hour
+
+// tokenTTLEnvVars maps roles to their TTL override env vars. Privileged roles
+// can be given shorter sessions than members (e.g. TOKEN_TTL_OWNER=4h).
+// Custom org roles fall back to TOKEN_TTL_DEFAULT.
+var tokenTTLEnvVars = map[string]string{
+	"owner":      "TOKEN_TTL_OWNER",
+	"maintainer": "TOKEN_TTL_MAINTAINER",
+	"member":     "TOKEN_TTL_MEMBER",
+}
+
+// ValidateTokenTTLs checks every configured TTL parses to a positive
+// duration; call it at startup so a typo fails the boot, not the first login.
+func ValidateTokenTTLs() error {
+	for _, envVar := range []string{"TOKEN_TTL_DEFAULT", "TOKEN_TTL_OWNER", "TOKEN_TTL_MAINTAINER", "TOKEN_TTL_MEMBER"} {
+		raw := os.Getenv(envVar)
+		if raw == "" {
+			continue
+		}
+		ttl, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", envVar, raw, err)
+		}
+		if ttl <= 0 {
+			return fmt.Errorf("invalid %s %q: must be positive", envVar, raw)
+		}
+	}
+	return nil
+}
+
+// tokenTTLForRole resolves the session lifetime for a role: role-specific env
+// var, then TOKEN_TTL_DEFAULT, then the built-in 24h. Malformed values were
+// rejected at startup by ValidateTokenTTLs, so parse errors just fall through.
+func tokenTTLForRole(role string) time.Duration {
+	if envVar, ok := tokenTTLEnvVars[role]; ok {
+		if raw := os.Getenv(envVar); raw != "" {
+			if ttl, err := time.ParseDuration(raw); err == nil && ttl > 0 {
+				return ttl
+			}
+		}
+	}
+	if raw := os.Getenv("TOKEN_TTL_DEFAULT"); raw != "" {
+		if ttl, err := time.ParseDuration(raw); err == nil && ttl > 0 {
+			return ttl
+		}
+	}
+	return defaultTokenTTL
+}
 
 type JWTUser struct {
 	UserID         string
@@ -22,6 +85,9 @@ type JWTClaims struct {
 	AccountID      string `json:"account_id"`
 	Role           string `json:"role"`
 	TokenVersion   int    `json:"token_version"`
+	// ImpersonatorID is set only on impersonation tokens and carries the
+	// real admin's user id; audit entries must record it.
+	ImpersonatorID string `json:"impersonator_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -33,11 +99,44 @@ func GenerateJWT(u JWTUser) (string, error) {
 		Role:           u.Role,
 		TokenVersion:   u.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(tokenTTLForRole(u.Role))),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+		},
+	}
+
+	return signWithCurrentKey(claims)
+}
+
+// signWithCurrentKey signs claims with the configured algorithm and the
+// keyset's current key, stamping the kid header so validators can pick the
+// right secret after a rotation.
+func signWithCurrentKey(claims JWTClaims) (string, error) {
+	kid, secret := currentSigningKey()
+	token := jwt.NewWithClaims(JWTSigningMethod(), claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	return token.SignedString(secret)
+}
+
+// ImpersonationTokenTTL keeps impersonation sessions short-lived.
+const ImpersonationTokenTTL = 15 * time.Minute
+
+// GenerateImpersonationJWT issues a token acting as user u on behalf of the
+// admin identified by impersonatorID.
+func GenerateImpersonationJWT(u JWTUser, impersonatorID string) (string, error) {
+	claims := JWTClaims{
+		UserID:         u.UserID,
+		OrganizationID: u.OrganizationID,
+		AccountID:      u.AccountID,
+		Role:           u.Role,
+		TokenVersion:   u.TokenVersion,
+		ImpersonatorID: impersonatorID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(ImpersonationTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return signWithCurrentKey(claims)
 }
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Keyset support for JWT rotation. JWT_KEYS holds kid:secret pairs
+// ("v1:oldsecret,v2:newsecret"), JWT_CURRENT_KID names the key new tokens
+// are signed with, and JWT_ALG picks the HMAC algorithm (HS256, HS384 or
+// HS512; default HS256). Validation accepts any kid still listed in
+// JWT_KEYS, so rotation is: add the new key, flip JWT_CURRENT_KID, remove
+// the old key once the longest token TTL has passed. Tokens with no kid —
+// and deployments with no keyset configured — verify against the legacy
+// single secret, so nothing breaks mid-transition.
+
+var hmacMethods = map[string]*jwt.SigningMethodHMAC{
+	"HS256": jwt.SigningMethodHS256,
+	"HS384": jwt.SigningMethodHS384,
+	"HS512": jwt.SigningMethodHS512,
+}
+
+// JWTSigningMethod returns the configured HMAC algorithm. Unknown values
+// fall back to HS256; ValidateJWTKeyConfig rejects them at startup.
+func JWTSigningMethod() *jwt.SigningMethodHMAC {
+	if method, ok := hmacMethods[os.Getenv("JWT_ALG")]; ok {
+		return method
+	}
+	return jwt.SigningMethodHS256
+}
+
+// jwtKeyset parses JWT_KEYS into kid → secret. Malformed entries are
+// skipped here and rejected by ValidateJWTKeyConfig at startup.
+func jwtKeyset() map[string][]byte {
+	raw := os.Getenv("JWT_KEYS")
+	if raw == "" {
+		return nil
+	}
+	keys := map[string][]byte{}
+	for _, pair := range strings.Split(raw, ",") {
+		kid, secret, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found || kid == "" || secret == "" {
+			continue
+		}
+		keys[kid] = []byte(secret)
+	}
+	return keys
+}
+
+// currentSigningKey resolves the key new tokens are signed with: the
+// JWT_CURRENT_KID entry of the keyset, or the legacy secret (empty kid)
+// when no keyset is configured.
+func currentSigningKey() (string, []byte) {
+	kid := os.Getenv("JWT_CURRENT_KID")
+	if keys := jwtKeyset(); keys != nil {
+		if secret, ok := keys[kid]; ok {
+			return kid, secret
+		}
+	}
+	return "", jwtSecret
+}
+
+// LegacyJWTTokensEnabled controls the deprecation window for kid-less
+// tokens signed with the single legacy secret. It defaults to open so a
+// keyset rollout doesn't invalidate live sessions; set LEGACY_JWT_TOKENS=false
+// once the longest token TTL has passed to retire the legacy key entirely.
+func LegacyJWTTokensEnabled() bool {
+	return strings.ToLower(os.Getenv("LEGACY_JWT_TOKENS")) != "false"
+}
+
+// JWTVerificationKey selects the verification key for a token's kid header.
+// An empty kid means a pre-keyset token and maps to the legacy secret —
+// unless a keyset is active and the legacy window has been closed, in which
+// case kid-less tokens are rejected outright. A kid no longer present in
+// the keyset is rejected (rotated out).
+func JWTVerificationKey(kid string) ([]byte, bool) {
+	if kid == "" {
+		if jwtKeyset() != nil && !LegacyJWTTokensEnabled() {
+			return nil, false
+		}
+		return jwtSecret, true
+	}
+	secret, ok := jwtKeyset()[kid]
+	return secret, ok
+}
+
+// ValidateJWTKeyConfig checks the keyset env vars parse and agree; call it
+// at startup so a typo fails the boot, not the first login.
+func ValidateJWTKeyConfig() error {
+	if alg := os.Getenv("JWT_ALG"); alg != "" {
+		if _, ok := hmacMethods[alg]; !ok {
+			return fmt.Errorf("invalid JWT_ALG %q: must be HS256, HS384 or HS512", alg)
+		}
+	}
+
+	raw := os.Getenv("JWT_KEYS")
+	kid := os.Getenv("JWT_CURRENT_KID")
+	if raw == "" {
+		if kid != "" {
+			return fmt.Errorf("JWT_CURRENT_KID %q set without JWT_KEYS", kid)
+		}
+		return nil
+	}
+
+	keys := map[string]bool{}
+	for _, pair := range strings.Split(raw, ",") {
+		id, secret, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found || id == "" || secret == "" {
+			return fmt.Errorf("invalid JWT_KEYS entry %q: expected kid:secret", pair)
+		}
+		keys[id] = true
+	}
+	if kid == "" {
+		return fmt.Errorf("JWT_KEYS set without JWT_CURRENT_KID")
+	}
+	if !keys[kid] {
+		return fmt.Errorf("JWT_CURRENT_KID %q not present in JWT_KEYS", kid)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// parseWithKeyset parses a token the way validateJWT does: the verification
+// key is selected by the token's kid header.
+func parseWithKeyset(t *testing.T, signed string) (*jwt.Token, *JWTClaims) {
+	t.Helper()
+	claims := &JWTClaims{}
+	token, err := jwt.ParseWithClaims(signed, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		secret, ok := JWTVerificationKey(kid)
+		if !ok {
+			return nil, jwt.ErrTokenUnverifiable
+		}
+		return secret, nil
+	})
+	if err != nil {
+		t.Fatalf("token failed validation: %v", err)
+	}
+	return token, claims
+}
+
+func keysetUser() JWTUser {
+	return JWTUser{
+		UserID:         "u-1",
+		OrganizationID: "o-1",
+		AccountID:      "1100000000000001",
+		Role:           "member",
+		TokenVersion:   1,
+	}
+}
+
+func TestGenerateJWTStampsTheCurrentKid(t *testing.T) {
+	t.Setenv("JWT_KEYS", "v1:old-rotation-secret,v2:new-rotation-secret")
+	t.Setenv("JWT_CURRENT_KID", "v2")
+
+	signed, err := GenerateJWT(keysetUser())
+	if err != nil {
+		t.Fatalf("GenerateJWT failed: %v", err)
+	}
+
+	token, claims := parseWithKeyset(t, signed)
+	if kid, _ := token.Header["kid"].(string); kid != "v2" {
+		t.Errorf("kid = %q, want the current key v2", kid)
+	}
+	if claims.UserID != "u-1" {
+		t.Errorf("claims = %+v, want the signed user", claims)
+	}
+}
+
+func TestRotatedOutButListedKeyStillValidates(t *testing.T) {
+	// A token issued under v1 before the flip to v2: still listed in
+	// JWT_KEYS, so live sessions survive the rotation window
+	t.Setenv("JWT_KEYS", "v1:old-rotation-secret,v2:new-rotation-secret")
+	t.Setenv("JWT_CURRENT_KID", "v1")
+	oldToken, err := GenerateJWT(keysetUser())
+	if err != nil {
+		t.Fatalf("GenerateJWT failed: %v", err)
+	}
+
+	t.Setenv("JWT_CURRENT_KID", "v2")
+	token, _ := parseWithKeyset(t, oldToken)
+	if kid, _ := token.Header["kid"].(string); kid != "v1" {
+		t.Errorf("kid = %q, want the rotated-out v1", kid)
+	}
+
+	// Once v1 is dropped from the keyset entirely, its tokens die
+	t.Setenv("JWT_KEYS", "v2:new-rotation-secret")
+	if _, ok := JWTVerificationKey("v1"); ok {
+		t.Error("a kid removed from the keyset still resolved a key")
+	}
+}
+
+func TestLegacyTokenWindow(t *testing.T) {
+	t.Setenv("JWT_KEYS", "v2:new-rotation-secret")
+	t.Setenv("JWT_CURRENT_KID", "v2")
+
+	// Default: kid-less tokens still verify against the legacy secret
+	if _, ok := JWTVerificationKey(""); !ok {
+		t.Error("kid-less tokens rejected during the default deprecation window")
+	}
+
+	// Window closed: kid-less tokens are rejected outright
+	t.Setenv("LEGACY_JWT_TOKENS", "false")
+	if _, ok := JWTVerificationKey(""); ok {
+		t.Error("kid-less tokens accepted after LEGACY_JWT_TOKENS=false")
+	}
+}
+
+func TestConfigurableHMACAlgorithms(t *testing.T) {
+	t.Setenv("JWT_KEYS", "v1:rotation-secret")
+	t.Setenv("JWT_CURRENT_KID", "v1")
+
+	for alg, method := range map[string]*jwt.SigningMethodHMAC{
+		"HS256": jwt.SigningMethodHS256,
+		"HS384": jwt.SigningMethodHS384,
+		"HS512": jwt.SigningMethodHS512,
+	} {
+		t.Setenv("JWT_ALG", alg)
+		if got := JWTSigningMethod(); got != method {
+			t.Errorf("JWTSigningMethod() under %s = %v", alg, got)
+			continue
+		}
+		signed, err := GenerateJWT(keysetUser())
+		if err != nil {
+			t.Fatalf("GenerateJWT under %s failed: %v", alg, err)
+		}
+		token, _ := parseWithKeyset(t, signed)
+		if token.Method.Alg() != alg {
+			t.Errorf("token signed with %s, want %s", token.Method.Alg(), alg)
+		}
+	}
+
+	// Unknown algorithms fall back to HS256 at sign time and fail the
+	// startup config check
+	t.Setenv("JWT_ALG", "HS224")
+	if JWTSigningMethod() != jwt.SigningMethodHS256 {
+		t.Error("unknown JWT_ALG did not fall back to HS256")
+	}
+	if err := ValidateJWTKeyConfig(); err == nil {
+		t.Error("ValidateJWTKeyConfig accepted an unknown JWT_ALG")
+	}
+}
+
+func TestValidateJWTKeyConfigCatchesMisconfiguration(t *testing.T) {
+	cases := []struct {
+		name, keys, kid string
+	}{
+		{"kid without keyset", "", "v1"},
+		{"keyset without kid", "v1:secret", ""},
+		{"kid missing from keyset", "v1:secret", "v2"},
+		{"malformed entry", "v1secret", "v1"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("JWT_KEYS", tc.keys)
+			t.Setenv("JWT_CURRENT_KID", tc.kid)
+			if err := ValidateJWTKeyConfig(); err == nil {
+				t.Error("misconfiguration passed the startup check")
+			}
+		})
+	}
+
+	t.Run("valid keyset", func(t *testing.T) {
+		t.Setenv("JWT_KEYS", "v1:old-secret,v2:new-secret")
+		t.Setenv("JWT_CURRENT_KID", "v2")
+		if err := ValidateJWTKeyConfig(); err != nil {
+			t.Errorf("a valid keyset failed the startup check: %v", err)
+		}
+	})
+}
+
+// Guard against sign/verify drift: a keyset token minted now must still be
+// valid a moment later under the same configuration.
+func TestKeysetTokenRoundTripsImmediately(t *testing.T) {
+	t.Setenv("JWT_KEYS", "v1:rotation-secret")
+	t.Setenv("JWT_CURRENT_KID", "v1")
+
+	signed, err := GenerateJWT(keysetUser())
+	if err != nil {
+		t.Fatalf("GenerateJWT failed: %v", err)
+	}
+	_, claims := parseWithKeyset(t, signed)
+	if claims.ExpiresAt == nil || !claims.ExpiresAt.After(time.Now()) {
+		t.Errorf("fresh token expiry = %v, want in the future", claims.ExpiresAt)
+	}
+}
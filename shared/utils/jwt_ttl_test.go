@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// tokenExpiry generates a token for role and returns how far in the future
+// its exp claim lands.
+func tokenExpiry(t *testing.T, role string) time.Duration {
+	t.Helper()
+	signed, err := GenerateJWT(JWTUser{
+		UserID: "user-1", OrganizationID: "org-1", Role: role, TokenVersion: 1,
+	})
+	if err != nil {
+		t.Fatalf("GenerateJWT failed: %v", err)
+	}
+
+	claims := &JWTClaims{}
+	if _, err := jwt.ParseWithClaims(signed, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := JWTVerificationKey(kid)
+		if !ok {
+			t.Fatalf("no verification key for kid %q", kid)
+		}
+		return key, nil
+	}); err != nil {
+		t.Fatalf("failed to parse generated token: %v", err)
+	}
+	return time.Until(claims.ExpiresAt.Time)
+}
+
+// within reports whether d is inside a few seconds of want, absorbing the
+// time between signing and the assertion.
+func within(d, want time.Duration) bool {
+	diff := d - want
+	return diff > -5*time.Second && diff <= 0
+}
+
+func TestGenerateJWTUsesPerRoleTTLs(t *testing.T) {
+	t.Setenv("TOKEN_TTL_OWNER", "4h")
+	t.Setenv("TOKEN_TTL_MAINTAINER", "8h")
+	t.Setenv("TOKEN_TTL_MEMBER", "48h")
+
+	cases := map[string]time.Duration{
+		"owner":      4 * time.Hour,
+		"maintainer": 8 * time.Hour,
+		"member":     48 * time.Hour,
+	}
+	for role, want := range cases {
+		if got := tokenExpiry(t, role); !within(got, want) {
+			t.Errorf("%s token expires in %s, want ~%s", role, got, want)
+		}
+	}
+}
+
+func TestGenerateJWTFallsBackToDefaultTTL(t *testing.T) {
+	t.Setenv("TOKEN_TTL_OWNER", "")
+	t.Setenv("TOKEN_TTL_DEFAULT", "2h")
+
+	// No role-specific var → configured default; custom org roles take the
+	// same path
+	for _, role := range []string{"owner", "billing-admin"} {
+		if got := tokenExpiry(t, role); !within(got, 2*time.Hour) {
+			t.Errorf("%s token expires in %s, want ~2h", role, got)
+		}
+	}
+}
+
+func TestGenerateJWTBuiltInDefault(t *testing.T) {
+	t.Setenv("TOKEN_TTL_MEMBER", "")
+	t.Setenv("TOKEN_TTL_DEFAULT", "")
+
+	if got := tokenExpiry(t, "member"); !within(got, defaultTokenTTL) {
+		t.Errorf("member token expires in %s, want ~%s", got, defaultTokenTTL)
+	}
+}
+
+func TestValidateTokenTTLs(t *testing.T) {
+	t.Setenv("TOKEN_TTL_OWNER", "4h")
+	t.Setenv("TOKEN_TTL_DEFAULT", "")
+	if err := ValidateTokenTTLs(); err != nil {
+		t.Errorf("valid configuration rejected: %v", err)
+	}
+
+	t.Setenv("TOKEN_TTL_OWNER", "four hours")
+	if err := ValidateTokenTTLs(); err == nil {
+		t.Error("a malformed TTL passed startup validation")
+	}
+
+	t.Setenv("TOKEN_TTL_OWNER", "-1h")
+	if err := ValidateTokenTTLs(); err == nil {
+		t.Error("a negative TTL passed startup validation")
+	}
+}
@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasher is the pluggable hashing strategy. Hashes are stored in
+// their algorithm's self-describing encoded form ($2a$... for bcrypt,
+// $argon2id$... for argon2id), so verification can dispatch on the stored
+// value regardless of which hasher is currently preferred.
+type PasswordHasher interface {
+	// Name is the PASSWORD_HASHER value selecting this implementation.
+	Name() string
+	// Hash returns the encoded hash for the password.
+	Hash(password string) (string, error)
+	// Verify reports whether the password matches the encoded hash.
+	Verify(encoded, password string) (bool, error)
+}
+
+// ErrUnknownHashFormat is returned when a stored hash matches no known
+// algorithm — a corrupt row, not a wrong password.
+var ErrUnknownHashFormat = errors.New("unrecognized password hash format")
+
+// ================
+// bcrypt (default)
+// ================
+
+type bcryptHasher struct{}
+
+func (bcryptHasher) Name() string { return "bcrypt" }
+
+func (bcryptHasher) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+func (bcryptHasher) Verify(encoded, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ========
+// argon2id
+// ========
+
+// Parameters follow the RFC 9106 second recommended option (64 MiB, t=3,
+// p=4), a reasonable interactive-login cost.
+type argon2idHasher struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	saltLength  uint32
+	keyLength   uint32
+}
+
+func newArgon2idHasher() argon2idHasher {
+	return argon2idHasher{
+		memory:      64 * 1024,
+		iterations:  3,
+		parallelism: 4,
+		saltLength:  16,
+		keyLength:   32,
+	}
+}
+
+func (argon2idHasher) Name() string { return "argon2id" }
+
+func (h argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.iterations, h.memory, h.parallelism, h.keyLength)
+
+	// Standard encoded form, compatible with other argon2id implementations
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.memory, h.iterations, h.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h argon2idHasher) Verify(encoded, password string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, ErrUnknownHashFormat
+	}
+
+	var memory, iterations uint32
+	var parallelism uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &parallelism); err != nil {
+		return false, ErrUnknownHashFormat
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, ErrUnknownHashFormat
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, ErrUnknownHashFormat
+	}
+
+	// Recompute with the hash's own parameters, not the hasher's — old
+	// hashes stay verifiable after a cost bump
+	key := argon2.IDKey([]byte(password), salt, iterations, memory, parallelism, uint32(len(expected)))
+	return subtle.ConstantTimeCompare(key, expected) == 1, nil
+}
+
+// =========
+// selection
+// =========
+
+// preferredHasher returns the implementation PASSWORD_HASHER selects;
+// anything unset or unknown falls back to bcrypt (the historical default).
+func preferredHasher() PasswordHasher {
+	if strings.ToLower(os.Getenv("PASSWORD_HASHER")) == "argon2id" {
+		return newArgon2idHasher()
+	}
+	return bcryptHasher{}
+}
+
+// hasherFor picks the implementation that produced the stored hash.
+func hasherFor(encoded string) (PasswordHasher, error) {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return newArgon2idHasher(), nil
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		return bcryptHasher{}, nil
+	}
+	return nil, ErrUnknownHashFormat
+}
+
+// HashPassword hashes with the preferred algorithm (PASSWORD_HASHER env,
+// bcrypt by default).
+func HashPassword(password string) (string, error) {
+	return preferredHasher().Hash(password)
+}
+
+// VerifyPassword checks a password against a stored hash, dispatching on the
+// hash's algorithm prefix so both bcrypt and argon2id values verify no
+// matter which hasher is currently preferred.
+func VerifyPassword(encoded, password string) (bool, error) {
+	h, err := hasherFor(encoded)
+	if err != nil {
+		return false, err
+	}
+	return h.Verify(encoded, password)
+}
+
+// NeedsRehash reports whether a stored hash uses a different algorithm than
+// the preferred one; login upgrades such hashes transparently after a
+// successful verify.
+func NeedsRehash(encoded string) bool {
+	h, err := hasherFor(encoded)
+	if err != nil {
+		return false
+	}
+	return h.Name() != preferredHasher().Name()
+}
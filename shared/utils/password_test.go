@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHashPasswordFollowsPreference(t *testing.T) {
+	t.Setenv("PASSWORD_HASHER", "")
+	hash, err := HashPassword("correct horse battery")
+	if err != nil {
+		t.Fatalf("HashPassword failed: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$2") {
+		t.Errorf("default hash %q is not bcrypt", hash)
+	}
+
+	t.Setenv("PASSWORD_HASHER", "argon2id")
+	hash, err = HashPassword("correct horse battery")
+	if err != nil {
+		t.Fatalf("HashPassword failed: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		t.Errorf("argon2id hash %q has the wrong prefix", hash)
+	}
+
+	// Unknown values fall back to the historical default instead of failing
+	t.Setenv("PASSWORD_HASHER", "scrypt")
+	hash, err = HashPassword("correct horse battery")
+	if err != nil {
+		t.Fatalf("HashPassword failed: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$2") {
+		t.Errorf("fallback hash %q is not bcrypt", hash)
+	}
+}
+
+func TestVerifyPasswordDispatchesOnStoredFormat(t *testing.T) {
+	// Hashes created under either algorithm must keep verifying regardless
+	// of which hasher is currently preferred
+	t.Setenv("PASSWORD_HASHER", "")
+	bcryptHash, err := HashPassword("correct horse battery")
+	if err != nil {
+		t.Fatalf("bcrypt hash failed: %v", err)
+	}
+	t.Setenv("PASSWORD_HASHER", "argon2id")
+	argonHash, err := HashPassword("correct horse battery")
+	if err != nil {
+		t.Fatalf("argon2id hash failed: %v", err)
+	}
+
+	for _, preferred := range []string{"", "argon2id"} {
+		t.Setenv("PASSWORD_HASHER", preferred)
+		for name, hash := range map[string]string{"bcrypt": bcryptHash, "argon2id": argonHash} {
+			ok, err := VerifyPassword(hash, "correct horse battery")
+			if err != nil || !ok {
+				t.Errorf("preferred=%q: %s hash did not verify: ok=%v err=%v", preferred, name, ok, err)
+			}
+			ok, err = VerifyPassword(hash, "wrong password")
+			if err != nil || ok {
+				t.Errorf("preferred=%q: %s hash accepted a wrong password: ok=%v err=%v", preferred, name, ok, err)
+			}
+		}
+	}
+}
+
+func TestVerifyPasswordRejectsUnknownFormat(t *testing.T) {
+	if _, err := VerifyPassword("plaintext-or-corrupt-row", "anything"); !errors.Is(err, ErrUnknownHashFormat) {
+		t.Errorf("err = %v, want ErrUnknownHashFormat", err)
+	}
+}
+
+func TestNeedsRehashTracksPreference(t *testing.T) {
+	t.Setenv("PASSWORD_HASHER", "")
+	bcryptHash, _ := HashPassword("correct horse battery")
+	t.Setenv("PASSWORD_HASHER", "argon2id")
+	argonHash, _ := HashPassword("correct horse battery")
+
+	if NeedsRehash(argonHash) {
+		t.Error("an argon2id hash needs no rehash while argon2id is preferred")
+	}
+	if !NeedsRehash(bcryptHash) {
+		t.Error("a bcrypt hash must rehash while argon2id is preferred")
+	}
+
+	t.Setenv("PASSWORD_HASHER", "")
+	if NeedsRehash(bcryptHash) {
+		t.Error("a bcrypt hash needs no rehash while bcrypt is preferred")
+	}
+	if !NeedsRehash(argonHash) {
+		t.Error("an argon2id hash must rehash while bcrypt is preferred")
+	}
+
+	if NeedsRehash("garbage") {
+		t.Error("an unrecognizable hash must not be rewritten by the rehash path")
+	}
+}
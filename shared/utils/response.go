@@ -7,6 +7,7 @@ import (
 // GenericResponse structure
 type GenericResponse struct {
 	Error   bool        `json:"error"`
+	Code    string      `json:"code,omitempty"` // stable error code for client-side translation
 	Message string      `json:"message"`
 	Data    interface{} `json:"data"`
 	Status  int         `json:"status"`
@@ -30,3 +31,12 @@ func APIResponse(errorFlag bool, message string, data interface{}, status ...int
 		Status:  code,
 	}
 }
+
+// APIErrorWithCode is APIResponse(true, ...) plus the stable error code, so
+// clients that translate messages themselves can key off it. An empty code is
+// omitted from the JSON.
+func APIErrorWithCode(code, message string, status int) GenericResponse {
+	resp := APIResponse(true, message, nil, status)
+	resp.Code = code
+	return resp
+}
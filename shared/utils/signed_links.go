@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"time"
+)
+
+// Account-action links (verify, invite, reset) used to put the raw single-use
+// token in the URL. Mail providers rewrite links and browsers log them, so a
+// truncated or mangled token produced a confusing generic "invalid token".
+// Signed links wrap the token in an HMAC-signed, expiring payload: any
+// corruption fails the signature check first and gets a specific error, and
+// the single-use token check still runs afterwards as before.
+
+// SignedLinkPrefix marks the new format; tokens without it are legacy raw
+// tokens, accepted while the deprecation window (LEGACY_LINK_TOKENS) is open.
+const SignedLinkPrefix = "v1."
+
+// Link purposes, matching the email template kinds. Signing binds the token
+// to its purpose so a reset link can't be replayed against verification.
+const (
+	LinkPurposeVerify = "verify"
+	LinkPurposeInvite = "invite"
+	LinkPurposeReset  = "reset"
+)
+
+var (
+	ErrLinkCorrupted = errors.New("link corrupted")
+	ErrLinkExpired   = errors.New("link expired")
+)
+
+// signedLinkPayload is what gets signed; the single-use token rides inside.
+type signedLinkPayload struct {
+	Purpose   string `json:"p"`
+	UserID    string `json:"u"`
+	Token     string `json:"t"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// linkSigningSecret prefers a dedicated secret so link keys can rotate
+// independently of JWTs, falling back to the JWT secret.
+func linkSigningSecret() []byte {
+	if s := os.Getenv("LINK_SIGNING_SECRET"); s != "" {
+		return []byte(s)
+	}
+	return jwtSecret
+}
+
+// LegacyLinkTokensEnabled controls the deprecation window for raw (unsigned)
+// tokens in links. Defaults to open; set LEGACY_LINK_TOKENS=false once all
+// outstanding emails predate their own expiry.
+func LegacyLinkTokensEnabled() bool {
+	return strings.ToLower(os.Getenv("LEGACY_LINK_TOKENS")) != "false"
+}
+
+// SignActionToken wraps a single-use token in the signed link format.
+func SignActionToken(purpose, userID, token string, expiresAt time.Time) string {
+	payload, _ := json.Marshal(signedLinkPayload{
+		Purpose:   purpose,
+		UserID:    userID,
+		Token:     token,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return SignedLinkPrefix + encoded + "." + signLink(encoded)
+}
+
+// VerifyActionToken checks signature, purpose and expiry of a signed link
+// value and returns the wrapped single-use token. Any tampering or truncation
+// is ErrLinkCorrupted; a valid signature past its expiry is ErrLinkExpired.
+func VerifyActionToken(purpose, signed string) (string, error) {
+	_, token, err := VerifyActionTokenWithUser(purpose, signed)
+	return token, err
+}
+
+// VerifyActionTokenWithUser is VerifyActionToken but also returns the user
+// the link was minted for, so consumers can answer idempotently when the
+// single-use token has already been spent (double-clicked links).
+func VerifyActionTokenWithUser(purpose, signed string) (userID, token string, err error) {
+	rest, ok := strings.CutPrefix(signed, SignedLinkPrefix)
+	if !ok {
+		return "", "", ErrLinkCorrupted
+	}
+	encoded, signature, ok := strings.Cut(rest, ".")
+	if !ok || !hmac.Equal([]byte(signLink(encoded)), []byte(signature)) {
+		return "", "", ErrLinkCorrupted
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", ErrLinkCorrupted
+	}
+	var payload signedLinkPayload
+	if err := json.Unmarshal(raw, &payload); err != nil || payload.Purpose != purpose {
+		return "", "", ErrLinkCorrupted
+	}
+	if time.Now().UTC().Unix() > payload.ExpiresAt {
+		return "", "", ErrLinkExpired
+	}
+
+	return payload.UserID, payload.Token, nil
+}
+
+func signLink(encoded string) string {
+	mac := hmac.New(sha256.New, linkSigningSecret())
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignedLinkRoundTrip(t *testing.T) {
+	signed := SignActionToken(LinkPurposeVerify, "user-1", "single-use-token", time.Now().UTC().Add(time.Hour))
+	if !strings.HasPrefix(signed, SignedLinkPrefix) {
+		t.Fatalf("signed link %q lacks the %s prefix", signed, SignedLinkPrefix)
+	}
+
+	token, err := VerifyActionToken(LinkPurposeVerify, signed)
+	if err != nil {
+		t.Fatalf("VerifyActionToken failed on a fresh link: %v", err)
+	}
+	if token != "single-use-token" {
+		t.Errorf("unwrapped token = %q, want single-use-token", token)
+	}
+}
+
+func TestSignedLinkRejectsTampering(t *testing.T) {
+	signed := SignActionToken(LinkPurposeVerify, "user-1", "single-use-token", time.Now().UTC().Add(time.Hour))
+
+	// The failure modes leaked links actually hit: provider rewriting
+	// (altered payload), truncation, and cross-purpose replay
+	flipped := []byte(signed)
+	flipped[len(SignedLinkPrefix)+3] ^= 1
+	cases := map[string]string{
+		"altered payload":   string(flipped),
+		"truncated":         signed[:len(signed)-10],
+		"missing signature": strings.SplitN(signed, ".", 3)[0] + "." + strings.SplitN(signed, ".", 3)[1],
+		"no prefix":         strings.TrimPrefix(signed, SignedLinkPrefix),
+		"garbage":           "v1.not-base64.not-a-mac",
+	}
+	for name, mangled := range cases {
+		if _, err := VerifyActionToken(LinkPurposeVerify, mangled); !errors.Is(err, ErrLinkCorrupted) {
+			t.Errorf("%s: err = %v, want ErrLinkCorrupted", name, err)
+		}
+	}
+
+	if _, err := VerifyActionToken(LinkPurposeReset, signed); !errors.Is(err, ErrLinkCorrupted) {
+		t.Errorf("cross-purpose replay: err = %v, want ErrLinkCorrupted", err)
+	}
+}
+
+func TestSignedLinkExpiry(t *testing.T) {
+	signed := SignActionToken(LinkPurposeReset, "user-1", "single-use-token", time.Now().UTC().Add(-time.Minute))
+	if _, err := VerifyActionToken(LinkPurposeReset, signed); !errors.Is(err, ErrLinkExpired) {
+		t.Errorf("expired link: err = %v, want ErrLinkExpired", err)
+	}
+}
+
+func TestLegacyLinkTokensWindow(t *testing.T) {
+	t.Setenv("LEGACY_LINK_TOKENS", "")
+	if !LegacyLinkTokensEnabled() {
+		t.Error("legacy window must default to open")
+	}
+	t.Setenv("LEGACY_LINK_TOKENS", "false")
+	if LegacyLinkTokensEnabled() {
+		t.Error("LEGACY_LINK_TOKENS=false must close the window")
+	}
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParseSort reads the `sort` query param (e.g. "-created_at,name") and
+// builds a safe ORDER BY clause. Each field must appear in the allowed map
+// (API name -> column name); unknown fields are rejected so user input can
+// never reach the query verbatim. A "-" prefix sorts descending. Returns ""
+// when no sort was requested so callers can apply their default.
+func ParseSort(c *gin.Context, allowed map[string]string) (string, error) {
+	raw := c.Query("sort")
+	if raw == "" {
+		return "", nil
+	}
+
+	var clauses []string
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		direction := "ASC"
+		if strings.HasPrefix(part, "-") {
+			direction = "DESC"
+			part = part[1:]
+		}
+
+		column, ok := allowed[part]
+		if !ok {
+			return "", fmt.Errorf("unsupported sort field %q", part)
+		}
+		clauses = append(clauses, column+" "+direction)
+	}
+
+	return strings.Join(clauses, ", "), nil
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sortContext builds a gin context whose request carries the given sort
+// query value.
+func sortContext(sort string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/?sort="+url.QueryEscape(sort), nil)
+	return c
+}
+
+func TestParseSort(t *testing.T) {
+	allowed := map[string]string{
+		"created_at": "created_at",
+		"name":       "LOWER(name)",
+		"role":       "role",
+	}
+
+	cases := []struct {
+		name    string
+		sort    string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty means caller default", sort: "", want: ""},
+		{name: "single field ascending", sort: "name", want: "LOWER(name) ASC"},
+		{name: "dash prefix sorts descending", sort: "-created_at", want: "created_at DESC"},
+		{name: "multiple fields keep order", sort: "-created_at,name", want: "created_at DESC, LOWER(name) ASC"},
+		{name: "whitespace and empty parts are tolerated", sort: " role ,, -name ", want: "role ASC, LOWER(name) DESC"},
+		{name: "unknown field is rejected", sort: "password", wantErr: true},
+		{name: "unknown field among valid ones is rejected", sort: "name,drop_table", wantErr: true},
+		{name: "column names are never taken verbatim", sort: "created_at; DELETE FROM users", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseSort(sortContext(tc.sort), allowed)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("ParseSort(%q) = %q, want error", tc.sort, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSort(%q) failed: %v", tc.sort, err)
+			}
+			if got != tc.want {
+				t.Errorf("ParseSort(%q) = %q, want %q", tc.sort, got, tc.want)
+			}
+		})
+	}
+}
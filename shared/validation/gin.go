@@ -0,0 +1,30 @@
+package validation
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterGinValidators wires the domain validators into gin's binding
+// engine so request structs can use them as tags:
+//
+//	Name     string `json:"name" binding:"required,slug"`
+//	Password string `json:"password" binding:"required,strongpassword"`
+//
+// Call it once at startup, before the first request is bound. Registration
+// errors only occur for empty tag names, so they are ignored.
+func RegisterGinValidators() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	_ = v.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return Slug(fl.Field().String()) == nil
+	})
+	_ = v.RegisterValidation("strongpassword", func(fl validator.FieldLevel) bool {
+		return Password(fl.Field().String()) == nil
+	})
+	_ = v.RegisterValidation("hexcolor6", func(fl validator.FieldLevel) bool {
+		return HexColor(fl.Field().String()) == nil
+	})
+}
@@ -0,0 +1,92 @@
+// Package validation centralizes the domain validators both services need
+// (email format, password policy, slugs, hex colors, role membership), so the
+// rules live in one place instead of being re-derived per handler. Each
+// validator returns a typed apperrors error a handler can surface directly;
+// RegisterGinValidators additionally exposes the non-trivial ones as binding
+// tags for request structs.
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"unicode"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/constants"
+)
+
+// MinPasswordLength is the floor for every password the platform accepts
+// (signup, invites, resets).
+const MinPasswordLength = 8
+
+var (
+	// slugPattern matches the identifier style used for role names and
+	// similar machine-facing handles: lowercase, starts with a letter,
+	// 2-50 chars of [a-z0-9_-].
+	slugPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,49}$`)
+
+	// hexColorPattern matches the only color format branding accepts
+	// (#RRGGBB).
+	hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// Email validates an address with the RFC 5322 parser. Bare addresses only —
+// display names ("Ada <ada@example.com>") are rejected so the stored value is
+// exactly what was given.
+func Email(s string) error {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return apperrors.Validation("invalid email address").WithCode("invalid_email")
+	}
+	return nil
+}
+
+// Password enforces the platform password policy: at least MinPasswordLength
+// characters, containing at least one letter and one digit. Length counts
+// runes, not bytes, so multi-byte characters aren't over-counted.
+func Password(s string) error {
+	var letters, digits, length int
+	for _, r := range s {
+		length++
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+		case unicode.IsDigit(r):
+			digits++
+		}
+	}
+	if length < MinPasswordLength {
+		return apperrors.Validation(fmt.Sprintf("password must be at least %d characters", MinPasswordLength)).WithCode("password_too_short")
+	}
+	if letters == 0 || digits == 0 {
+		return apperrors.Validation("password must contain at least one letter and one digit").WithCode("password_too_weak")
+	}
+	return nil
+}
+
+// Slug validates a machine-facing handle (role names, template keys):
+// lowercase letters, digits, '-' or '_', starting with a letter, 2-50 chars.
+func Slug(s string) error {
+	if !slugPattern.MatchString(s) {
+		return apperrors.Validation("must be lowercase letters, digits, '-' or '_' (2-50 chars)").WithCode("invalid_slug")
+	}
+	return nil
+}
+
+// HexColor validates a #RRGGBB color string.
+func HexColor(s string) error {
+	if !hexColorPattern.MatchString(s) {
+		return apperrors.Validation("must be a hex color like #4F46E5").WithCode("invalid_hex_color")
+	}
+	return nil
+}
+
+// Role validates membership in the built-in role set. Custom org roles
+// resolve through org_roles and are checked against the DB, not here.
+func Role(s string) error {
+	if !constants.IsBuiltInRole(s) {
+		return apperrors.Validation("role must be one of owner, maintainer or member").WithCode("invalid_role")
+	}
+	return nil
+}
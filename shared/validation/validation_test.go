@@ -0,0 +1,100 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+)
+
+func TestEmail(t *testing.T) {
+	valid := []string{
+		"user@example.com",
+		"user+tag@example.co.uk",
+		"Upper.Case@Example.COM",
+		"用户@example.com", // unicode local parts parse under RFC 5322
+	}
+	for _, email := range valid {
+		if err := Email(email); err != nil {
+			t.Errorf("Email(%q) = %v, want nil", email, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"plainaddress",
+		"@example.com",
+		"user@",
+		"Ada Lovelace <ada@example.com>", // display names are rejected
+		"user@example.com ",              // the stored value must be exactly what was given
+	}
+	for _, email := range invalid {
+		err := Email(email)
+		if err == nil {
+			t.Errorf("Email(%q) = nil, want invalid_email", email)
+			continue
+		}
+		if apperrors.Code(err) != "invalid_email" {
+			t.Errorf("Email(%q) code = %q, want invalid_email", email, apperrors.Code(err))
+		}
+	}
+}
+
+func TestPassword(t *testing.T) {
+	if err := Password("Chosen1Password"); err != nil {
+		t.Errorf("a policy-conforming password was rejected: %v", err)
+	}
+	// Length counts runes: eight multi-byte characters are eight, not 24
+	if err := Password("密码密码密码密1"); err != nil {
+		t.Errorf("an eight-rune password was rejected: %v", err)
+	}
+
+	if code := apperrors.Code(Password("Ab1")); code != "password_too_short" {
+		t.Errorf("short password code = %q, want password_too_short", code)
+	}
+	if code := apperrors.Code(Password("lettersonly")); code != "password_too_weak" {
+		t.Errorf("digit-less password code = %q, want password_too_weak", code)
+	}
+	if code := apperrors.Code(Password("12345678")); code != "password_too_weak" {
+		t.Errorf("letter-less password code = %q, want password_too_weak", code)
+	}
+}
+
+func TestSlug(t *testing.T) {
+	for _, slug := range []string{"ab", "billing-admin", "role_2", strings.Repeat("a", 50)} {
+		if err := Slug(slug); err != nil {
+			t.Errorf("Slug(%q) = %v, want nil", slug, err)
+		}
+	}
+	for _, slug := range []string{"", "a", "2fast", "Upper", "has space", "-leading", strings.Repeat("a", 51)} {
+		if err := Slug(slug); err == nil {
+			t.Errorf("Slug(%q) = nil, want invalid_slug", slug)
+		}
+	}
+}
+
+func TestHexColor(t *testing.T) {
+	for _, color := range []string{"#4F46E5", "#abcdef", "#000000"} {
+		if err := HexColor(color); err != nil {
+			t.Errorf("HexColor(%q) = %v, want nil", color, err)
+		}
+	}
+	for _, color := range []string{"", "4F46E5", "#F00", "#GGGGGG", "purple"} {
+		if err := HexColor(color); err == nil {
+			t.Errorf("HexColor(%q) = nil, want invalid_hex_color", color)
+		}
+	}
+}
+
+func TestRole(t *testing.T) {
+	for _, role := range []string{"owner", "maintainer", "member"} {
+		if err := Role(role); err != nil {
+			t.Errorf("Role(%q) = %v, want nil", role, err)
+		}
+	}
+	for _, role := range []string{"", "admin", "Owner"} {
+		if err := Role(role); err == nil {
+			t.Errorf("Role(%q) = nil, want invalid_role", role)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/i18n"
 	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
 	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
 	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/services"
@@ -29,16 +32,46 @@ func (h *AuthenticationHandler) SignUp(c *gin.Context) {
 		return
 	}
 
-	// Call service layer
+	// Call service layer; typed errors carry their status (409 duplicate
+	// name, 422 validation), anything untyped is a genuine 500
 	res, err := h.authService.SignUp(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusConflict, utils.APIResponse(true, "Signup failed: "+err.Error(), nil, http.StatusConflict))
+		status := apperrors.Status(err, http.StatusInternalServerError)
+		// coded errors carry their code and a message translated for the
+		// client's Accept-Language; untyped errors keep the old format
+		if code := apperrors.Code(err); code != "" {
+			c.JSON(status, utils.APIErrorWithCode(code, i18n.ErrorMessage(c.GetHeader("Accept-Language"), err), status))
+			return
+		}
+		c.JSON(status, utils.APIResponse(true, "Signup failed: "+err.Error(), nil, status))
 		return
 	}
 
 	c.JSON(http.StatusCreated, utils.APIResponse(false, "Signup successful", res, http.StatusCreated))
 }
 
+// CheckSignupAvailability answers whether an organization name is still
+// free, for pre-submit validation in signup forms. Deliberately limited to
+// org names: email availability is never exposed (user enumeration).
+func (h *AuthenticationHandler) CheckSignupAvailability(c *gin.Context) {
+	name := strings.TrimSpace(c.Query("organization_name"))
+	if name == "" {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "organization_name query parameter is required", nil, http.StatusBadRequest))
+		return
+	}
+
+	available, err := h.authService.OrganizationNameAvailable(name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.APIResponse(true, "availability check failed", nil, http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "availability checked", gin.H{
+		"organization_name": name,
+		"available":         available,
+	}))
+}
+
 func (h *AuthenticationHandler) VerifyAccount(c *gin.Context) {
 	var req models.VerifyAccountRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -46,9 +79,15 @@ func (h *AuthenticationHandler) VerifyAccount(c *gin.Context) {
 		return
 	}
 
+	// 400 invalid token, 410 expired; re-verifying succeeds idempotently
 	res, err := h.authService.VerifyAccount(c.Request.Context(), req.Token)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "Verification failed: "+err.Error(), nil, http.StatusBadRequest))
+		status := apperrors.Status(err, http.StatusInternalServerError)
+		if code := apperrors.Code(err); code != "" {
+			c.JSON(status, utils.APIErrorWithCode(code, i18n.ErrorMessage(c.GetHeader("Accept-Language"), err), status))
+			return
+		}
+		c.JSON(status, utils.APIResponse(true, "Verification failed: "+err.Error(), nil, status))
 		return
 	}
 
@@ -113,11 +152,16 @@ func (h *AuthenticationHandler) ResetPassword(c *gin.Context) {
 		return
 	}
 
-	claims, exists := c.Get("userClaims")
+	claimsRaw, exists := c.Get("userClaims")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "Unauthorized", nil, http.StatusUnauthorized))
 		return
 	}
+	claims, ok := claimsRaw.(*utils.JWTClaims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "Unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
 
 	resp, err := h.authService.ResetPassword(claims, req.OldPassword, req.NewPassword)
 	if err != nil {
@@ -128,6 +172,19 @@ func (h *AuthenticationHandler) ResetPassword(c *gin.Context) {
 	c.JSON(http.StatusOK, utils.APIResponse(false, "Password updated successfully", resp))
 }
 
+// IntrospectToken validates a JWT for gateways/sidecars and returns its
+// claims, or {active: false} for any invalid token.
+func (h *AuthenticationHandler) IntrospectToken(c *gin.Context) {
+	var req models.TokenIntrospectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "Invalid request payload", nil, http.StatusBadRequest))
+		return
+	}
+
+	res := h.authService.IntrospectToken(req.Token)
+	c.JSON(http.StatusOK, utils.APIResponse(false, "Token introspected", res))
+}
+
 func (h *AuthenticationHandler) ResetPasswordByEmail(c *gin.Context) {
 	var req models.ResetPasswordByEmailRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -137,9 +194,76 @@ func (h *AuthenticationHandler) ResetPasswordByEmail(c *gin.Context) {
 
 	resp, err := h.authService.ResetPasswordByEmail(req.Token, req.NewPassword)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		// 410 for expired links (apperrors.Gone), 400 otherwise
+		status := apperrors.Status(err, http.StatusBadRequest)
+		c.JSON(status, utils.APIResponse(true, err.Error(), nil, status))
 		return
 	}
 
 	c.JSON(http.StatusOK, utils.APIResponse(false, "Password reset successfully", resp))
 }
+
+// Impersonate issues a short-lived impersonation token for the target user.
+func (h *AuthenticationHandler) Impersonate(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	// An impersonated session cannot start another impersonation
+	if claims.ImpersonatorID != "" {
+		c.JSON(http.StatusForbidden, utils.APIResponse(true, "already impersonating", nil, http.StatusForbidden))
+		return
+	}
+
+	targetUUID, ok := ParseUUIDParam(c, "userID")
+	if !ok {
+		return
+	}
+
+	resp, err := h.authService.Impersonate(claims, targetUUID.String())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "impersonation started", resp))
+}
+
+// StopImpersonation swaps an impersonation token back for the admin's own.
+func (h *AuthenticationHandler) StopImpersonation(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	resp, err := h.authService.StopImpersonation(claims)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "impersonation ended", resp))
+}
+
+// JoinByDomain is public: users with an email on a verified org domain get a
+// pending member account plus a verification email.
+func (h *AuthenticationHandler) JoinByDomain(c *gin.Context) {
+	var req models.JoinByDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid request: "+err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	resp, err := h.authService.JoinByDomain(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "account created, verification email sent", resp))
+}
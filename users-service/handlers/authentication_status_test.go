@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/constants"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/outbox"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// authFixture wires the real service over an ephemeral DB behind a bare
+// router, so the tests below pin the HTTP status per error class — the
+// contract the frontend and the alerting both depend on.
+type authFixture struct {
+	router *gin.Engine
+	db     *gorm.DB
+	emails *utils.MemoryEmailProvider
+	clock  *utils.FakeClock
+}
+
+func newAuthFixture(t *testing.T) *authFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db := testutil.NewTestDB(t)
+	emails := utils.NewMemoryEmailProvider()
+	clock := utils.NewFakeClock(time.Now().UTC())
+	handler := NewAuthenticationHandler(services.NewAuthenticationService(db, emails, clock))
+
+	router := gin.New()
+	router.POST("/signup", handler.SignUp)
+	router.POST("/verify", handler.VerifyAccount)
+
+	return &authFixture{router: router, db: db, emails: emails, clock: clock}
+}
+
+func (f *authFixture) post(t *testing.T, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	f.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// verificationToken signs an org up and pulls the verification token out of
+// the delivered email, the way a real recipient would.
+func (f *authFixture) verificationToken(t *testing.T, orgName string) string {
+	t.Helper()
+	rec := f.post(t, "/signup", `{
+		"organization_name": "`+orgName+`",
+		"name": "Owner Person",
+		"email": "owner@`+strings.ToLower(orgName)+`.example.com",
+		"password": "test-password-123"
+	}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("signup returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if err := outbox.NewDispatcher(f.db, f.emails).DispatchOnce(); err != nil {
+		t.Fatalf("outbox dispatch failed: %v", err)
+	}
+	email, ok := f.emails.Last()
+	if !ok {
+		t.Fatal("no verification email was delivered")
+	}
+	token := email.Token("token")
+	if token == "" {
+		t.Fatalf("verification email carries no token: %s", email.Body)
+	}
+	return token
+}
+
+func TestSignUpStatusCodes(t *testing.T) {
+	f := newAuthFixture(t)
+
+	// First signup succeeds
+	if rec := f.post(t, "/signup", `{"organization_name": "Acme", "name": "A", "email": "a@example.com", "password": "test-password-123"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("signup = %d, want 201: %s", rec.Code, rec.Body.String())
+	}
+
+	cases := []struct {
+		name string
+		body string
+		want int
+	}{
+		{
+			name: "duplicate organization name is a conflict",
+			body: `{"organization_name": "Acme", "name": "B", "email": "b@example.com", "password": "test-password-123"}`,
+			want: http.StatusConflict,
+		},
+		{
+			name: "invalid email is a validation error",
+			body: `{"organization_name": "Beta", "name": "B", "email": "not-an-email", "password": "test-password-123"}`,
+			want: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "weak password is a validation error",
+			body: `{"organization_name": "Gamma", "name": "C", "email": "c@example.com", "password": "short"}`,
+			want: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "malformed JSON is a bad request",
+			body: `{not json`,
+			want: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := f.post(t, "/signup", tc.body)
+			if rec.Code != tc.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tc.want, rec.Body.String())
+			}
+			if tc.want >= 500 {
+				return
+			}
+			// Client errors never read as alerting-worthy 500s
+			var envelope map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+				t.Errorf("response is not JSON: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestVerifyAccountStatusCodes(t *testing.T) {
+	f := newAuthFixture(t)
+	token := f.verificationToken(t, "Verifia")
+
+	// Unknown token: 400
+	if rec := f.post(t, "/verify", `{"token": "no-such-token"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown token = %d, want 400: %s", rec.Code, rec.Body.String())
+	}
+
+	// Valid token: 200
+	if rec := f.post(t, "/verify", `{"token": "`+token+`"}`); rec.Code != http.StatusOK {
+		t.Fatalf("verification = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+
+	// Re-verifying the same account is an idempotent 200, not an error
+	if rec := f.post(t, "/verify", `{"token": "`+token+`"}`); rec.Code != http.StatusOK {
+		t.Errorf("re-verification = %d, want idempotent 200: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestVerifyAccountExpiredTokenIsGone(t *testing.T) {
+	f := newAuthFixture(t)
+	token := f.verificationToken(t, "Expira")
+
+	f.clock.Advance(constants.VerifyTokenTTL + time.Hour)
+
+	rec := f.post(t, "/verify", `{"token": "`+token+`"}`)
+	if rec.Code != http.StatusGone {
+		t.Errorf("expired token = %d, want 410: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/cache"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/db"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/middleware"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CacheStats reports hit/miss counters for the auth-path caches. It's a plain
+// function (no service behind it) exposed under /admin for support tooling.
+func CacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.APIResponse(false, "cache stats fetched", gin.H{
+		"users":         cache.Users.Stats(),
+		"organizations": cache.Organizations.Stats(),
+	}))
+}
+
+// SlowRequests reports the per-route slow-request counters collected by
+// middleware.SlowRequestLogger.
+func SlowRequests(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.APIResponse(false, "slow request stats fetched", middleware.SlowRequestStats()))
+}
+
+// DBRetryStats reports how many DB operations needed transient-error
+// retries; a climbing number usually means the database is flapping.
+func DBRetryStats(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.APIResponse(false, "db retry stats fetched", gin.H{
+		"retried_operations": db.RetriedOperations(),
+	}))
+}
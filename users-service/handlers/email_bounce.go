@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// sesBounceNotification is the subset of an AWS SES bounce notification we
+// care about.
+type sesBounceNotification struct {
+	NotificationType string `json:"notificationType"`
+	Bounce           struct {
+		BouncedRecipients []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"bouncedRecipients"`
+	} `json:"bounce"`
+}
+
+// sendGridEvent is one entry of a SendGrid event webhook batch.
+type sendGridEvent struct {
+	Email string `json:"email"`
+	Event string `json:"event"`
+}
+
+// parseBouncedEmails extracts bounced recipient addresses from either an SES
+// bounce notification or a SendGrid event batch.
+func parseBouncedEmails(body []byte) []string {
+	var emails []string
+
+	var ses sesBounceNotification
+	if err := json.Unmarshal(body, &ses); err == nil && strings.EqualFold(ses.NotificationType, "Bounce") {
+		for _, r := range ses.Bounce.BouncedRecipients {
+			if r.EmailAddress != "" {
+				emails = append(emails, r.EmailAddress)
+			}
+		}
+		return emails
+	}
+
+	var events []sendGridEvent
+	if err := json.Unmarshal(body, &events); err == nil {
+		for _, e := range events {
+			if strings.EqualFold(e.Event, "bounce") && e.Email != "" {
+				emails = append(emails, e.Email)
+			}
+		}
+	}
+
+	return emails
+}
+
+// EmailBounceWebhook ingests provider bounce notifications (SES/SendGrid)
+// and flags the affected users' emails as bounced.
+func (h *UserHandler) EmailBounceWebhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "failed to read request body", nil, http.StatusBadRequest))
+		return
+	}
+
+	emails := parseBouncedEmails(body)
+	if len(emails) == 0 {
+		// Nothing actionable (e.g. delivery notification); acknowledge so the
+		// provider does not retry.
+		c.JSON(http.StatusOK, utils.APIResponse(false, "no bounced recipients in payload", gin.H{"flagged": 0}))
+		return
+	}
+
+	flagged, err := h.userService.MarkEmailsBounced(emails)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.APIResponse(true, err.Error(), nil, http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "bounce recorded", gin.H{"flagged": flagged}))
+}
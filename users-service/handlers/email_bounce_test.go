@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBouncedEmailsSES(t *testing.T) {
+	payload := []byte(`{
+		"notificationType": "Bounce",
+		"bounce": {
+			"bouncedRecipients": [
+				{"emailAddress": "gone@example.com"},
+				{"emailAddress": "also.gone@example.com"}
+			]
+		}
+	}`)
+
+	got := parseBouncedEmails(payload)
+	want := []string{"gone@example.com", "also.gone@example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseBouncedEmails = %v, want %v", got, want)
+	}
+}
+
+func TestParseBouncedEmailsSendGrid(t *testing.T) {
+	payload := []byte(`[
+		{"email": "delivered@example.com", "event": "delivered"},
+		{"email": "gone@example.com", "event": "bounce"},
+		{"email": "opened@example.com", "event": "open"},
+		{"email": "also.gone@example.com", "event": "Bounce"}
+	]`)
+
+	got := parseBouncedEmails(payload)
+	want := []string{"gone@example.com", "also.gone@example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseBouncedEmails = %v, want %v", got, want)
+	}
+}
+
+func TestParseBouncedEmailsNothingActionable(t *testing.T) {
+	cases := map[string]string{
+		"SES delivery notification": `{"notificationType": "Delivery", "bounce": {"bouncedRecipients": [{"emailAddress": "x@example.com"}]}}`,
+		"SendGrid without bounces":  `[{"email": "x@example.com", "event": "click"}]`,
+		"empty recipients":          `{"notificationType": "Bounce", "bounce": {"bouncedRecipients": []}}`,
+		"garbage":                   `not json at all`,
+	}
+	for name, payload := range cases {
+		if got := parseBouncedEmails([]byte(payload)); len(got) != 0 {
+			t.Errorf("%s: parseBouncedEmails = %v, want none", name, got)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func previewRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/dev/email-preview", EmailPreview)
+	return router
+}
+
+func renderPreview(t *testing.T, router *gin.Engine, payload string) (*httptest.ResponseRecorder, map[string]string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/dev/email-preview", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+
+	var envelope struct {
+		Data map[string]string `json:"data"`
+	}
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("response is not the JSON envelope: %s", rec.Body.String())
+		}
+	}
+	return rec, envelope.Data
+}
+
+func TestEmailPreviewReflectsSampleData(t *testing.T) {
+	router := previewRouter()
+
+	rec, data := renderPreview(t, router, `{
+		"template": "invite",
+		"data": {
+			"name": "Pat Example",
+			"organization_name": "Acme",
+			"link": "https://app.example.com/pl/accept-invite?token=sample"
+		},
+		"branding": {"primary_color": "#1A2B3C"}
+	}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(data["html"], "Pat Example") || !strings.Contains(data["html"], "Acme") {
+		t.Errorf("rendered HTML lacks the supplied sample data")
+	}
+	if !strings.Contains(data["html"], "#1A2B3C") {
+		t.Error("rendered HTML ignores the supplied branding color")
+	}
+	if !strings.Contains(data["text"], "Pat Example") {
+		t.Errorf("plain-text flattening lacks the sample name: %q", data["text"])
+	}
+	if strings.Contains(data["text"], "<") {
+		t.Errorf("plain-text flattening still contains markup: %q", data["text"])
+	}
+}
+
+func TestEmailPreviewEscapesFreeFormInput(t *testing.T) {
+	router := previewRouter()
+
+	rec, data := renderPreview(t, router, `{
+		"template": "verify",
+		"data": {"name": "<script>alert(1)</script>", "organization_name": "Acme"}
+	}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(data["html"], "<script>") {
+		t.Error("free-form sample data was interpolated unescaped")
+	}
+	if !strings.Contains(data["html"], "&lt;script&gt;") {
+		t.Error("escaped sample data is missing from the preview")
+	}
+}
+
+func TestEmailPreviewRejectsBadRequests(t *testing.T) {
+	router := previewRouter()
+
+	for name, payload := range map[string]string{
+		"unknown template": `{"template": "newsletter"}`,
+		"missing template": `{"data": {"name": "Pat"}}`,
+		"malformed JSON":   `{"template":`,
+	} {
+		rec, _ := renderPreview(t, router, payload)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s answered %d, want 400", name, rec.Code)
+		}
+	}
+}
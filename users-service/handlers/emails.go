@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/outbox"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/services"
+	"github.com/gin-gonic/gin"
+)
+
+type EmailHandler struct {
+	emailService services.EmailService
+}
+
+func NewEmailHandler(emailService services.EmailService) *EmailHandler {
+	return &EmailHandler{emailService: emailService}
+}
+
+// ListEmails returns recent outbox messages for the caller's org with their
+// delivery state. ?status=failed narrows to undelivered mail.
+func (h *EmailHandler) ListEmails(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	status := c.Query("status")
+	switch status {
+	case "", "pending", "sent", "failed":
+	default:
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "status must be pending, sent or failed", nil, http.StatusBadRequest))
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	emails, err := h.emailService.ListEmails(claims.OrganizationID, status, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.APIResponse(true, err.Error(), nil, http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "emails fetched", gin.H{"emails": emails}))
+}
+
+// RetryEmail re-queues a failed message for delivery.
+func (h *EmailHandler) RetryEmail(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	if err := h.emailService.RetryEmail(claims.OrganizationID, c.Param("id")); err != nil {
+		status := apperrors.Status(err, http.StatusBadRequest)
+		c.JSON(status, utils.APIErrorWithCode(apperrors.Code(err), err.Error(), status))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "email re-queued for delivery", nil))
+}
+
+// EmailMetrics reports the in-process delivery-failure counters, labeled by
+// template and failure class. Like the cache stats, it's a plain function
+// under /admin for support tooling.
+func EmailMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.APIResponse(false, "email metrics fetched", gin.H{
+		"failures": outbox.FailureCounts(),
+	}))
+}
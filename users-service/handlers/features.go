@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/features"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Features dumps the deployment's env-driven feature toggles, so support can
+// see at a glance which capabilities exist in this install. Owner only —
+// unlike runtime flags these never change without a restart.
+func Features(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.APIResponse(false, "active features fetched", features.Active()))
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/flags"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FeatureFlags lists every flag row (global values and per-org overrides).
+// Service-key protected: flags are ops tooling, not tenant settings.
+func FeatureFlags(c *gin.Context) {
+	all, err := flags.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.APIResponse(true, err.Error(), nil, http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "feature flags fetched", all))
+}
+
+// SetFeatureFlag flips a flag at runtime without a restart. Omitting
+// organization_id targets the global value; setting it creates or updates a
+// per-tenant override.
+func SetFeatureFlag(c *gin.Context) {
+	var req models.SetFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "Invalid request payload", nil, http.StatusBadRequest))
+		return
+	}
+
+	var orgID *uuid.UUID
+	if req.OrganizationID != nil && *req.OrganizationID != "" {
+		parsed, err := uuid.Parse(*req.OrganizationID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid organization id", nil, http.StatusBadRequest))
+			return
+		}
+		orgID = &parsed
+	}
+
+	if err := flags.Set(req.Name, orgID, *req.Enabled); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "feature flag updated", gin.H{
+		"name":    req.Name,
+		"enabled": *req.Enabled,
+	}))
+}
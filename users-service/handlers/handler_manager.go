@@ -8,6 +8,10 @@ type HandlerManager struct {
 	AuthenticationHandler *AuthenticationHandler
 	UserHandler           *UserHandler
 	OrganizationHandler   *OrganizationHandler
+	InviteLinkHandler     *InviteLinkHandler
+	PromptTemplateHandler *PromptTemplateHandler
+	OrgRoleHandler        *OrgRoleHandler
+	EmailHandler          *EmailHandler
 }
 
 func NewHandlerManager(sm *services.ServiceManager) *HandlerManager {
@@ -15,5 +19,9 @@ func NewHandlerManager(sm *services.ServiceManager) *HandlerManager {
 		AuthenticationHandler: NewAuthenticationHandler(sm.AuthenticationService),
 		UserHandler:           NewUserHandler(sm.UserService),
 		OrganizationHandler:   NewOrganizationHandler(sm.OrganizationService),
+		InviteLinkHandler:     NewInviteLinkHandler(sm.InviteLinkService),
+		PromptTemplateHandler: NewPromptTemplateHandler(sm.PromptTemplateService),
+		OrgRoleHandler:        NewOrgRoleHandler(sm.OrgRoleService),
+		EmailHandler:          NewEmailHandler(sm.EmailService),
 	}
 }
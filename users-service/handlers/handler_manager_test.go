@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/services"
+)
+
+// Smoke test: one handler per service, all constructed from the manager.
+func TestNewHandlerManagerWiresEveryHandler(t *testing.T) {
+	hm := NewHandlerManager(services.NewServiceManager(testutil.NewTestDB(t)))
+
+	if hm.AuthenticationHandler == nil {
+		t.Error("AuthenticationHandler is nil")
+	}
+	if hm.UserHandler == nil {
+		t.Error("UserHandler is nil")
+	}
+	if hm.OrganizationHandler == nil {
+		t.Error("OrganizationHandler is nil")
+	}
+	if hm.InviteLinkHandler == nil {
+		t.Error("InviteLinkHandler is nil")
+	}
+	if hm.PromptTemplateHandler == nil {
+		t.Error("PromptTemplateHandler is nil")
+	}
+	if hm.OrgRoleHandler == nil {
+		t.Error("OrgRoleHandler is nil")
+	}
+	if hm.EmailHandler == nil {
+		t.Error("EmailHandler is nil")
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InviteLinkHandler struct {
+	inviteLinkService services.InviteLinkService
+}
+
+func NewInviteLinkHandler(inviteLinkService services.InviteLinkService) *InviteLinkHandler {
+	return &InviteLinkHandler{inviteLinkService: inviteLinkService}
+}
+
+func (h *InviteLinkHandler) CreateInviteLink(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	var req models.CreateInviteLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid request: "+err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	creatorID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid user id in token", nil, http.StatusBadRequest))
+		return
+	}
+	orgID, err := uuid.Parse(claims.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid organization id in token", nil, http.StatusBadRequest))
+		return
+	}
+
+	resp, err := h.inviteLinkService.CreateInviteLink(creatorID, claims.Role, orgID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "invite link created", resp))
+}
+
+func (h *InviteLinkHandler) GetInviteLinks(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	links, err := h.inviteLinkService.GetInviteLinks(claims.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.APIResponse(true, err.Error(), nil, http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "invite links fetched", links))
+}
+
+func (h *InviteLinkHandler) RevokeInviteLink(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	linkUUID, ok := ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.inviteLinkService.RevokeInviteLink(claims.OrganizationID, linkUUID.String()); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "invite link revoked", nil))
+}
+
+// Join is public: a new user consumes a shared link token to request a
+// pending account that still needs email verification.
+func (h *InviteLinkHandler) Join(c *gin.Context) {
+	var req models.JoinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid request: "+err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	resp, err := h.inviteLinkService.JoinByLink(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "account created, verification email sent", resp))
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type OrgRoleHandler struct {
+	orgRoleService services.OrgRoleService
+}
+
+func NewOrgRoleHandler(orgRoleService services.OrgRoleService) *OrgRoleHandler {
+	return &OrgRoleHandler{orgRoleService: orgRoleService}
+}
+
+func (h *OrgRoleHandler) CreateOrgRole(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	var req models.CreateOrgRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid request: "+err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	orgID, err := uuid.Parse(claims.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid organization id in token", nil, http.StatusBadRequest))
+		return
+	}
+
+	resp, err := h.orgRoleService.CreateOrgRole(orgID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "role created", resp))
+}
+
+func (h *OrgRoleHandler) GetOrgRoles(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	roles, err := h.orgRoleService.GetOrgRoles(claims.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.APIResponse(true, err.Error(), nil, http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "roles fetched", roles))
+}
+
+func (h *OrgRoleHandler) UpdateOrgRole(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	var req models.UpdateOrgRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid request: "+err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	resp, err := h.orgRoleService.UpdateOrgRole(claims.OrganizationID, c.Param("name"), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "role updated", resp))
+}
+
+// DeleteOrgRole blocks when users still hold the role unless ?reassign_to=
+// names a valid replacement role.
+func (h *OrgRoleHandler) DeleteOrgRole(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	if err := h.orgRoleService.DeleteOrgRole(claims.OrganizationID, c.Param("name"), c.Query("reassign_to")); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "role deleted", nil))
+}
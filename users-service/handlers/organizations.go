@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"encoding/csv"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
 	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
 	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/services"
 	"github.com/gin-gonic/gin"
@@ -16,6 +21,56 @@ func NewOrganizationHandler(orgService services.OrganizationService) *Organizati
 	return &OrganizationHandler{orgService: orgService}
 }
 
+// SetSeatLimit is service-to-service only (billing); routed behind
+// ServiceKeyAuth, so there are no user claims — the org comes from the path.
+func (h *OrganizationHandler) SetSeatLimit(c *gin.Context) {
+	var req models.SetSeatLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid request: "+err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	if err := h.orgService.SetSeatLimit(c.Param("id"), req.SeatLimit); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "seat limit updated", gin.H{"seat_limit": req.SeatLimit}))
+}
+
+// SetTier is service-to-service only (billing); routed behind ServiceKeyAuth,
+// so there are no user claims — the org comes from the path.
+func (h *OrganizationHandler) SetTier(c *gin.Context) {
+	var req models.SetTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid request: "+err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	if err := h.orgService.SetTier(c.Param("id"), req.Tier); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "tier updated", gin.H{"tier": req.Tier}))
+}
+
+// ListAbuseReports serves the internal moderation listing (service-key auth):
+// all tenants' abuse reports with optional org_id / status / category filters.
+func (h *OrganizationHandler) ListAbuseReports(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	reports, err := h.orgService.ListAbuseReports(
+		c.Query("org_id"), c.Query("status"), c.Query("category"), limit,
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "abuse reports fetched", gin.H{"reports": reports}))
+}
+
 func (h *OrganizationHandler) GetOrganizationDetails(c *gin.Context) {
 	claimsRaw, exists := c.Get("userClaims")
 	if !exists {
@@ -92,3 +147,283 @@ func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
 	c.JSON(http.StatusOK, utils.APIResponse(false, "organization deleted successfully", orgID))
 
 }
+
+func (h *OrganizationHandler) RegenerateAccountID(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	newAccountID, err := h.orgService.RegenerateAccountID(claims.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "account ID regenerated", gin.H{
+		"account_id": newAccountID,
+	}))
+}
+
+// GetUsageReport serves the org usage report as JSON or streamed CSV.
+// Query params: from/to (YYYY-MM-DD, UTC), granularity=day|month, format=json|csv.
+func (h *OrganizationHandler) GetUsageReport(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	now := time.Now().UTC()
+	to := now
+	from := now.AddDate(0, -1, 0)
+
+	var err error
+	if raw := c.Query("from"); raw != "" {
+		if from, err = time.ParseInLocation("2006-01-02", raw, time.UTC); err != nil {
+			c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid 'from' date, expected YYYY-MM-DD", nil, http.StatusBadRequest))
+			return
+		}
+	}
+	if raw := c.Query("to"); raw != "" {
+		if to, err = time.ParseInLocation("2006-01-02", raw, time.UTC); err != nil {
+			c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid 'to' date, expected YYYY-MM-DD", nil, http.StatusBadRequest))
+			return
+		}
+	}
+
+	granularity := c.DefaultQuery("granularity", "day")
+
+	buckets, err := h.orgService.GetUsageReport(claims.OrganizationID, from, to, granularity)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	if c.DefaultQuery("format", "json") == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="usage-report.csv"`)
+
+		w := csv.NewWriter(c.Writer)
+		_ = w.Write([]string{"bucket", "predictions", "prompt_tokens", "completion_tokens", "active_users"})
+		for _, b := range buckets {
+			_ = w.Write([]string{
+				b.Bucket.Format("2006-01-02"),
+				strconv.FormatInt(b.Predictions, 10),
+				strconv.FormatInt(b.PromptTokens, 10),
+				strconv.FormatInt(b.CompletionTokens, 10),
+				strconv.FormatInt(b.ActiveUsers, 10),
+			})
+			w.Flush()
+		}
+		w.Flush()
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "usage report generated", buckets))
+}
+
+func (h *OrganizationHandler) AddDomain(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	var req models.AddOrganizationDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid request: "+err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	resp, err := h.orgService.AddDomain(claims.OrganizationID, req.Domain)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "domain added, publish the TXT record then verify", resp))
+}
+
+func (h *OrganizationHandler) GetDomains(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	domains, err := h.orgService.GetDomains(claims.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.APIResponse(true, err.Error(), nil, http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "domains fetched", domains))
+}
+
+func (h *OrganizationHandler) VerifyDomain(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	resp, err := h.orgService.VerifyDomain(claims.OrganizationID, c.Param("domain"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "domain verified", resp))
+}
+
+func (h *OrganizationHandler) RemoveDomain(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	if err := h.orgService.RemoveDomain(claims.OrganizationID, c.Param("domain")); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "domain removed", nil))
+}
+
+// GetOnboardingProgress serves the getting-started checklist for the
+// caller's organization. Owner/maintainer only (enforced in routes).
+func (h *OrganizationHandler) GetOnboardingProgress(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	progress, err := h.orgService.GetOnboardingProgress(claims.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "onboarding progress fetched", progress))
+}
+
+// DismissOnboarding hides (or re-shows) the getting-started checklist for
+// the whole organization. Owners only (enforced in routes).
+func (h *OrganizationHandler) DismissOnboarding(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	var req models.DismissOnboardingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "dismissed is required", nil, http.StatusBadRequest))
+		return
+	}
+
+	if err := h.orgService.SetOnboardingDismissed(claims.OrganizationID, *req.Dismissed); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "onboarding checklist updated", gin.H{"dismissed": *req.Dismissed}))
+}
+
+// UpdateBranding stores the org's email branding (logo, accent color,
+// footer). Values are validated server-side; owners only (enforced in routes).
+func (h *OrganizationHandler) UpdateBranding(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	var req models.UpdateBrandingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "Invalid request payload", nil, http.StatusBadRequest))
+		return
+	}
+
+	resp, err := h.orgService.UpdateBranding(claims.OrganizationID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "branding updated", resp))
+}
+
+// GetBranding returns the org's current email branding for the settings UI.
+func (h *OrganizationHandler) GetBranding(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	resp, err := h.orgService.GetBranding(claims.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "branding fetched", resp))
+}
+
+// UpdateOrgSettings applies owner-editable org settings (default locale).
+func (h *OrganizationHandler) UpdateOrgSettings(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	var req models.UpdateOrgSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "Invalid request payload", nil, http.StatusBadRequest))
+		return
+	}
+
+	if err := h.orgService.UpdateSettings(claims.OrganizationID, req); err != nil {
+		status := apperrors.Status(err, http.StatusBadRequest)
+		c.JSON(status, utils.APIResponse(true, err.Error(), nil, status))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "organization settings updated", nil, http.StatusOK))
+}
+
+// PreviewBranding renders a sample invite email with the org's current
+// branding, for the settings UI preview pane.
+func (h *OrganizationHandler) PreviewBranding(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	body, err := h.orgService.PreviewBrandedInvite(claims.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "branding preview rendered", gin.H{"html": body}))
+}
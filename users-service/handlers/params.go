@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ParseUUIDParam validates a :param path segment before any service code
+// runs, so malformed or padded ids get one consistent 400 instead of
+// assorted errors from deep inside the service layer. On failure the
+// response has already been written; callers just return.
+func ParseUUIDParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(strings.TrimSpace(c.Param(name)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, fmt.Sprintf("invalid %s: must be a UUID", name), nil, http.StatusBadRequest))
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// paramRouter mounts ParseUUIDParam behind a single route so the tests
+// exercise it exactly the way the handlers do.
+func paramRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/things/:id", func(c *gin.Context) {
+		id, ok := ParseUUIDParam(c, "id")
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id": id.String()})
+	})
+	return router
+}
+
+func TestParseUUIDParamAcceptsPaddedUUID(t *testing.T) {
+	router := paramRouter()
+	id := uuid.New()
+
+	rec := httptest.NewRecorder()
+	target := fmt.Sprintf("/things/%%20%s%%20", id) // " <uuid> " url-encoded
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("padded UUID rejected with %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), id.String()) {
+		t.Errorf("parsed id missing from response: %s", rec.Body.String())
+	}
+}
+
+func TestParseUUIDParamRejectsMalformedValues(t *testing.T) {
+	router := paramRouter()
+
+	for _, raw := range []string{"123", "not-a-uuid", "deadbeef"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/things/"+raw, nil))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%q answered %d, want 400", raw, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), "invalid id: must be a UUID") {
+			t.Errorf("%q response lacks the validation message: %s", raw, rec.Body.String())
+		}
+	}
+}
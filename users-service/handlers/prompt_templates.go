@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PromptTemplateHandler struct {
+	promptTemplateService services.PromptTemplateService
+}
+
+func NewPromptTemplateHandler(promptTemplateService services.PromptTemplateService) *PromptTemplateHandler {
+	return &PromptTemplateHandler{promptTemplateService: promptTemplateService}
+}
+
+func (h *PromptTemplateHandler) CreatePromptTemplate(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	var req models.CreatePromptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid request: "+err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	orgID, err := uuid.Parse(claims.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid organization id in token", nil, http.StatusBadRequest))
+		return
+	}
+	creatorID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid user id in token", nil, http.StatusBadRequest))
+		return
+	}
+
+	resp, err := h.promptTemplateService.CreatePromptTemplate(orgID, creatorID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "prompt template created", resp))
+}
+
+func (h *PromptTemplateHandler) GetPromptTemplates(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	templates, err := h.promptTemplateService.GetPromptTemplates(claims.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.APIResponse(true, err.Error(), nil, http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "prompt templates fetched", templates))
+}
+
+func (h *PromptTemplateHandler) GetPromptTemplate(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	templateUUID, ok := ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	template, err := h.promptTemplateService.GetPromptTemplate(claims.OrganizationID, templateUUID.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, utils.APIResponse(true, err.Error(), nil, http.StatusNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "prompt template fetched", template))
+}
+
+func (h *PromptTemplateHandler) UpdatePromptTemplate(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	var req models.UpdatePromptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "invalid request: "+err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	templateUUID, ok := ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.promptTemplateService.UpdatePromptTemplate(claims.OrganizationID, claims.UserID, claims.Role, templateUUID.String(), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "prompt template updated", resp))
+}
+
+func (h *PromptTemplateHandler) DeletePromptTemplate(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	templateUUID, ok := ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.promptTemplateService.DeletePromptTemplate(claims.OrganizationID, claims.UserID, claims.Role, templateUUID.String()); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "prompt template deleted", nil))
+}
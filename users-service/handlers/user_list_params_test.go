@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/services"
+	"github.com/gin-gonic/gin"
+)
+
+// userListRouter mounts GetUsersByOrganization behind a stub that injects
+// the claims the auth middleware would have resolved.
+func userListRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	db := testutil.NewTestDB(t)
+	org := testutil.NewOrganization(db).Active().Build()
+	handler := NewUserHandler(services.NewUserService(db))
+
+	router := gin.New()
+	router.GET("/users", func(c *gin.Context) {
+		c.Set("userClaims", &utils.JWTClaims{OrganizationID: org.ID.String()})
+	}, handler.GetUsersByOrganization)
+	return router
+}
+
+func TestUserListRejectsMalformedCreationWindow(t *testing.T) {
+	router := userListRouter(t)
+
+	for _, query := range []string{
+		"created_after=yesterday",
+		"created_before=2026-13-40",
+		"created_after=2026-02-01T00:00:00Z&created_before=2026-01-01T00:00:00Z",
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%q answered %d, want 400", query, rec.Code)
+		}
+	}
+}
+
+func TestUserListAcceptsValidCreationWindow(t *testing.T) {
+	router := userListRouter(t)
+
+	rec := httptest.NewRecorder()
+	target := "/users?created_after=2020-01-01T00:00:00Z&created_before=2030-01-01T00:00:00Z"
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "Users fetched successfully") {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
@@ -1,8 +1,14 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/i18n"
 	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
 	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
 	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/services"
@@ -35,7 +41,8 @@ func (h *UserHandler) InviteUserHandler(c *gin.Context) {
 		return
 	}
 
-	// 🔹 Call service
+	// 🔹 Call service; 409 for duplicate emails, 403 for role-rule
+	// violations, plain errors stay 400
 	resp, err := h.userService.InviteUser(
 		uuid.MustParse(userClaims.UserID),
 		userClaims.Role,
@@ -43,7 +50,8 @@ func (h *UserHandler) InviteUserHandler(c *gin.Context) {
 		req,
 	)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		status := apperrors.Status(err, http.StatusBadRequest)
+		c.JSON(status, utils.APIErrorWithCode(apperrors.Code(err), i18n.ErrorMessage(c.GetHeader("Accept-Language"), err), status))
 		return
 	}
 
@@ -75,7 +83,43 @@ func (h *UserHandler) GetUsersByOrganization(c *gin.Context) {
 
 	orgID := claims.OrganizationID
 
-	users, err := h.userService.GetUsersByOrganization(orgID)
+	orderBy, err := utils.ParseSort(c, map[string]string{
+		"name":       "name",
+		"email":      "email",
+		"role":       "role",
+		"status":     "status",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	// Optional RFC3339 creation-time window (either bound may be omitted)
+	var createdAfter, createdBefore *time.Time
+	if raw := c.Query("created_after"); raw != "" {
+		t, perr := time.Parse(time.RFC3339, raw)
+		if perr != nil {
+			c.JSON(http.StatusBadRequest, utils.APIResponse(true, "created_after must be an RFC3339 timestamp", nil, http.StatusBadRequest))
+			return
+		}
+		createdAfter = &t
+	}
+	if raw := c.Query("created_before"); raw != "" {
+		t, perr := time.Parse(time.RFC3339, raw)
+		if perr != nil {
+			c.JSON(http.StatusBadRequest, utils.APIResponse(true, "created_before must be an RFC3339 timestamp", nil, http.StatusBadRequest))
+			return
+		}
+		createdBefore = &t
+	}
+	if createdAfter != nil && createdBefore != nil && createdAfter.After(*createdBefore) {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "created_after must not be later than created_before", nil, http.StatusBadRequest))
+		return
+	}
+
+	users, err := h.userService.GetUsersByOrganization(orgID, orderBy, createdAfter, createdBefore)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError,
 			utils.APIResponse(
@@ -107,20 +151,12 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 
 	orgID := claims.OrganizationID
 
-	userID := c.Param("id")
-	if userID == "" {
-		c.JSON(http.StatusBadRequest,
-			utils.APIResponse(
-				true,
-				"user id is required",
-				nil,
-				http.StatusBadRequest,
-			),
-		)
+	userUUID, ok := ParseUUIDParam(c, "id")
+	if !ok {
 		return
 	}
 
-	user, err := h.userService.GetUserByID(orgID, userID)
+	user, err := h.userService.GetUserByID(orgID, userUUID.String())
 	if err != nil {
 		c.JSON(http.StatusNotFound,
 			utils.APIResponse(
@@ -170,15 +206,11 @@ func (h *UserHandler) GetCurrentUser(c *gin.Context) {
 }
 
 func (h *UserHandler) DeleteUser(c *gin.Context) {
-	targetUserID := c.Param("id")
-
-	if targetUserID == "" {
-		c.JSON(
-			http.StatusBadRequest,
-			utils.APIResponse(true, "user id is required", nil, http.StatusBadRequest),
-		)
+	targetUUID, ok := ParseUUIDParam(c, "id")
+	if !ok {
 		return
 	}
+	targetUserID := targetUUID.String()
 
 	claimsRaw, exists := c.Get("userClaims")
 	if !exists {
@@ -213,15 +245,11 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 }
 
 func (h *UserHandler) SuspendUser(c *gin.Context) {
-	targetUserID := c.Param("id")
-
-	if targetUserID == "" {
-		c.JSON(
-			http.StatusBadRequest,
-			utils.APIResponse(true, "user id is required", nil, http.StatusBadRequest),
-		)
+	targetUUID, ok := ParseUUIDParam(c, "id")
+	if !ok {
 		return
 	}
+	targetUserID := targetUUID.String()
 
 	claimsRaw, exists := c.Get("userClaims")
 	if !exists {
@@ -254,3 +282,227 @@ func (h *UserHandler) SuspendUser(c *gin.Context) {
 		utils.APIResponse(false, "user deleted successfully", nil, http.StatusOK),
 	)
 }
+
+// UpdateUser applies a combined name/role/status edit from the admin UI.
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	targetUUID, ok := ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	targetUserID := targetUUID.String()
+
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	var req models.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "Invalid request payload", nil, http.StatusBadRequest))
+		return
+	}
+
+	resp, err := h.userService.UpdateUser(
+		claims.OrganizationID,
+		claims.UserID,
+		claims.Role,
+		targetUserID,
+		req,
+	)
+	if err != nil {
+		c.JSON(http.StatusForbidden, utils.APIResponse(true, err.Error(), nil, http.StatusForbidden))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "user updated successfully", resp))
+}
+
+// GetMemberDirectory lists the org's active members (id/name/role only) for
+// every authenticated user, with search and pagination. Service-side it 403s
+// for regular members when the org has hidden the directory.
+func (h *UserHandler) GetMemberDirectory(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	resp, err := h.userService.GetMemberDirectory(claims.OrganizationID, claims.Role, strings.TrimSpace(c.Query("search")), page, limit)
+	if err != nil {
+		status := apperrors.Status(err, http.StatusBadRequest)
+		c.JSON(status, utils.APIResponse(true, err.Error(), nil, status))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "Members fetched successfully", resp))
+}
+
+// UpdateProfile applies a self-service name/locale edit (PATCH /users/me).
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	var req models.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "Invalid request payload", nil, http.StatusBadRequest))
+		return
+	}
+
+	resp, err := h.userService.UpdateProfile(claims.UserID, req)
+	if err != nil {
+		status := apperrors.Status(err, http.StatusBadRequest)
+		c.JSON(status, utils.APIResponse(true, err.Error(), nil, status))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "profile updated successfully", resp))
+}
+
+// ResolveUsers serves the service-key-protected bulk display-info lookup
+// (GET /internal/v1/users?ids=a,b,c). No user claims here — callers are other
+// services, authenticated by X-Service-Key.
+func (h *UserHandler) ResolveUsers(c *gin.Context) {
+	idsParam := strings.TrimSpace(c.Query("ids"))
+	if idsParam == "" {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, "ids query parameter is required", nil, http.StatusBadRequest))
+		return
+	}
+
+	var ids []string
+	for _, id := range strings.Split(idsParam, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	resp, err := h.userService.ResolveUsers(ids)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "Users resolved successfully", resp))
+}
+
+// ResendAllInvites re-blasts invite emails to every pending user in the
+// caller's organization. Owner only (enforced in routes).
+func (h *UserHandler) ResendAllInvites(c *gin.Context) {
+	claims, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	userClaims := claims.(*utils.JWTClaims)
+
+	resp, err := h.userService.ResendAllInvites(userClaims.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "Invite resend completed", resp))
+}
+
+// GetInviteStats serves the invitation funnel (sent / accepted / expired /
+// revoked / median time-to-accept) over a ?days= window. Owner/maintainer
+// only (enforced in routes).
+func (h *UserHandler) GetInviteStats(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+
+	resp, err := h.userService.GetInviteStats(claims.OrganizationID, days)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.APIResponse(true, err.Error(), nil, http.StatusBadRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "Invite stats fetched successfully", resp))
+}
+
+// AnonymizeUser scrubs a user's PII for GDPR deletion. Owner only (enforced
+// in routes); the audit entry records the id but never the identity.
+func (h *UserHandler) AnonymizeUser(c *gin.Context) {
+	targetUUID, ok := ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	targetUserID := targetUUID.String()
+
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	if err := h.userService.AnonymizeUser(claims, targetUserID); err != nil {
+		c.JSON(http.StatusForbidden, utils.APIResponse(true, err.Error(), nil, http.StatusForbidden))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "user anonymized", nil, http.StatusOK))
+}
+
+// ForceActivateUser manually activates a user stuck in pending (lost
+// verification email). Owner only (enforced in routes); the manual action is
+// audit-logged by the service.
+func (h *UserHandler) ForceActivateUser(c *gin.Context) {
+	targetUUID, ok := ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	if err := h.userService.ForceActivateUser(claims, targetUUID.String()); err != nil {
+		statusCode := apperrors.Status(err, http.StatusBadRequest)
+		c.JSON(statusCode, utils.APIResponse(true, err.Error(), nil, statusCode))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.APIResponse(false, "user activated", nil, http.StatusOK))
+}
+
+// ExportPersonalData serves the caller's GDPR data bundle as a download.
+// The raw bundle is the response body (no envelope) so the saved file is
+// the export itself.
+func (h *UserHandler) ExportPersonalData(c *gin.Context) {
+	claimsRaw, exists := c.Get("userClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.APIResponse(true, "unauthorized", nil, http.StatusUnauthorized))
+		return
+	}
+	claims := claimsRaw.(*utils.JWTClaims)
+
+	export, err := h.userService.ExportPersonalData(claims)
+	if err != nil {
+		statusCode := apperrors.Status(err, http.StatusBadRequest)
+		c.JSON(statusCode, utils.APIResponse(true, err.Error(), nil, statusCode))
+		return
+	}
+
+	c.Header("Content-Disposition",
+		fmt.Sprintf("attachment; filename=personal-data-%s.json", export.GeneratedAt.Format("2006-01-02")))
+	c.JSON(http.StatusOK, export)
+}
@@ -1,11 +1,25 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/cache"
 	"github.com/Bipul-Dubey/ai-knowledgebase/shared/db"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/events"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/features"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/flags"
 	"github.com/Bipul-Dubey/ai-knowledgebase/shared/middleware"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/outbox"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/tracing"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/validation"
 	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/handlers"
 	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/routes"
 	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/services"
@@ -18,6 +32,25 @@ func main() {
 		log.Println("⚠️  No .env file found, relying on environment variables")
 	}
 
+	// Fail boot on malformed TOKEN_TTL_* values rather than at first login
+	if err := utils.ValidateTokenTTLs(); err != nil {
+		log.Fatal("Invalid token TTL configuration: ", err)
+	}
+
+	// Same for the JWT keyset (JWT_KEYS / JWT_CURRENT_KID / JWT_ALG)
+	if err := utils.ValidateJWTKeyConfig(); err != nil {
+		log.Fatal("Invalid JWT key configuration: ", err)
+	}
+
+	// Flag stale config from the retired sequential account-id scheme
+	utils.WarnDeprecatedAccountIDBase()
+
+	// Deployment-level FEATURE_* toggles, fixed for the process lifetime
+	features.Load()
+
+	// Custom binding tags (slug, strongpassword) used by the request structs
+	validation.RegisterGinValidators()
+
 	// Initialize database
 	database, err := db.NewDB()
 	if err != nil {
@@ -34,6 +67,41 @@ func main() {
 		}
 	}()
 
+	// Feature flags read from the DB with a short-TTL cache
+	flags.Init(database)
+
+	// DB spans hang off the request span started by middleware.Tracing
+	if err := database.Use(tracing.GormPlugin()); err != nil {
+		log.Printf("⚠️  tracing plugin not registered: %v", err)
+	}
+
+	// Auth-cache invalidator: when another instance changes a user (verify,
+	// password reset), drop our cached copy so the TTL isn't the only bound.
+	// Webhook dispatch and usage counters subscribe the same way.
+	for _, eventType := range []string{"user.verified", "password.reset"} {
+		events.Subscribe(eventType, func(e events.Event) error {
+			cache.InvalidateUser(e.ActorID)
+			return nil
+		})
+	}
+
+	// Onboarding checklist: completion is recorded as events arrive so the
+	// read endpoint stays a single-row lookup. chats-service marks the
+	// document/prediction steps itself.
+	events.Subscribe("user.verified", func(e events.Event) error {
+		services.MarkOnboardingStep(database, e.OrgID, services.StepAccountVerified)
+		return nil
+	})
+	events.Subscribe("user.invited", func(e events.Event) error {
+		services.MarkOnboardingStep(database, e.OrgID, services.StepMemberInvited)
+		return nil
+	})
+
+	// Outbox dispatcher: delivers the emails committed alongside business
+	// writes (signup, invites), so a crash can't lose them
+	outboxDispatcher := outbox.NewDispatcher(database, nil)
+	outboxDispatcher.Start()
+
 	// Initialize service layer
 	serviceManager := services.NewServiceManager(database)
 
@@ -43,8 +111,10 @@ func main() {
 	r := gin.New()
 
 	r.Use(middleware.CORSMiddleware())
+	r.Use(middleware.Tracing())
 	r.Use(gin.Logger())
-	r.Use(gin.Recovery())
+	r.Use(middleware.RecoveryMiddleware())
+	r.Use(middleware.SlowRequestLogger())
 
 	r.OPTIONS("/*path", func(c *gin.Context) {
 		c.Status(204)
@@ -57,6 +127,34 @@ func main() {
 		port = "8080"
 	}
 
-	log.Printf("🚀 Users Service starting on port %s", port)
-	log.Fatal(r.Run(":" + port))
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: r,
+	}
+
+	go func() {
+		log.Printf("🚀 Users Service starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Server failed:", err)
+		}
+	}()
+
+	// Graceful shutdown: stop accepting requests, then drain the email queue
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("🛑 Shutting down Users Service...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("Error shutting down HTTP server: %v", err)
+	}
+
+	if err := utils.ShutdownEmailQueue(ctx); err != nil {
+		log.Printf("Error draining email queue: %v", err)
+	}
+
+	outboxDispatcher.Stop()
 }
@@ -0,0 +1,109 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/handlers"
+	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// forceActivateFixture wires the full router over one DB so the tests can
+// exercise the route guard with real JWTs and then log the target in.
+type forceActivateFixture struct {
+	router *gin.Engine
+	db     *gorm.DB
+	org    models.Organization
+	target models.User
+}
+
+func newForceActivateFixture(t *testing.T) *forceActivateFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	db := testutil.NewTestDB(t)
+	org := testutil.NewOrganization(db).Active().Build()
+	target := testutil.NewUser(db, org).Pending(time.Hour).WithEmail("stuck@example.com").Build()
+	router := SetupRoutes(gin.New(), handlers.NewHandlerManager(services.NewServiceManager(db)), db)
+	return &forceActivateFixture{router: router, db: db, org: org, target: target}
+}
+
+func (f *forceActivateFixture) forceActivate(token string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	target := fmt.Sprintf("/api/v1/users/%s/force-activate", f.target.ID)
+	req := httptest.NewRequest(http.MethodPost, target, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	f.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestForceActivateIsOwnerOnly(t *testing.T) {
+	f := newForceActivateFixture(t)
+
+	member := testutil.NewUser(f.db, f.org).Active().WithEmail("member@example.com").Build()
+	maintainer := testutil.NewUser(f.db, f.org).Maintainer().Active().WithEmail("maint@example.com").Build()
+
+	for _, caller := range []models.User{member, maintainer} {
+		rec := f.forceActivate(testutil.MintJWT(caller, f.org))
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s got %d, want 403", caller.Role, rec.Code)
+		}
+	}
+
+	// Still pending: nothing was activated along the way
+	var after models.User
+	if err := f.db.First(&after, "id = ?", f.target.ID).Error; err != nil {
+		t.Fatalf("target lookup failed: %v", err)
+	}
+	if after.Status != "pending" {
+		t.Errorf("target status = %s after rejected calls, want pending", after.Status)
+	}
+}
+
+func TestForceActivateIsScopedToTheOwnersOrg(t *testing.T) {
+	f := newForceActivateFixture(t)
+
+	otherOrg := testutil.NewOrganization(f.db).Active().Named("Other Org").Build()
+	otherOwner := testutil.NewUser(f.db, otherOrg).Owner().Active().WithEmail("other@example.com").Build()
+
+	rec := f.forceActivate(testutil.MintJWT(otherOwner, otherOrg))
+	if rec.Code == http.StatusOK {
+		t.Fatalf("an owner of another org force-activated the target: %s", rec.Body.String())
+	}
+}
+
+func TestForceActivatedUserCanLogIn(t *testing.T) {
+	f := newForceActivateFixture(t)
+	owner := testutil.NewUser(f.db, f.org).Owner().Active().WithEmail("owner@example.com").Build()
+
+	if rec := f.forceActivate(testutil.MintJWT(owner, f.org)); rec.Code != http.StatusOK {
+		t.Fatalf("owner force-activate failed with %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var after models.User
+	if err := f.db.First(&after, "id = ?", f.target.ID).Error; err != nil {
+		t.Fatalf("target lookup failed: %v", err)
+	}
+	if after.Status != "active" || after.InviteToken != nil || after.ExpiresAt != nil {
+		t.Errorf("after activation: status=%s token=%v expiry=%v, want active with both cleared",
+			after.Status, after.InviteToken, after.ExpiresAt)
+	}
+
+	body := fmt.Sprintf(`{"email":"stuck@example.com","password":%q,"account_id":%q}`,
+		testutil.FixturePassword, f.org.AccountID)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	f.router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("login after force-activation answered %d: %s", rec.Code, rec.Body.String())
+	}
+}
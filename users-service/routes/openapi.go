@@ -0,0 +1,257 @@
+package routes
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// The OpenAPI document is generated from the actual request/response structs
+// so the served contract cannot drift from the code. It is exposed at
+// /api/v1/openapi.json (+ a Swagger UI at /api/v1/docs) behind the
+// ENABLE_API_DOCS env flag.
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidType = reflect.TypeOf(uuid.UUID{})
+)
+
+// schemaFor builds a JSON-schema fragment for a Go type by walking its
+// json tags. Nested structs are inlined to keep the generator simple.
+func schemaFor(t reflect.Type) map[string]interface{} {
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case uuidType:
+		return map[string]interface{}{"type": "string", "format": "uuid"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := schemaFor(t.Elem())
+		s["nullable"] = true
+		return s
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Interface:
+		return map[string]interface{}{} // any
+	case reflect.Struct:
+		props := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" { // unexported
+				continue
+			}
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			props[name] = schemaFor(f.Type)
+		}
+		return map[string]interface{}{"type": "object", "properties": props}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// schemaNames lists every struct exposed under components.schemas.
+var schemaNames = map[string]interface{}{
+	"GenericResponse":            utils.GenericResponse{},
+	"SignupRequest":              models.SignupRequest{},
+	"SignupResponse":             models.SignupResponse{},
+	"LoginRequest":               models.LoginRequest{},
+	"LoginResponse":              models.LoginResponse{},
+	"VerifyAccountRequest":       models.VerifyAccountRequest{},
+	"VerifyAccountResponse":      models.VerifyAccountResponse{},
+	"InviteUserRequest":          models.InviteUserRequest{},
+	"InviteUserResponse":         models.InviteUserResponse{},
+	"AcceptInviteRequest":        models.AcceptInviteRequest{},
+	"AcceptInviteResponse":       models.AcceptInviteResponse{},
+	"ResetPasswordRequest":       models.ResetPasswordRequest{},
+	"TokenIntrospectionRequest":  models.TokenIntrospectionRequest{},
+	"TokenIntrospectionResponse": models.TokenIntrospectionResponse{},
+	"OrganizationDetails":        models.OrganizationDetailsResponse{},
+	"DashboardStats":             models.DashboardStatsResponse{},
+	"UserResponse":               models.UserResponse{},
+}
+
+func ref(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// operation builds a single path operation. reqSchema/respSchema are
+// components.schemas names; either can be "" when the operation has no
+// body / returns only the generic envelope.
+func operation(summary, tag, reqSchema, respSchema string, security string) map[string]interface{} {
+	op := map[string]interface{}{
+		"summary": summary,
+		"tags":    []string{tag},
+	}
+	if reqSchema != "" {
+		op["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": ref(reqSchema)},
+			},
+		}
+	}
+	respContent := ref("GenericResponse")
+	if respSchema != "" {
+		respContent = map[string]interface{}{
+			"allOf": []interface{}{
+				ref("GenericResponse"),
+				map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"data": ref(respSchema)},
+				},
+			},
+		}
+	}
+	op["responses"] = map[string]interface{}{
+		"200": map[string]interface{}{
+			"description": "OK",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": respContent},
+			},
+		},
+	}
+	if security != "" {
+		op["security"] = []map[string]interface{}{{security: []string{}}}
+	}
+	return op
+}
+
+// OpenAPISpec assembles the full document for the users-service routes.
+func OpenAPISpec() map[string]interface{} {
+	schemas := map[string]interface{}{}
+	for name, v := range schemaNames {
+		schemas[name] = schemaFor(reflect.TypeOf(v))
+	}
+
+	paths := map[string]interface{}{
+		"/api/v1/signup": map[string]interface{}{
+			"post": operation("Create an organization and its owner", "auth", "SignupRequest", "SignupResponse", ""),
+		},
+		"/api/v1/verify-account": map[string]interface{}{
+			"post": operation("Verify an account from an email token", "auth", "VerifyAccountRequest", "VerifyAccountResponse", ""),
+		},
+		"/api/v1/login": map[string]interface{}{
+			"post": operation("Log in with email, password and account ID", "auth", "LoginRequest", "LoginResponse", ""),
+		},
+		"/api/v1/accept-invite": map[string]interface{}{
+			"post": operation("Accept an organization invite", "auth", "AcceptInviteRequest", "AcceptInviteResponse", ""),
+		},
+		"/api/v1/forgot-password": map[string]interface{}{
+			"post": operation("Request a password reset email", "auth", "", "", ""),
+		},
+		"/api/v1/reset-password-email": map[string]interface{}{
+			"post": operation("Reset the password using an emailed token", "auth", "", "", ""),
+		},
+		"/api/v1/reset-password": map[string]interface{}{
+			"post": operation("Change the password while logged in", "auth", "ResetPasswordRequest", "", "bearerAuth"),
+		},
+		"/api/v1/token/introspect": map[string]interface{}{
+			"post": operation("Introspect a JWT (service-to-service)", "auth", "TokenIntrospectionRequest", "TokenIntrospectionResponse", "serviceKey"),
+		},
+		"/api/v1/organization/details": map[string]interface{}{
+			"get": operation("Fetch organization details", "organization", "", "OrganizationDetails", "bearerAuth"),
+		},
+		"/api/v1/organization/dashboard-stats": map[string]interface{}{
+			"get": operation("Fetch dashboard statistics", "organization", "", "DashboardStats", "bearerAuth"),
+		},
+		"/api/v1/organization/regenerate-account-id": map[string]interface{}{
+			"post": operation("Regenerate the organization account ID (owner only)", "organization", "", "", "bearerAuth"),
+		},
+		"/api/v1/organization": map[string]interface{}{
+			"delete": operation("Delete the organization (owner only)", "organization", "", "", "bearerAuth"),
+		},
+		"/api/v1/users/invite": map[string]interface{}{
+			"post": operation("Invite a user into the organization", "users", "InviteUserRequest", "InviteUserResponse", "bearerAuth"),
+		},
+		"/api/v1/users/resend-verification": map[string]interface{}{
+			"post": operation("Resend a verification email", "users", "", "", "bearerAuth"),
+		},
+		"/api/v1/users": map[string]interface{}{
+			"get": operation("List organization users", "users", "", "UserResponse", "bearerAuth"),
+		},
+		"/api/v1/users/me": map[string]interface{}{
+			"get": operation("Fetch the current user", "users", "", "UserResponse", "bearerAuth"),
+		},
+		"/api/v1/users/{id}": map[string]interface{}{
+			"get":    operation("Fetch a user by id", "users", "", "UserResponse", "bearerAuth"),
+			"delete": operation("Delete a user", "users", "", "", "bearerAuth"),
+		},
+		"/api/v1/users/{id}/suspend": map[string]interface{}{
+			"patch": operation("Suspend a user", "users", "", "", "bearerAuth"),
+		},
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Users Service",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":         "http",
+					"scheme":       "bearer",
+					"bearerFormat": "JWT",
+				},
+				"serviceKey": map[string]interface{}{
+					"type": "apiKey",
+					"in":   "header",
+					"name": "X-Service-Key",
+				},
+			},
+		},
+	}
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Users Service API Docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    SwaggerUIBundle({ url: "/api/v1/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>`
+
+// RegisterDocsRoutes serves the generated spec and a Swagger UI.
+func RegisterDocsRoutes(api *gin.RouterGroup) {
+	api.GET("/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, OpenAPISpec())
+	})
+	api.GET("/docs", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+	})
+}
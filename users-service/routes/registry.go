@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RouteInfo is one row of the effective route table, taken from the engine
+// after registration.
+//
+// Deliberate scope cut versus a full declarative registry: duplicate-route
+// detection is delegated to gin, which already fails fast (panics at
+// startup) when the same method+path is registered twice, and required
+// roles are not part of the listing because authorization is attached as
+// inline middleware rather than declarative route metadata. Revisit if
+// routes ever move to a data-driven table.
+type RouteInfo struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Handler string `json:"handler"` // fully-qualified handler func name
+}
+
+// RouteTable returns the engine's registered routes sorted by path then
+// method, for deterministic output.
+func RouteTable(r *gin.Engine) []RouteInfo {
+	routes := r.Routes()
+	table := make([]RouteInfo, 0, len(routes))
+	for _, rt := range routes {
+		table = append(table, RouteInfo{
+			Method:  rt.Method,
+			Path:    rt.Path,
+			Handler: rt.Handler,
+		})
+	}
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].Path != table[j].Path {
+			return table[i].Path < table[j].Path
+		}
+		return table[i].Method < table[j].Method
+	})
+	return table
+}
+
+// RegisterDebugRoutes exposes GET /debug/routes listing the effective route
+// table. Non-production only: the table leaks internal endpoint names, so
+// APP_ENV=production deployments (the same switch the rest of the service
+// uses) never serve it.
+func RegisterDebugRoutes(r *gin.Engine, api *gin.RouterGroup) {
+	if os.Getenv("APP_ENV") == "production" {
+		return
+	}
+	api.GET("/debug/routes", func(c *gin.Context) {
+		c.JSON(http.StatusOK, utils.APIResponse(false, "routes fetched", RouteTable(r)))
+	})
+}
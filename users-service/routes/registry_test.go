@@ -0,0 +1,81 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRouteTableIsSortedAndComplete(t *testing.T) {
+	router := testRouter(t)
+
+	table := RouteTable(router)
+	if len(table) == 0 {
+		t.Fatal("route table is empty")
+	}
+	if !sort.SliceIsSorted(table, func(i, j int) bool {
+		if table[i].Path != table[j].Path {
+			return table[i].Path < table[j].Path
+		}
+		return table[i].Method < table[j].Method
+	}) {
+		t.Error("route table is not sorted by path then method")
+	}
+
+	found := false
+	for _, rt := range table {
+		if rt.Method == http.MethodPost && rt.Path == "/api/v1/login" {
+			found = true
+			if rt.Handler == "" {
+				t.Error("login route has no handler name")
+			}
+		}
+	}
+	if !found {
+		t.Error("POST /api/v1/login is missing from the route table")
+	}
+}
+
+func TestDebugRoutesServedOutsideProduction(t *testing.T) {
+	t.Setenv("APP_ENV", "development")
+	router := testRouter(t)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/debug/routes", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "/api/v1/login") {
+		t.Errorf("route listing lacks known routes: %s", rec.Body.String())
+	}
+}
+
+func TestEmailPreviewHiddenInProduction(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	router := testRouter(t)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/dev/email-preview",
+		strings.NewReader(`{"template":"verify"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d in production, want 404", rec.Code)
+	}
+}
+
+func TestDebugRoutesHiddenInProduction(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	router := testRouter(t)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/debug/routes", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d in production, want 404", rec.Code)
+	}
+}
@@ -1,8 +1,15 @@
 package routes
 
 import (
+	"net/http"
+	"os"
+	"time"
+
 	"github.com/Bipul-Dubey/ai-knowledgebase/shared/constants"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/features"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/flags"
 	"github.com/Bipul-Dubey/ai-knowledgebase/shared/middleware"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
 	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/handlers"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -11,35 +18,165 @@ import (
 func SetupRoutes(r *gin.Engine, h *handlers.HandlerManager, db *gorm.DB) *gin.Engine {
 	// r := gin.Default()
 
+	// Unknown routes and wrong methods answer with the same JSON envelope as
+	// everything else, instead of gin's plain-text defaults
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, utils.APIErrorWithCode("ROUTE_NOT_FOUND", "route not found", http.StatusNotFound))
+	})
+	r.NoMethod(func(c *gin.Context) {
+		c.JSON(http.StatusMethodNotAllowed, utils.APIErrorWithCode("METHOD_NOT_ALLOWED", "method not allowed", http.StatusMethodNotAllowed))
+	})
+
 	api := r.Group("/api/v1")
 	{
-		api.POST("/signup", h.AuthenticationHandler.SignUp)
+		api.POST("/signup", middleware.RequireFlag(flags.SignupEnabled), h.AuthenticationHandler.SignUp)
+		// pre-submit availability check; rate-limited by IP (unauthenticated)
+		api.GET("/signup/check", middleware.RequireFlag(flags.SignupEnabled), middleware.RateLimitMiddleware(), h.AuthenticationHandler.CheckSignupAvailability)
 		api.POST("/verify-account", h.AuthenticationHandler.VerifyAccount) // verify email if create account by itself
 		api.POST("/login", h.AuthenticationHandler.Login)
 		api.POST("/accept-invite", h.AuthenticationHandler.AcceptInviteHandler) // accept invite from any organization
 
+		api.POST("/join", middleware.RequireFeature(features.InviteLinks), h.InviteLinkHandler.Join)                          // consume a shareable invite link
+		api.POST("/join-by-domain", middleware.RequireFeature(features.DomainAutoJoin), h.AuthenticationHandler.JoinByDomain) // verified corporate domain auto-join
+
 		api.POST("/forgot-password", h.AuthenticationHandler.ForgotPassword)
 		api.POST("/reset-password-email", h.AuthenticationHandler.ResetPasswordByEmail)
 
+		// service-to-service (API gateway / sidecar) endpoints
+		api.POST("/token/introspect", middleware.ServiceKeyAuth(), h.AuthenticationHandler.IntrospectToken)
+		api.PATCH("/internal/organizations/:id/seat-limit", middleware.ServiceKeyAuth(), h.OrganizationHandler.SetSeatLimit)
+		api.PATCH("/internal/organizations/:id/tier", middleware.ServiceKeyAuth(), h.OrganizationHandler.SetTier)
+		api.GET("/internal/v1/users", middleware.ServiceKeyAuth(), h.UserHandler.ResolveUsers)
+		// moderation: all tenants' abuse reports with filters
+		api.GET("/internal/abuse-reports", middleware.ServiceKeyAuth(), h.OrganizationHandler.ListAbuseReports)
+
+		// runtime feature flags (maintenance mode); ops tooling, not tenant-facing
+		api.GET("/internal/flags", middleware.ServiceKeyAuth(), handlers.FeatureFlags)
+		api.POST("/internal/flags", middleware.ServiceKeyAuth(), handlers.SetFeatureFlag)
+
+		// email provider webhooks
+		api.POST("/email/bounce", middleware.EmailBounceAuth(), h.UserHandler.EmailBounceWebhook)
+
+		// API docs (generated from the request/response structs)
+		if os.Getenv("ENABLE_API_DOCS") == "true" {
+			RegisterDocsRoutes(api)
+		}
+
+		// Dev-only email preview: render a template with sample data without
+		// sending anything. Never served in production.
+		if os.Getenv("APP_ENV") != "production" {
+			api.POST("/dev/email-preview", handlers.EmailPreview)
+		}
+
 		// new group with authentication
 		auth := api.Group("")
 		auth.Use(middleware.AuthMiddleware(db))
+		auth.Use(middleware.RateLimitMiddleware())
 		{
 			auth.POST("/reset-password", h.AuthenticationHandler.ResetPassword)
 
+			// ADMIN (support/debug tooling)
+			admin := auth.Group("/admin")
+			{
+				admin.POST("/impersonate/:userID", middleware.RequireFeature(features.Impersonation), middleware.RoleAuthorization(constants.RoleOwner), h.AuthenticationHandler.Impersonate)
+				admin.DELETE("/impersonate", middleware.RequireFeature(features.Impersonation), h.AuthenticationHandler.StopImpersonation)
+				admin.GET("/cache-stats", middleware.RoleAuthorization(constants.RoleOwner), handlers.CacheStats)
+				admin.GET("/slow-requests", middleware.RoleAuthorization(constants.RoleOwner), handlers.SlowRequests)
+				admin.GET("/db-retries", middleware.RoleAuthorization(constants.RoleOwner), handlers.DBRetryStats)
+				// env-driven feature toggles active in this deployment
+				admin.GET("/debug/features", middleware.RoleAuthorization(constants.RoleOwner), handlers.Features)
+				// outbox delivery-failure counters by template and class
+				admin.GET("/email-metrics", middleware.RoleAuthorization(constants.RoleOwner), handlers.EmailMetrics)
+			}
+
 			// ORGANIZATION
 			org := auth.Group("/organization")
 			{
 				org.GET("/details", h.OrganizationHandler.GetOrganizationDetails)
 				org.GET("/dashboard-stats", h.OrganizationHandler.GetDashboardStats)
+				org.GET("/onboarding", middleware.RoleAuthorization(constants.RoleOwner, constants.RoleMaintainer), h.OrganizationHandler.GetOnboardingProgress)
+				org.PATCH("/onboarding", middleware.RoleAuthorization(constants.RoleOwner), h.OrganizationHandler.DismissOnboarding)
+				org.GET("/usage-report", middleware.RoleAuthorization(constants.RoleOwner), h.OrganizationHandler.GetUsageReport)
 				org.DELETE("", h.OrganizationHandler.DeleteOrganization)
+				org.POST("/regenerate-account-id", middleware.RoleAuthorization(constants.RoleOwner), h.OrganizationHandler.RegenerateAccountID)
+
+				// Custom roles (permission bundles). Maintainers may view the
+				// matrix; only owners define it.
+				orgRoles := org.Group("/roles")
+				{
+					orgRoles.POST("", middleware.RequirePermission(constants.CapOrgSettingsWrite), h.OrgRoleHandler.CreateOrgRole)
+					orgRoles.GET("", middleware.RequirePermission(constants.CapOrgSettingsRead), h.OrgRoleHandler.GetOrgRoles)
+					orgRoles.PATCH("/:name", middleware.RequirePermission(constants.CapOrgSettingsWrite), h.OrgRoleHandler.UpdateOrgRole)
+					orgRoles.DELETE("/:name", middleware.RequirePermission(constants.CapOrgSettingsWrite), h.OrgRoleHandler.DeleteOrgRole)
+				}
+
+				// Org-level settings (default email language for new members)
+				org.PATCH("/settings", middleware.RequirePermission(constants.CapOrgSettingsWrite), h.OrganizationHandler.UpdateOrgSettings)
+
+				// Email branding (logo / accent color / footer in outgoing mail)
+				org.GET("/branding", middleware.RequirePermission(constants.CapOrgSettingsRead), h.OrganizationHandler.GetBranding)
+				org.PATCH("/branding", middleware.RequirePermission(constants.CapOrgSettingsWrite), h.OrganizationHandler.UpdateBranding)
+				org.POST("/branding/preview", middleware.RequirePermission(constants.CapOrgSettingsRead), h.OrganizationHandler.PreviewBranding)
+
+				// Verified email domains enabling auto-join. Maintainers can
+				// view the settings; only owners can change them.
+				domains := org.Group("/domains")
+				{
+					domains.POST("", middleware.RequirePermission(constants.CapOrgSettingsWrite), h.OrganizationHandler.AddDomain)
+					domains.GET("", middleware.RequirePermission(constants.CapOrgSettingsRead), h.OrganizationHandler.GetDomains)
+					domains.POST("/:domain/verify", middleware.RequirePermission(constants.CapOrgSettingsWrite), h.OrganizationHandler.VerifyDomain)
+					domains.DELETE("/:domain", middleware.RequirePermission(constants.CapOrgSettingsWrite), h.OrganizationHandler.RemoveDomain)
+				}
+			}
+
+			// Member directory: active users only, trimmed to id/name/role,
+			// for @-mentions and sharing. Owners can hide it from members
+			// via the member_directory_visible org setting.
+			auth.GET("/members", h.UserHandler.GetMemberDirectory)
+
+			// GDPR data download; tightly rate-limited on top of the group
+			// limiter — assembling the bundle is expensive
+			auth.GET("/me/export", middleware.NewRateLimiter(5, time.Hour).Middleware(), h.UserHandler.ExportPersonalData)
+
+			// EMAIL DELIVERY (outbox visibility: what was sent, what failed)
+			emails := auth.Group("/emails")
+			emails.Use(middleware.RoleAuthorization(constants.RoleOwner))
+			{
+				emails.GET("", h.EmailHandler.ListEmails)
+				emails.POST("/:id/retry", h.EmailHandler.RetryEmail)
+			}
+
+			// Bulk invite re-send (e.g. after fixing SMTP config)
+			auth.POST("/invites/resend-all", middleware.RequireFlag(flags.InvitesEnabled), middleware.RoleAuthorization(constants.RoleOwner), h.UserHandler.ResendAllInvites)
+			// Invitation funnel metrics
+			auth.GET("/invites/stats", middleware.RoleAuthorization(constants.RoleOwner, constants.RoleMaintainer), h.UserHandler.GetInviteStats)
+
+			// INVITE LINKS (shareable join links)
+			links := auth.Group("/invite-links")
+			links.Use(middleware.RequireFeature(features.InviteLinks))
+			links.Use(middleware.RoleAuthorization(constants.RoleOwner, constants.RoleMaintainer))
+			{
+				links.POST("", h.InviteLinkHandler.CreateInviteLink)
+				links.GET("", h.InviteLinkHandler.GetInviteLinks)
+				links.DELETE("/:id", h.InviteLinkHandler.RevokeInviteLink)
+			}
+
+			// PROMPT TEMPLATES (reusable {{placeholder}} prompts)
+			templates := auth.Group("/prompt-templates")
+			{
+				templates.POST("", h.PromptTemplateHandler.CreatePromptTemplate)
+				templates.GET("", h.PromptTemplateHandler.GetPromptTemplates)
+				templates.GET("/:id", h.PromptTemplateHandler.GetPromptTemplate)
+				templates.PATCH("/:id", h.PromptTemplateHandler.UpdatePromptTemplate)
+				templates.DELETE("/:id", h.PromptTemplateHandler.DeletePromptTemplate)
 			}
 
 			// USER
 			users := auth.Group("/users")
 			{
-				users.POST("/invite", middleware.RoleAuthorization(constants.RoleOwner, constants.RoleMaintainer), h.UserHandler.InviteUserHandler)
-				users.POST("/resend-verification", middleware.RoleAuthorization(constants.RoleOwner, constants.RoleMaintainer), h.UserHandler.ResendVerificationEmail)
+				users.POST("/invite", middleware.RequireFlag(flags.InvitesEnabled), middleware.RequirePermission(constants.CapUsersInvite), h.UserHandler.InviteUserHandler)
+				users.POST("/resend-verification", middleware.RequirePermission(constants.CapUsersInvite), h.UserHandler.ResendVerificationEmail)
 
 				users.GET(
 					"",
@@ -52,19 +189,29 @@ func SetupRoutes(r *gin.Engine, h *handlers.HandlerManager, db *gorm.DB) *gin.En
 					h.UserHandler.GetCurrentUser,
 				)
 
+				// self-service profile edit (name, email language)
+				users.PATCH("/me", h.UserHandler.UpdateProfile)
+
 				users.GET(
 					"/:id",
 					middleware.RoleAuthorization(constants.RoleOwner, constants.RoleMaintainer),
 					h.UserHandler.GetUserByID,
 				)
 
-				users.PATCH("/:id/suspend", middleware.RoleAuthorization(constants.RoleOwner, constants.RoleMaintainer), h.UserHandler.SuspendUser)
+				users.PATCH("/:id", middleware.RoleAuthorization(constants.RoleOwner, constants.RoleMaintainer), h.UserHandler.UpdateUser)
+				users.PATCH("/:id/suspend", middleware.RequirePermission(constants.CapUsersSuspend), h.UserHandler.SuspendUser)
 				users.DELETE("/:id", middleware.RoleAuthorization(constants.RoleOwner, constants.RoleMaintainer), h.UserHandler.DeleteUser)
+				// GDPR "right to be forgotten": scrubs PII, keeps the id
+				users.POST("/:id/anonymize", middleware.RoleAuthorization(constants.RoleOwner), h.UserHandler.AnonymizeUser)
+				users.POST("/:id/force-activate", middleware.RoleAuthorization(constants.RoleOwner), h.UserHandler.ForceActivateUser)
 			}
 
 		}
 
 	}
 
+	// Effective route table for local debugging (non-production only)
+	RegisterDebugRoutes(r, api)
+
 	return r
 }
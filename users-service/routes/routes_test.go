@@ -0,0 +1,64 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/handlers"
+	"github.com/Bipul-Dubey/ai-knowledgebase/users-service/services"
+	"github.com/gin-gonic/gin"
+)
+
+func testRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	db := testutil.NewTestDB(t)
+	return SetupRoutes(gin.New(), handlers.NewHandlerManager(services.NewServiceManager(db)), db)
+}
+
+func envelopeCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var envelope struct {
+		Error bool   `json:"error"`
+		Code  string `json:"code"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("response is not the JSON envelope: %s", rec.Body.String())
+	}
+	if !envelope.Error {
+		t.Errorf("error flag not set: %s", rec.Body.String())
+	}
+	return envelope.Code
+}
+
+func TestUnknownRouteAnswersJSON404(t *testing.T) {
+	router := testRouter(t)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/no-such-route", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+	if code := envelopeCode(t, rec); code != "ROUTE_NOT_FOUND" {
+		t.Errorf("code = %q, want ROUTE_NOT_FOUND", code)
+	}
+}
+
+func TestWrongMethodAnswersJSON405(t *testing.T) {
+	router := testRouter(t)
+
+	// /api/v1/login only accepts POST
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/login", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want 405", rec.Code)
+	}
+	if code := envelopeCode(t, rec); code != "METHOD_NOT_ALLOWED" {
+		t.Errorf("code = %q, want METHOD_NOT_ALLOWED", code)
+	}
+}
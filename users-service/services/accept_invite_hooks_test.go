@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	"gorm.io/gorm"
+)
+
+// recordingHook is a test double for AcceptInviteHook: it fails BeforeCommit
+// with vetoErr when set, and signals AfterCommit deliveries on a channel
+// (AfterCommit runs on its own goroutine, so tests must wait on it).
+type recordingHook struct {
+	vetoErr   error
+	before    int
+	committed chan models.User
+}
+
+func newRecordingHook(vetoErr error) *recordingHook {
+	return &recordingHook{vetoErr: vetoErr, committed: make(chan models.User, 1)}
+}
+
+func (h *recordingHook) BeforeCommit(tx *gorm.DB, user *models.User) error {
+	h.before++
+	return h.vetoErr
+}
+
+func (h *recordingHook) AfterCommit(user models.User) {
+	h.committed <- user
+}
+
+// inviteFor seeds an org with an owner, invites email, and returns the org
+// plus the token delivered by email.
+func inviteFor(t *testing.T, h *authHarness, email string) (models.Organization, string) {
+	t.Helper()
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+	if _, err := h.users.InviteUser(owner.ID, "owner", org.ID, models.InviteUserRequest{
+		Email: email,
+		Role:  "member",
+		Name:  "New Member",
+	}); err != nil {
+		t.Fatalf("InviteUser failed: %v", err)
+	}
+	return org, h.lastEmailToken(t, "token")
+}
+
+func TestAcceptInviteHookRunsAroundCommit(t *testing.T) {
+	hook := newRecordingHook(nil)
+	h := newAuthHarness(t, hook)
+	org, token := inviteFor(t, h, "hooked@example.com")
+
+	accept, err := h.auth.AcceptInvite(models.AcceptInviteRequest{
+		Name:      "New Member",
+		Email:     "hooked@example.com",
+		Password:  "Chosen1Password",
+		AccountID: org.AccountID,
+		Token:     token,
+	})
+	if err != nil {
+		t.Fatalf("AcceptInvite failed: %v", err)
+	}
+	if hook.before != 1 {
+		t.Errorf("BeforeCommit ran %d times, want 1", hook.before)
+	}
+
+	select {
+	case committed := <-hook.committed:
+		if committed.ID != accept.UserID {
+			t.Errorf("AfterCommit saw user %s, want %s", committed.ID, accept.UserID)
+		}
+		if committed.Status != "active" {
+			t.Errorf("AfterCommit saw status %s, want active", committed.Status)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("AfterCommit was never notified")
+	}
+}
+
+func TestAcceptInviteHookVetoRollsBack(t *testing.T) {
+	hook := newRecordingHook(errors.New("workspace provisioning failed"))
+	h := newAuthHarness(t, hook)
+	org, token := inviteFor(t, h, "vetoed@example.com")
+
+	_, err := h.auth.AcceptInvite(models.AcceptInviteRequest{
+		Name:      "New Member",
+		Email:     "vetoed@example.com",
+		Password:  "Chosen1Password",
+		AccountID: org.AccountID,
+		Token:     token,
+	})
+	if err == nil {
+		t.Fatal("AcceptInvite succeeded despite the hook veto")
+	}
+
+	// The veto rolled the whole transaction back: the invite is untouched
+	// and usable once the external problem is fixed
+	var user models.User
+	if err := h.db.First(&user, "LOWER(email) = ?", "vetoed@example.com").Error; err != nil {
+		t.Fatalf("user lookup failed: %v", err)
+	}
+	if user.Status != "pending" {
+		t.Errorf("user status = %s, want pending", user.Status)
+	}
+	if user.InviteToken == nil {
+		t.Error("invite token was cleared by a rolled-back acceptance")
+	}
+
+	select {
+	case <-hook.committed:
+		t.Fatal("AfterCommit fired for a vetoed acceptance")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Retrying with the same token works once the hook stops failing
+	hook.vetoErr = nil
+	if _, err := h.auth.AcceptInvite(models.AcceptInviteRequest{
+		Name:      "New Member",
+		Email:     "vetoed@example.com",
+		Password:  "Chosen1Password",
+		AccountID: org.AccountID,
+		Token:     token,
+	}); err != nil {
+		t.Fatalf("retry after the veto cleared failed: %v", err)
+	}
+}
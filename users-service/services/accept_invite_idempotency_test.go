@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+// The accept-invite token is single-use: the winning accept clears it under
+// the row lock, so a replay — double-submitted form, leaked email link —
+// reports the account as already active instead of re-processing the invite.
+func TestAcceptInviteTokenIsSingleUse(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+
+	if _, err := h.users.InviteUser(owner.ID, "owner", org.ID, models.InviteUserRequest{
+		Email: "replayed@example.com",
+		Role:  "member",
+		Name:  "New Member",
+	}); err != nil {
+		t.Fatalf("InviteUser failed: %v", err)
+	}
+	token := h.lastEmailToken(t, "token")
+
+	req := models.AcceptInviteRequest{
+		Name:      "New Member",
+		Email:     "replayed@example.com",
+		Password:  "Chosen1Password",
+		AccountID: org.AccountID,
+		Token:     token,
+	}
+
+	first, err := h.auth.AcceptInvite(req)
+	if err != nil {
+		t.Fatalf("first AcceptInvite failed: %v", err)
+	}
+	if first.Status != "active" {
+		t.Fatalf("first accept left status %s, want active", first.Status)
+	}
+
+	// The replay is rejected — even when it tries to sneak in a different
+	// name and password
+	replay := req
+	replay.Name = "Impostor"
+	replay.Password = "Attacker1Password"
+	if _, err := h.auth.AcceptInvite(replay); err == nil {
+		t.Fatal("second AcceptInvite with the same token succeeded")
+	} else if !strings.Contains(err.Error(), "already active") {
+		t.Errorf("replay error = %q, want an already-active report", err)
+	}
+
+	// The committed state is untouched by the replay attempt
+	var user models.User
+	if err := h.db.First(&user, "id = ?", first.UserID).Error; err != nil {
+		t.Fatalf("user lookup failed: %v", err)
+	}
+	if user.Name != "New Member" {
+		t.Errorf("user name = %q, the replay must not overwrite it", user.Name)
+	}
+	if user.InviteToken != nil {
+		t.Error("invite token survived acceptance")
+	}
+	if _, err := h.auth.Login(context.Background(), &models.LoginRequest{
+		AccountID: org.AccountID,
+		Email:     "replayed@example.com",
+		Password:  "Chosen1Password",
+	}); err != nil {
+		t.Errorf("login with the originally chosen password failed: %v", err)
+	}
+}
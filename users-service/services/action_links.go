@@ -0,0 +1,41 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+	utils "github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+)
+
+// resolveActionToken unwraps the token a verify/invite/reset endpoint
+// received. Signed links are verified (signature, purpose, expiry) before
+// the caller's single-use token check; a mangled link — mail-provider
+// rewriting, truncation — fails here with a specific error instead of a
+// generic "invalid token". Legacy raw tokens pass through untouched while
+// the deprecation window (LEGACY_LINK_TOKENS) is open.
+func resolveActionToken(purpose, incoming string) (string, error) {
+	_, token, err := resolveActionTokenWithUser(purpose, incoming)
+	return token, err
+}
+
+// resolveActionTokenWithUser additionally returns the user id a signed link
+// was minted for (empty for legacy raw tokens), letting VerifyAccount answer
+// a double-clicked link idempotently after the token has been consumed.
+func resolveActionTokenWithUser(purpose, incoming string) (userID, token string, err error) {
+	if strings.HasPrefix(incoming, utils.SignedLinkPrefix) {
+		userID, token, err := utils.VerifyActionTokenWithUser(purpose, incoming)
+		switch {
+		case errors.Is(err, utils.ErrLinkExpired):
+			return "", "", apperrors.Gone("link has expired").WithCode("token_expired")
+		case err != nil:
+			return "", "", apperrors.BadRequest("this link is corrupted or incomplete, request a new one").WithCode("link_corrupted")
+		}
+		return userID, token, nil
+	}
+
+	if !utils.LegacyLinkTokensEnabled() {
+		return "", "", apperrors.BadRequest("invalid token").WithCode("invalid_token")
+	}
+	return "", incoming, nil
+}
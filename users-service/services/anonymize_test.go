@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/middleware"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+func TestAnonymizeUserScrubsPII(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+	target := testutil.NewUser(h.db, org).Named("Grace Hopper").WithEmail("grace@example.com").Active().Build()
+
+	ownerClaims, err := middleware.ValidateJWT(testutil.MintJWT(owner, org), h.db)
+	if err != nil {
+		t.Fatalf("owner token failed validation: %v", err)
+	}
+
+	if err := h.users.AnonymizeUser(ownerClaims, target.ID.String()); err != nil {
+		t.Fatalf("AnonymizeUser failed: %v", err)
+	}
+
+	// The row survives under the original id, but carries no PII
+	var scrubbed models.User
+	if err := h.db.First(&scrubbed, "id = ?", target.ID).Error; err != nil {
+		t.Fatalf("anonymized user no longer resolves: %v", err)
+	}
+	if scrubbed.Name == "Grace Hopper" || strings.Contains(scrubbed.Email, "grace") {
+		t.Errorf("PII survived anonymization: name=%q email=%q", scrubbed.Name, scrubbed.Email)
+	}
+	if scrubbed.Password != "" {
+		t.Error("password hash survived anonymization")
+	}
+	if scrubbed.InviteToken != nil || scrubbed.ExpiresAt != nil {
+		t.Error("tokens survived anonymization")
+	}
+	if scrubbed.Status != "anonymized" {
+		t.Errorf("status = %q, want anonymized", scrubbed.Status)
+	}
+
+	// The old credentials are dead
+	if _, err := h.auth.Login(context.Background(), &models.LoginRequest{
+		AccountID: org.AccountID,
+		Email:     "grace@example.com",
+		Password:  testutil.FixturePassword,
+	}); err == nil {
+		t.Error("anonymized user can still log in")
+	}
+
+	// A token minted before anonymization is dead too
+	if _, err := middleware.ValidateJWT(testutil.MintJWT(target, org), h.db); err == nil {
+		t.Error("pre-anonymization token still validates")
+	}
+
+	// The audit entry references the original id without re-identifying it
+	var entry models.AuditLog
+	if err := h.db.First(&entry, "action = ?", "user.anonymize").Error; err != nil {
+		t.Fatalf("no user.anonymize audit entry: %v", err)
+	}
+	if !strings.Contains(entry.Details, target.ID.String()) {
+		t.Errorf("audit details %q lack the original user id", entry.Details)
+	}
+	if strings.Contains(entry.Details, "Grace") || strings.Contains(entry.Details, "grace@") {
+		t.Errorf("audit details %q leak PII", entry.Details)
+	}
+}
+
+func TestAnonymizeUserGuards(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+	secondOwner := testutil.NewUser(h.db, org).Owner().Active().Build()
+
+	otherOrg := testutil.NewOrganization(h.db).Active().Build()
+	outsider := testutil.NewUser(h.db, otherOrg).Active().Build()
+
+	ownerClaims, err := middleware.ValidateJWT(testutil.MintJWT(owner, org), h.db)
+	if err != nil {
+		t.Fatalf("owner token failed validation: %v", err)
+	}
+
+	for name, target := range map[string]string{
+		"yourself":         owner.ID.String(),
+		"an owner":         secondOwner.ID.String(),
+		"another org's":    outsider.ID.String(),
+		"a malformed id":   "not-a-uuid",
+		"a nonexistent id": "7f0a2bb2-66cb-4f4e-9f39-2f2f36a1d000",
+	} {
+		if err := h.users.AnonymizeUser(ownerClaims, target); err == nil {
+			t.Errorf("AnonymizeUser allowed anonymizing %s", name)
+		}
+	}
+}
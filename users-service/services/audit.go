@@ -0,0 +1,43 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	utils "github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// recordAudit appends an audit_logs row for the given claims. The real admin
+// is recorded whenever the claims belong to an impersonation token. Audit
+// failures are logged but never fail the calling operation.
+func recordAudit(db *gorm.DB, claims *utils.JWTClaims, action, details string) {
+	orgUUID, err := uuid.Parse(claims.OrganizationID)
+	if err != nil {
+		fmt.Printf("[WARN] audit skipped for %s: invalid org id: %v\n", action, err)
+		return
+	}
+	actorUUID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		fmt.Printf("[WARN] audit skipped for %s: invalid actor id: %v\n", action, err)
+		return
+	}
+
+	entry := models.AuditLog{
+		ID:             uuid.New(),
+		OrganizationID: orgUUID,
+		ActorID:        actorUUID,
+		Action:         action,
+		Details:        details,
+	}
+	if claims.ImpersonatorID != "" {
+		if impUUID, err := uuid.Parse(claims.ImpersonatorID); err == nil {
+			entry.ImpersonatorID = &impUUID
+		}
+	}
+
+	if err := db.Create(&entry).Error; err != nil {
+		fmt.Printf("[WARN] failed to record audit entry %s: %v\n", action, err)
+	}
+}
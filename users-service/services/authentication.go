@@ -2,19 +2,24 @@ package services
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
 	"os"
-	"strconv"
-	"time"
-
+	"strings"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/cache"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/constants"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/db"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/events"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/middleware"
 	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/outbox"
 	utils "github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
-	"github.com/gin-gonic/gin"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/validation"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AuthenticationService interface {
@@ -22,22 +27,103 @@ type AuthenticationService interface {
 	VerifyAccount(ctx context.Context, token string) (*models.VerifyAccountResponse, error)
 	Login(ctx context.Context, req *models.LoginRequest) (*models.LoginResponse, error)
 	AcceptInvite(req models.AcceptInviteRequest) (*models.AcceptInviteResponse, error)
-	ForgotPassword(email, accountID string) (interface{}, error)
-	ResetPassword(claims any, oldPassword, newPassword string) (interface{}, error)
-	ResetPasswordByEmail(token string, newPassword string) (interface{}, error)
+	ForgotPassword(email, accountID string) (*models.ForgotPasswordResponse, error)
+	ResetPassword(claims *utils.JWTClaims, oldPassword, newPassword string) (*models.ResetPasswordResponse, error)
+	ResetPasswordByEmail(token string, newPassword string) (*models.ResetPasswordResponse, error)
+	IntrospectToken(token string) *models.TokenIntrospectionResponse
+	Impersonate(claims *utils.JWTClaims, targetUserID string) (*models.LoginResponse, error)
+	StopImpersonation(claims *utils.JWTClaims) (*models.LoginResponse, error)
+	JoinByDomain(req models.JoinByDomainRequest) (*models.JoinResponse, error)
+	OrganizationNameAvailable(name string) (bool, error)
+}
+
+// AcceptInviteHook lets integrators provision external resources around
+// invite acceptance. BeforeCommit runs inside the accept-invite transaction
+// and can veto the acceptance by returning an error (rolling everything
+// back); AfterCommit is notified asynchronously once the acceptance has
+// committed. Hooks are registered at service construction.
+type AcceptInviteHook interface {
+	BeforeCommit(tx *gorm.DB, user *models.User) error
+	AfterCommit(user models.User)
 }
+
 type authenticationService struct {
-	db *gorm.DB
+	db                *gorm.DB
+	emails            utils.EmailProvider
+	clock             utils.Clock
+	acceptInviteHooks []AcceptInviteHook
+}
+
+// NewAuthenticationService wires the service against the given email
+// provider and clock (pass nil for the SMTP sender / system clock).
+// Tests inject a utils.MemoryEmailProvider to capture tokens from
+// generated emails and a utils.FakeClock to drive expiry.
+func NewAuthenticationService(db *gorm.DB, emails utils.EmailProvider, clock utils.Clock, acceptInviteHooks ...AcceptInviteHook) AuthenticationService {
+	if emails == nil {
+		emails = utils.NewEmailSender()
+	}
+	if clock == nil {
+		clock = utils.SystemClock{}
+	}
+	return &authenticationService{db: db, emails: emails, clock: clock, acceptInviteHooks: acceptInviteHooks}
 }
 
-func NewAuthenticationService(db *gorm.DB) AuthenticationService {
-	return &authenticationService{db: db}
+// generateUniqueAccountID allocates a random checksummed account id,
+// retrying a few times if the candidate collides with an existing
+// organization or a retired alias.
+func generateUniqueAccountID(tx *gorm.DB) (string, error) {
+	for attempt := 0; attempt < 5; attempt++ {
+		candidate, err := utils.GenerateAccountID()
+		if err != nil {
+			return "", err
+		}
+
+		var count int64
+		if err := tx.Model(&models.Organization{}).Where("account_id = ?", candidate).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count > 0 {
+			continue
+		}
+		if err := tx.Model(&models.AccountIDAlias{}).Where("account_id = ?", candidate).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+	}
+	return "", errors.New("failed to allocate a unique account ID")
 }
 
 // ======
 // SignUp
 // ======
+// OrganizationNameAvailable mirrors the uniqueness check SignUp performs, so
+// signup forms can validate before submitting. Only org names are exposed
+// this way — answering for emails would let callers enumerate users.
+func (s *authenticationService) OrganizationNameAvailable(name string) (bool, error) {
+	var existing models.Organization
+	err := s.db.Where("name = ?", name).First(&existing).Error
+	if err == nil {
+		return false, nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true, nil
+	}
+	return false, err
+}
+
 func (s *authenticationService) SignUp(ctx context.Context, req *models.SignupRequest) (*models.SignupResponse, error) {
+	req.Email = utils.NormalizeEmail(req.Email)
+
+	// SignupRequest predates binding tags, so enforce the shared rules here
+	if err := validation.Email(req.Email); err != nil {
+		return nil, err
+	}
+	if err := validation.Password(req.Password); err != nil {
+		return nil, err
+	}
+
 	tx := s.db.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return nil, tx.Error
@@ -53,38 +139,21 @@ func (s *authenticationService) SignUp(ctx context.Context, req *models.SignupRe
 	var existingOrg models.Organization
 	if err := tx.Where("name = ?", req.OrganizationName).First(&existingOrg).Error; err == nil {
 		tx.Rollback()
-		return nil, errors.New("organization with this name already exists")
+		return nil, apperrors.Conflict("organization with this name already exists").WithCode("org_name_taken")
 	}
 
-	// 2️⃣ Generate incremental account_id
-	var maxAccountID sql.NullString
-	if err := tx.Model(&models.Organization{}).Select("MAX(account_id)").Scan(&maxAccountID).Error; err != nil {
+	// 2️⃣ Generate a random, checksummed account_id (retry on collision)
+	accountID, err := generateUniqueAccountID(tx)
+	if err != nil {
 		tx.Rollback()
-		return nil, fmt.Errorf("failed to fetch max account ID: %w", err)
-	}
-
-	var lastID int64
-	if maxAccountID.Valid && maxAccountID.String != "" {
-		parsedID, parseErr := strconv.ParseInt(maxAccountID.String, 10, 64)
-		if parseErr != nil {
-			tx.Rollback()
-			return nil, fmt.Errorf("failed to parse last account ID: %w", parseErr)
-		}
-		lastID = parsedID
-	} else {
-		lastID = 1100000000000000
+		return nil, err
 	}
 
-	accountID := fmt.Sprintf("%016d", lastID+1)
-
 	// 3️⃣ Create organization
 	org := models.Organization{
-		ID:        uuid.New(),
 		Name:      req.OrganizationName,
 		AccountID: accountID,
 		Status:    "pending",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
 	}
 	if err := tx.Create(&org).Error; err != nil {
 		tx.Rollback()
@@ -92,7 +161,7 @@ func (s *authenticationService) SignUp(ctx context.Context, req *models.SignupRe
 	}
 
 	// 4️⃣ Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
 		tx.Rollback()
 		return nil, err
@@ -100,21 +169,19 @@ func (s *authenticationService) SignUp(ctx context.Context, req *models.SignupRe
 
 	// 5️⃣ Generate invite token
 	inviteToken, _ := utils.GenerateSecureToken(32)
-	expiresAt := time.Now().Add(1 * time.Hour)
+	expiresAt := s.clock.Now().Add(constants.VerifyTokenTTL)
 
 	// 6️⃣ Create owner user
 	user := models.User{
-		ID:             uuid.New(),
 		OrganizationID: org.ID,
 		Name:           req.OwnerName,
 		Email:          req.Email,
-		Password:       string(hashedPassword),
+		Password:       hashedPassword,
 		Role:           "owner",
 		Status:         "pending",
+		Locale:         utils.NormalizeLocale(req.Locale),
 		InviteToken:    &inviteToken,
 		ExpiresAt:      &expiresAt,
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
 	}
 	if err := tx.Create(&user).Error; err != nil {
 		tx.Rollback()
@@ -128,32 +195,32 @@ func (s *authenticationService) SignUp(ctx context.Context, req *models.SignupRe
 		return nil, err
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		return nil, err
-	}
+	// 8️⃣ Record the verification email in the outbox within the same
+	// transaction, so a crash after commit can't lose it; the dispatcher
+	// delivers it once the commit lands. The link carries a signed wrapper,
+	// not the raw token.
+	frontendURL := os.Getenv("FRONTEND_BASE_URL")
+	signedToken := utils.SignActionToken(utils.LinkPurposeVerify, user.ID.String(), inviteToken, expiresAt)
+	verifyLink := fmt.Sprintf("%s/pl/verify-account?token=%s&account_id=%s", frontendURL, signedToken, accountID)
 
-	// 8️⃣ Send verification email asynchronously
-	go func() {
-		frontendURL := os.Getenv("FRONTEND_BASE_URL")
-		verifyLink := fmt.Sprintf("%s/pl/verify-account?token=%s&account_id=%s", frontendURL, inviteToken, accountID)
+	emailSubject, emailBody, _ := utils.RenderEmail("verify", user.Locale, utils.EmailTemplateData{
+		Name:             req.OwnerName,
+		OrganizationName: req.OrganizationName,
+		AccountID:        org.AccountID,
+		Link:             verifyLink,
+	})
 
-		emailBody := fmt.Sprintf(`
-			<h2>Welcome to %s!</h2>
-			<p>Hi %s,</p>
-			<b>Account ID: %s</b>
-			<p>Please verify your account by clicking the button below:</p>
-			<a href="%s" style="background:#4F46E5;color:white;padding:10px 20px;border-radius:6px;text-decoration:none;">Verify Account</a>
-			<p>This link will expire in 1 hour.</p>
-		`, req.OrganizationName, req.OwnerName, org.AccountID, verifyLink)
+	if err := outbox.Enqueue(tx, &org.ID, "verify", req.Email, emailSubject, emailBody); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
-		emailSender := utils.NewEmailSender()
-		if err := emailSender.SendEmail(req.Email, "Verify Your Account", emailBody); err != nil {
-			fmt.Printf("[WARN] Failed to send verification email: %v\n", err)
-		}
-	}()
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
 
 	// 9️⃣ Return response including account_id
-	return &models.SignupResponse{
+	res := &models.SignupResponse{
 		OrganizationID: org.ID,
 		AccountID:      org.AccountID,
 		UserID:         user.ID,
@@ -161,54 +228,124 @@ func (s *authenticationService) SignUp(ctx context.Context, req *models.SignupRe
 		Email:          user.Email,
 		Role:           user.Role,
 		Status:         user.Status,
-		InviteToken:    inviteToken,
-		ExpiresAt:      &expiresAt,
-	}, nil
+	}
+
+	// The verification token only goes out via email; exposing it in the API
+	// response lets anyone verify without inbox access.
+	if devExposeTokens() {
+		res.InviteToken = inviteToken
+		res.ExpiresAt = &expiresAt
+	}
+
+	return res, nil
+}
+
+// devExposeTokens reports whether invite/verification tokens should be echoed
+// in API responses. This is for local testing only and must never be enabled
+// in production.
+func devExposeTokens() bool {
+	if os.Getenv("DEV_EXPOSE_TOKENS") != "true" {
+		return false
+	}
+	fmt.Println("⚠️ DEV_EXPOSE_TOKENS is enabled: invite tokens are exposed in API responses (insecure, local testing only)")
+	return true
 }
 
 // ======
 // VerifyAccount
 // ======
+// VerifyAccount is retry-safe: verifying an already-active account is a 200
+// success with is_verified true (double-clicked links are not an error), while
+// an unknown token stays 400 and an expired one 410 — the frontend only shows
+// an error when something is actually wrong.
 func (s *authenticationService) VerifyAccount(ctx context.Context, token string) (*models.VerifyAccountResponse, error) {
+	// 🔏 Signed links verify signature/purpose/expiry before the single-use
+	// token check below; legacy raw tokens pass through during the window.
+	// linkUserID (signed links only) lets a double-clicked link succeed
+	// idempotently after the winner cleared the token.
+	linkUserID, token, err := resolveActionTokenWithUser(utils.LinkPurposeVerify, token)
+	if err != nil {
+		return nil, err
+	}
+
 	var user models.User
+	consumed := false
+
+	// force primary: the token may have been written moments ago (signup),
+	// and a lagging replica would report it as invalid
+	err = db.Primary(s.db).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1️⃣ SELECT ... FOR UPDATE by invite token: two concurrent submits of
+		// the same link serialize here instead of both consuming the token
+		// (and both publishing user.verified)
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("invite_token = ?", token).
+			First(&user).Error; err != nil {
+			// Token already consumed: a signed link still names its user, so
+			// a re-clicked link on an active account is an idempotent success
+			// rather than a scary "invalid token"
+			if linkUserID != "" {
+				if err := tx.Where("id = ? AND status = ?", linkUserID, "active").
+					First(&user).Error; err == nil {
+					return nil
+				}
+			}
+			return apperrors.BadRequest("invalid token").WithCode("invalid_token")
+		}
 
-	// 1️⃣ Find user by invite token
-	if err := s.db.WithContext(ctx).
-		Where("invite_token = ?", token).
-		First(&user).Error; err != nil {
-		return nil, fmt.Errorf("invalid token")
-	}
+		// 2️⃣ Already verified: idempotent success, a double-submitted link is
+		// not an error (the winner of a race clears the token on commit, so
+		// only near-simultaneous losers land here)
+		if user.Status == "active" {
+			return nil
+		}
 
-	// 2️⃣ Check if already verified
-	if user.Status == "active" {
-		return nil, fmt.Errorf("account already verified")
-	}
+		// 3️⃣ Check if token expired (nil expiry with a live token is an
+		// inconsistent row and counts as expired)
+		if user.ExpiresAt == nil || s.clock.Now().After(*user.ExpiresAt) {
+			return apperrors.Gone("token has expired").WithCode("token_expired")
+		}
 
-	// 3️⃣ Check if token expired
-	if user.ExpiresAt != nil && time.Now().After(*user.ExpiresAt) {
-		return nil, fmt.Errorf("token has expired")
-	}
+		// 4️⃣ Consume the token: activate and clear it in the same transaction
+		user.Status = "active"
+		user.InviteToken = nil
+		user.ExpiresAt = nil
+		acceptedAt := s.clock.Now()
+		user.AcceptedAt = &acceptedAt
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		consumed = true
+
+		// 5️⃣ If user is owner, activate organization
+		if user.Role == "owner" {
+			result := tx.Model(&models.Organization{}).
+				Where("id = ?", user.OrganizationID).
+				Update("status", "active")
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("organization not found")
+			}
+		}
 
-	// 4️⃣ Update user status to active and clear token
-	user.Status = "active"
-	user.InviteToken = nil
-	user.ExpiresAt = nil
-	user.UpdatedAt = time.Now()
-	if err := s.db.Save(&user).Error; err != nil {
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// 5️⃣ If user is owner, activate organization
-	if user.Role == "owner" {
-		var org models.Organization
-		if err := s.db.First(&org, "id = ?", user.OrganizationID).Error; err != nil {
-			return nil, fmt.Errorf("organization not found")
-		}
-		org.Status = "active"
-		org.UpdatedAt = time.Now()
-		if err := s.db.Save(&org).Error; err != nil {
-			return nil, err
-		}
+	// Side effects only for the attempt that actually consumed the token,
+	// so retries can't double-publish events
+	if consumed {
+		cache.InvalidateUser(user.ID.String())
+		events.Publish(events.Event{
+			Type:    "user.verified",
+			OrgID:   user.OrganizationID.String(),
+			ActorID: user.ID.String(),
+			Payload: map[string]interface{}{"email": user.Email},
+		})
 	}
 
 	// 6️⃣ Prepare response
@@ -224,15 +361,32 @@ func (s *authenticationService) VerifyAccount(ctx context.Context, token string)
 }
 
 func (s *authenticationService) Login(ctx context.Context, req *models.LoginRequest) (*models.LoginResponse, error) {
-	// 1️⃣ Find organization by account_id (BIGINT)
+	req.AccountID = utils.NormalizeAccountID(req.AccountID)
+	if err := utils.ValidateAccountID(req.AccountID); err != nil {
+		return nil, err
+	}
+
+	// 1️⃣ Find organization by account_id (retried: login is a pure read and
+	// the first thing to fail during a Postgres failover blip)
 	var org models.Organization
-	if err := s.db.Where("account_id = ?", req.AccountID).First(&org).Error; err != nil {
+	if err := db.Retry(func() error {
+		return s.db.Where("account_id = ?", req.AccountID).First(&org).Error
+	}); err != nil {
+		// Retired id? Point the user at the regenerated one.
+		var alias models.AccountIDAlias
+		if aliasErr := s.db.Where("account_id = ?", req.AccountID).First(&alias).Error; aliasErr == nil {
+			return nil, errors.New("account ID changed: ask your organization admin for the new account ID")
+		}
 		return nil, errors.New("organization not found")
 	}
 
 	// 2️⃣ Find user by email + organization_id
 	var user models.User
-	if err := s.db.Where("email = ? AND organization_id = ?", req.Email, org.ID).First(&user).Error; err != nil {
+	// case-insensitive: rows written before emails were normalized may still
+	// be mixed-case, and the lower-index makes this lookup cheap
+	if err := db.Retry(func() error {
+		return s.db.Where("LOWER(email) = ? AND organization_id = ?", utils.NormalizeEmail(req.Email), org.ID).First(&user).Error
+	}); err != nil {
 		return nil, errors.New("invalid credentials")
 	}
 
@@ -241,11 +395,24 @@ func (s *authenticationService) Login(ctx context.Context, req *models.LoginRequ
 		return nil, errors.New("user is not active")
 	}
 
-	// 4️⃣ Verify password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+	// 4️⃣ Verify password (dispatches on the stored hash's algorithm)
+	ok, err := utils.VerifyPassword(user.Password, req.Password)
+	if err != nil || !ok {
 		return nil, errors.New("invalid credentials")
 	}
 
+	// 5️⃣ Transparent upgrade: re-hash with the preferred algorithm when the
+	// stored hash uses the other one. Best effort — a failed rehash must
+	// never fail a correct login.
+	if utils.NeedsRehash(user.Password) {
+		if rehashed, rerr := utils.HashPassword(req.Password); rerr == nil {
+			if uerr := s.db.Model(&models.User{}).Where("id = ?", user.ID).
+				Update("password", rehashed).Error; uerr != nil {
+				fmt.Printf("⚠️ Failed to upgrade password hash for user %s: %v\n", user.ID, uerr)
+			}
+		}
+	}
+
 	jwtUser := utils.JWTUser{
 		UserID:         user.ID.String(),
 		OrganizationID: user.OrganizationID.String(),
@@ -273,42 +440,97 @@ func (s *authenticationService) Login(ctx context.Context, req *models.LoginRequ
 }
 
 func (s *authenticationService) AcceptInvite(req models.AcceptInviteRequest) (*models.AcceptInviteResponse, error) {
-	var user models.User
-	if err := s.db.
-		Joins("JOIN organizations o ON o.id = users.organization_id").
-		Where("users.email = ? AND users.invite_token = ? AND o.account_id = ?", req.Email, req.Token, req.AccountID).
-		First(&user).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("invalid invite token or account id")
-		}
+	req.AccountID = utils.NormalizeAccountID(req.AccountID)
+	if err := utils.ValidateAccountID(req.AccountID); err != nil {
 		return nil, err
 	}
 
-	if user.ExpiresAt != nil && user.ExpiresAt.Before(time.Now()) {
-		return nil, errors.New("invite token has expired")
+	// 🔏 Signed links verify signature/purpose/expiry before the single-use
+	// token check below; legacy raw tokens pass through during the window
+	token, err := resolveActionToken(utils.LinkPurposeInvite, req.Token)
+	if err != nil {
+		return nil, err
 	}
+	req.Token = token
 
-	if user.Status == "active" {
-		return nil, errors.New("user already active, no need to accept invite")
-	}
+	var user models.User
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// SELECT ... FOR UPDATE on the user row: two concurrent accepts with
+		// the same token serialize here, so the second one re-reads the
+		// committed state instead of double-processing the invite. The lookup
+		// is by email + account (not token) so the loser of the race still
+		// finds the row and reports "already active".
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "users"}}).
+			Joins("JOIN organizations o ON o.id = users.organization_id").
+			Where("LOWER(users.email) = ? AND o.account_id = ?", utils.NormalizeEmail(req.Email), req.AccountID).
+			First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("invalid invite token or account id")
+			}
+			return err
+		}
+
+		// Re-checked under the row lock
+		if user.Status == "active" {
+			return errors.New("user already active, no need to accept invite")
+		}
+
+		if user.InviteToken == nil || *user.InviteToken != req.Token {
+			return errors.New("invalid invite token or account id")
+		}
+
+		if user.ExpiresAt == nil || user.ExpiresAt.Before(s.clock.Now()) {
+			// nil expiry with a live token is an inconsistent row; expired
+			return apperrors.Gone("invite token has expired").WithCode("token_expired")
+		}
+
+		hashedPassword, err := utils.HashPassword(req.Password)
+		if err != nil {
+			return errors.New("failed to hash password")
+		}
+
+		user.Name = req.Name
+		user.Password = hashedPassword
+		user.Status = "active"
+		user.InviteToken = nil
+		user.ExpiresAt = nil
+		acceptedAt := s.clock.Now()
+		user.AcceptedAt = &acceptedAt
+
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+
+		// Pre-commit hooks run inside the transaction and may veto the
+		// acceptance (e.g. external workspace provisioning failed).
+		for _, hook := range s.acceptInviteHooks {
+			if err := hook.BeforeCommit(tx, &user); err != nil {
+				return fmt.Errorf("accept-invite hook rejected: %w", err)
+			}
+		}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		return nil
+	})
 	if err != nil {
-		return nil, errors.New("failed to hash password")
+		return nil, err
 	}
 
-	user.Name = req.Name
-	user.Password = string(hashedPassword)
-	user.Status = "active"
-	user.InviteToken = nil
-	user.ExpiresAt = nil
-	user.UpdatedAt = time.Now()
+	cache.InvalidateUser(user.ID.String())
+	events.Publish(events.Event{
+		Type:    "user.verified",
+		OrgID:   user.OrganizationID.String(),
+		ActorID: user.ID.String(),
+		Payload: map[string]interface{}{"email": user.Email, "via": "invite"},
+	})
 
-	if err := s.db.Save(&user).Error; err != nil {
-		return nil, err
+	// Post-commit notifications are best-effort and async
+	for _, hook := range s.acceptInviteHooks {
+		go hook.AfterCommit(user)
 	}
 
-	return &models.AcceptInviteResponse{
+	resp := &models.AcceptInviteResponse{
 		UserID:         user.ID,
 		Name:           user.Name,
 		Email:          user.Email,
@@ -316,16 +538,52 @@ func (s *authenticationService) AcceptInvite(req models.AcceptInviteRequest) (*m
 		Role:           user.Role,
 		Status:         user.Status,
 		IsVerified:     true,
-	}, nil
+	}
+
+	// Optional auto-login: the user was just activated with a password they
+	// chose, so a session can be issued without the separate login round
+	// trip. Orgs enforcing 2FA get a challenge marker instead — the full
+	// challenge lives in the regular login flow. Best effort: a token
+	// failure must not undo a committed acceptance.
+	if req.AutoLogin {
+		var org models.Organization
+		if err := s.db.First(&org, "id = ?", user.OrganizationID).Error; err != nil {
+			fmt.Printf("⚠️ Auto-login org lookup failed for user %s: %v\n", user.ID, err)
+			return resp, nil
+		}
+		if org.Enforce2FA {
+			resp.TwoFactorRequired = true
+			return resp, nil
+		}
+		token, err := utils.GenerateJWT(utils.JWTUser{
+			UserID:         user.ID.String(),
+			OrganizationID: user.OrganizationID.String(),
+			AccountID:      org.AccountID,
+			Role:           user.Role,
+			TokenVersion:   user.TokenVersion,
+		})
+		if err != nil {
+			fmt.Printf("⚠️ Auto-login token generation failed for user %s: %v\n", user.ID, err)
+			return resp, nil
+		}
+		resp.AccessToken = token
+	}
+
+	return resp, nil
 }
 
 // 🔹 Forgot Password
-func (s *authenticationService) ForgotPassword(email, accountID string) (interface{}, error) {
+func (s *authenticationService) ForgotPassword(email, accountID string) (*models.ForgotPasswordResponse, error) {
+	accountID = utils.NormalizeAccountID(accountID)
+	if err := utils.ValidateAccountID(accountID); err != nil {
+		return nil, err
+	}
+
 	var user models.User
 
 	// 🔹 Fetch user by email + account ID + active status
 	if err := s.db.Joins("JOIN organizations o ON o.id = users.organization_id").
-		Where("users.email = ? AND o.account_id = ? AND users.status = ?", email, accountID, "active").
+		Where("LOWER(users.email) = ? AND o.account_id = ? AND users.status = ?", utils.NormalizeEmail(email), accountID, "active").
 		First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("user not registered or inactive in this organization")
@@ -335,7 +593,7 @@ func (s *authenticationService) ForgotPassword(email, accountID string) (interfa
 
 	// 🔹 Generate reset token and expiry
 	resetToken := uuid.NewString()
-	expiresAt := time.Now().Add(1 * time.Hour)
+	expiresAt := s.clock.Now().Add(constants.ResetTokenTTL)
 
 	// 🔹 Update user with new token
 	if err := s.db.Model(&user).Updates(map[string]interface{}{
@@ -345,76 +603,106 @@ func (s *authenticationService) ForgotPassword(email, accountID string) (interfa
 		return nil, err
 	}
 
-	// 🔹 Prepare reset password link
+	// 🔹 Prepare reset password link (signed wrapper, not the raw token)
 	frontendURL := os.Getenv("FRONTEND_BASE_URL")
-	resetLink := fmt.Sprintf("%s/pl/reset-password?token=%s", frontendURL, resetToken)
-
-	// 🔹 Email content
-	subject := "Reset Your Password"
-	body := fmt.Sprintf(`
-		<h2>Password Reset Request</h2>
-		<p>Hello %s,</p>
-		<p>We received a request to reset your password. Click below to set a new password:</p>
-		<a href="%s" style="background:#4F46E5;color:white;padding:10px 20px;border-radius:6px;text-decoration:none;">Reset Password</a>
-		<p>This link will expire in 1 hour. If you didn’t request a password reset, you can safely ignore this email.</p>
-	`, user.Name, resetLink)
-
-	// 🔹 Send email using shared util
-	emailSender := utils.NewEmailSender()
-	if err := emailSender.SendEmail(user.Email, subject, body); err != nil {
+	signedToken := utils.SignActionToken(utils.LinkPurposeReset, user.ID.String(), resetToken, expiresAt)
+	resetLink := fmt.Sprintf("%s/pl/reset-password?token=%s", frontendURL, signedToken)
+
+	// 🔹 Email content, localized to the user's locale and org-branded
+	var org models.Organization
+	s.db.Select("brand_logo_url, brand_primary_color, email_footer_text").
+		Where("id = ?", user.OrganizationID).First(&org)
+
+	subject, body, _ := utils.RenderEmail("reset", user.Locale, utils.EmailTemplateData{
+		Name:     user.Name,
+		Link:     resetLink,
+		Branding: orgBranding(org),
+	})
+
+	// 🔹 Send email via the injected provider
+	if err := s.emails.SendEmail(user.Email, subject, body); err != nil {
 		fmt.Printf("⚠️ Failed to send reset email: %v\n", err)
 		return nil, errors.New("failed to send reset password email, please try again later")
 	}
 
 	// 🔹 Return minimal response
-	return gin.H{
-		"email":      user.Email,
-		"account_id": accountID,
-		"message":    "Password reset link sent successfully",
-		"expires_at": expiresAt,
-	}, nil
+	resp := &models.ForgotPasswordResponse{
+		Email:     user.Email,
+		AccountID: accountID,
+		ExpiresAt: expiresAt,
+	}
+
+	// Local testing without an inbox: expose the reset link in the response
+	if devExposeTokens() {
+		resp.ResetLink = resetLink
+	}
+
+	return resp, nil
 }
 
-func (s *authenticationService) ResetPasswordByEmail(token string, newPassword string) (interface{}, error) {
-	var user models.User
-	if err := s.db.Where("invite_token = ?", token).First(&user).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("invalid or expired reset link")
-		}
+func (s *authenticationService) ResetPasswordByEmail(token string, newPassword string) (*models.ResetPasswordResponse, error) {
+	// 🔏 Signed links verify signature/purpose/expiry before the single-use
+	// token check below; legacy raw tokens pass through during the window
+	token, err := resolveActionToken(utils.LinkPurposeReset, token)
+	if err != nil {
 		return nil, err
 	}
 
-	// ⏰ Check token expiry
-	if user.ExpiresAt.Before(time.Now()) {
-		return nil, errors.New("reset link expired")
-	}
+	// 🧩 Hash outside the transaction — bcrypt is slow and we hold a row lock
+	hashed, _ := utils.HashPassword(newPassword)
 
-	// 🧩 Hash new password
-	hashed, _ := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
-	user.Password = string(hashed)
-	user.Status = "active"
-	user.TokenVersion += 1
-	user.InviteToken = nil
-	user.ExpiresAt = nil
+	var user models.User
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// 1️⃣ Check-and-consume: lock the row so two concurrent submissions of
+		// the same token serialize; the loser re-reads after the clear commits
+		// and sees no matching token
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("invite_token = ?", token).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("invalid or expired reset link")
+			}
+			return err
+		}
 
-	if err := s.db.Save(&user).Error; err != nil {
+		// ⏰ Check token expiry. A nil expiry alongside a live token is an
+		// inconsistent row (cleanup job or a partially completed earlier
+		// reset); treat it as expired instead of panicking on the deref
+		if user.ExpiresAt == nil || user.ExpiresAt.Before(s.clock.Now()) {
+			return apperrors.Gone("reset link expired").WithCode("token_expired")
+		}
+
+		// 2️⃣ Consume the token and set the new password atomically
+		user.Password = hashed
+		user.Status = "active"
+		user.TokenVersion += 1
+		user.InviteToken = nil
+		user.ExpiresAt = nil
+
+		return tx.Save(&user).Error
+	})
+	if err != nil {
 		return nil, err
 	}
-
-	return gin.H{
-		"user_id":  user.ID,
-		"email":    user.Email,
-		"status":   user.Status,
-		"verified": true,
+	cache.InvalidateUser(user.ID.String())
+	events.Publish(events.Event{
+		Type:    "password.reset",
+		OrgID:   user.OrganizationID.String(),
+		ActorID: user.ID.String(),
+		Payload: map[string]interface{}{"via": "email_link"},
+	})
+
+	return &models.ResetPasswordResponse{
+		UserID:   user.ID,
+		Email:    user.Email,
+		Status:   user.Status,
+		Verified: true,
 	}, nil
 }
 
 // 🔹 Reset Password
-func (s *authenticationService) ResetPassword(claims any, oldPassword, newPassword string) (interface{}, error) {
-	userClaims := claims.(*utils.JWTClaims)
-
+func (s *authenticationService) ResetPassword(claims *utils.JWTClaims, oldPassword, newPassword string) (*models.ResetPasswordResponse, error) {
 	var user models.User
-	if err := s.db.First(&user, "id = ?", userClaims.UserID).Error; err != nil {
+	if err := s.db.First(&user, "id = ?", claims.UserID).Error; err != nil {
 		return nil, errors.New("user not found")
 	}
 
@@ -422,23 +710,251 @@ func (s *authenticationService) ResetPassword(claims any, oldPassword, newPasswo
 		return nil, errors.New("user is not active")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
+	if ok, err := utils.VerifyPassword(user.Password, oldPassword); err != nil || !ok {
 		return nil, errors.New("old password is incorrect")
 	}
 
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
-	user.Password = string(hashedPassword)
+	hashedPassword, _ := utils.HashPassword(newPassword)
+	user.Password = hashedPassword
 	user.TokenVersion += 1
 
 	if err := s.db.Save(&user).Error; err != nil {
 		return nil, err
 	}
+	cache.InvalidateUser(user.ID.String())
+	events.Publish(events.Event{
+		Type:    "password.reset",
+		OrgID:   user.OrganizationID.String(),
+		ActorID: user.ID.String(),
+		Payload: map[string]interface{}{"via": "old_password"},
+	})
+
+	return &models.ResetPasswordResponse{
+		UserID:   user.ID,
+		Email:    user.Email,
+		Role:     user.Role,
+		Status:   user.Status,
+		Verified: true,
+	}, nil
+}
+
+// IntrospectToken runs the same validation as AuthMiddleware (signature,
+// expiry, user active, token version) and reports the claims for gateways.
+// Invalid tokens yield {active: false} rather than an error, per RFC 7662.
+func (s *authenticationService) IntrospectToken(token string) *models.TokenIntrospectionResponse {
+	claims, err := middleware.ValidateJWT(token, s.db)
+	if err != nil {
+		return &models.TokenIntrospectionResponse{Active: false}
+	}
 
-	return gin.H{
-		"user_id":  user.ID,
-		"email":    user.Email,
-		"role":     user.Role,
-		"status":   user.Status,
-		"verified": true,
+	res := &models.TokenIntrospectionResponse{
+		Active:         true,
+		UserID:         claims.UserID,
+		OrganizationID: claims.OrganizationID,
+		Role:           claims.Role,
+	}
+	if claims.ExpiresAt != nil {
+		res.Exp = claims.ExpiresAt.Unix()
+	}
+	return res
+}
+
+// Impersonate issues a short-lived token acting as the target user while
+// recording the real admin in the token and the audit log. Owners only
+// (enforced at the route), within their own organization.
+func (s *authenticationService) Impersonate(claims *utils.JWTClaims, targetUserID string) (*models.LoginResponse, error) {
+	targetUUID, err := uuid.Parse(targetUserID)
+	if err != nil {
+		return nil, errors.New("invalid target user id")
+	}
+
+	if targetUserID == claims.UserID {
+		return nil, errors.New("cannot impersonate yourself")
+	}
+
+	var target models.User
+	if err := s.db.Where("id = ? AND organization_id = ?", targetUUID, claims.OrganizationID).First(&target).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	if target.Status != "active" {
+		return nil, errors.New("target user is not active")
+	}
+
+	var org models.Organization
+	if err := s.db.First(&org, "id = ?", target.OrganizationID).Error; err != nil {
+		return nil, errors.New("organization not found")
+	}
+
+	token, err := utils.GenerateImpersonationJWT(utils.JWTUser{
+		UserID:         target.ID.String(),
+		OrganizationID: target.OrganizationID.String(),
+		AccountID:      org.AccountID,
+		Role:           target.Role,
+		TokenVersion:   target.TokenVersion,
+	}, claims.UserID)
+	if err != nil {
+		return nil, errors.New("failed to generate impersonation token")
+	}
+
+	recordAudit(s.db, claims, "impersonation.start", fmt.Sprintf("impersonating user %s", target.ID))
+
+	return &models.LoginResponse{
+		AccessToken:      token,
+		UserID:           target.ID,
+		OrganizationID:   target.OrganizationID,
+		Role:             target.Role,
+		Name:             target.Name,
+		Email:            target.Email,
+		Status:           target.Status,
+		OrganizationName: org.Name,
+	}, nil
+}
+
+// StopImpersonation ends an impersonation session by issuing a regular
+// token for the real admin behind the current impersonation token.
+func (s *authenticationService) StopImpersonation(claims *utils.JWTClaims) (*models.LoginResponse, error) {
+	if claims.ImpersonatorID == "" {
+		return nil, errors.New("not an impersonation session")
+	}
+
+	var admin models.User
+	if err := s.db.Where("id = ? AND organization_id = ?", claims.ImpersonatorID, claims.OrganizationID).First(&admin).Error; err != nil {
+		return nil, errors.New("impersonating admin not found")
+	}
+
+	if admin.Status != "active" {
+		return nil, errors.New("impersonating admin is not active")
+	}
+
+	var org models.Organization
+	if err := s.db.First(&org, "id = ?", admin.OrganizationID).Error; err != nil {
+		return nil, errors.New("organization not found")
+	}
+
+	token, err := utils.GenerateJWT(utils.JWTUser{
+		UserID:         admin.ID.String(),
+		OrganizationID: admin.OrganizationID.String(),
+		AccountID:      org.AccountID,
+		Role:           admin.Role,
+		TokenVersion:   admin.TokenVersion,
+	})
+	if err != nil {
+		return nil, errors.New("failed to generate access token")
+	}
+
+	recordAudit(s.db, claims, "impersonation.stop", fmt.Sprintf("stopped impersonating user %s", claims.UserID))
+
+	return &models.LoginResponse{
+		AccessToken:      token,
+		UserID:           admin.ID,
+		OrganizationID:   admin.OrganizationID,
+		Role:             admin.Role,
+		Name:             admin.Name,
+		Email:            admin.Email,
+		Status:           admin.Status,
+		OrganizationName: org.Name,
+	}, nil
+}
+
+// ======
+// JoinByDomain
+// ======
+// JoinByDomain creates a pending member account in the org that has verified
+// ownership of the email's domain. The account activates like a normal
+// signup: only after the verification email is confirmed.
+func (s *authenticationService) JoinByDomain(req models.JoinByDomainRequest) (*models.JoinResponse, error) {
+	req.Email = utils.NormalizeEmail(req.Email)
+
+	at := strings.LastIndex(req.Email, "@")
+	if at < 0 {
+		return nil, errors.New("invalid email address")
+	}
+	domain := strings.ToLower(req.Email[at+1:])
+
+	// 1️⃣ Only a verified domain enables auto-join
+	var orgDomain models.OrganizationDomain
+	if err := s.db.
+		Where("domain = ? AND verified_at IS NOT NULL", domain).
+		First(&orgDomain).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("no organization has verified this email domain")
+		}
+		return nil, err
+	}
+
+	var org models.Organization
+	if err := s.db.First(&org, "id = ?", orgDomain.OrganizationID).Error; err != nil {
+		return nil, err
+	}
+
+	// 2️⃣ Reject duplicate membership
+	var existing models.User
+	if err := s.db.Where("organization_id = ? AND LOWER(email) = ?", org.ID, req.Email).First(&existing).Error; err == nil {
+		if existing.Status == "active" {
+			return nil, errors.New("user already exists and is active")
+		}
+		return nil, errors.New("user has already been invited")
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, errors.New("failed to hash password")
+	}
+
+	verifyToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, errors.New("failed to generate verification token")
+	}
+	expiresAt := s.clock.Now().Add(constants.VerifyTokenTTL)
+
+	user := models.User{
+		OrganizationID: org.ID,
+		Name:           req.Name,
+		Email:          req.Email,
+		Password:       hashedPassword,
+		Role:           "member",
+		Status:         "pending",
+		Locale:         utils.NormalizeLocale(org.DefaultLocale),
+		InviteToken:    &verifyToken,
+		ExpiresAt:      &expiresAt,
+	}
+	// Seat check and insert share a transaction so concurrent joins
+	// serialize on the org row and can't overshoot the limit
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureSeatAvailable(tx, org.ID); err != nil {
+			return err
+		}
+		return tx.Create(&user).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3️⃣ Send verification email asynchronously via the email queue
+	frontendURL := os.Getenv("FRONTEND_BASE_URL")
+	signedToken := utils.SignActionToken(utils.LinkPurposeVerify, user.ID.String(), verifyToken, expiresAt)
+	verifyLink := fmt.Sprintf("%s/pl/verify-account?token=%s&account_id=%s", frontendURL, signedToken, org.AccountID)
+
+	emailSubject, emailBody, _ := utils.RenderEmail("verify", user.Locale, utils.EmailTemplateData{
+		Name:             user.Name,
+		OrganizationName: org.Name,
+		AccountID:        org.AccountID,
+		Link:             verifyLink,
+	})
+
+	if err := utils.QueueEmail(user.Email, emailSubject, emailBody); err != nil {
+		fmt.Printf("[WARN] Failed to queue verification email: %v\n", err)
+	}
+
+	return &models.JoinResponse{
+		UserID:         user.ID,
+		OrganizationID: user.OrganizationID,
+		Email:          user.Email,
+		Role:           user.Role,
+		Status:         user.Status,
 	}, nil
 }
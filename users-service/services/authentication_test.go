@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/constants"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+// End-to-end service tests for the account lifecycle: the flows run exactly
+// as in production — tokens travel only inside generated emails and are read
+// back out of them — with the memory provider standing in for SMTP and the
+// fake clock driving expiry.
+
+func TestSignUpVerifyLoginHappyPath(t *testing.T) {
+	h := newAuthHarness(t)
+
+	signup, err := h.auth.SignUp(context.Background(), &models.SignupRequest{
+		OrganizationName: "Acme",
+		OwnerName:        "Ada",
+		Email:            "ada@example.com",
+		Password:         "Str0ngPassword",
+	})
+	if err != nil {
+		t.Fatalf("SignUp failed: %v", err)
+	}
+	if signup.Status != "pending" || signup.Role != "owner" {
+		t.Fatalf("unexpected signup response: %+v", signup)
+	}
+
+	// The verification token only exists inside the delivered email
+	token := h.lastEmailToken(t, "token")
+	email, _ := h.emails.Last()
+	if email.To != "ada@example.com" {
+		t.Errorf("verification email went to %s", email.To)
+	}
+
+	verify, err := h.auth.VerifyAccount(context.Background(), token)
+	if err != nil {
+		t.Fatalf("VerifyAccount failed: %v", err)
+	}
+	if !verify.IsVerified || verify.Status != "active" {
+		t.Fatalf("unexpected verify response: %+v", verify)
+	}
+
+	// Verifying the owner activates the organization
+	var org models.Organization
+	if err := h.db.First(&org, "id = ?", signup.OrganizationID).Error; err != nil {
+		t.Fatalf("organization lookup failed: %v", err)
+	}
+	if org.Status != "active" {
+		t.Errorf("organization status = %s, want active", org.Status)
+	}
+
+	login, err := h.auth.Login(context.Background(), &models.LoginRequest{
+		AccountID: signup.AccountID,
+		Email:     "ada@example.com",
+		Password:  "Str0ngPassword",
+	})
+	if err != nil {
+		t.Fatalf("Login after verification failed: %v", err)
+	}
+	if login.AccessToken == "" {
+		t.Error("login returned an empty access token")
+	}
+}
+
+func TestVerifyAccountExpiredToken(t *testing.T) {
+	h := newAuthHarness(t)
+
+	signup, err := h.auth.SignUp(context.Background(), &models.SignupRequest{
+		OrganizationName: "Acme",
+		OwnerName:        "Ada",
+		Email:            "ada@example.com",
+		Password:         "Str0ngPassword",
+	})
+	if err != nil {
+		t.Fatalf("SignUp failed: %v", err)
+	}
+	token := h.lastEmailToken(t, "token")
+
+	h.clock.Advance(constants.VerifyTokenTTL + time.Minute)
+
+	_, err = h.auth.VerifyAccount(context.Background(), token)
+	if err == nil {
+		t.Fatal("VerifyAccount accepted an expired token")
+	}
+	if apperrors.Code(err) != "token_expired" {
+		t.Errorf("error code = %q, want token_expired", apperrors.Code(err))
+	}
+
+	// The account must stay unusable
+	if _, err := h.auth.Login(context.Background(), &models.LoginRequest{
+		AccountID: signup.AccountID,
+		Email:     "ada@example.com",
+		Password:  "Str0ngPassword",
+	}); err == nil {
+		t.Error("login succeeded for an unverified account")
+	}
+}
+
+func TestInviteAcceptHappyPath(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+
+	invite, err := h.users.InviteUser(owner.ID, "owner", org.ID, models.InviteUserRequest{
+		Email: "new.member@example.com",
+		Role:  "member",
+		Name:  "New Member",
+	})
+	if err != nil {
+		t.Fatalf("InviteUser failed: %v", err)
+	}
+	if invite.Status != "pending" {
+		t.Fatalf("invited user status = %s, want pending", invite.Status)
+	}
+
+	token := h.lastEmailToken(t, "token")
+
+	accept, err := h.auth.AcceptInvite(models.AcceptInviteRequest{
+		Name:      "New Member",
+		Email:     "new.member@example.com",
+		Password:  "Chosen1Password",
+		AccountID: org.AccountID,
+		Token:     token,
+	})
+	if err != nil {
+		t.Fatalf("AcceptInvite failed: %v", err)
+	}
+	if accept.Status != "active" || !accept.IsVerified {
+		t.Fatalf("unexpected accept response: %+v", accept)
+	}
+
+	// The chosen password works for a regular login
+	login, err := h.auth.Login(context.Background(), &models.LoginRequest{
+		AccountID: org.AccountID,
+		Email:     "new.member@example.com",
+		Password:  "Chosen1Password",
+	})
+	if err != nil {
+		t.Fatalf("login after accepting the invite failed: %v", err)
+	}
+	if login.Role != "member" {
+		t.Errorf("login role = %s, want member", login.Role)
+	}
+}
+
+func TestAcceptInviteExpiredToken(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+
+	if _, err := h.users.InviteUser(owner.ID, "owner", org.ID, models.InviteUserRequest{
+		Email: "late@example.com",
+		Role:  "member",
+		Name:  "Latecomer",
+	}); err != nil {
+		t.Fatalf("InviteUser failed: %v", err)
+	}
+	token := h.lastEmailToken(t, "token")
+
+	h.clock.Advance(constants.InviteTokenTTL + time.Minute)
+
+	_, err := h.auth.AcceptInvite(models.AcceptInviteRequest{
+		Name:      "Latecomer",
+		Email:     "late@example.com",
+		Password:  "Chosen1Password",
+		AccountID: org.AccountID,
+		Token:     token,
+	})
+	if err == nil {
+		t.Fatal("AcceptInvite accepted an expired token")
+	}
+	if apperrors.Code(err) != "token_expired" {
+		t.Errorf("error code = %q, want token_expired", apperrors.Code(err))
+	}
+
+	// The invite stays pending; nothing was activated
+	var user models.User
+	if err := h.db.First(&user, "LOWER(email) = ?", "late@example.com").Error; err != nil {
+		t.Fatalf("user lookup failed: %v", err)
+	}
+	if user.Status != "pending" {
+		t.Errorf("user status = %s, want pending", user.Status)
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/middleware"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+// acceptRequest invites email into org and returns a ready accept-invite
+// request carrying the emailed token.
+func acceptRequest(t *testing.T, h *authHarness, org models.Organization, owner models.User, email string) models.AcceptInviteRequest {
+	t.Helper()
+	if _, err := h.users.InviteUser(owner.ID, "owner", org.ID, models.InviteUserRequest{
+		Email: email,
+		Role:  "member",
+		Name:  "New Member",
+	}); err != nil {
+		t.Fatalf("InviteUser failed: %v", err)
+	}
+	return models.AcceptInviteRequest{
+		Name:      "New Member",
+		Email:     email,
+		Password:  "Chosen1Password",
+		AccountID: org.AccountID,
+		Token:     h.lastEmailToken(t, "token"),
+	}
+}
+
+func TestAcceptInviteAutoLoginIssuesSession(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+
+	req := acceptRequest(t, h, org, owner, "auto@example.com")
+	req.AutoLogin = true
+
+	accept, err := h.auth.AcceptInvite(req)
+	if err != nil {
+		t.Fatalf("AcceptInvite failed: %v", err)
+	}
+	if accept.TwoFactorRequired {
+		t.Fatal("2FA challenge raised for an org that doesn't enforce it")
+	}
+	if accept.AccessToken == "" {
+		t.Fatal("auto-login returned no access token")
+	}
+
+	// The issued session passes the same validation as a login token
+	claims, err := middleware.ValidateJWT(accept.AccessToken, h.db)
+	if err != nil {
+		t.Fatalf("auto-login token failed validation: %v", err)
+	}
+	if claims.UserID != accept.UserID.String() || claims.Role != "member" {
+		t.Errorf("claims = %+v, want the accepted member's session", claims)
+	}
+}
+
+func TestAcceptInviteWithoutAutoLoginIssuesNoToken(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+
+	accept, err := h.auth.AcceptInvite(acceptRequest(t, h, org, owner, "plain@example.com"))
+	if err != nil {
+		t.Fatalf("AcceptInvite failed: %v", err)
+	}
+	if accept.AccessToken != "" || accept.TwoFactorRequired {
+		t.Errorf("opt-out acceptance leaked session state: %+v", accept)
+	}
+}
+
+func TestAcceptInviteAutoLoginHonors2FAEnforcement(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+
+	// Enforce 2FA through the settings path owners actually use
+	enforce := true
+	if err := NewOrganizationService(h.db).UpdateSettings(org.ID.String(), models.UpdateOrgSettingsRequest{
+		Enforce2FA: &enforce,
+	}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+
+	req := acceptRequest(t, h, org, owner, "challenged@example.com")
+	req.AutoLogin = true
+
+	accept, err := h.auth.AcceptInvite(req)
+	if err != nil {
+		t.Fatalf("AcceptInvite failed: %v", err)
+	}
+	if accept.Status != "active" {
+		t.Errorf("acceptance status = %s; 2FA must gate the session, not the acceptance", accept.Status)
+	}
+	if !accept.TwoFactorRequired {
+		t.Error("expected the 2FA challenge marker")
+	}
+	if accept.AccessToken != "" {
+		t.Error("a session token was issued despite 2FA enforcement")
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/cache"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	utils "github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/validation"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxFooterTextLength caps the free-form footer so branding can't smuggle an
+// essay (or worse) into every email.
+const maxFooterTextLength = 500
+
+// orgBranding maps the org's stored branding columns onto the struct the
+// email renderer takes.
+func orgBranding(org models.Organization) utils.OrgBranding {
+	return utils.OrgBranding{
+		LogoURL:      org.BrandLogoURL,
+		PrimaryColor: org.BrandPrimaryColor,
+		FooterText:   org.EmailFooterText,
+	}
+}
+
+// UpdateBranding validates and stores email branding. Empty strings clear a
+// value back to the platform default; nil fields are left untouched.
+func (s *organizationService) UpdateBranding(orgID string, req models.UpdateBrandingRequest) (*models.BrandingResponse, error) {
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		return nil, errors.New("invalid organization ID")
+	}
+
+	// 1️⃣ Validate before touching anything
+	updates := map[string]interface{}{}
+	if req.BrandLogoURL != nil {
+		if *req.BrandLogoURL != "" && !utils.ValidLogoURL(*req.BrandLogoURL) {
+			return nil, errors.New("brand_logo_url must be an absolute https URL")
+		}
+		updates["brand_logo_url"] = *req.BrandLogoURL
+	}
+	if req.BrandPrimaryColor != nil {
+		if *req.BrandPrimaryColor != "" && validation.HexColor(*req.BrandPrimaryColor) != nil {
+			return nil, errors.New("brand_primary_color must be a hex color like #4F46E5")
+		}
+		updates["brand_primary_color"] = *req.BrandPrimaryColor
+	}
+	if req.EmailFooterText != nil {
+		if len(*req.EmailFooterText) > maxFooterTextLength {
+			return nil, fmt.Errorf("email_footer_text cannot exceed %d characters", maxFooterTextLength)
+		}
+		updates["email_footer_text"] = *req.EmailFooterText
+	}
+	if len(updates) == 0 {
+		return nil, errors.New("no branding fields provided")
+	}
+
+	// 2️⃣ Apply and reload
+	result := s.db.Model(&models.Organization{}).Where("id = ?", orgUUID).Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("organization not found")
+	}
+	cache.InvalidateOrganization(orgID)
+	MarkOnboardingStep(s.db, orgID, StepBrandingSet)
+
+	var org models.Organization
+	if err := s.db.First(&org, "id = ?", orgUUID).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.BrandingResponse{
+		BrandLogoURL:      org.BrandLogoURL,
+		BrandPrimaryColor: org.BrandPrimaryColor,
+		EmailFooterText:   org.EmailFooterText,
+	}, nil
+}
+
+// GetBranding returns the org's current email branding so the settings UI can
+// populate its form; empty fields mean platform defaults.
+func (s *organizationService) GetBranding(orgID string) (*models.BrandingResponse, error) {
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		return nil, errors.New("invalid organization ID")
+	}
+
+	var org models.Organization
+	if err := s.db.Select("brand_logo_url, brand_primary_color, email_footer_text").
+		Where("id = ?", orgUUID).First(&org).Error; err != nil {
+		return nil, errors.New("organization not found")
+	}
+
+	return &models.BrandingResponse{
+		BrandLogoURL:      org.BrandLogoURL,
+		BrandPrimaryColor: org.BrandPrimaryColor,
+		EmailFooterText:   org.EmailFooterText,
+	}, nil
+}
+
+// PreviewBrandedInvite renders a sample invite email with the org's current
+// branding so the settings UI can show what invitees will actually receive.
+func (s *organizationService) PreviewBrandedInvite(orgID string) (string, error) {
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		return "", errors.New("invalid organization ID")
+	}
+
+	var org models.Organization
+	if err := s.db.First(&org, "id = ?", orgUUID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errors.New("organization not found")
+		}
+		return "", err
+	}
+
+	frontendURL := os.Getenv("FRONTEND_BASE_URL")
+	_, body, err := utils.RenderEmail("invite", utils.DefaultLocale, utils.EmailTemplateData{
+		Name:             "Alex Example",
+		OrganizationName: org.Name,
+		AccountID:        org.AccountID,
+		Link:             frontendURL + "/pl/accept-invite?token=preview",
+		InviterName:      "A teammate",
+		Branding:         orgBranding(org),
+	})
+	return body, err
+}
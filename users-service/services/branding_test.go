@@ -0,0 +1,91 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+func TestUpdateBrandingValidation(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	orgs := NewOrganizationService(h.db)
+
+	longFooter := strings.Repeat("x", maxFooterTextLength+1)
+	cases := []struct {
+		name string
+		req  models.UpdateBrandingRequest
+	}{
+		{"http logo url", models.UpdateBrandingRequest{BrandLogoURL: strptr("http://insecure.example.com/logo.png")}},
+		{"javascript logo url", models.UpdateBrandingRequest{BrandLogoURL: strptr("javascript:alert(1)")}},
+		{"shorthand hex color", models.UpdateBrandingRequest{BrandPrimaryColor: strptr("#F00")}},
+		{"named color", models.UpdateBrandingRequest{BrandPrimaryColor: strptr("rebeccapurple")}},
+		{"oversized footer", models.UpdateBrandingRequest{EmailFooterText: &longFooter}},
+		{"empty request", models.UpdateBrandingRequest{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := orgs.UpdateBranding(org.ID.String(), tc.req); err == nil {
+				t.Error("invalid branding was accepted")
+			}
+		})
+	}
+}
+
+func TestUpdateBrandingStoresAndPreviewReflectsIt(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	orgs := NewOrganizationService(h.db)
+
+	resp, err := orgs.UpdateBranding(org.ID.String(), models.UpdateBrandingRequest{
+		BrandLogoURL:      strptr("https://cdn.example.com/logo.png"),
+		BrandPrimaryColor: strptr("#1A2B3C"),
+		EmailFooterText:   strptr("Acme Corp <legal@acme.example>"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateBranding failed: %v", err)
+	}
+	if resp.BrandPrimaryColor != "#1A2B3C" {
+		t.Errorf("stored color = %q, want #1A2B3C", resp.BrandPrimaryColor)
+	}
+
+	body, err := orgs.PreviewBrandedInvite(org.ID.String())
+	if err != nil {
+		t.Fatalf("PreviewBrandedInvite failed: %v", err)
+	}
+
+	if !strings.Contains(body, "https://cdn.example.com/logo.png") {
+		t.Error("preview does not include the branded logo")
+	}
+	if !strings.Contains(body, "#1A2B3C") {
+		t.Error("preview does not use the branded accent color")
+	}
+	// Free-form footer text is escaped, never raw HTML
+	if strings.Contains(body, "<legal@acme.example>") {
+		t.Error("footer text was injected unescaped")
+	}
+	if !strings.Contains(body, "&lt;legal@acme.example&gt;") {
+		t.Errorf("escaped footer text missing from preview")
+	}
+	if !strings.Contains(body, org.Name) {
+		t.Error("preview does not mention the organization")
+	}
+}
+
+func TestPreviewWithoutBrandingUsesDefaults(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+
+	body, err := NewOrganizationService(h.db).PreviewBrandedInvite(org.ID.String())
+	if err != nil {
+		t.Fatalf("PreviewBrandedInvite failed: %v", err)
+	}
+	if !strings.Contains(body, "#4F46E5") {
+		t.Error("unbranded preview does not use the default accent color")
+	}
+	if strings.Contains(body, "<img") {
+		t.Error("unbranded preview contains a logo")
+	}
+}
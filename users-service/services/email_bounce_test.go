@@ -0,0 +1,44 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+func TestMarkEmailsBouncedFlagsMatchingUsers(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	bounced := testutil.NewUser(h.db, org).WithEmail("bounced@example.com").Active().Build()
+	healthy := testutil.NewUser(h.db, org).WithEmail("healthy@example.com").Active().Build()
+
+	// Providers echo the address as typed; matching is case-insensitive
+	flagged, err := h.users.MarkEmailsBounced([]string{"Bounced@Example.COM", "unknown@example.com"})
+	if err != nil {
+		t.Fatalf("MarkEmailsBounced failed: %v", err)
+	}
+	if flagged != 1 {
+		t.Errorf("flagged %d users, want 1", flagged)
+	}
+
+	var flaggedUser, untouched models.User
+	if err := h.db.First(&flaggedUser, "id = ?", bounced.ID).Error; err != nil {
+		t.Fatalf("user lookup failed: %v", err)
+	}
+	if flaggedUser.EmailStatus != "bounced" {
+		t.Errorf("email status = %q, want bounced", flaggedUser.EmailStatus)
+	}
+
+	if err := h.db.First(&untouched, "id = ?", healthy.ID).Error; err != nil {
+		t.Fatalf("user lookup failed: %v", err)
+	}
+	if untouched.EmailStatus == "bounced" {
+		t.Error("an unaffected user was flagged")
+	}
+
+	// An empty batch is a no-op, not an error
+	if flagged, err := h.users.MarkEmailsBounced(nil); err != nil || flagged != 0 {
+		t.Errorf("empty batch: flagged=%d err=%v", flagged, err)
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+func TestEmailsAreCaseInsensitive(t *testing.T) {
+	h := newAuthHarness(t)
+
+	// Mixed-case signup stores the normalized address
+	signup, err := h.auth.SignUp(context.Background(), &models.SignupRequest{
+		OrganizationName: "Acme",
+		OwnerName:        "Ada",
+		Email:            "Ada.Lovelace@Example.COM",
+		Password:         "Str0ngPassword",
+	})
+	if err != nil {
+		t.Fatalf("SignUp failed: %v", err)
+	}
+
+	var owner models.User
+	if err := h.db.First(&owner, "id = ?", signup.UserID).Error; err != nil {
+		t.Fatalf("owner lookup failed: %v", err)
+	}
+	if owner.Email != "ada.lovelace@example.com" {
+		t.Errorf("stored email = %q, want it lowercased", owner.Email)
+	}
+
+	token := h.lastEmailToken(t, "token")
+	if _, err := h.auth.VerifyAccount(context.Background(), token); err != nil {
+		t.Fatalf("VerifyAccount failed: %v", err)
+	}
+
+	// Logging in with yet another casing reaches the same account
+	login, err := h.auth.Login(context.Background(), &models.LoginRequest{
+		AccountID: signup.AccountID,
+		Email:     "ADA.LOVELACE@example.com",
+		Password:  "Str0ngPassword",
+	})
+	if err != nil {
+		t.Fatalf("login with a differently cased email failed: %v", err)
+	}
+	if login.UserID != owner.ID {
+		t.Errorf("login resolved user %s, want %s", login.UserID, owner.ID)
+	}
+}
+
+func TestInviteDedupIgnoresCase(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+
+	if _, err := h.users.InviteUser(owner.ID, "owner", org.ID, models.InviteUserRequest{
+		Email: "new.member@example.com",
+		Role:  "member",
+		Name:  "New Member",
+	}); err != nil {
+		t.Fatalf("InviteUser failed: %v", err)
+	}
+
+	// The same address with different casing is the same person
+	if _, err := h.users.InviteUser(owner.ID, "owner", org.ID, models.InviteUserRequest{
+		Email: "New.Member@Example.com",
+		Role:  "member",
+		Name:  "New Member Again",
+	}); err == nil {
+		t.Fatal("a differently cased duplicate invite was accepted")
+	}
+
+	var count int64
+	if err := h.db.Model(&models.User{}).Where("organization_id = ?", org.ID).Count(&count).Error; err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if count != 2 { // owner + the single invite
+		t.Errorf("org has %d users, want 2", count)
+	}
+}
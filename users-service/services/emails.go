@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"gorm.io/gorm"
+)
+
+// Owner-facing view over the email outbox: list delivery state for the org's
+// messages and re-queue ones that exhausted their attempts. Delivery itself
+// stays in shared/outbox; this only reads and flips rows.
+
+type EmailService interface {
+	ListEmails(orgID, status string, limit int) ([]models.OutboxEmailSummary, error)
+	RetryEmail(orgID, emailID string) error
+}
+
+type emailService struct {
+	db *gorm.DB
+}
+
+func NewEmailService(db *gorm.DB) EmailService {
+	return &emailService{db: db}
+}
+
+// ListEmails returns recent outbox rows for the org, optionally filtered by
+// status (pending / sent / failed). Bodies are never returned — they can
+// contain signed action links.
+func (s *emailService) ListEmails(orgID, status string, limit int) ([]models.OutboxEmailSummary, error) {
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	query := s.db.Model(&models.OutboxEmail{}).
+		Select("id, recipient, template, status, attempts, last_error, sent_at, created_at").
+		Where("organization_id = ?", orgID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var emails []models.OutboxEmailSummary
+	if err := query.Order("created_at DESC").Limit(limit).Find(&emails).Error; err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
+// RetryEmail resets a failed message back to pending with a fresh attempt
+// budget; the dispatcher picks it up on its next poll. The guarded UPDATE is
+// org-scoped and only touches failed rows, so a retry can't re-send something
+// already delivered.
+func (s *emailService) RetryEmail(orgID, emailID string) error {
+	result := s.db.Model(&models.OutboxEmail{}).
+		Where("id = ? AND organization_id = ? AND status = ?", emailID, orgID, "failed").
+		Updates(map[string]interface{}{
+			"status":     "pending",
+			"attempts":   0,
+			"last_error": "",
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		// Distinguish "not yours / doesn't exist" from "exists but not failed"
+		var count int64
+		s.db.Model(&models.OutboxEmail{}).
+			Where("id = ? AND organization_id = ?", emailID, orgID).
+			Count(&count)
+		if count == 0 {
+			return apperrors.NotFound("email not found")
+		}
+		return apperrors.Conflict("only failed emails can be retried").WithCode("email_not_failed")
+	}
+	return nil
+}
+
+// emailDeliveryWarning checks whether the org's most recent terminal emails
+// (sent or failed) were all failures, and if so returns a warning the invite
+// endpoints embed in their responses. Best-effort: a query error just means
+// no warning.
+func emailDeliveryWarning(db *gorm.DB, orgID string) *models.EmailHealthWarning {
+	threshold := 3
+	if v := os.Getenv("EMAIL_FAILURE_WARN_THRESHOLD"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			threshold = parsed
+		}
+	}
+
+	var statuses []string
+	if err := db.Model(&models.OutboxEmail{}).
+		Where("organization_id = ? AND status IN ?", orgID, []string{"sent", "failed"}).
+		Order("created_at DESC").
+		Limit(threshold).
+		Pluck("status", &statuses).Error; err != nil {
+		return nil
+	}
+	if len(statuses) < threshold {
+		return nil
+	}
+	for _, st := range statuses {
+		if st != "failed" {
+			return nil
+		}
+	}
+	return &models.EmailHealthWarning{
+		ConsecutiveFailures: threshold,
+		Message:             fmt.Sprintf("the last %d emails for this organization failed to deliver; check your recipient addresses or contact support", threshold),
+	}
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	"github.com/google/uuid"
+)
+
+// Token expiry is driven entirely by the injected clock, so the boundary can
+// be tested without sleeping or back-dating rows. The cutoffs are exclusive:
+// a token is still good at the exact expiry instant and dead one tick later.
+var expiryCases = []struct {
+	name        string
+	offset      time.Duration // clock position relative to the token's expiry
+	wantExpired bool
+}{
+	{"just before expiry", -time.Second, false},
+	{"exactly at expiry", 0, false},
+	{"just after expiry", time.Second, true},
+}
+
+// setTokenExpiry pins a user's single-use token and expiry to exact values.
+func setTokenExpiry(t *testing.T, h *authHarness, userID uuid.UUID, token string, expiresAt time.Time) {
+	t.Helper()
+	if err := h.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"invite_token": token,
+		"expires_at":   expiresAt,
+	}).Error; err != nil {
+		t.Fatalf("failed to set token expiry: %v", err)
+	}
+}
+
+func TestVerifyAccountExpiryBoundary(t *testing.T) {
+	for _, tc := range expiryCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newAuthHarness(t)
+			org := testutil.NewOrganization(h.db).Build()
+			user := testutil.NewUser(h.db, org).Owner().Pending(time.Hour).Build()
+
+			expiresAt := h.clock.Now().Truncate(time.Second).Add(time.Hour)
+			setTokenExpiry(t, h, user.ID, "verify-token", expiresAt)
+			h.clock.Set(expiresAt.Add(tc.offset))
+
+			_, err := h.auth.VerifyAccount(context.Background(), "verify-token")
+			if tc.wantExpired && err == nil {
+				t.Fatal("expected an expired-token error")
+			}
+			if !tc.wantExpired && err != nil {
+				t.Fatalf("VerifyAccount failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestAcceptInviteExpiryBoundary(t *testing.T) {
+	for _, tc := range expiryCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newAuthHarness(t)
+			org := testutil.NewOrganization(h.db).Active().Build()
+			user := testutil.NewUser(h.db, org).
+				WithEmail("invitee@example.com").
+				Pending(time.Hour).
+				Build()
+
+			expiresAt := h.clock.Now().Truncate(time.Second).Add(time.Hour)
+			setTokenExpiry(t, h, user.ID, "invite-token", expiresAt)
+			h.clock.Set(expiresAt.Add(tc.offset))
+
+			_, err := h.auth.AcceptInvite(models.AcceptInviteRequest{
+				Name:      "Invitee",
+				Email:     "invitee@example.com",
+				Password:  "Chosen1Password",
+				AccountID: org.AccountID,
+				Token:     "invite-token",
+			})
+			if tc.wantExpired && err == nil {
+				t.Fatal("expected an expired-token error")
+			}
+			if !tc.wantExpired && err != nil {
+				t.Fatalf("AcceptInvite failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestResetPasswordByEmailExpiryBoundary(t *testing.T) {
+	for _, tc := range expiryCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newAuthHarness(t)
+			org := testutil.NewOrganization(h.db).Active().Build()
+			user := testutil.NewUser(h.db, org).Active().Build()
+
+			expiresAt := h.clock.Now().Truncate(time.Second).Add(time.Hour)
+			setTokenExpiry(t, h, user.ID, "reset-token", expiresAt)
+			h.clock.Set(expiresAt.Add(tc.offset))
+
+			_, err := h.auth.ResetPasswordByEmail("reset-token", "NewPassw0rd1")
+			if tc.wantExpired && err == nil {
+				t.Fatal("expected an expired-token error")
+			}
+			if !tc.wantExpired && err != nil {
+				t.Fatalf("ResetPasswordByEmail failed: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	utils "github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GDPR data portability (the counterpart to AnonymizeUser). Every query is
+// keyed on the caller's own user id, so the bundle can never contain
+// another user's rows no matter what the client sends.
+
+// ExportPersonalData assembles the caller's profile, org membership,
+// conversations with messages, and audit trail into one bundle.
+func (s *userService) ExportPersonalData(claims *utils.JWTClaims) (*models.PersonalDataExport, error) {
+	userUUID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	orgUUID, err := uuid.Parse(claims.OrganizationID)
+	if err != nil {
+		return nil, errors.New("invalid organization ID")
+	}
+
+	// 1️⃣ Profile (sensitive fields already stripped by ToUserResponse)
+	var user models.User
+	if err := s.db.Where("id = ? AND organization_id = ?", userUUID, orgUUID).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	var org models.Organization
+	if err := s.db.First(&org, "id = ?", orgUUID).Error; err != nil {
+		return nil, err
+	}
+
+	export := &models.PersonalDataExport{
+		GeneratedAt: time.Now().UTC(),
+		Profile:     models.ToUserResponse(user),
+		Membership: models.ExportMembership{
+			OrganizationID:   org.ID,
+			OrganizationName: org.Name,
+			AccountID:        org.AccountID,
+			Role:             user.Role,
+			JoinedAt:         user.AcceptedAt,
+		},
+		Chats:      []models.ExportChat{},
+		AuditTrail: []models.ExportAuditEntry{},
+	}
+
+	// 2️⃣ Conversations + messages (chats-service tables in the shared DB;
+	// a missing table in a partial deployment yields an empty section
+	// rather than a failed export)
+	var chats []models.ExportChat
+	err = s.db.Table("chats").
+		Select("id, title, created_at").
+		Where("organization_id = ? AND user_id = ? AND deleted_at IS NULL", orgUUID, userUUID).
+		Order("created_at ASC").
+		Find(&chats).Error
+	if err == nil {
+		for i := range chats {
+			var messages []models.ExportMessage
+			if err := s.db.Table("messages").
+				Select("role, content, created_at").
+				Where("chat_id = ? AND organization_id = ?", chats[i].ID, orgUUID).
+				Order("created_at ASC").
+				Find(&messages).Error; err == nil {
+				chats[i].Messages = messages
+			} else {
+				chats[i].Messages = []models.ExportMessage{}
+			}
+		}
+		export.Chats = chats
+	}
+
+	// 3️⃣ Audit entries where the caller acted
+	var trail []models.ExportAuditEntry
+	if err := s.db.Model(&models.AuditLog{}).
+		Select("action, details, created_at").
+		Where("organization_id = ? AND actor_id = ?", orgUUID, userUUID).
+		Order("created_at ASC").
+		Find(&trail).Error; err == nil {
+		export.AuditTrail = trail
+	}
+
+	recordAudit(s.db, claims, "user.export", "personal data export downloaded")
+
+	return export, nil
+}
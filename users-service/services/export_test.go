@@ -0,0 +1,98 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	utils "github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/google/uuid"
+)
+
+// The chats tables belong to chats-service; the export reads them from the
+// shared DB, so the tests create a minimal mirror of them.
+func createChatTables(t *testing.T, h *authHarness) {
+	t.Helper()
+	for _, ddl := range []string{
+		`CREATE TABLE chats (id TEXT PRIMARY KEY, organization_id TEXT, user_id TEXT,
+			title TEXT, created_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE messages (id TEXT PRIMARY KEY, chat_id TEXT, organization_id TEXT,
+			role TEXT, content TEXT, created_at DATETIME)`,
+	} {
+		if err := h.db.Exec(ddl).Error; err != nil {
+			t.Fatalf("failed to create chat tables: %v", err)
+		}
+	}
+}
+
+func addChat(t *testing.T, h *authHarness, orgID, userID uuid.UUID, title, message string) {
+	t.Helper()
+	chatID := uuid.New()
+	if err := h.db.Exec(
+		"INSERT INTO chats (id, organization_id, user_id, title, created_at) VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)",
+		chatID, orgID, userID, title).Error; err != nil {
+		t.Fatalf("failed to insert chat: %v", err)
+	}
+	if err := h.db.Exec(
+		"INSERT INTO messages (id, chat_id, organization_id, role, content, created_at) VALUES (?, ?, ?, 'user', ?, CURRENT_TIMESTAMP)",
+		uuid.New(), chatID, orgID, message).Error; err != nil {
+		t.Fatalf("failed to insert message: %v", err)
+	}
+}
+
+func exportClaims(user models.User) *utils.JWTClaims {
+	return &utils.JWTClaims{
+		UserID:         user.ID.String(),
+		OrganizationID: user.OrganizationID.String(),
+		Role:           user.Role,
+	}
+}
+
+func TestExportContainsOnlyTheCallersRecords(t *testing.T) {
+	h := newAuthHarness(t)
+	createChatTables(t, h)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	caller := testutil.NewUser(h.db, org).Active().Named("Caller").WithEmail("caller@example.com").Build()
+	colleague := testutil.NewUser(h.db, org).Active().Named("Colleague").WithEmail("colleague@example.com").Build()
+
+	addChat(t, h, org.ID, caller.ID, "Caller thread", "caller's question")
+	addChat(t, h, org.ID, colleague.ID, "Colleague thread", "colleague's question")
+	recordAudit(h.db, exportClaims(caller), "user.login", "caller signed in")
+	recordAudit(h.db, exportClaims(colleague), "user.login", "colleague signed in")
+
+	export, err := h.users.ExportPersonalData(exportClaims(caller))
+	if err != nil {
+		t.Fatalf("ExportPersonalData failed: %v", err)
+	}
+
+	if export.Profile.Email != "caller@example.com" || export.Membership.OrganizationID != org.ID {
+		t.Errorf("bundle profile/membership = %+v / %+v, want the caller's", export.Profile, export.Membership)
+	}
+	if len(export.Chats) != 1 || export.Chats[0].Title != "Caller thread" {
+		t.Fatalf("bundle chats = %+v, want only the caller's thread", export.Chats)
+	}
+	if len(export.Chats[0].Messages) != 1 || export.Chats[0].Messages[0].Content != "caller's question" {
+		t.Errorf("bundle messages = %+v, want only the caller's message", export.Chats[0].Messages)
+	}
+	for _, entry := range export.AuditTrail {
+		if entry.Details == "colleague signed in" {
+			t.Error("another user's audit entry leaked into the bundle")
+		}
+	}
+}
+
+func TestExportSurvivesMissingChatTables(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	caller := testutil.NewUser(h.db, org).Active().Build()
+
+	// A users-service-only deployment has no chats tables; the export still
+	// serves the sections it owns
+	export, err := h.users.ExportPersonalData(exportClaims(caller))
+	if err != nil {
+		t.Fatalf("ExportPersonalData failed without chat tables: %v", err)
+	}
+	if export.Chats == nil || len(export.Chats) != 0 {
+		t.Errorf("bundle chats = %+v, want an empty section", export.Chats)
+	}
+}
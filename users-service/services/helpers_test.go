@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/outbox"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	utils "github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"gorm.io/gorm"
+)
+
+// authHarness is the test bootstrap for the service layer: an ephemeral
+// database with the memory email provider and a fake clock injected, wired
+// the same way main.go wires the real dependencies.
+type authHarness struct {
+	db     *gorm.DB
+	emails *utils.MemoryEmailProvider
+	clock  *utils.FakeClock
+	auth   AuthenticationService
+	users  UserService
+}
+
+func newAuthHarness(t *testing.T, hooks ...AcceptInviteHook) *authHarness {
+	t.Helper()
+	db := testutil.NewTestDB(t)
+	emails := utils.NewMemoryEmailProvider()
+	clock := utils.NewFakeClock(time.Now().UTC())
+	return &authHarness{
+		db:     db,
+		emails: emails,
+		clock:  clock,
+		auth:   NewAuthenticationService(db, emails, clock, hooks...),
+		users:  NewUserService(db),
+	}
+}
+
+// deliverOutbox drains pending outbox rows into the memory provider, so tests
+// pull tokens out of the generated emails the way a real recipient would.
+func (h *authHarness) deliverOutbox(t *testing.T) {
+	t.Helper()
+	if err := outbox.NewDispatcher(h.db, h.emails).DispatchOnce(); err != nil {
+		t.Fatalf("outbox dispatch failed: %v", err)
+	}
+}
+
+// lastEmailToken delivers the outbox and extracts the named query parameter
+// from the most recent email's action link.
+func (h *authHarness) lastEmailToken(t *testing.T, param string) string {
+	t.Helper()
+	h.deliverOutbox(t)
+	email, ok := h.emails.Last()
+	if !ok {
+		t.Fatal("no email was delivered")
+	}
+	token := email.Token(param)
+	if token == "" {
+		t.Fatalf("email carries no %q parameter: %s", param, email.Body)
+	}
+	return token
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/middleware"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	utils "github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+)
+
+// impersonationFixture seeds an active org with an owner and a member and
+// returns parsed claims for the owner's own session.
+func impersonationFixture(t *testing.T, h *authHarness) (models.Organization, models.User, models.User, *utils.JWTClaims) {
+	t.Helper()
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+	member := testutil.NewUser(h.db, org).Active().Build()
+
+	claims, err := middleware.ValidateJWT(testutil.MintJWT(owner, org), h.db)
+	if err != nil {
+		t.Fatalf("owner token failed validation: %v", err)
+	}
+	return org, owner, member, claims
+}
+
+func TestImpersonateIssuesScopedToken(t *testing.T) {
+	h := newAuthHarness(t)
+	_, owner, member, ownerClaims := impersonationFixture(t, h)
+
+	login, err := h.auth.Impersonate(ownerClaims, member.ID.String())
+	if err != nil {
+		t.Fatalf("Impersonate failed: %v", err)
+	}
+	if login.UserID != member.ID || login.Role != "member" {
+		t.Fatalf("unexpected impersonation response: %+v", login)
+	}
+
+	// The token acts as the member but names the real admin
+	claims, err := middleware.ValidateJWT(login.AccessToken, h.db)
+	if err != nil {
+		t.Fatalf("impersonation token failed validation: %v", err)
+	}
+	if claims.UserID != member.ID.String() {
+		t.Errorf("claims user = %s, want %s", claims.UserID, member.ID)
+	}
+	if claims.Role != "member" {
+		t.Errorf("claims role = %s, want member", claims.Role)
+	}
+	if claims.ImpersonatorID != owner.ID.String() {
+		t.Errorf("claims impersonator = %q, want %s", claims.ImpersonatorID, owner.ID)
+	}
+
+	// Starting the session left an audit trail naming the admin as actor
+	var entry models.AuditLog
+	if err := h.db.First(&entry, "action = ?", "impersonation.start").Error; err != nil {
+		t.Fatalf("no impersonation.start audit entry: %v", err)
+	}
+	if entry.ActorID != owner.ID {
+		t.Errorf("audit actor = %s, want %s", entry.ActorID, owner.ID)
+	}
+}
+
+func TestImpersonateRejectsBadTargets(t *testing.T) {
+	h := newAuthHarness(t)
+	org, owner, _, ownerClaims := impersonationFixture(t, h)
+	suspended := testutil.NewUser(h.db, org).Suspended().Build()
+
+	otherOrg := testutil.NewOrganization(h.db).Active().Build()
+	outsider := testutil.NewUser(h.db, otherOrg).Active().Build()
+
+	for name, target := range map[string]string{
+		"self":              owner.ID.String(),
+		"suspended user":    suspended.ID.String(),
+		"other org's user":  outsider.ID.String(),
+		"malformed user id": "not-a-uuid",
+	} {
+		if _, err := h.auth.Impersonate(ownerClaims, target); err == nil {
+			t.Errorf("Impersonate allowed %s", name)
+		}
+	}
+}
+
+func TestActionsUnderImpersonationAuditTheRealAdmin(t *testing.T) {
+	h := newAuthHarness(t)
+	_, owner, member, ownerClaims := impersonationFixture(t, h)
+
+	login, err := h.auth.Impersonate(ownerClaims, member.ID.String())
+	if err != nil {
+		t.Fatalf("Impersonate failed: %v", err)
+	}
+	actingClaims, err := middleware.ValidateJWT(login.AccessToken, h.db)
+	if err != nil {
+		t.Fatalf("impersonation token failed validation: %v", err)
+	}
+
+	recordAudit(h.db, actingClaims, "test.action", "performed while impersonating")
+
+	var entry models.AuditLog
+	if err := h.db.First(&entry, "action = ?", "test.action").Error; err != nil {
+		t.Fatalf("no audit entry for the impersonated action: %v", err)
+	}
+	if entry.ActorID != member.ID {
+		t.Errorf("audit actor = %s, want the impersonated user %s", entry.ActorID, member.ID)
+	}
+	if entry.ImpersonatorID == nil || *entry.ImpersonatorID != owner.ID {
+		t.Errorf("audit impersonator = %v, want the real admin %s", entry.ImpersonatorID, owner.ID)
+	}
+}
+
+func TestStopImpersonationRestoresTheAdmin(t *testing.T) {
+	h := newAuthHarness(t)
+	_, owner, member, ownerClaims := impersonationFixture(t, h)
+
+	// A regular session has nothing to stop
+	if _, err := h.auth.StopImpersonation(ownerClaims); err == nil {
+		t.Error("StopImpersonation succeeded on a non-impersonation session")
+	}
+
+	login, err := h.auth.Impersonate(ownerClaims, member.ID.String())
+	if err != nil {
+		t.Fatalf("Impersonate failed: %v", err)
+	}
+	actingClaims, err := middleware.ValidateJWT(login.AccessToken, h.db)
+	if err != nil {
+		t.Fatalf("impersonation token failed validation: %v", err)
+	}
+
+	restored, err := h.auth.StopImpersonation(actingClaims)
+	if err != nil {
+		t.Fatalf("StopImpersonation failed: %v", err)
+	}
+	if restored.UserID != owner.ID || restored.Role != "owner" {
+		t.Fatalf("unexpected restore response: %+v", restored)
+	}
+
+	claims, err := middleware.ValidateJWT(restored.AccessToken, h.db)
+	if err != nil {
+		t.Fatalf("restored token failed validation: %v", err)
+	}
+	if claims.UserID != owner.ID.String() || claims.ImpersonatorID != "" {
+		t.Errorf("restored claims = %+v, want a plain owner session", claims)
+	}
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+// Copy-pasted credentials arrive padded and mixed-case; the service layer
+// normalizes before lookup, so these must all resolve to the same account.
+
+func TestLoginNormalizesPaddedAndMixedCaseInput(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	testutil.NewUser(h.db, org).Owner().Active().WithEmail("owner@example.com").Build()
+
+	if err := tryLogin(h, "  Owner@Example.COM  ", testutil.FixturePassword, "  "+org.AccountID+"  "); err != nil {
+		t.Errorf("padded mixed-case login rejected: %v", err)
+	}
+}
+
+func TestForgotPasswordNormalizesPaddedAccountID(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	testutil.NewUser(h.db, org).Owner().Active().WithEmail("owner@example.com").Build()
+
+	if _, err := h.auth.ForgotPassword("OWNER@example.com", " "+org.AccountID+" "); err != nil {
+		t.Errorf("padded account id rejected on password reset: %v", err)
+	}
+	// The reset email actually went out for the normalized identity
+	if token := h.lastEmailToken(t, "token"); token == "" {
+		t.Error("no reset token was issued")
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/cache"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+func TestIntrospectTokenActive(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	user := testutil.NewUser(h.db, org).Maintainer().Active().Build()
+
+	res := h.auth.IntrospectToken(testutil.MintJWT(user, org))
+
+	if !res.Active {
+		t.Fatal("expected a freshly minted token to introspect as active")
+	}
+	if res.UserID != user.ID.String() {
+		t.Errorf("user id = %q, want %q", res.UserID, user.ID.String())
+	}
+	if res.OrganizationID != org.ID.String() {
+		t.Errorf("organization id = %q, want %q", res.OrganizationID, org.ID.String())
+	}
+	if res.Role != "maintainer" {
+		t.Errorf("role = %q, want maintainer", res.Role)
+	}
+	if res.Exp <= time.Now().Unix() {
+		t.Errorf("exp %d is not in the future", res.Exp)
+	}
+}
+
+func TestIntrospectTokenExpired(t *testing.T) {
+	t.Setenv("TOKEN_TTL_MEMBER", "1ns")
+
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	user := testutil.NewUser(h.db, org).Active().Build()
+
+	token := testutil.MintJWT(user, org)
+	time.Sleep(10 * time.Millisecond)
+
+	res := h.auth.IntrospectToken(token)
+	if res.Active {
+		t.Fatal("expected an expired token to introspect as inactive")
+	}
+	if res.UserID != "" || res.Role != "" {
+		t.Errorf("inactive response must carry no claims, got %+v", res)
+	}
+}
+
+func TestIntrospectTokenSuspendedUser(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	user := testutil.NewUser(h.db, org).Active().Build()
+	token := testutil.MintJWT(user, org)
+
+	if err := h.db.Model(&user).Update("status", "suspended").Error; err != nil {
+		t.Fatalf("failed to suspend user: %v", err)
+	}
+	cache.InvalidateUser(user.ID.String())
+
+	if res := h.auth.IntrospectToken(token); res.Active {
+		t.Fatal("expected a suspended user's token to introspect as inactive")
+	}
+}
+
+func TestIntrospectTokenStaleVersion(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	user := testutil.NewUser(h.db, org).Active().Build()
+	token := testutil.MintJWT(user, org)
+
+	// A password reset bumps token_version; tokens minted before it die
+	if err := h.db.Model(&user).Update("token_version", user.TokenVersion+1).Error; err != nil {
+		t.Fatalf("failed to bump token version: %v", err)
+	}
+	cache.InvalidateUser(user.ID.String())
+
+	if res := h.auth.IntrospectToken(token); res.Active {
+		t.Fatal("expected a pre-reset token to introspect as inactive")
+	}
+}
+
+func TestIntrospectTokenGarbage(t *testing.T) {
+	h := newAuthHarness(t)
+
+	if res := h.auth.IntrospectToken("not-a-jwt"); res.Active {
+		t.Fatal("expected garbage input to introspect as inactive")
+	}
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/constants"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	utils "github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InviteLinkService interface {
+	CreateInviteLink(creatorID uuid.UUID, creatorRole string, orgID uuid.UUID, req models.CreateInviteLinkRequest) (*models.InviteLinkResponse, error)
+	GetInviteLinks(orgID string) ([]models.InviteLinkResponse, error)
+	RevokeInviteLink(orgID, linkID string) error
+	JoinByLink(req models.JoinRequest) (*models.JoinResponse, error)
+}
+
+type inviteLinkService struct {
+	db *gorm.DB
+}
+
+func NewInviteLinkService(db *gorm.DB) InviteLinkService {
+	return &inviteLinkService{db: db}
+}
+
+func (s *inviteLinkService) CreateInviteLink(creatorID uuid.UUID, creatorRole string, orgID uuid.UUID, req models.CreateInviteLinkRequest) (*models.InviteLinkResponse, error) {
+	// No explicit role: fall back to the org's configured default join role
+	if req.Role == "" {
+		var org models.Organization
+		if err := s.db.Select("default_join_role").First(&org, "id = ?", orgID).Error; err != nil {
+			return nil, errors.New("organization not found")
+		}
+		req.Role = org.DefaultJoinRole
+		if req.Role == "" {
+			req.Role = string(constants.RoleMember)
+		}
+	}
+
+	// 1️⃣ Role-based rules (same as email invites)
+	switch creatorRole {
+	case "owner":
+		if req.Role != "maintainer" && req.Role != "member" {
+			return nil, errors.New("owner can create links for maintainer or member only")
+		}
+	case "maintainer":
+		if req.Role != "member" {
+			return nil, errors.New("maintainer can create links for member only")
+		}
+	default:
+		return nil, errors.New("members cannot create invite links")
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, errors.New("failed to generate link token")
+	}
+
+	link := models.InviteLink{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		Token:          token,
+		Role:           req.Role,
+		CreatedBy:      creatorID,
+		MaxUses:        req.MaxUses,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if req.ExpiresInHours > 0 {
+		expiresAt := time.Now().UTC().Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		link.ExpiresAt = &expiresAt
+	}
+
+	if err := s.db.Create(&link).Error; err != nil {
+		return nil, err
+	}
+
+	resp := toInviteLinkResponse(link)
+	return &resp, nil
+}
+
+func (s *inviteLinkService) GetInviteLinks(orgID string) ([]models.InviteLinkResponse, error) {
+	var links []models.InviteLink
+	if err := s.db.
+		Where("organization_id = ?", orgID).
+		Order("created_at DESC").
+		Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.InviteLinkResponse, 0, len(links))
+	for _, link := range links {
+		responses = append(responses, toInviteLinkResponse(link))
+	}
+	return responses, nil
+}
+
+func (s *inviteLinkService) RevokeInviteLink(orgID, linkID string) error {
+	result := s.db.Model(&models.InviteLink{}).
+		Where("id = ? AND organization_id = ? AND revoked_at IS NULL", linkID, orgID).
+		Update("revoked_at", time.Now().UTC())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("invite link not found or already revoked")
+	}
+	return nil
+}
+
+func (s *inviteLinkService) JoinByLink(req models.JoinRequest) (*models.JoinResponse, error) {
+	req.Email = utils.NormalizeEmail(req.Email)
+
+	var user models.User
+	var org models.Organization
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 1️⃣ Lock the link row so concurrent joins can't over-consume MaxUses
+		var link models.InviteLink
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token = ?", req.Token).
+			First(&link).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("invalid join link")
+			}
+			return err
+		}
+
+		if link.RevokedAt != nil {
+			return errors.New("join link has been revoked")
+		}
+		if link.ExpiresAt != nil && link.ExpiresAt.Before(time.Now().UTC()) {
+			return errors.New("join link has expired")
+		}
+		if link.MaxUses > 0 && link.Uses >= link.MaxUses {
+			return errors.New("join link has reached its maximum uses")
+		}
+
+		if err := tx.First(&org, "id = ?", link.OrganizationID).Error; err != nil {
+			return err
+		}
+
+		// 2️⃣ The org's allowed-domain settings apply to link joins too
+		if !emailDomainAllowed(org.AllowedEmailDomains, req.Email) {
+			return errors.New("email domain is not allowed by this organization")
+		}
+
+		// 3️⃣ Reject duplicate membership
+		var existing models.User
+		if err := tx.Where("organization_id = ? AND LOWER(email) = ?", link.OrganizationID, req.Email).First(&existing).Error; err == nil {
+			if existing.Status == "active" {
+				return errors.New("user already exists and is active")
+			}
+			return errors.New("user has already been invited")
+		}
+
+		hashedPassword, err := utils.HashPassword(req.Password)
+		if err != nil {
+			return errors.New("failed to hash password")
+		}
+
+		// 4️⃣ Seat limit, counted under the same transaction as the insert
+		if err := ensureSeatAvailable(tx, link.OrganizationID); err != nil {
+			return err
+		}
+
+		// 5️⃣ Pending account: activation still requires email verification
+		verifyToken, err := utils.GenerateSecureToken(32)
+		if err != nil {
+			return errors.New("failed to generate verification token")
+		}
+		expiresAt := time.Now().UTC().Add(constants.VerifyTokenTTL)
+
+		user = models.User{
+			ID:             uuid.New(),
+			OrganizationID: link.OrganizationID,
+			Name:           req.Name,
+			Email:          req.Email,
+			Password:       hashedPassword,
+			Role:           link.Role,
+			Status:         "pending",
+			Locale:         inviteLocale(tx, link.OrganizationID, ""),
+			InvitedBy:      &link.CreatedBy,
+			InviteToken:    &verifyToken,
+			ExpiresAt:      &expiresAt,
+			CreatedAt:      time.Now().UTC(),
+			UpdatedAt:      time.Now().UTC(),
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+
+		// 6️⃣ Count the use atomically under the row lock
+		return tx.Model(&models.InviteLink{}).
+			Where("id = ?", link.ID).
+			Update("uses", gorm.Expr("uses + 1")).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 7️⃣ Send verification email asynchronously via the email queue; the
+	// link carries a signed wrapper, not the raw token
+	frontendURL := os.Getenv("FRONTEND_BASE_URL")
+	signedToken := utils.SignActionToken(utils.LinkPurposeVerify, user.ID.String(), *user.InviteToken, *user.ExpiresAt)
+	verifyLink := fmt.Sprintf("%s/pl/verify-account?token=%s&account_id=%s", frontendURL, signedToken, org.AccountID)
+
+	emailSubject, emailBody, _ := utils.RenderEmail("verify", user.Locale, utils.EmailTemplateData{
+		Name:             user.Name,
+		OrganizationName: org.Name,
+		AccountID:        org.AccountID,
+		Link:             verifyLink,
+	})
+
+	if err := utils.QueueEmail(user.Email, emailSubject, emailBody); err != nil {
+		fmt.Printf("[WARN] Failed to queue verification email: %v\n", err)
+	}
+
+	return &models.JoinResponse{
+		UserID:         user.ID,
+		OrganizationID: user.OrganizationID,
+		Email:          user.Email,
+		Role:           user.Role,
+		Status:         user.Status,
+	}, nil
+}
+
+// emailDomainAllowed checks the email's domain against the org's
+// comma-separated allowlist; an empty allowlist permits any domain.
+func emailDomainAllowed(allowedDomains, email string) bool {
+	allowedDomains = strings.TrimSpace(allowedDomains)
+	if allowedDomains == "" {
+		return true
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+
+	for _, allowed := range strings.Split(allowedDomains, ",") {
+		if strings.EqualFold(strings.TrimSpace(allowed), domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func toInviteLinkResponse(link models.InviteLink) models.InviteLinkResponse {
+	return models.InviteLinkResponse{
+		ID:        link.ID,
+		Token:     link.Token,
+		Role:      link.Role,
+		MaxUses:   link.MaxUses,
+		Uses:      link.Uses,
+		ExpiresAt: link.ExpiresAt,
+		RevokedAt: link.RevokedAt,
+		CreatedAt: link.CreatedAt,
+		JoinLink:  fmt.Sprintf("%s/pl/join?token=%s", os.Getenv("FRONTEND_BASE_URL"), link.Token),
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+func joinLinkFixture(t *testing.T) (*authHarness, InviteLinkService, models.Organization, string) {
+	t.Helper()
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+
+	links := NewInviteLinkService(h.db)
+	link, err := links.CreateInviteLink(owner.ID, "owner", org.ID, models.CreateInviteLinkRequest{Role: "member"})
+	if err != nil {
+		t.Fatalf("CreateInviteLink failed: %v", err)
+	}
+	return h, links, org, link.Token
+}
+
+func joinReq(email, token string) models.JoinRequest {
+	return models.JoinRequest{
+		Name:     "Link Joiner",
+		Email:    email,
+		Password: "Chosen1Password",
+		Token:    token,
+	}
+}
+
+func TestJoinByLinkCreatesPendingMember(t *testing.T) {
+	h, links, org, token := joinLinkFixture(t)
+
+	resp, err := links.JoinByLink(joinReq("joiner@example.com", token))
+	if err != nil {
+		t.Fatalf("JoinByLink failed: %v", err)
+	}
+	if resp.OrganizationID != org.ID || resp.Role != "member" || resp.Status != "pending" {
+		t.Errorf("join response = %+v, want pending member of the link's org", resp)
+	}
+
+	// Activation still goes through email verification
+	var user models.User
+	if err := h.db.First(&user, "id = ?", resp.UserID).Error; err != nil {
+		t.Fatalf("joined user lookup failed: %v", err)
+	}
+	if user.Status != "pending" || user.InviteToken == nil {
+		t.Errorf("joined user: status=%s token=%v, want pending with a verify token", user.Status, user.InviteToken)
+	}
+}
+
+func TestJoinByLinkRejectsRevokedLink(t *testing.T) {
+	h, links, org, token := joinLinkFixture(t)
+
+	stored, err := links.GetInviteLinks(org.ID.String())
+	if err != nil || len(stored) != 1 {
+		t.Fatalf("GetInviteLinks = %v, %v, want the one created link", stored, err)
+	}
+	if err := links.RevokeInviteLink(org.ID.String(), stored[0].ID.String()); err != nil {
+		t.Fatalf("RevokeInviteLink failed: %v", err)
+	}
+
+	if _, err := links.JoinByLink(joinReq("late@example.com", token)); err == nil {
+		t.Error("a revoked link still admitted a join")
+	}
+
+	// No orphan user came out of the rejected join
+	var count int64
+	if err := h.db.Model(&models.User{}).
+		Where("organization_id = ? AND email = ?", org.ID, "late@example.com").
+		Count(&count).Error; err != nil {
+		t.Fatalf("user count failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("%d users exist after a rejected join, want 0", count)
+	}
+}
+
+func TestJoinByLinkEnforcesSeatQuota(t *testing.T) {
+	h, links, org, token := joinLinkFixture(t)
+
+	// One seat is already taken by the owner; cap the org at two
+	limit := 2
+	if err := h.db.Model(&models.Organization{}).Where("id = ?", org.ID).
+		Update("seat_limit", &limit).Error; err != nil {
+		t.Fatalf("failed to set the seat limit: %v", err)
+	}
+
+	if _, err := links.JoinByLink(joinReq("fits@example.com", token)); err != nil {
+		t.Fatalf("join within the quota failed: %v", err)
+	}
+	if _, err := links.JoinByLink(joinReq("overflow@example.com", token)); err == nil {
+		t.Error("a join beyond the seat limit succeeded")
+	}
+}
+
+func TestJoinByLinkHonorsExpiryAndMaxUses(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+	links := NewInviteLinkService(h.db)
+
+	single, err := links.CreateInviteLink(owner.ID, "owner", org.ID, models.CreateInviteLinkRequest{Role: "member", MaxUses: 1})
+	if err != nil {
+		t.Fatalf("CreateInviteLink failed: %v", err)
+	}
+	if _, err := links.JoinByLink(joinReq("first@example.com", single.Token)); err != nil {
+		t.Fatalf("first use of a single-use link failed: %v", err)
+	}
+	if _, err := links.JoinByLink(joinReq("second@example.com", single.Token)); err == nil {
+		t.Error("a single-use link admitted a second join")
+	}
+
+	expired := models.InviteLink{}
+	if err := h.db.First(&expired, "token = ?", single.Token).Error; err != nil {
+		t.Fatalf("link lookup failed: %v", err)
+	}
+	past := time.Now().UTC().Add(-time.Hour)
+	if err := h.db.Model(&models.InviteLink{}).Where("id = ?", expired.ID).
+		Updates(map[string]interface{}{"expires_at": past, "uses": 0}).Error; err != nil {
+		t.Fatalf("failed to expire the link: %v", err)
+	}
+	if _, err := links.JoinByLink(joinReq("expired@example.com", single.Token)); err == nil {
+		t.Error("an expired link admitted a join")
+	}
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+// Fixture users carry bcrypt hashes; with argon2id preferred, a successful
+// login must transparently upgrade the stored hash without the user noticing.
+func TestLoginUpgradesHashToPreferredAlgorithm(t *testing.T) {
+	t.Setenv("PASSWORD_HASHER", "argon2id")
+
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	user := testutil.NewUser(h.db, org).Active().Build()
+
+	login := func() error {
+		_, err := h.auth.Login(context.Background(), &models.LoginRequest{
+			AccountID: org.AccountID,
+			Email:     user.Email,
+			Password:  testutil.FixturePassword,
+		})
+		return err
+	}
+
+	if err := login(); err != nil {
+		t.Fatalf("login against the bcrypt hash failed: %v", err)
+	}
+
+	var upgraded models.User
+	if err := h.db.First(&upgraded, "id = ?", user.ID).Error; err != nil {
+		t.Fatalf("user lookup failed: %v", err)
+	}
+	if !strings.HasPrefix(upgraded.Password, "$argon2id$") {
+		t.Fatalf("stored hash %q was not upgraded to argon2id", upgraded.Password[:12])
+	}
+
+	// The same password keeps working against the upgraded hash
+	if err := login(); err != nil {
+		t.Fatalf("login after the upgrade failed: %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+func directoryFixture(t *testing.T) (*authHarness, models.Organization) {
+	t.Helper()
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	testutil.NewUser(h.db, org).Owner().Active().Named("Owner One").Build()
+	testutil.NewUser(h.db, org).Maintainer().Active().Named("Maintainer One").Build()
+	testutil.NewUser(h.db, org).Active().Named("Member One").Build()
+	testutil.NewUser(h.db, org).Pending(time.Hour).Named("Pending One").Build()
+	testutil.NewUser(h.db, org).Suspended().Named("Suspended One").Build()
+	return h, org
+}
+
+func TestMemberDirectoryListsActiveUsersOnly(t *testing.T) {
+	h, org := directoryFixture(t)
+
+	directory, err := h.users.GetMemberDirectory(org.ID.String(), "member", "", 1, 50)
+	if err != nil {
+		t.Fatalf("GetMemberDirectory failed: %v", err)
+	}
+
+	if directory.Total != 3 || len(directory.Members) != 3 {
+		t.Fatalf("directory lists %d/%d users, want 3 active", directory.Total, len(directory.Members))
+	}
+	for _, entry := range directory.Members {
+		if entry.Name == "Pending One" || entry.Name == "Suspended One" {
+			t.Errorf("non-active user %q leaked into the directory", entry.Name)
+		}
+	}
+}
+
+func TestMemberDirectoryVisibilityPerRole(t *testing.T) {
+	h, org := directoryFixture(t)
+
+	// Owner switches the directory off for members
+	if err := h.db.Model(&models.Organization{}).Where("id = ?", org.ID).
+		Update("member_directory_visible", false).Error; err != nil {
+		t.Fatalf("failed to hide the directory: %v", err)
+	}
+
+	if _, err := h.users.GetMemberDirectory(org.ID.String(), "member", "", 1, 50); err == nil {
+		t.Error("a member listed the directory while it is hidden")
+	}
+	// Owners and maintainers keep access: they have the full listing anyway
+	for _, role := range []string{"owner", "maintainer"} {
+		if _, err := h.users.GetMemberDirectory(org.ID.String(), role, "", 1, 50); err != nil {
+			t.Errorf("%s was blocked from the hidden directory: %v", role, err)
+		}
+	}
+}
+
+func TestMemberDirectoryPaginates(t *testing.T) {
+	h, org := directoryFixture(t)
+
+	first, err := h.users.GetMemberDirectory(org.ID.String(), "member", "", 1, 2)
+	if err != nil {
+		t.Fatalf("GetMemberDirectory failed: %v", err)
+	}
+	second, err := h.users.GetMemberDirectory(org.ID.String(), "member", "", 2, 2)
+	if err != nil {
+		t.Fatalf("GetMemberDirectory failed: %v", err)
+	}
+
+	if first.Total != 3 || len(first.Members) != 2 || len(second.Members) != 1 {
+		t.Errorf("pagination: total=%d pages=%d/%d, want 3 split 2/1",
+			first.Total, len(first.Members), len(second.Members))
+	}
+	if first.Members[0].Name != "Maintainer One" {
+		t.Errorf("first page starts with %q, want name-ordered Maintainer One", first.Members[0].Name)
+	}
+}
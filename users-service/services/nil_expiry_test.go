@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	"github.com/google/uuid"
+)
+
+// A user row can end up with a token but no expiry (cleanup job, partially
+// completed earlier reset). Every consuming flow must treat that state as an
+// expired link — a 410 with token_expired — never dereference the nil.
+
+// setOrphanToken pins a token while clearing the expiry, the inconsistent
+// state these tests exist for.
+func setOrphanToken(t *testing.T, h *authHarness, userID uuid.UUID, token string) {
+	t.Helper()
+	if err := h.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"invite_token": token,
+		"expires_at":   nil,
+	}).Error; err != nil {
+		t.Fatalf("failed to orphan the token: %v", err)
+	}
+}
+
+func assertGoneTokenExpired(t *testing.T, err error) {
+	t.Helper()
+	if err == nil {
+		t.Fatal("a token without an expiry was accepted")
+	}
+	if code := apperrors.Code(err); code != "token_expired" {
+		t.Errorf("code = %q, want token_expired", code)
+	}
+	if status := apperrors.Status(err, http.StatusInternalServerError); status != http.StatusGone {
+		t.Errorf("status = %d, want 410", status)
+	}
+}
+
+func TestVerifyAccountTreatsNilExpiryAsExpired(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Build()
+	user := testutil.NewUser(h.db, org).Owner().Pending(time.Hour).Build()
+	setOrphanToken(t, h, user.ID, "orphan-verify-token")
+
+	_, err := h.auth.VerifyAccount(context.Background(), "orphan-verify-token")
+	assertGoneTokenExpired(t, err)
+}
+
+func TestAcceptInviteTreatsNilExpiryAsExpired(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	user := testutil.NewUser(h.db, org).WithEmail("invitee@example.com").Pending(time.Hour).Build()
+	setOrphanToken(t, h, user.ID, "orphan-invite-token")
+
+	_, err := h.auth.AcceptInvite(models.AcceptInviteRequest{
+		Name:      "Invitee",
+		Email:     "invitee@example.com",
+		Password:  "Chosen1Password",
+		AccountID: org.AccountID,
+		Token:     "orphan-invite-token",
+	})
+	assertGoneTokenExpired(t, err)
+}
+
+func TestResetPasswordByEmailTreatsNilExpiryAsExpired(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	user := testutil.NewUser(h.db, org).Active().Build()
+	setOrphanToken(t, h, user.ID, "orphan-reset-token")
+
+	_, err := h.auth.ResetPasswordByEmail("orphan-reset-token", "NewPassw0rd1")
+	assertGoneTokenExpired(t, err)
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Getting-started checklist. Completion is recorded into the organization's
+// onboarding_state JSONB as it happens (event subscribers in main.go, direct
+// calls from services, and chats-service for the steps it owns), so the read
+// endpoint is a single-row lookup instead of a fan-out over five tables.
+
+const (
+	StepAccountVerified  = "account_verified"
+	StepMemberInvited    = "member_invited"
+	StepDocumentUploaded = "document_uploaded"
+	StepFirstPrediction  = "first_prediction"
+	StepBrandingSet      = "branding_set"
+)
+
+// Checklist order as shown to the user. chats-service marks
+// document_uploaded and first_prediction with the same keys.
+var onboardingSteps = []struct {
+	Key   string
+	Label string
+}{
+	{StepAccountVerified, "Verify your email address"},
+	{StepMemberInvited, "Invite your first team member"},
+	{StepDocumentUploaded, "Upload your first document"},
+	{StepFirstPrediction, "Ask your first question"},
+	{StepBrandingSet, "Set up your email branding"},
+}
+
+// MarkOnboardingStep records a completed checklist step with its timestamp.
+// The guarded UPDATE only fires when the key is absent, so repeated triggers
+// (a second invite, a retried event) are no-ops and the original completion
+// time is kept. Failures are logged but never fail the calling operation.
+func MarkOnboardingStep(db *gorm.DB, orgID, step string) {
+	if orgID == "" {
+		return
+	}
+	err := db.Exec(`
+		UPDATE organizations
+		SET onboarding_state = jsonb_set(COALESCE(onboarding_state, '{}'::jsonb), ARRAY[?], to_jsonb(NOW()), true)
+		WHERE id = ?
+		  AND NOT jsonb_exists(COALESCE(onboarding_state, '{}'::jsonb), ?)
+	`, step, orgID, step).Error
+	if err != nil {
+		fmt.Printf("[WARN] onboarding step %s not recorded for org %s: %v\n", step, orgID, err)
+	}
+}
+
+// parseOnboardingState decodes the stored JSONB into step completion times
+// and the dismissed flag. Unknown keys are ignored; a malformed timestamp
+// degrades to "complete, time unknown" rather than an error.
+func parseOnboardingState(raw string) (map[string]*time.Time, bool) {
+	completed := map[string]*time.Time{}
+	if raw == "" {
+		return completed, false
+	}
+
+	var state map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return completed, false
+	}
+
+	dismissed := false
+	if v, ok := state["dismissed"]; ok {
+		_ = json.Unmarshal(v, &dismissed)
+	}
+
+	for _, step := range onboardingSteps {
+		v, ok := state[step.Key]
+		if !ok {
+			continue
+		}
+		var stamp string
+		if err := json.Unmarshal(v, &stamp); err != nil {
+			completed[step.Key] = nil
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+			completed[step.Key] = &t
+		} else {
+			completed[step.Key] = nil
+		}
+	}
+	return completed, dismissed
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+// setOnboardingState writes the raw JSONB column directly; the mark helpers
+// use Postgres jsonb functions the test database doesn't speak.
+func setOnboardingState(t *testing.T, h *authHarness, orgID, state string) {
+	t.Helper()
+	if err := h.db.Exec("UPDATE organizations SET onboarding_state = ? WHERE id = ?", state, orgID).Error; err != nil {
+		t.Fatalf("failed to set onboarding state: %v", err)
+	}
+}
+
+func TestGetOnboardingProgressFreshOrg(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+
+	progress, err := NewOrganizationService(h.db).GetOnboardingProgress(org.ID.String())
+	if err != nil {
+		t.Fatalf("GetOnboardingProgress failed: %v", err)
+	}
+
+	if progress.Completed != 0 || progress.Dismissed {
+		t.Errorf("fresh org: completed=%d dismissed=%v, want 0/false", progress.Completed, progress.Dismissed)
+	}
+	if progress.Total != len(onboardingSteps) || len(progress.Steps) != progress.Total {
+		t.Errorf("total=%d steps=%d, want %d", progress.Total, len(progress.Steps), len(onboardingSteps))
+	}
+	for _, step := range progress.Steps {
+		if step.Complete || step.CompletedAt != nil {
+			t.Errorf("step %s reads complete on a fresh org", step.Key)
+		}
+	}
+}
+
+func TestGetOnboardingProgressPartiallyComplete(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	verified := time.Date(2026, 8, 1, 9, 0, 0, 0, time.UTC)
+	setOnboardingState(t, h, org.ID.String(),
+		`{"account_verified": "`+verified.Format(time.RFC3339Nano)+`", "member_invited": "2026-08-02T10:00:00Z"}`)
+
+	progress, err := NewOrganizationService(h.db).GetOnboardingProgress(org.ID.String())
+	if err != nil {
+		t.Fatalf("GetOnboardingProgress failed: %v", err)
+	}
+
+	if progress.Completed != 2 {
+		t.Errorf("completed = %d, want 2", progress.Completed)
+	}
+	byKey := map[string]bool{}
+	for _, step := range progress.Steps {
+		byKey[step.Key] = step.Complete
+		if step.Key == StepAccountVerified {
+			if step.CompletedAt == nil || !step.CompletedAt.Equal(verified) {
+				t.Errorf("account_verified completed_at = %v, want %v", step.CompletedAt, verified)
+			}
+		}
+	}
+	if !byKey[StepAccountVerified] || !byKey[StepMemberInvited] {
+		t.Error("recorded steps are not reported complete")
+	}
+	if byKey[StepFirstPrediction] || byKey[StepBrandingSet] {
+		t.Error("unrecorded steps are reported complete")
+	}
+}
+
+func TestGetOnboardingProgressDismissedAndDegraded(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	// A malformed timestamp degrades to "complete, time unknown"; the
+	// dismissed flag rides in the same JSONB
+	setOnboardingState(t, h, org.ID.String(),
+		`{"dismissed": true, "first_prediction": "not-a-timestamp"}`)
+
+	progress, err := NewOrganizationService(h.db).GetOnboardingProgress(org.ID.String())
+	if err != nil {
+		t.Fatalf("GetOnboardingProgress failed: %v", err)
+	}
+
+	if !progress.Dismissed {
+		t.Error("dismissed flag was not surfaced")
+	}
+	for _, step := range progress.Steps {
+		if step.Key != StepFirstPrediction {
+			continue
+		}
+		if !step.Complete {
+			t.Error("a step with a malformed timestamp should still read complete")
+		}
+		if step.CompletedAt != nil {
+			t.Errorf("malformed timestamp produced completed_at %v, want nil", step.CompletedAt)
+		}
+	}
+}
+
+func TestParseOnboardingStateToleratesGarbage(t *testing.T) {
+	for _, raw := range []string{"", "not json", `{"unknown_step": "2026-01-01T00:00:00Z"}`} {
+		completed, dismissed := parseOnboardingState(raw)
+		if len(completed) != 0 || dismissed {
+			t.Errorf("parseOnboardingState(%q) = %v, %v; want empty, false", raw, completed, dismissed)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+func TestGetOrganizationDetailsBreakdown(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+	testutil.NewUser(h.db, org).Maintainer().Active().Build()
+	testutil.NewUser(h.db, org).Active().Build()
+	testutil.NewUser(h.db, org).Pending(72).Build()
+	testutil.NewUser(h.db, org).Pending(72).Build()
+	testutil.NewUser(h.db, org).Suspended().Build()
+
+	orgs := NewOrganizationService(h.db)
+	details, err := orgs.GetOrganizationDetails(org.ID.String(), "owner")
+	if err != nil {
+		t.Fatalf("GetOrganizationDetails failed: %v", err)
+	}
+
+	if details.TotalUsers != 6 {
+		t.Errorf("total_users = %d, want 6", details.TotalUsers)
+	}
+	if details.AccountID != org.AccountID {
+		t.Errorf("account_id = %q, want the stored string %q", details.AccountID, org.AccountID)
+	}
+
+	wantByStatus := map[string]int{"active": 3, "pending": 2, "suspended": 1}
+	for status, want := range wantByStatus {
+		if got := details.UsersByStatus[status]; got != want {
+			t.Errorf("users_by_status[%s] = %d, want %d", status, got, want)
+		}
+	}
+	wantByRole := map[string]int{"owner": 1, "maintainer": 1, "member": 4}
+	for role, want := range wantByRole {
+		if got := details.UsersByRole[role]; got != want {
+			t.Errorf("users_by_role[%s] = %d, want %d", role, got, want)
+		}
+	}
+
+	if details.PendingInvites != 2 {
+		t.Errorf("pending_invites = %d, want 2", details.PendingInvites)
+	}
+	// Back-compat aggregates stay in sync with the maps
+	if details.TotalMaintainers != 1 || details.TotalMembers != 4 {
+		t.Errorf("maintainers/members = %d/%d, want 1/4", details.TotalMaintainers, details.TotalMembers)
+	}
+	if details.SeatsUsed != 5 {
+		t.Errorf("seats_used = %d, want 5 (active + pending)", details.SeatsUsed)
+	}
+	if details.OwnerEmail == nil || *details.OwnerEmail != owner.Email {
+		t.Errorf("owner_email = %v, want %s", details.OwnerEmail, owner.Email)
+	}
+}
+
+func TestGetOrganizationDetailsHidesBreakdownFromMembers(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	testutil.NewUser(h.db, org).Owner().Active().Build()
+	testutil.NewUser(h.db, org).Pending(72).Build()
+
+	orgs := NewOrganizationService(h.db)
+	details, err := orgs.GetOrganizationDetails(org.ID.String(), "member")
+	if err != nil {
+		t.Fatalf("GetOrganizationDetails failed: %v", err)
+	}
+
+	if details.UsersByStatus != nil || details.UsersByRole != nil {
+		t.Error("breakdown maps must not be populated for members")
+	}
+	if details.OwnerEmail != nil {
+		t.Error("owner email must not be exposed to members")
+	}
+	if details.Name != org.Name {
+		t.Errorf("name = %q, want %q", details.Name, org.Name)
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	utils "github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var domainPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+$`)
+
+// lookupTXT is swappable so DNS isn't hit outside of real verification calls.
+var lookupTXT = net.LookupTXT
+
+// txtRecordValue is the record the domain owner must publish before the
+// verify endpoint can succeed.
+func txtRecordValue(challenge string) string {
+	return fmt.Sprintf("aikb-domain-verification=%s", challenge)
+}
+
+func (s *organizationService) AddDomain(orgID, domain string) (*models.OrganizationDomainResponse, error) {
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		return nil, errors.New("invalid organization ID")
+	}
+
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if !domainPattern.MatchString(domain) {
+		return nil, errors.New("invalid domain name")
+	}
+
+	// One org per domain: a second claim is rejected regardless of who
+	// verified first, so ownership disputes surface immediately.
+	var existing models.OrganizationDomain
+	if err := s.db.Where("domain = ?", domain).First(&existing).Error; err == nil {
+		if existing.OrganizationID == orgUUID {
+			return nil, errors.New("domain already added to this organization")
+		}
+		return nil, errors.New("domain is already claimed by another organization")
+	}
+
+	challenge, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return nil, errors.New("failed to generate domain challenge")
+	}
+
+	record := models.OrganizationDomain{
+		ID:             uuid.New(),
+		OrganizationID: orgUUID,
+		Domain:         domain,
+		TxtChallenge:   challenge,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := s.db.Create(&record).Error; err != nil {
+		return nil, err
+	}
+
+	resp := toDomainResponse(record)
+	return &resp, nil
+}
+
+func (s *organizationService) GetDomains(orgID string) ([]models.OrganizationDomainResponse, error) {
+	var domains []models.OrganizationDomain
+	if err := s.db.
+		Where("organization_id = ?", orgID).
+		Order("created_at DESC").
+		Find(&domains).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.OrganizationDomainResponse, 0, len(domains))
+	for _, d := range domains {
+		responses = append(responses, toDomainResponse(d))
+	}
+	return responses, nil
+}
+
+// VerifyDomain checks DNS for the TXT challenge and marks the domain
+// verified. Only verified domains enable auto-join.
+func (s *organizationService) VerifyDomain(orgID, domain string) (*models.OrganizationDomainResponse, error) {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+
+	var record models.OrganizationDomain
+	if err := s.db.Where("organization_id = ? AND domain = ?", orgID, domain).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("domain not found for this organization")
+		}
+		return nil, err
+	}
+
+	if record.VerifiedAt == nil {
+		records, err := lookupTXT(domain)
+		if err != nil {
+			return nil, fmt.Errorf("DNS lookup failed for %s: %w", domain, err)
+		}
+
+		expected := txtRecordValue(record.TxtChallenge)
+		found := false
+		for _, txt := range records {
+			if strings.TrimSpace(txt) == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("TXT record %q not found on %s", expected, domain)
+		}
+
+		now := time.Now().UTC()
+		record.VerifiedAt = &now
+		if err := s.db.Model(&models.OrganizationDomain{}).
+			Where("id = ?", record.ID).
+			Update("verified_at", now).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	resp := toDomainResponse(record)
+	return &resp, nil
+}
+
+// RemoveDomain only disables future auto-joins; members who already joined
+// through the domain keep their accounts.
+func (s *organizationService) RemoveDomain(orgID, domain string) error {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+
+	result := s.db.
+		Where("organization_id = ? AND domain = ?", orgID, domain).
+		Delete(&models.OrganizationDomain{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("domain not found for this organization")
+	}
+	return nil
+}
+
+func toDomainResponse(d models.OrganizationDomain) models.OrganizationDomainResponse {
+	return models.OrganizationDomainResponse{
+		ID:         d.ID,
+		Domain:     d.Domain,
+		Verified:   d.VerifiedAt != nil,
+		VerifiedAt: d.VerifiedAt,
+		CreatedAt:  d.CreatedAt,
+		TxtRecord:  txtRecordValue(d.TxtChallenge),
+	}
+}
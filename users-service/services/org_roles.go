@@ -0,0 +1,215 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/cache"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/constants"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/validation"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OrgRoleService interface {
+	CreateOrgRole(orgID uuid.UUID, req models.CreateOrgRoleRequest) (*models.OrgRoleResponse, error)
+	GetOrgRoles(orgID string) ([]models.OrgRoleResponse, error)
+	UpdateOrgRole(orgID, roleName string, req models.UpdateOrgRoleRequest) (*models.OrgRoleResponse, error)
+	DeleteOrgRole(orgID, roleName, reassignTo string) error
+}
+
+type orgRoleService struct {
+	db *gorm.DB
+}
+
+func NewOrgRoleService(db *gorm.DB) OrgRoleService {
+	return &orgRoleService{db: db}
+}
+
+// Role names look like slugs so they read cleanly in JWTs and audit logs;
+// the pattern lives in shared/validation (validation.Slug).
+
+// validateRolePermissions rejects unknown capability strings.
+func validateRolePermissions(permissions []string) error {
+	for _, p := range permissions {
+		if !constants.CapabilityExists(p) {
+			return fmt.Errorf("unknown permission %q", p)
+		}
+	}
+	return nil
+}
+
+func (s *orgRoleService) CreateOrgRole(orgID uuid.UUID, req models.CreateOrgRoleRequest) (*models.OrgRoleResponse, error) {
+	name := strings.ToLower(strings.TrimSpace(req.Name))
+	if err := validation.Slug(name); err != nil {
+		return nil, errors.New("role name must be lowercase letters, digits, '-' or '_' (2-50 chars)")
+	}
+	// Built-in roles stay immutable and cannot be shadowed
+	if constants.IsBuiltInRole(name) {
+		return nil, errors.New("built-in roles cannot be redefined")
+	}
+	if err := validateRolePermissions(req.Permissions); err != nil {
+		return nil, err
+	}
+
+	role := models.OrgRole{
+		OrganizationID: orgID,
+		Name:           name,
+		Permissions:    strings.Join(req.Permissions, ","),
+	}
+	if err := s.db.Create(&role).Error; err != nil {
+		return nil, errors.New("failed to create role; the name may already be in use")
+	}
+
+	resp := s.toOrgRoleResponse(role)
+	return &resp, nil
+}
+
+func (s *orgRoleService) GetOrgRoles(orgID string) ([]models.OrgRoleResponse, error) {
+	var roles []models.OrgRole
+	if err := s.db.
+		Where("organization_id = ?", orgID).
+		Order("name ASC").
+		Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.OrgRoleResponse, 0, len(roles))
+	for _, role := range roles {
+		responses = append(responses, s.toOrgRoleResponse(role))
+	}
+	return responses, nil
+}
+
+func (s *orgRoleService) UpdateOrgRole(orgID, roleName string, req models.UpdateOrgRoleRequest) (*models.OrgRoleResponse, error) {
+	if req.Permissions == nil {
+		return nil, errors.New("permissions must be provided")
+	}
+	if err := validateRolePermissions(*req.Permissions); err != nil {
+		return nil, err
+	}
+
+	role, err := s.findOrgRole(orgID, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(role).
+		Update("permissions", strings.Join(*req.Permissions, ",")).Error; err != nil {
+		return nil, err
+	}
+	cache.InvalidateOrgRole(orgID, roleName)
+
+	role, err = s.findOrgRole(orgID, roleName)
+	if err != nil {
+		return nil, err
+	}
+	resp := s.toOrgRoleResponse(*role)
+	return &resp, nil
+}
+
+// DeleteOrgRole removes a custom role. If users still hold it, the caller
+// must name a reassignment target (built-in or another custom role); the
+// holders are moved with a token-version bump so pre-change tokens stop
+// validating, mirroring UpdateUser's role-change behavior.
+func (s *orgRoleService) DeleteOrgRole(orgID, roleName, reassignTo string) error {
+	role, err := s.findOrgRole(orgID, roleName)
+	if err != nil {
+		return err
+	}
+
+	var affectedIDs []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var holders int64
+		if err := tx.Model(&models.User{}).
+			Where("organization_id = ? AND role = ?", orgID, roleName).
+			Count(&holders).Error; err != nil {
+			return err
+		}
+
+		if holders > 0 {
+			if reassignTo == "" {
+				return fmt.Errorf("%d user(s) still hold this role; pass reassign_to to move them first", holders)
+			}
+			if reassignTo == "owner" {
+				return errors.New("cannot reassign users to owner")
+			}
+			if !constants.IsBuiltInRole(reassignTo) {
+				if _, err := s.findOrgRoleTx(tx, orgID, reassignTo); err != nil {
+					return errors.New("reassignment target role not found")
+				}
+			}
+
+			if err := tx.Model(&models.User{}).
+				Where("organization_id = ? AND role = ?", orgID, roleName).
+				Pluck("id", &affectedIDs).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&models.User{}).
+				Where("organization_id = ? AND role = ?", orgID, roleName).
+				Updates(map[string]interface{}{
+					"role":          reassignTo,
+					"token_version": gorm.Expr("token_version + 1"),
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(role).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	cache.InvalidateOrgRole(orgID, roleName)
+	for _, id := range affectedIDs {
+		cache.InvalidateUser(id)
+	}
+	return nil
+}
+
+func (s *orgRoleService) findOrgRole(orgID, roleName string) (*models.OrgRole, error) {
+	return s.findOrgRoleTx(s.db, orgID, roleName)
+}
+
+func (s *orgRoleService) findOrgRoleTx(tx *gorm.DB, orgID, roleName string) (*models.OrgRole, error) {
+	var role models.OrgRole
+	if err := tx.
+		Where("organization_id = ? AND name = ?", orgID, roleName).
+		First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("role not found")
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (s *orgRoleService) toOrgRoleResponse(role models.OrgRole) models.OrgRoleResponse {
+	var userCount int64
+	s.db.Model(&models.User{}).
+		Where("organization_id = ? AND role = ?", role.OrganizationID, role.Name).
+		Count(&userCount)
+
+	return models.OrgRoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Permissions: role.PermissionList(),
+		UserCount:   userCount,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
+
+// orgRoleExists is used by invite / role-change validation to accept custom
+// role names alongside the built-ins.
+func orgRoleExists(db *gorm.DB, orgID interface{}, roleName string) bool {
+	var count int64
+	db.Model(&models.OrgRole{}).
+		Where("organization_id = ? AND name = ?", orgID, roleName).
+		Count(&count)
+	return count > 0
+}
@@ -2,8 +2,14 @@ package services
 
 import (
 	"errors"
-	"strconv"
-
+	"strings"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/cache"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/constants"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/db"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/i18n"
 	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -13,6 +19,21 @@ type OrganizationService interface {
 	GetOrganizationDetails(orgID string, role string) (*models.OrganizationDetailsResponse, error)
 	GetDashboardStats(orgID string, userID string) (*models.DashboardStatsResponse, error)
 	DeleteOrganization(orgID string) error
+	RegenerateAccountID(orgID string) (string, error)
+	GetUsageReport(orgID string, from, to time.Time, granularity string) ([]models.UsageReportBucket, error)
+	GetOnboardingProgress(orgID string) (*models.OnboardingProgressResponse, error)
+	SetOnboardingDismissed(orgID string, dismissed bool) error
+	GetBranding(orgID string) (*models.BrandingResponse, error)
+	UpdateBranding(orgID string, req models.UpdateBrandingRequest) (*models.BrandingResponse, error)
+	UpdateSettings(orgID string, req models.UpdateOrgSettingsRequest) error
+	PreviewBrandedInvite(orgID string) (string, error)
+	SetSeatLimit(orgID string, seatLimit *int) error
+	SetTier(orgID, tier string) error
+	ListAbuseReports(orgID, reportStatus, category string, limit int) ([]models.AbuseReportAdminRow, error)
+	AddDomain(orgID, domain string) (*models.OrganizationDomainResponse, error)
+	GetDomains(orgID string) ([]models.OrganizationDomainResponse, error)
+	VerifyDomain(orgID, domain string) (*models.OrganizationDomainResponse, error)
+	RemoveDomain(orgID, domain string) error
 }
 type organizationService struct {
 	db *gorm.DB
@@ -32,35 +53,39 @@ func (s *organizationService) GetOrganizationDetails(orgID, role string) (*model
 		return nil, errors.New("invalid organization ID")
 	}
 
-	var org models.Organization
-	if err := s.db.First(&org, "id = ?", orgUUID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("organization not found")
+	// Orgs change rarely but are fetched on every details/dashboard hit;
+	// account-id regeneration and deletion invalidate explicitly
+	org, found := cache.Organizations.Get(orgID)
+	if !found {
+		if err := db.Retry(func() error {
+			return db.Replica(s.db, "").First(&org, "id = ?", orgUUID).Error
+		}); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("organization not found")
+			}
+			return nil, err
 		}
-		return nil, err
+		cache.Organizations.Set(orgID, org)
 	}
 
 	// 🧩 Base response
 	response := &models.OrganizationDetailsResponse{
-		OrganizationID: org.ID.String(),
-		Name:           org.Name,
-		Status:         org.Status,
-		CreatedAt:      org.CreatedAt,
-		UpdatedAt:      org.UpdatedAt,
-	}
-
-	// Convert AccountID to int64 if stored as string
-	if org.AccountID != "" {
-		accountIDInt, err := strconv.ParseInt(org.AccountID, 10, 64)
-		if err == nil {
-			response.AccountID = accountIDInt
-		}
+		OrganizationID:       org.ID.String(),
+		Name:                 org.Name,
+		Status:               org.Status,
+		CreatedAt:            org.CreatedAt,
+		UpdatedAt:            org.UpdatedAt,
+		MessageRetentionDays: org.MessageRetentionDays,
+		LastPurgeAt:          org.LastPurgeAt,
+		DefaultLocale:        org.DefaultLocale,
 	}
 
+	response.AccountID = org.AccountID
+
 	// 👤 Fetch creator details
 	if org.CreatedBy != nil {
 		var creator models.User
-		if err := s.db.Select("id, name").
+		if err := db.Replica(s.db, "").Select("id, name").
 			Where("id = ?", *org.CreatedBy).
 			First(&creator).Error; err == nil {
 
@@ -75,32 +100,35 @@ func (s *organizationService) GetOrganizationDetails(orgID, role string) (*model
 	// 🧠 If not member, attach additional details
 	if role != "member" {
 
-		// ✅ Total Users
-		var totalUsers int64
-		if err := s.db.Model(&models.User{}).
+		// ✅ Role/status breakdown in a single grouped query
+		var breakdown []struct {
+			Role   string
+			Status string
+			Count  int
+		}
+		if err := db.Replica(s.db, "").Model(&models.User{}).
+			Select("role, status, COUNT(*) AS count").
 			Where("organization_id = ?", org.ID).
-			Count(&totalUsers).Error; err != nil {
+			Group("role, status").
+			Scan(&breakdown).Error; err != nil {
 			return nil, err
 		}
-		response.TotalUsers = int(totalUsers)
 
-		// ✅ Total Maintainers
-		var totalMaintainers int64
-		if err := s.db.Model(&models.User{}).
-			Where("organization_id = ? AND role = ?", org.ID, "maintainer").
-			Count(&totalMaintainers).Error; err != nil {
-			return nil, err
+		response.UsersByStatus = map[string]int{}
+		response.UsersByRole = map[string]int{}
+		for _, b := range breakdown {
+			response.TotalUsers += b.Count
+			response.UsersByStatus[b.Status] += b.Count
+			response.UsersByRole[b.Role] += b.Count
 		}
-		response.TotalMaintainers = int(totalMaintainers)
+		// Kept for response backwards compatibility
+		response.TotalMaintainers = response.UsersByRole["maintainer"]
+		response.TotalMembers = response.UsersByRole["member"]
+		response.PendingInvites = response.UsersByStatus["pending"]
 
-		// ✅ Total Members
-		var totalMembers int64
-		if err := s.db.Model(&models.User{}).
-			Where("organization_id = ? AND role = ?", org.ID, "member").
-			Count(&totalMembers).Error; err != nil {
-			return nil, err
-		}
-		response.TotalMembers = int(totalMembers)
+		// 💺 Seats: active + pending occupy one each
+		response.SeatLimit = org.SeatLimit
+		response.SeatsUsed = response.UsersByStatus["active"] + response.UsersByStatus["pending"]
 
 		// ✅ Owner Email
 		var owner models.User
@@ -133,7 +161,7 @@ func (s *organizationService) GetDashboardStats(orgID string, userID string) (*m
 	// ------------------------------------------------
 	// Organization Name
 	// ------------------------------------------------
-	if err := s.db.Raw(`
+	if err := db.Replica(s.db, userID).Raw(`
 		SELECT name FROM organizations WHERE id = ?
 	`, orgUUID).Scan(&stats.OrganizationName).Error; err != nil {
 		return nil, err
@@ -142,7 +170,7 @@ func (s *organizationService) GetDashboardStats(orgID string, userID string) (*m
 	// ------------------------------------------------
 	// Current User Info
 	// ------------------------------------------------
-	if err := s.db.Raw(`
+	if err := db.Replica(s.db, userID).Raw(`
 		SELECT name, role
 		FROM users
 		WHERE id = ?
@@ -153,7 +181,7 @@ func (s *organizationService) GetDashboardStats(orgID string, userID string) (*m
 	// ------------------------------------------------
 	// USERS
 	// ------------------------------------------------
-	if err := s.db.Raw(`
+	if err := db.Replica(s.db, userID).Raw(`
 		SELECT COUNT(*) 
 		FROM users
 		WHERE organization_id = ?
@@ -161,7 +189,7 @@ func (s *organizationService) GetDashboardStats(orgID string, userID string) (*m
 		return nil, err
 	}
 
-	if err := s.db.Raw(`
+	if err := db.Replica(s.db, userID).Raw(`
 		SELECT COUNT(*) 
 		FROM users
 		WHERE organization_id = ?
@@ -173,7 +201,7 @@ func (s *organizationService) GetDashboardStats(orgID string, userID string) (*m
 	// ------------------------------------------------
 	// DOCUMENTS
 	// ------------------------------------------------
-	if err := s.db.Raw(`
+	if err := db.Replica(s.db, userID).Raw(`
 		SELECT COUNT(*) 
 		FROM documents
 		WHERE organization_id = ?
@@ -181,7 +209,7 @@ func (s *organizationService) GetDashboardStats(orgID string, userID string) (*m
 		return nil, err
 	}
 
-	if err := s.db.Raw(`
+	if err := db.Replica(s.db, userID).Raw(`
 		SELECT COUNT(*) 
 		FROM documents
 		WHERE organization_id = ?
@@ -193,7 +221,7 @@ func (s *organizationService) GetDashboardStats(orgID string, userID string) (*m
 	// ------------------------------------------------
 	// CHATS
 	// ------------------------------------------------
-	if err := s.db.Raw(`
+	if err := db.Replica(s.db, userID).Raw(`
 		SELECT COUNT(*) 
 		FROM chats
 		WHERE organization_id = ?
@@ -201,7 +229,7 @@ func (s *organizationService) GetDashboardStats(orgID string, userID string) (*m
 		return nil, err
 	}
 
-	if err := s.db.Raw(`
+	if err := db.Replica(s.db, userID).Raw(`
 		SELECT COUNT(*) 
 		FROM chats
 		WHERE organization_id = ?
@@ -213,7 +241,7 @@ func (s *organizationService) GetDashboardStats(orgID string, userID string) (*m
 	// ------------------------------------------------
 	// QUERIES
 	// ------------------------------------------------
-	if err := s.db.Raw(`
+	if err := db.Replica(s.db, userID).Raw(`
 		SELECT COUNT(*)
 		FROM messages
 		WHERE organization_id = ?
@@ -225,7 +253,7 @@ func (s *organizationService) GetDashboardStats(orgID string, userID string) (*m
 	// ------------------------------------------------
 	// TOTAL MESSAGES
 	// ------------------------------------------------
-	if err := s.db.Raw(`
+	if err := db.Replica(s.db, userID).Raw(`
 		SELECT COUNT(*)
 		FROM messages
 		WHERE organization_id = ?
@@ -236,7 +264,7 @@ func (s *organizationService) GetDashboardStats(orgID string, userID string) (*m
 	// ------------------------------------------------
 	// TOTAL COST
 	// ------------------------------------------------
-	if err := s.db.Raw(`
+	if err := db.Replica(s.db, userID).Raw(`
 		SELECT COALESCE(SUM(total_cost), 0)
 		FROM token_usage
 		WHERE organization_id = ?
@@ -244,6 +272,17 @@ func (s *organizationService) GetDashboardStats(orgID string, userID string) (*m
 		return nil, err
 	}
 
+	// ------------------------------------------------
+	// STORAGE
+	// ------------------------------------------------
+	if err := db.Replica(s.db, userID).Raw(`
+		SELECT storage_used_bytes, storage_quota_bytes
+		FROM organizations
+		WHERE id = ?
+	`, orgUUID).Row().Scan(&stats.StorageUsedBytes, &stats.StorageQuotaBytes); err != nil {
+		return nil, err
+	}
+
 	// ------------------------------------------------
 	// LAST 30 DAYS ACTIVITY
 	// ------------------------------------------------
@@ -278,7 +317,7 @@ func (s *organizationService) GetDashboardStats(orgID string, userID string) (*m
 			ORDER BY d.date;
 	`
 
-	if err := s.db.Raw(chartQuery, orgUUID, orgUUID).
+	if err := db.Replica(s.db, userID).Raw(chartQuery, orgUUID, orgUUID).
 		Scan(&activity).Error; err != nil {
 		return nil, err
 	}
@@ -308,6 +347,7 @@ func (s *organizationService) DeleteOrganization(orgID string) error {
 		return errors.New("organization not found")
 	}
 
+	cache.InvalidateOrganization(orgID)
 	return nil
 }
 
@@ -326,7 +366,7 @@ func (s *organizationService) DeleteOrganization(orgID string) error {
 		Where("id = ?", orgUUID).
 		Updates(map[string]interface{}{
 			"is_deleted": true,
-			"deleted_at": time.Now(),
+			"deleted_at": time.Now().UTC(),
 		})
 
 	if result.Error != nil {
@@ -340,3 +380,300 @@ func (s *organizationService) DeleteOrganization(orgID string) error {
 	return nil
 }
 */
+
+// RegenerateAccountID replaces an organization's account id with a fresh
+// random one. The old id is kept in account_id_aliases so stale login
+// attempts get an "account ID changed" hint instead of "not found".
+func (s *organizationService) RegenerateAccountID(orgID string) (string, error) {
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		return "", errors.New("invalid organization ID")
+	}
+
+	var newAccountID string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var org models.Organization
+		if err := tx.First(&org, "id = ?", orgUUID).Error; err != nil {
+			return errors.New("organization not found")
+		}
+
+		newAccountID, err = generateUniqueAccountID(tx)
+		if err != nil {
+			return err
+		}
+
+		alias := models.AccountIDAlias{
+			ID:             uuid.New(),
+			OrganizationID: org.ID,
+			AccountID:      org.AccountID,
+		}
+		if err := tx.Create(&alias).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&org).Update("account_id", newAccountID).Error
+	})
+	if err != nil {
+		return "", err
+	}
+
+	cache.InvalidateOrganization(orgID)
+	return newAccountID, nil
+}
+
+// SetSeatLimit is reached only through the service-key internal API (billing
+// tier changes); nil clears the limit. Lowering the limit below current
+// usage keeps existing members but blocks new invites/joins.
+func (s *organizationService) SetSeatLimit(orgID string, seatLimit *int) error {
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		return errors.New("invalid organization ID")
+	}
+	if seatLimit != nil && *seatLimit < 1 {
+		return errors.New("seat limit must be at least 1 (or null for unlimited)")
+	}
+
+	result := s.db.Model(&models.Organization{}).
+		Where("id = ?", orgUUID).
+		Update("seat_limit", seatLimit)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("organization not found")
+	}
+
+	cache.InvalidateOrganization(orgID)
+	return nil
+}
+
+// SetTier is reached only through the service-key internal API. The tier
+// itself is validated at the binding layer; chats-service reads it to weight
+// the inference queue.
+func (s *organizationService) SetTier(orgID, tier string) error {
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		return errors.New("invalid organization ID")
+	}
+
+	result := s.db.Model(&models.Organization{}).
+		Where("id = ?", orgUUID).
+		Update("tier", tier)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("organization not found")
+	}
+
+	cache.InvalidateOrganization(orgID)
+	return nil
+}
+
+// ListAbuseReports serves the internal moderation listing: every tenant's
+// reports (or one org's with orgID set), filtered by status/category, newest
+// first. The reports live in a chats-service table; both services share one
+// database, so this reads it directly like the usage report does.
+func (s *organizationService) ListAbuseReports(orgID, reportStatus, category string, limit int) ([]models.AbuseReportAdminRow, error) {
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	query := db.Replica(s.db, "").Table("abuse_reports").
+		Select(`abuse_reports.id, abuse_reports.organization_id, abuse_reports.message_id,
+			abuse_reports.reporter_user_id, abuse_reports.category, abuse_reports.details,
+			abuse_reports.status, abuse_reports.created_at,
+			organizations.moderation_mode, organizations.abuse_flagged_at`).
+		Joins("JOIN organizations ON organizations.id = abuse_reports.organization_id")
+	if orgID != "" {
+		orgUUID, err := uuid.Parse(orgID)
+		if err != nil {
+			return nil, errors.New("invalid organization ID")
+		}
+		query = query.Where("abuse_reports.organization_id = ?", orgUUID)
+	}
+	if reportStatus != "" {
+		query = query.Where("abuse_reports.status = ?", reportStatus)
+	}
+	if category != "" {
+		query = query.Where("abuse_reports.category = ?", category)
+	}
+
+	var reports []models.AbuseReportAdminRow
+	if err := query.Order("abuse_reports.created_at DESC").Limit(limit).Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// UpdateSettings applies owner-editable org settings (default locale for new
+// members, member-directory visibility). Nil fields are untouched.
+func (s *organizationService) UpdateSettings(orgID string, req models.UpdateOrgSettingsRequest) error {
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		return errors.New("invalid organization ID")
+	}
+
+	updates := map[string]interface{}{}
+
+	if req.DefaultLocale != nil {
+		if !i18n.Supported(*req.DefaultLocale) {
+			return apperrors.Validation("locale is not supported")
+		}
+		updates["default_locale"] = i18n.Normalize(*req.DefaultLocale)
+	}
+	if req.MemberDirectoryVisible != nil {
+		updates["member_directory_visible"] = *req.MemberDirectoryVisible
+	}
+	if req.DefaultJoinRole != nil {
+		role := strings.ToLower(strings.TrimSpace(*req.DefaultJoinRole))
+		if role != string(constants.RoleMember) && role != string(constants.RoleMaintainer) {
+			return apperrors.Validation("default join role must be member or maintainer")
+		}
+		updates["default_join_role"] = role
+	}
+	if req.StorageQuotaBytes != nil {
+		if *req.StorageQuotaBytes < 0 {
+			return apperrors.Validation("storage quota cannot be negative")
+		}
+		updates["storage_quota_bytes"] = *req.StorageQuotaBytes
+	}
+	if req.Enforce2FA != nil {
+		updates["enforce_2fa"] = *req.Enforce2FA
+	}
+
+	if len(updates) == 0 {
+		return errors.New("at least one setting is required")
+	}
+
+	result := s.db.Model(&models.Organization{}).
+		Where("id = ?", orgUUID).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("organization not found")
+	}
+
+	cache.InvalidateOrganization(orgID)
+	return nil
+}
+
+// GetUsageReport aggregates inference usage into UTC day/month buckets from
+// the messages table and the token_usage_daily rollup in a single grouped
+// query. Ranges longer than 12 months are rejected.
+func (s *organizationService) GetUsageReport(orgID string, from, to time.Time, granularity string) ([]models.UsageReportBucket, error) {
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		return nil, errors.New("invalid organization ID")
+	}
+
+	if granularity != "day" && granularity != "month" {
+		return nil, errors.New("granularity must be day or month")
+	}
+	if !to.After(from) {
+		return nil, errors.New("'to' must be after 'from'")
+	}
+	if to.After(from.AddDate(1, 0, 0)) {
+		return nil, errors.New("date range cannot exceed 12 months")
+	}
+
+	from = from.UTC()
+	to = to.UTC()
+
+	// Usage reports scan months of messages/token rows — prime replica work
+	var buckets []models.UsageReportBucket
+	err = db.Replica(s.db, "").Raw(`
+		WITH msg AS (
+			SELECT date_trunc(?, created_at AT TIME ZONE 'UTC') AS bucket,
+			       COUNT(*) FILTER (WHERE role = 'user') AS predictions,
+			       COUNT(DISTINCT sender_user_id) AS active_users
+			FROM messages
+			WHERE organization_id = ? AND created_at >= ? AND created_at < ?
+			GROUP BY 1
+		), tok AS (
+			SELECT date_trunc(?, day::timestamp) AS bucket,
+			       SUM(prompt_tokens) AS prompt_tokens,
+			       SUM(completion_tokens) AS completion_tokens
+			FROM token_usage_daily
+			WHERE organization_id = ? AND day >= ?::date AND day < ?::date
+			GROUP BY 1
+		)
+		SELECT COALESCE(m.bucket, t.bucket) AS bucket,
+		       COALESCE(m.predictions, 0) AS predictions,
+		       COALESCE(t.prompt_tokens, 0) AS prompt_tokens,
+		       COALESCE(t.completion_tokens, 0) AS completion_tokens,
+		       COALESCE(m.active_users, 0) AS active_users
+		FROM msg m
+		FULL OUTER JOIN tok t ON m.bucket = t.bucket
+		ORDER BY bucket
+	`, granularity, orgUUID, from, to, granularity, orgUUID, from, to).Scan(&buckets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// GetOnboardingProgress serves the getting-started checklist from the
+// organization's stored onboarding_state. Completion is recorded as the
+// actions happen (see MarkOnboardingStep), so this is a single-row read —
+// no fan-out counting over users/documents/chats on every dashboard load.
+func (s *organizationService) GetOnboardingProgress(orgID string) (*models.OnboardingProgressResponse, error) {
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		return nil, errors.New("invalid organization ID")
+	}
+
+	var org models.Organization
+	if err := db.Replica(s.db, "").First(&org, "id = ?", orgUUID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("organization not found")
+		}
+		return nil, err
+	}
+
+	completed, dismissed := parseOnboardingState(org.OnboardingState)
+
+	steps := make([]models.OnboardingStep, 0, len(onboardingSteps))
+	for _, def := range onboardingSteps {
+		step := models.OnboardingStep{Key: def.Key, Label: def.Label}
+		if stamp, ok := completed[def.Key]; ok {
+			step.Complete = true
+			step.CompletedAt = stamp
+		}
+		steps = append(steps, step)
+	}
+
+	response := &models.OnboardingProgressResponse{Steps: steps, Total: len(steps), Dismissed: dismissed}
+	for _, step := range steps {
+		if step.Complete {
+			response.Completed++
+		}
+	}
+	return response, nil
+}
+
+// SetOnboardingDismissed hides (or un-hides) the checklist for the whole
+// organization. Stored in the same JSONB as the steps; setting it twice is
+// harmless.
+func (s *organizationService) SetOnboardingDismissed(orgID string, dismissed bool) error {
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		return errors.New("invalid organization ID")
+	}
+
+	result := s.db.Exec(`
+		UPDATE organizations
+		SET onboarding_state = jsonb_set(COALESCE(onboarding_state, '{}'::jsonb), '{dismissed}', to_jsonb(?::boolean), true)
+		WHERE id = ?
+	`, dismissed, orgUUID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("organization not found")
+	}
+	return nil
+}
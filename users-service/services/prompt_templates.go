@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PromptTemplateService interface {
+	CreatePromptTemplate(orgID, creatorID uuid.UUID, req models.CreatePromptTemplateRequest) (*models.PromptTemplateResponse, error)
+	GetPromptTemplates(orgID string) ([]models.PromptTemplateResponse, error)
+	GetPromptTemplate(orgID, templateID string) (*models.PromptTemplateResponse, error)
+	UpdatePromptTemplate(orgID, requestingUserID, requestingRole, templateID string, req models.UpdatePromptTemplateRequest) (*models.PromptTemplateResponse, error)
+	DeletePromptTemplate(orgID, requestingUserID, requestingRole, templateID string) error
+}
+
+type promptTemplateService struct {
+	db *gorm.DB
+}
+
+func NewPromptTemplateService(db *gorm.DB) PromptTemplateService {
+	return &promptTemplateService{db: db}
+}
+
+// placeholderPattern matches {{variable}} markers; rendering happens at query
+// time in chats-service, so both sides must agree on this syntax.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// templateVariables lists distinct placeholder names in body order.
+func templateVariables(body string) []string {
+	seen := map[string]bool{}
+	variables := []string{}
+	for _, match := range placeholderPattern.FindAllStringSubmatch(body, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			variables = append(variables, match[1])
+		}
+	}
+	return variables
+}
+
+// validateTemplateBody rejects bodies with malformed placeholders (an opening
+// {{ that never matches the pattern), which would silently leak raw markers
+// into the rendered prompt.
+func validateTemplateBody(body string) error {
+	stripped := placeholderPattern.ReplaceAllString(body, "")
+	if strings.Contains(stripped, "{{") || strings.Contains(stripped, "}}") {
+		return errors.New("template body has malformed placeholders; use {{variable_name}}")
+	}
+	return nil
+}
+
+func (s *promptTemplateService) CreatePromptTemplate(orgID, creatorID uuid.UUID, req models.CreatePromptTemplateRequest) (*models.PromptTemplateResponse, error) {
+	if err := validateTemplateBody(req.Body); err != nil {
+		return nil, err
+	}
+
+	template := models.PromptTemplate{
+		OrganizationID: orgID,
+		Name:           strings.TrimSpace(req.Name),
+		Body:           req.Body,
+		CreatedBy:      creatorID,
+	}
+	if template.Name == "" {
+		return nil, errors.New("template name cannot be empty")
+	}
+
+	if err := s.db.Create(&template).Error; err != nil {
+		return nil, err
+	}
+
+	resp := toPromptTemplateResponse(template)
+	return &resp, nil
+}
+
+func (s *promptTemplateService) GetPromptTemplates(orgID string) ([]models.PromptTemplateResponse, error) {
+	var templates []models.PromptTemplate
+	if err := s.db.
+		Where("organization_id = ?", orgID).
+		Order("name ASC").
+		Find(&templates).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.PromptTemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		responses = append(responses, toPromptTemplateResponse(template))
+	}
+	return responses, nil
+}
+
+func (s *promptTemplateService) GetPromptTemplate(orgID, templateID string) (*models.PromptTemplateResponse, error) {
+	template, err := s.findOrgTemplate(orgID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toPromptTemplateResponse(*template)
+	return &resp, nil
+}
+
+func (s *promptTemplateService) UpdatePromptTemplate(orgID, requestingUserID, requestingRole, templateID string, req models.UpdatePromptTemplateRequest) (*models.PromptTemplateResponse, error) {
+	if req.Name == nil && req.Body == nil {
+		return nil, errors.New("at least one field must be provided")
+	}
+
+	template, err := s.findOrgTemplate(orgID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTemplateWriteAccess(*template, requestingUserID, requestingRole); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("template name cannot be empty")
+		}
+		updates["name"] = name
+	}
+	if req.Body != nil {
+		if err := validateTemplateBody(*req.Body); err != nil {
+			return nil, err
+		}
+		updates["body"] = *req.Body
+	}
+
+	if err := s.db.Model(template).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	template, err = s.findOrgTemplate(orgID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	resp := toPromptTemplateResponse(*template)
+	return &resp, nil
+}
+
+func (s *promptTemplateService) DeletePromptTemplate(orgID, requestingUserID, requestingRole, templateID string) error {
+	template, err := s.findOrgTemplate(orgID, templateID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkTemplateWriteAccess(*template, requestingUserID, requestingRole); err != nil {
+		return err
+	}
+
+	return s.db.Delete(template).Error
+}
+
+// findOrgTemplate loads a template strictly within the caller's org; a
+// template from another org is indistinguishable from a missing one.
+func (s *promptTemplateService) findOrgTemplate(orgID, templateID string) (*models.PromptTemplate, error) {
+	var template models.PromptTemplate
+	if err := s.db.
+		Where("id = ? AND organization_id = ?", templateID, orgID).
+		First(&template).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("prompt template not found")
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// checkTemplateWriteAccess: the creator can edit their own templates; owners
+// and maintainers can edit any template in the org.
+func (s *promptTemplateService) checkTemplateWriteAccess(template models.PromptTemplate, requestingUserID, requestingRole string) error {
+	if requestingRole == "owner" || requestingRole == "maintainer" {
+		return nil
+	}
+	if template.CreatedBy.String() == requestingUserID {
+		return nil
+	}
+	return errors.New("only the template creator or an admin can modify this template")
+}
+
+func toPromptTemplateResponse(template models.PromptTemplate) models.PromptTemplateResponse {
+	return models.PromptTemplateResponse{
+		ID:        template.ID,
+		Name:      template.Name,
+		Body:      template.Body,
+		Variables: templateVariables(template.Body),
+		CreatedBy: template.CreatedBy,
+		CreatedAt: template.CreatedAt,
+		UpdatedAt: template.UpdatedAt,
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+func TestTemplateVariables(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{name: "no placeholders", body: "plain prompt", want: []string{}},
+		{name: "single placeholder", body: "Summarize {{document}}", want: []string{"document"}},
+		{name: "body order, deduplicated", body: "{{tone}} answer about {{topic}} in {{tone}}", want: []string{"tone", "topic"}},
+		{name: "whitespace inside markers", body: "{{ customer_name }}", want: []string{"customer_name"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := templateVariables(tc.body); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("templateVariables(%q) = %v, want %v", tc.body, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateTemplateBody(t *testing.T) {
+	for _, body := range []string{"plain", "Summarize {{document}} as {{tone}}"} {
+		if err := validateTemplateBody(body); err != nil {
+			t.Errorf("validateTemplateBody(%q) rejected a valid body: %v", body, err)
+		}
+	}
+	for _, body := range []string{"{{unterminated", "stray }} closer", "{{bad name}}"} {
+		if err := validateTemplateBody(body); err == nil {
+			t.Errorf("validateTemplateBody(%q) accepted a malformed body", body)
+		}
+	}
+}
+
+func TestPromptTemplatesAreOrgScoped(t *testing.T) {
+	h := newAuthHarness(t)
+	svc := NewPromptTemplateService(h.db)
+
+	org := testutil.NewOrganization(h.db).Active().Build()
+	creator := testutil.NewUser(h.db, org).Active().Build()
+	otherOrg := testutil.NewOrganization(h.db).Active().Build()
+
+	created, err := svc.CreatePromptTemplate(org.ID, creator.ID, models.CreatePromptTemplateRequest{
+		Name: "Summary",
+		Body: "Summarize {{document}}",
+	})
+	if err != nil {
+		t.Fatalf("CreatePromptTemplate failed: %v", err)
+	}
+	if !reflect.DeepEqual(created.Variables, []string{"document"}) {
+		t.Errorf("created template variables = %v, want [document]", created.Variables)
+	}
+
+	// A template from another org is indistinguishable from a missing one
+	if _, err := svc.GetPromptTemplate(otherOrg.ID.String(), created.ID.String()); err == nil {
+		t.Error("GetPromptTemplate leaked a template across orgs")
+	}
+	name := "Stolen"
+	if _, err := svc.UpdatePromptTemplate(otherOrg.ID.String(), creator.ID.String(), "owner", created.ID.String(),
+		models.UpdatePromptTemplateRequest{Name: &name}); err == nil {
+		t.Error("UpdatePromptTemplate modified a template across orgs")
+	}
+	if err := svc.DeletePromptTemplate(otherOrg.ID.String(), creator.ID.String(), "owner", created.ID.String()); err == nil {
+		t.Error("DeletePromptTemplate removed a template across orgs")
+	}
+
+	// The owning org still sees the untouched template
+	got, err := svc.GetPromptTemplate(org.ID.String(), created.ID.String())
+	if err != nil {
+		t.Fatalf("GetPromptTemplate in the owning org failed: %v", err)
+	}
+	if got.Name != "Summary" {
+		t.Errorf("template name = %q after cross-org attempts, want Summary", got.Name)
+	}
+}
+
+func TestPromptTemplateWriteAccess(t *testing.T) {
+	h := newAuthHarness(t)
+	svc := NewPromptTemplateService(h.db)
+
+	org := testutil.NewOrganization(h.db).Active().Build()
+	creator := testutil.NewUser(h.db, org).Active().Build()
+	colleague := testutil.NewUser(h.db, org).Active().Build()
+	maintainer := testutil.NewUser(h.db, org).Maintainer().Active().Build()
+
+	created, err := svc.CreatePromptTemplate(org.ID, creator.ID, models.CreatePromptTemplateRequest{
+		Name: "Summary",
+		Body: "Summarize {{document}}",
+	})
+	if err != nil {
+		t.Fatalf("CreatePromptTemplate failed: %v", err)
+	}
+
+	name := "Renamed"
+	if _, err := svc.UpdatePromptTemplate(org.ID.String(), colleague.ID.String(), "member", created.ID.String(),
+		models.UpdatePromptTemplateRequest{Name: &name}); err == nil {
+		t.Error("a member who didn't create the template was allowed to edit it")
+	}
+	if _, err := svc.UpdatePromptTemplate(org.ID.String(), creator.ID.String(), "member", created.ID.String(),
+		models.UpdatePromptTemplateRequest{Name: &name}); err != nil {
+		t.Errorf("the creator was denied editing their own template: %v", err)
+	}
+	if err := svc.DeletePromptTemplate(org.ID.String(), maintainer.ID.String(), "maintainer", created.ID.String()); err != nil {
+		t.Errorf("a maintainer was denied deleting an org template: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/constants"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+// backdateLastSend moves a user's updated_at past the resend cooldown, as if
+// their last invite went out long ago.
+func backdateLastSend(t *testing.T, h *authHarness, user models.User) {
+	t.Helper()
+	past := time.Now().UTC().Add(-constants.ResendInviteCooldown - time.Minute)
+	// Raw SQL: the BeforeUpdate hook would re-stamp updated_at on a GORM update
+	if err := h.db.Exec("UPDATE users SET updated_at = ? WHERE id = ?", past, user.ID).Error; err != nil {
+		t.Fatalf("failed to backdate user: %v", err)
+	}
+}
+
+func TestResendAllInvitesOnlyTouchesPendingUsers(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	pendingOne := testutil.NewUser(h.db, org).Pending(72 * time.Hour).Build()
+	pendingTwo := testutil.NewUser(h.db, org).Pending(72 * time.Hour).Build()
+	active := testutil.NewUser(h.db, org).Active().Build()
+	suspended := testutil.NewUser(h.db, org).Suspended().Build()
+	backdateLastSend(t, h, pendingOne)
+	backdateLastSend(t, h, pendingTwo)
+
+	resp, err := h.users.ResendAllInvites(org.ID.String())
+	if err != nil {
+		t.Fatalf("ResendAllInvites failed: %v", err)
+	}
+
+	if resp.Total != 2 {
+		t.Errorf("total = %d, want 2 pending users", resp.Total)
+	}
+	queued := 0
+	for _, result := range resp.Results {
+		if result.Status == "queued" {
+			queued++
+		}
+		if result.UserID == active.ID.String() || result.UserID == suspended.ID.String() {
+			t.Errorf("non-pending user %s appears in the results", result.Email)
+		}
+	}
+	if queued != 2 {
+		t.Errorf("queued %d invites, want 2", queued)
+	}
+
+	// Pending users got fresh tokens; everyone else is untouched
+	var refreshed models.User
+	if err := h.db.First(&refreshed, "id = ?", pendingOne.ID).Error; err != nil {
+		t.Fatalf("user lookup failed: %v", err)
+	}
+	if refreshed.InviteToken == nil || *refreshed.InviteToken == *pendingOne.InviteToken {
+		t.Error("pending user's invite token was not regenerated")
+	}
+
+	var untouchedActive models.User
+	if err := h.db.First(&untouchedActive, "id = ?", active.ID).Error; err != nil {
+		t.Fatalf("user lookup failed: %v", err)
+	}
+	if untouchedActive.InviteToken != nil {
+		t.Error("active user was given an invite token")
+	}
+}
+
+func TestResendAllInvitesRespectsTheCooldown(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	recent := testutil.NewUser(h.db, org).Pending(72 * time.Hour).Build()
+	overdue := testutil.NewUser(h.db, org).Pending(72 * time.Hour).Build()
+	backdateLastSend(t, h, overdue)
+
+	resp, err := h.users.ResendAllInvites(org.ID.String())
+	if err != nil {
+		t.Fatalf("ResendAllInvites failed: %v", err)
+	}
+
+	if resp.Total != 2 || resp.Skipped != 1 {
+		t.Fatalf("total/skipped = %d/%d, want 2/1", resp.Total, resp.Skipped)
+	}
+	for _, result := range resp.Results {
+		switch result.UserID {
+		case recent.ID.String():
+			if result.Status != "skipped" {
+				t.Errorf("recently invited user status = %q, want skipped", result.Status)
+			}
+		case overdue.ID.String():
+			if result.Status != "queued" {
+				t.Errorf("overdue user status = %q, want queued", result.Status)
+			}
+		}
+	}
+
+	// The skipped user's token survived untouched
+	var still models.User
+	if err := h.db.First(&still, "id = ?", recent.ID).Error; err != nil {
+		t.Fatalf("user lookup failed: %v", err)
+	}
+	if still.InviteToken == nil || *still.InviteToken != *recent.InviteToken {
+		t.Error("a cooled-down user's token was regenerated anyway")
+	}
+}
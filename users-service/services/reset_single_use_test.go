@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+func tryLogin(h *authHarness, email, password, accountID string) error {
+	_, err := h.auth.Login(context.Background(), &models.LoginRequest{
+		Email:     email,
+		Password:  password,
+		AccountID: accountID,
+	})
+	return err
+}
+
+func resetTokenFor(t *testing.T, h *authHarness, email, accountID string) string {
+	t.Helper()
+	if _, err := h.auth.ForgotPassword(email, accountID); err != nil {
+		t.Fatalf("ForgotPassword failed: %v", err)
+	}
+	return h.lastEmailToken(t, "token")
+}
+
+func TestResetTokenIsSingleUse(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	user := testutil.NewUser(h.db, org).Active().Build()
+
+	token := resetTokenFor(t, h, user.Email, org.AccountID)
+
+	if _, err := h.auth.ResetPasswordByEmail(token, "new-password-456"); err != nil {
+		t.Fatalf("first reset failed: %v", err)
+	}
+
+	// The consumed token must not work a second time, whatever the password
+	if _, err := h.auth.ResetPasswordByEmail(token, "attacker-password-789"); err == nil {
+		t.Fatal("a consumed reset token was accepted again")
+	}
+
+	// Only the first reset's password works
+	if err := tryLogin(h, user.Email, "new-password-456", org.AccountID); err != nil {
+		t.Errorf("login with the reset password failed: %v", err)
+	}
+	if err := tryLogin(h, user.Email, testutil.FixturePassword, org.AccountID); err == nil {
+		t.Error("the pre-reset password still works")
+	}
+	if err := tryLogin(h, user.Email, "attacker-password-789", org.AccountID); err == nil {
+		t.Error("the replayed reset's password works")
+	}
+}
+
+func TestSimultaneousResetsYieldExactlyOneSuccess(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	user := testutil.NewUser(h.db, org).Active().Build()
+
+	token := resetTokenFor(t, h, user.Email, org.AccountID)
+
+	var wg sync.WaitGroup
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := h.auth.ResetPasswordByEmail(token, "race-password-123")
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	successes := 0
+	for err := range results {
+		if err == nil {
+			successes++
+		}
+	}
+	if successes != 1 {
+		t.Fatalf("%d resets succeeded with one token, want exactly 1", successes)
+	}
+}
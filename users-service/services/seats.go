@@ -0,0 +1,40 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ensureSeatAvailable enforces the org's seat limit. It must run inside the
+// same transaction as the user insert: the SELECT ... FOR UPDATE on the org
+// row serializes concurrent invites/joins so they can't overshoot the limit.
+// Seats are counted as active + pending members — suspending or deleting a
+// user frees its seat immediately.
+func ensureSeatAvailable(tx *gorm.DB, orgID uuid.UUID) error {
+	var org models.Organization
+	if err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&org, "id = ?", orgID).Error; err != nil {
+		return err
+	}
+
+	if org.SeatLimit == nil {
+		return nil
+	}
+
+	var used int64
+	if err := tx.Model(&models.User{}).
+		Where("organization_id = ? AND status IN ?", orgID, []string{"active", "pending"}).
+		Count(&used).Error; err != nil {
+		return err
+	}
+
+	if used >= int64(*org.SeatLimit) {
+		return fmt.Errorf("SEAT_LIMIT_REACHED: %d of %d seats in use", used, *org.SeatLimit)
+	}
+	return nil
+}
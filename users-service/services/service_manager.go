@@ -8,12 +8,20 @@ type ServiceManager struct {
 	AuthenticationService AuthenticationService
 	UserService           UserService
 	OrganizationService   OrganizationService
+	InviteLinkService     InviteLinkService
+	PromptTemplateService PromptTemplateService
+	OrgRoleService        OrgRoleService
+	EmailService          EmailService
 }
 
 func NewServiceManager(db *gorm.DB) *ServiceManager {
 	return &ServiceManager{
-		AuthenticationService: NewAuthenticationService(db),
+		AuthenticationService: NewAuthenticationService(db, nil, nil),
 		UserService:           NewUserService(db),
 		OrganizationService:   NewOrganizationService(db),
+		InviteLinkService:     NewInviteLinkService(db),
+		PromptTemplateService: NewPromptTemplateService(db),
+		OrgRoleService:        NewOrgRoleService(db),
+		EmailService:          NewEmailService(db),
 	}
 }
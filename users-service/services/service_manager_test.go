@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+// Smoke test: the manager is the single composition point routes depend on,
+// so every service it carries must come back constructed.
+func TestNewServiceManagerWiresEveryService(t *testing.T) {
+	sm := NewServiceManager(testutil.NewTestDB(t))
+
+	if sm.AuthenticationService == nil {
+		t.Error("AuthenticationService is nil")
+	}
+	if sm.UserService == nil {
+		t.Error("UserService is nil")
+	}
+	if sm.OrganizationService == nil {
+		t.Error("OrganizationService is nil")
+	}
+	if sm.InviteLinkService == nil {
+		t.Error("InviteLinkService is nil")
+	}
+	if sm.PromptTemplateService == nil {
+		t.Error("PromptTemplateService is nil")
+	}
+	if sm.OrgRoleService == nil {
+		t.Error("OrgRoleService is nil")
+	}
+	if sm.EmailService == nil {
+		t.Error("EmailService is nil")
+	}
+}
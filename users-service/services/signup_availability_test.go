@@ -0,0 +1,28 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+func TestOrganizationNameAvailability(t *testing.T) {
+	h := newAuthHarness(t)
+	testutil.NewOrganization(h.db).Active().Build() // "Test Organization"
+
+	available, err := h.auth.OrganizationNameAvailable("Test Organization")
+	if err != nil {
+		t.Fatalf("availability check failed: %v", err)
+	}
+	if available {
+		t.Error("a taken org name was reported available")
+	}
+
+	available, err = h.auth.OrganizationNameAvailable("Entirely New Org")
+	if err != nil {
+		t.Fatalf("availability check failed: %v", err)
+	}
+	if !available {
+		t.Error("an unused org name was reported taken")
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+func strptr(s string) *string { return &s }
+
+func TestUpdateUserAppliesCombinedEdit(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+	member := testutil.NewUser(h.db, org).Active().Build()
+
+	updated, err := h.users.UpdateUser(org.ID.String(), owner.ID.String(), "owner", member.ID.String(),
+		models.UpdateUserRequest{
+			Name: strptr("Promoted Person"),
+			Role: strptr("maintainer"),
+		})
+	if err != nil {
+		t.Fatalf("UpdateUser failed: %v", err)
+	}
+	if updated.Name != "Promoted Person" || updated.Role != "maintainer" {
+		t.Fatalf("combined edit not applied: %+v", updated)
+	}
+
+	// The role change invalidated previously issued tokens
+	var after models.User
+	if err := h.db.First(&after, "id = ?", member.ID).Error; err != nil {
+		t.Fatalf("user lookup failed: %v", err)
+	}
+	if after.TokenVersion != member.TokenVersion+1 {
+		t.Errorf("token version = %d, want %d after a role change", after.TokenVersion, member.TokenVersion+1)
+	}
+}
+
+func TestUpdateUserHierarchy(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+	owner := testutil.NewUser(h.db, org).Owner().Active().Build()
+	secondOwner := testutil.NewUser(h.db, org).Owner().Active().Build()
+	maintainer := testutil.NewUser(h.db, org).Maintainer().Active().Build()
+	member := testutil.NewUser(h.db, org).Active().Build()
+
+	cases := []struct {
+		name       string
+		requester  models.User
+		role       string
+		target     models.User
+		req        models.UpdateUserRequest
+		wantErrSub string
+	}{
+		{
+			name: "owner cannot be edited", requester: owner, role: "owner", target: secondOwner,
+			req: models.UpdateUserRequest{Name: strptr("X")}, wantErrSub: "owner cannot be edited",
+		},
+		{
+			name: "maintainer cannot edit a maintainer", requester: maintainer, role: "maintainer", target: maintainer,
+			req: models.UpdateUserRequest{Name: strptr("X")}, wantErrSub: "not authorized",
+		},
+		{
+			name: "member cannot edit anyone", requester: member, role: "member", target: member,
+			req: models.UpdateUserRequest{Name: strptr("X")}, wantErrSub: "not authorized",
+		},
+		{
+			name: "maintainer cannot change roles", requester: maintainer, role: "maintainer", target: member,
+			req: models.UpdateUserRequest{Role: strptr("maintainer")}, wantErrSub: "only owner",
+		},
+		{
+			name: "nobody is promoted to owner", requester: owner, role: "owner", target: member,
+			req: models.UpdateUserRequest{Role: strptr("owner")}, wantErrSub: "cannot promote",
+		},
+		{
+			name: "unknown roles are rejected", requester: owner, role: "owner", target: member,
+			req: models.UpdateUserRequest{Role: strptr("superuser")}, wantErrSub: "custom role",
+		},
+		{
+			name: "empty edits are rejected", requester: owner, role: "owner", target: member,
+			req: models.UpdateUserRequest{}, wantErrSub: "at least one",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := h.users.UpdateUser(org.ID.String(), tc.requester.ID.String(), tc.role, tc.target.ID.String(), tc.req)
+			if err == nil {
+				t.Fatal("UpdateUser succeeded, want an error")
+			}
+			if !strings.Contains(err.Error(), tc.wantErrSub) {
+				t.Errorf("err = %q, want it to mention %q", err, tc.wantErrSub)
+			}
+		})
+	}
+
+	// A maintainer may still rename a member
+	if _, err := h.users.UpdateUser(org.ID.String(), maintainer.ID.String(), "maintainer", member.ID.String(),
+		models.UpdateUserRequest{Name: strptr("Renamed By Maintainer")}); err != nil {
+		t.Errorf("maintainer renaming a member failed: %v", err)
+	}
+}
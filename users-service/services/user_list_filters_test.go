@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+)
+
+func TestUserListCreationWindow(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Active().Build()
+
+	// Three members created ten, five and one day(s) ago; BeforeCreate stamps
+	// now, so the rows are backdated directly
+	ages := map[string]int{"Old Member": 10, "Mid Member": 5, "New Member": 1}
+	for name, days := range ages {
+		user := testutil.NewUser(h.db, org).Active().Named(name).Build()
+		past := time.Now().UTC().AddDate(0, 0, -days)
+		if err := h.db.Exec("UPDATE users SET created_at = ? WHERE id = ?", past, user.ID).Error; err != nil {
+			t.Fatalf("failed to backdate %s: %v", name, err)
+		}
+	}
+
+	names := func(after, before *time.Time) map[string]bool {
+		t.Helper()
+		users, err := h.users.GetUsersByOrganization(org.ID.String(), "", after, before)
+		if err != nil {
+			t.Fatalf("GetUsersByOrganization failed: %v", err)
+		}
+		got := make(map[string]bool, len(users))
+		for _, u := range users {
+			got[u.Name] = true
+		}
+		return got
+	}
+	daysAgo := func(d int) *time.Time {
+		ts := time.Now().UTC().AddDate(0, 0, -d)
+		return &ts
+	}
+
+	// Both bounds: only the middle user falls in the 7..3-days-ago window
+	got := names(daysAgo(7), daysAgo(3))
+	if len(got) != 1 || !got["Mid Member"] {
+		t.Errorf("bounded window returned %v, want only Mid Member", got)
+	}
+
+	// Open-ended: only created_after drops the oldest user
+	got = names(daysAgo(7), nil)
+	if len(got) != 2 || got["Old Member"] {
+		t.Errorf("open-ended window returned %v, want Mid and New Member", got)
+	}
+
+	// No bounds: the full org listing
+	if got = names(nil, nil); len(got) != 3 {
+		t.Errorf("unbounded listing returned %v, want all three members", got)
+	}
+}
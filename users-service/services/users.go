@@ -6,20 +6,37 @@ import (
 	"os"
 	"time"
 
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/cache"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/constants"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/db"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/events"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/i18n"
 	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/outbox"
 	utils "github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserService interface {
 	InviteUser(inviterID uuid.UUID, inviterRole string, orgID uuid.UUID, req models.InviteUserRequest) (*models.InviteUserResponse, error)
 	ResendVerificationEmail(accountID string, email string) error
-	GetUsersByOrganization(orgID string) ([]models.UserResponse, error)
+	GetUsersByOrganization(orgID string, orderBy string, createdAfter, createdBefore *time.Time) ([]models.UserResponse, error)
+	GetMemberDirectory(orgID, requesterRole, search string, page, limit int) (*models.MemberDirectoryResponse, error)
 	GetUserByID(orgID, userID string) (*models.UserResponse, error)
 	DeleteUser(orgID, requestingUserID, requestingRole, targetUserID string) error
 	SuspendUser(orgID, requestingUserID, requestingRole, targetUserID string) error
+	UpdateUser(orgID, requestingUserID, requestingRole, targetUserID string, req models.UpdateUserRequest) (*models.UserResponse, error)
+	UpdateProfile(userID string, req models.UpdateProfileRequest) (*models.UserResponse, error)
+	MarkEmailsBounced(emails []string) (int64, error)
+	ResolveUsers(ids []string) (*models.ResolveUsersResponse, error)
+	ResendAllInvites(orgID string) (*models.ResendAllInvitesResponse, error)
+	GetInviteStats(orgID string, days int) (*models.InviteStatsResponse, error)
+	AnonymizeUser(claims *utils.JWTClaims, targetUserID string) error
+	ForceActivateUser(claims *utils.JWTClaims, targetUserID string) error
+	ExportPersonalData(claims *utils.JWTClaims) (*models.PersonalDataExport, error)
 }
 
 type userService struct {
@@ -31,27 +48,32 @@ func NewUserService(db *gorm.DB) UserService {
 }
 
 func (s *userService) InviteUser(inviterID uuid.UUID, inviterRole string, orgID uuid.UUID, req models.InviteUserRequest) (*models.InviteUserResponse, error) {
-	// 1️⃣ Role-based rules
+	req.Email = utils.NormalizeEmail(req.Email)
+
+	// 1️⃣ Role-based rules; owners may also invite into custom org roles
 	switch inviterRole {
 	case "owner":
-		if req.Role != "maintainer" && req.Role != "member" {
-			return nil, errors.New("owner can invite only maintainer or member")
+		if req.Role == "owner" {
+			return nil, apperrors.Forbidden("cannot invite another owner")
+		}
+		if req.Role != "maintainer" && req.Role != "member" && !orgRoleExists(s.db, orgID, req.Role) {
+			return nil, apperrors.Forbidden("role must be maintainer, member or a custom role defined for this organization")
 		}
 	case "maintainer":
 		if req.Role != "member" {
-			return nil, errors.New("maintainer can invite only member")
+			return nil, apperrors.Forbidden("maintainer can invite only member")
 		}
 	default:
-		return nil, errors.New("members cannot invite users")
+		return nil, apperrors.Forbidden("members cannot invite users")
 	}
 
 	// 2️⃣ Check if user already exists
 	var existing models.User
-	if err := s.db.Where("organization_id = ? AND email = ?", orgID, req.Email).First(&existing).Error; err == nil {
+	if err := s.db.Where("organization_id = ? AND LOWER(email) = ?", orgID, req.Email).First(&existing).Error; err == nil {
 		if existing.Status == "active" {
-			return nil, errors.New("user already exists and is active")
+			return nil, apperrors.Conflict("user already exists and is active").WithCode("user_already_active")
 		}
-		return nil, errors.New("user has already been invited")
+		return nil, apperrors.Conflict("user has already been invited").WithCode("user_already_invited")
 	}
 
 	// 3️⃣ Generate temporary password
@@ -59,28 +81,24 @@ func (s *userService) InviteUser(inviterID uuid.UUID, inviterRole string, orgID
 	if err != nil {
 		return nil, errors.New("failed to create temporary password")
 	}
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(tempPassword), bcrypt.DefaultCost)
+	hashedPassword, _ := utils.HashPassword(tempPassword)
 
 	// 4️⃣ Create invite token
 	inviteToken := uuid.NewString()
-	expiresAt := time.Now().Add(48 * time.Hour)
+	expiresAt := time.Now().UTC().Add(constants.InviteTokenTTL)
 
 	// 5️⃣ Create new user record
 	newUser := &models.User{
-		ID:             uuid.New(),
 		OrganizationID: orgID,
 		Name:           req.Name,
 		Email:          req.Email,
 		Role:           req.Role,
 		Status:         "pending",
+		Locale:         inviteLocale(s.db, orgID, req.Locale),
 		InvitedBy:      &inviterID,
 		InviteToken:    &inviteToken,
 		ExpiresAt:      &expiresAt,
-		Password:       string(hashedPassword),
-	}
-
-	if err := s.db.Create(newUser).Error; err != nil {
-		return nil, err
+		Password:       hashedPassword,
 	}
 
 	// 6️⃣ Fetch inviter name and organization name for email
@@ -88,41 +106,70 @@ func (s *userService) InviteUser(inviterID uuid.UUID, inviterRole string, orgID
 	s.db.Select("name").Where("id = ?", inviterID).First(&inviter)
 
 	var org models.Organization
-	s.db.Select("name, account_id").Where("id = ?", orgID).First(&org)
+	s.db.Select("name, account_id, brand_logo_url, brand_primary_color, email_footer_text").Where("id = ?", orgID).First(&org)
+
+	// Seat check, insert and the invitation email's outbox row share a
+	// transaction: concurrent invites serialize on the org row, and a crash
+	// after commit can't lose the email. The link carries a signed wrapper,
+	// not the raw token.
+	var inviteLink string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureSeatAvailable(tx, orgID); err != nil {
+			return err
+		}
+		if err := tx.Create(newUser).Error; err != nil {
+			return err
+		}
 
-	// 7️⃣ Send invitation email asynchronously
-	frontendURL := os.Getenv("FRONTEND_BASE_URL")
-	inviteLink := fmt.Sprintf("%s/pl/accept-invite?token=%s&account_id=%s", frontendURL, inviteToken, org.AccountID)
-	go func() {
-		emailBody := fmt.Sprintf(`
-		<h2>You're invited to join %s!</h2>
-		<p>Hi %s,</p>
-		<p>%s has invited you to join the organization <strong>%s</strong>.</p>
-		<p>Click the button below to accept the invitation and set your password:</p>
-		<a href="%s" style="background:#4F46E5;color:white;padding:10px 20px;border-radius:6px;text-decoration:none;">Accept Invitation</a>
-		<p>This link will expire in 48 hours.</p>
-		<p><strong>Note:</strong> When logging in, please use the following account ID: <code>%s</code></p>
-	`, org.Name, newUser.Name, inviter.Name, org.Name, inviteLink, org.AccountID)
-
-		emailSender := utils.NewEmailSender()
-		if err := emailSender.SendEmail(newUser.Email, "You're invited to join "+org.Name, emailBody); err != nil {
-			fmt.Printf("[WARN] Failed to send invite email: %v\n", err)
-		}
-	}()
-
-	// 8️⃣ Return response
-	return &models.InviteUserResponse{
-		UserID:     newUser.ID,
-		Email:      newUser.Email,
-		Name:       newUser.Name,
-		Role:       newUser.Role,
-		Status:     newUser.Status,
-		ExpiresAt:  newUser.ExpiresAt,
-		InviteLink: inviteLink,
-	}, nil
+		frontendURL := os.Getenv("FRONTEND_BASE_URL")
+		signedToken := utils.SignActionToken(utils.LinkPurposeInvite, newUser.ID.String(), inviteToken, expiresAt)
+		inviteLink = fmt.Sprintf("%s/pl/accept-invite?token=%s&account_id=%s", frontendURL, signedToken, org.AccountID)
+
+		emailSubject, emailBody, _ := utils.RenderEmail("invite", newUser.Locale, utils.EmailTemplateData{
+			Name:             newUser.Name,
+			OrganizationName: org.Name,
+			AccountID:        org.AccountID,
+			Link:             inviteLink,
+			InviterName:      inviter.Name,
+			Branding:         orgBranding(org),
+		})
+
+		return outbox.Enqueue(tx, &orgID, "invite", newUser.Email, emailSubject, emailBody)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events.Publish(events.Event{
+		Type:    "user.invited",
+		OrgID:   orgID.String(),
+		ActorID: inviterID.String(),
+		Payload: map[string]interface{}{
+			"user_id": newUser.ID.String(),
+			"email":   newUser.Email,
+			"role":    newUser.Role,
+		},
+	})
+
+	// 8️⃣ Return response (invite link only goes out via email)
+	res := &models.InviteUserResponse{
+		UserID:    newUser.ID,
+		Email:     newUser.Email,
+		Name:      newUser.Name,
+		Role:      newUser.Role,
+		Status:    newUser.Status,
+		ExpiresAt: newUser.ExpiresAt,
+	}
+	if devExposeTokens() {
+		res.InviteLink = inviteLink
+	}
+	res.EmailWarning = emailDeliveryWarning(s.db, orgID.String())
+	return res, nil
 }
 
 func (s *userService) ResendVerificationEmail(accountID string, email string) error {
+	accountID = utils.NormalizeAccountID(accountID)
+
 	var org models.Organization
 	if err := s.db.Where("account_id = ?", accountID).First(&org).Error; err != nil {
 		return errors.New("organization not found for this account ID")
@@ -130,7 +177,7 @@ func (s *userService) ResendVerificationEmail(accountID string, email string) er
 
 	var user models.User
 	if err := s.db.
-		Where("email = ? AND organization_id = ? AND status IN ?", email, org.ID, []string{"pending", "suspended"}).
+		Where("LOWER(email) = ? AND organization_id = ? AND status IN ?", utils.NormalizeEmail(email), org.ID, []string{"pending", "suspended"}).
 		First(&user).Error; err != nil {
 		return errors.New("no pending or suspended user found with this email for the given account")
 	}
@@ -140,7 +187,7 @@ func (s *userService) ResendVerificationEmail(accountID string, email string) er
 
 	// Always regenerate a fresh token
 	token, _ := utils.GenerateSecureToken(32)
-	expiresAt := time.Now().Add(1 * time.Hour)
+	expiresAt := time.Now().UTC().Add(constants.VerifyTokenTTL)
 	user.InviteToken = &token
 	user.ExpiresAt = &expiresAt
 	if wasSuspended {
@@ -149,9 +196,13 @@ func (s *userService) ResendVerificationEmail(accountID string, email string) er
 	if err := s.db.Save(&user).Error; err != nil {
 		return err
 	}
+	if wasSuspended {
+		cache.InvalidateUser(user.ID.String())
+	}
 
 	frontendURL := os.Getenv("FRONTEND_BASE_URL")
-	verifyLink := fmt.Sprintf("%s/pl/verify-account?token=%s", frontendURL, token)
+	signedToken := utils.SignActionToken(utils.LinkPurposeVerify, user.ID.String(), token, expiresAt)
+	verifyLink := fmt.Sprintf("%s/pl/verify-account?token=%s", frontendURL, signedToken)
 
 	var emailSubject, emailBody string
 	if wasSuspended {
@@ -165,60 +216,119 @@ func (s *userService) ResendVerificationEmail(accountID string, email string) er
 		<p>This link will expire in 1 hour.</p>
 	`, org.Name, user.Name, org.Name, verifyLink)
 	} else {
-		emailSubject = "Verify Your Account"
-		emailBody = fmt.Sprintf(`
-		<h2>Account Verification</h2>
-		<p>Hello %s,</p>
-		<p>Please verify your account for organization <strong>%s</strong> by clicking below:</p>
-		<a href="%s" style="background:#4F46E5;color:white;padding:10px 20px;border-radius:6px;text-decoration:none;">Verify Account</a>
-		<p>This link will expire in 1 hour.</p>
-	`, user.Name, org.Name, verifyLink)
+		emailSubject, emailBody, _ = utils.RenderEmail("verify", user.Locale, utils.EmailTemplateData{
+			Name:             user.Name,
+			OrganizationName: org.Name,
+			AccountID:        org.AccountID,
+			Link:             verifyLink,
+			Branding:         orgBranding(org),
+		})
 	}
 
 	emailSender := utils.NewEmailSender()
 	return emailSender.SendEmail(user.Email, emailSubject, emailBody)
 }
 
+// orgUsersQuery is the one base query for listing an organization's users;
+// every listing (admin view, member directory) layers projections and
+// filters on top of it instead of diverging.
+func orgUsersQuery(db *gorm.DB, orgUUID uuid.UUID) *gorm.DB {
+	return db.Model(&models.User{}).Where("organization_id = ?", orgUUID)
+}
+
 // ==============================
 // Get Users by Organization
 // ==============================
-func (s *userService) GetUsersByOrganization(orgID string) ([]models.UserResponse, error) {
+func (s *userService) GetUsersByOrganization(orgID string, orderBy string, createdAfter, createdBefore *time.Time) ([]models.UserResponse, error) {
 
 	orgUUID, err := uuid.Parse(orgID)
 	if err != nil {
 		return nil, errors.New("invalid organization id")
 	}
 
+	// orderBy comes from utils.ParseSort, so it is allowlisted
+	if orderBy == "" {
+		orderBy = "created_at DESC"
+	}
+
+	query := orgUsersQuery(db.Replica(s.db, ""), orgUUID)
+
+	// Optional creation-time window; either bound may be open
+	if createdAfter != nil {
+		query = query.Where("created_at >= ?", *createdAfter)
+	}
+	if createdBefore != nil {
+		query = query.Where("created_at <= ?", *createdBefore)
+	}
+
 	var users []models.User
 
-	err = s.db.
-		Where("organization_id = ? AND is_deleted = false", orgUUID).
-		Order("created_at DESC").
+	err = query.
+		Order(orderBy).
 		Find(&users).Error
 
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert to response
-	var response []models.UserResponse
-	for _, u := range users {
-		response = append(response, models.UserResponse{
-			ID:             u.ID,
-			OrganizationID: u.OrganizationID,
-			Name:           u.Name,
-			Email:          u.Email,
-			Role:           u.Role,
-			Status:         u.Status,
-			InvitedBy:      u.InvitedBy,
-			ExpiresAt:      u.ExpiresAt,
-			ReactivatedAt:  u.ReactivatedAt,
-			CreatedAt:      u.CreatedAt,
-			UpdatedAt:      u.UpdatedAt,
-		})
+	// Convert to sanitized DTOs
+	return models.ToUserResponses(users), nil
+}
+
+// GetMemberDirectory is the privacy-trimmed listing every active user may
+// browse (for @-mentions and sharing): active users only, projected down to
+// id/name/role — no emails. Owners can hide it from regular members via the
+// member_directory_visible org setting; owners and maintainers always see it
+// (they have the full listing anyway).
+func (s *userService) GetMemberDirectory(orgID, requesterRole, search string, page, limit int) (*models.MemberDirectoryResponse, error) {
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		return nil, errors.New("invalid organization id")
 	}
 
-	return response, nil
+	if requesterRole != string(constants.RoleOwner) && requesterRole != string(constants.RoleMaintainer) {
+		var org models.Organization
+		if err := s.db.Select("member_directory_visible").Where("id = ?", orgUUID).First(&org).Error; err != nil {
+			return nil, errors.New("organization not found")
+		}
+		if !org.MemberDirectoryVisible {
+			return nil, apperrors.Forbidden("the member directory is disabled for this organization")
+		}
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	query := orgUsersQuery(db.Replica(s.db, ""), orgUUID).Where("status = ?", "active")
+	if search != "" {
+		query = query.Where("name ILIKE ?", "%"+search+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	members := []models.MemberDirectoryEntry{}
+	if err := query.
+		Select("id, name, role").
+		Order("name ASC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Scan(&members).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.MemberDirectoryResponse{
+		Members: members,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+	}, nil
 }
 
 // ==============================
@@ -234,7 +344,7 @@ func (s *userService) GetUserByID(orgID, userID string) (*models.UserResponse, e
 	var user models.User
 
 	err = s.db.
-		Where("organization_id = ? AND id = ? AND is_deleted = false", orgID, userUUID).
+		Where("organization_id = ? AND id = ?", orgID, userUUID).
 		First(&user).Error
 
 	if err != nil {
@@ -244,20 +354,7 @@ func (s *userService) GetUserByID(orgID, userID string) (*models.UserResponse, e
 		return nil, err
 	}
 
-	response := models.UserResponse{
-		ID:             user.ID,
-		OrganizationID: user.OrganizationID,
-		Name:           user.Name,
-		Email:          user.Email,
-		Role:           user.Role,
-		Status:         user.Status,
-		InvitedBy:      user.InvitedBy,
-		ExpiresAt:      user.ExpiresAt,
-		ReactivatedAt:  user.ReactivatedAt,
-		CreatedAt:      user.CreatedAt,
-		UpdatedAt:      user.UpdatedAt,
-	}
-
+	response := models.ToUserResponse(user)
 	return &response, nil
 }
 
@@ -313,16 +410,21 @@ func (s *userService) DeleteUser(orgID, requestingUserID, requestingRole, target
 		return errors.New("not authorized to delete users")
 	}
 
-	// ✅ Soft delete recommended
-	err = s.db.Model(&models.User{}).
-		Where("id = ?", targetUUID).
-		Updates(map[string]interface{}{
-			"is_deleted": true,
-			"deleted_at": time.Now(),
-			"deleted_by": requestingUserID,
-		}).Error
+	// ✅ Soft delete via gorm.DeletedAt; record who deleted first
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).
+			Where("id = ?", targetUUID).
+			Update("deleted_by", requestingUserID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.User{}, "id = ?", targetUUID).Error
+	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	cache.InvalidateUser(targetUserID)
+	return nil
 }
 
 func (s *userService) SuspendUser(orgID, requestingUserID, requestingRole, targetUserID string) error {
@@ -382,10 +484,512 @@ func (s *userService) SuspendUser(orgID, requestingUserID, requestingRole, targe
 		return errors.New("user already suspended")
 	}
 
-	// ✅ Suspend
-	return s.db.Model(&models.User{}).
+	// ✅ Suspend; drop the auth cache entry so in-flight tokens stop
+	// validating immediately rather than after the TTL
+	if err := s.db.Model(&models.User{}).
 		Where("id = ?", targetUUID).
 		Updates(map[string]interface{}{
 			"status": "suspended",
+		}).Error; err != nil {
+		return err
+	}
+
+	cache.InvalidateUser(targetUserID)
+	return nil
+}
+
+// MarkEmailsBounced flags every user whose email appears in a provider
+// bounce notification, so admins can see the invite never arrived.
+// UpdateUser applies a combined name/role/status edit in one transaction,
+// enforcing per-field authorization. TokenVersion is bumped when role or
+// status changes so tokens issued before the edit stop validating.
+func (s *userService) UpdateUser(orgID, requestingUserID, requestingRole, targetUserID string, req models.UpdateUserRequest) (*models.UserResponse, error) {
+	if req.Name == nil && req.Role == nil && req.Status == nil {
+		return nil, errors.New("at least one of name, role or status is required")
+	}
+
+	targetUUID, err := uuid.Parse(targetUserID)
+	if err != nil {
+		return nil, errors.New("invalid target user id")
+	}
+
+	var user models.User
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND organization_id = ?", targetUUID, orgID).
+			First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("user not found")
+			}
+			return err
+		}
+
+		// 🔐 Hierarchy: requester can only edit users below their own role
+		switch requestingRole {
+		case "owner":
+			if user.Role == "owner" {
+				return errors.New("owner cannot be edited")
+			}
+		case "maintainer":
+			if user.Role != "member" {
+				return errors.New("not authorized to edit this user")
+			}
+		default:
+			return errors.New("not authorized to edit users")
+		}
+
+		updates := map[string]interface{}{}
+		bumpTokenVersion := false
+
+		if req.Name != nil {
+			if *req.Name == "" {
+				return errors.New("name cannot be empty")
+			}
+			updates["name"] = *req.Name
+		}
+
+		// Role changes follow the invite hierarchy: only the owner moves
+		// users between maintainer, member and custom org roles
+		if req.Role != nil && *req.Role != user.Role {
+			if requestingRole != "owner" {
+				return errors.New("only owner can change roles")
+			}
+			if *req.Role == "owner" {
+				return errors.New("cannot promote to owner")
+			}
+			if *req.Role != "maintainer" && *req.Role != "member" && !orgRoleExists(tx, orgID, *req.Role) {
+				return errors.New("role must be maintainer, member or a custom role defined for this organization")
+			}
+			updates["role"] = *req.Role
+			bumpTokenVersion = true
+		}
+
+		// Status follows the suspend rules
+		if req.Status != nil && *req.Status != user.Status {
+			if *req.Status != "active" && *req.Status != "suspended" {
+				return errors.New("status must be active or suspended")
+			}
+			if requestingUserID == targetUserID {
+				return errors.New("you cannot change your own status")
+			}
+			updates["status"] = *req.Status
+			if *req.Status == "active" {
+				updates["reactivated_at"] = time.Now().UTC()
+			}
+			bumpTokenVersion = true
+		}
+
+		if len(updates) == 0 {
+			return errors.New("no changes to apply")
+		}
+
+		if bumpTokenVersion {
+			updates["token_version"] = gorm.Expr("token_version + 1")
+		}
+
+		if err := tx.Model(&models.User{}).Where("id = ?", targetUUID).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", targetUUID).First(&user).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cache.InvalidateUser(targetUserID)
+
+	response := models.ToUserResponse(user)
+	return &response, nil
+}
+
+// UpdateProfile applies a self-service edit (name / email language). Role,
+// status and email stay admin-only via UpdateUser.
+func (s *userService) UpdateProfile(userID string, req models.UpdateProfileRequest) (*models.UserResponse, error) {
+	if req.Name == nil && req.Locale == nil {
+		return nil, errors.New("at least one of name or locale is required")
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user id")
+	}
+
+	updates := map[string]interface{}{}
+
+	if req.Name != nil {
+		if *req.Name == "" {
+			return nil, errors.New("name cannot be empty")
+		}
+		updates["name"] = *req.Name
+	}
+
+	if req.Locale != nil {
+		if !i18n.Supported(*req.Locale) {
+			return nil, apperrors.Validation("locale is not supported")
+		}
+		updates["locale"] = i18n.Normalize(*req.Locale)
+	}
+
+	if err := s.db.Model(&models.User{}).Where("id = ?", userUUID).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	cache.InvalidateUser(userID)
+
+	// Pin this user's reads to the primary briefly so the edit is visible
+	// on their next fetch even with a lagging replica
+	db.MarkUserWrite(userID)
+
+	var user models.User
+	if err := db.Primary(s.db).Where("id = ?", userUUID).First(&user).Error; err != nil {
+		return nil, err
+	}
+
+	response := models.ToUserResponse(user)
+	return &response, nil
+}
+
+// inviteLocale resolves the language for a newly created member: an explicit
+// request value wins, otherwise the organization's default locale.
+func inviteLocale(db *gorm.DB, orgID uuid.UUID, requested string) string {
+	if requested != "" {
+		return utils.NormalizeLocale(requested)
+	}
+	var org models.Organization
+	if err := db.Select("default_locale").Where("id = ?", orgID).First(&org).Error; err != nil {
+		return utils.DefaultLocale
+	}
+	return utils.NormalizeLocale(org.DefaultLocale)
+}
+
+func (s *userService) MarkEmailsBounced(emails []string) (int64, error) {
+	if len(emails) == 0 {
+		return 0, nil
+	}
+
+	// providers echo the address as the recipient typed it, so match
+	// case-insensitively
+	normalized := make([]string, 0, len(emails))
+	for _, email := range emails {
+		normalized = append(normalized, utils.NormalizeEmail(email))
+	}
+
+	result := s.db.Model(&models.User{}).
+		Where("LOWER(email) IN ?", normalized).
+		Update("email_status", "bounced")
+
+	return result.RowsAffected, result.Error
+}
+
+// ResendAllInvites regenerates tokens and re-queues invite emails for every
+// pending user in the org (e.g. after fixing an SMTP misconfiguration). Users
+// re-invited within ResendInviteCooldown are skipped; emails go through the
+// async queue, which applies its own rate limiting.
+func (s *userService) ResendAllInvites(orgID string) (*models.ResendAllInvitesResponse, error) {
+	var org models.Organization
+	if err := s.db.Where("id = ?", orgID).First(&org).Error; err != nil {
+		return nil, errors.New("organization not found")
+	}
+
+	// 1️⃣ Only pending users — active/suspended/deleted are untouched
+	var pending []models.User
+	if err := s.db.
+		Where("organization_id = ? AND status = ?", org.ID, "pending").
+		Order("created_at ASC").
+		Find(&pending).Error; err != nil {
+		return nil, err
+	}
+
+	response := &models.ResendAllInvitesResponse{
+		Total:   len(pending),
+		Results: make([]models.ResendInviteResult, 0, len(pending)),
+	}
+
+	frontendURL := os.Getenv("FRONTEND_BASE_URL")
+
+	for i := range pending {
+		user := &pending[i]
+		result := models.ResendInviteResult{
+			UserID: user.ID.String(),
+			Email:  user.Email,
+		}
+
+		// 2️⃣ Per-user cooldown: the row is touched every time an invite goes
+		// out, so UpdatedAt tracks the last send
+		if time.Since(user.UpdatedAt) < constants.ResendInviteCooldown {
+			result.Status = "skipped"
+			result.Reason = "resent too recently"
+			response.Skipped++
+			response.Results = append(response.Results, result)
+			continue
+		}
+
+		// 3️⃣ Regenerate token + expiry, same as a fresh invite
+		inviteToken := uuid.NewString()
+		expiresAt := time.Now().UTC().Add(constants.InviteTokenTTL)
+		user.InviteToken = &inviteToken
+		user.ExpiresAt = &expiresAt
+		if err := s.db.Save(user).Error; err != nil {
+			result.Status = "failed"
+			result.Reason = err.Error()
+			response.Failed++
+			response.Results = append(response.Results, result)
+			continue
+		}
+
+		// 4️⃣ Queue the email (async, rate-limited by the queue itself)
+		signedToken := utils.SignActionToken(utils.LinkPurposeInvite, user.ID.String(), inviteToken, expiresAt)
+		inviteLink := fmt.Sprintf("%s/pl/accept-invite?token=%s&account_id=%s", frontendURL, signedToken, org.AccountID)
+		emailSubject, emailBody, _ := utils.RenderEmail("invite", user.Locale, utils.EmailTemplateData{
+			Name:             user.Name,
+			OrganizationName: org.Name,
+			AccountID:        org.AccountID,
+			Link:             inviteLink,
+			Branding:         orgBranding(org),
+		})
+		if err := utils.QueueEmail(user.Email, emailSubject, emailBody); err != nil {
+			result.Status = "failed"
+			result.Reason = err.Error()
+			response.Failed++
+			response.Results = append(response.Results, result)
+			continue
+		}
+
+		result.Status = "queued"
+		response.Queued++
+		response.Results = append(response.Results, result)
+	}
+
+	response.EmailWarning = emailDeliveryWarning(s.db, orgID)
+	return response, nil
+}
+
+// GetInviteStats computes the invitation funnel in one grouped query over the
+// users table. A resent invite reuses the same user row, so the whole chain
+// counts as one invite; soft-deleted users stay in Sent (the invite did go
+// out), and deleting one that never accepted counts as revoked. The median
+// uses accepted_at, so only invites accepted since that column exists
+// contribute to it.
+func (s *userService) GetInviteStats(orgID string, days int) (*models.InviteStatsResponse, error) {
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		return nil, errors.New("invalid organization id")
+	}
+
+	if days < 1 || days > 365 {
+		days = 30
+	}
+
+	var row struct {
+		Sent          int64
+		Accepted      int64
+		Expired       int64
+		Revoked       int64
+		MedianSeconds *float64
+	}
+
+	// raw SQL: FILTER + percentile_cont aggregate everything server-side;
+	// Raw also skips gorm's soft-delete scope, which is what we want here
+	err = s.db.Raw(`
+		SELECT
+			COUNT(*) AS sent,
+			COUNT(*) FILTER (WHERE status = 'active') AS accepted,
+			COUNT(*) FILTER (WHERE deleted_at IS NULL AND status = 'pending' AND expires_at IS NOT NULL AND expires_at < now()) AS expired,
+			COUNT(*) FILTER (WHERE deleted_at IS NOT NULL AND accepted_at IS NULL) AS revoked,
+			EXTRACT(EPOCH FROM percentile_cont(0.5) WITHIN GROUP (ORDER BY accepted_at - created_at)
+				FILTER (WHERE accepted_at IS NOT NULL)) AS median_seconds
+		FROM users
+		WHERE organization_id = ?
+		  AND invited_by IS NOT NULL
+		  AND created_at >= now() - make_interval(days => ?)
+	`, orgUUID, days).Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.InviteStatsResponse{
+		WindowDays:            days,
+		Sent:                  row.Sent,
+		Accepted:              row.Accepted,
+		Expired:               row.Expired,
+		Revoked:               row.Revoked,
+		MedianSecondsToAccept: row.MedianSeconds,
+	}, nil
+}
+
+// maxResolveIDs caps the internal bulk lookup so one bad caller can't turn it
+// into a table scan.
+const maxResolveIDs = 100
+
+// ResolveUsers is the internal (service-key) bulk lookup behind
+// GET /internal/v1/users. Malformed and unknown IDs are reported in Missing
+// rather than failing the call, so callers can render partial lists.
+func (s *userService) ResolveUsers(ids []string) (*models.ResolveUsersResponse, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("at least one user id is required")
+	}
+	if len(ids) > maxResolveIDs {
+		return nil, fmt.Errorf("too many ids: %d (max %d)", len(ids), maxResolveIDs)
+	}
+
+	// 1️⃣ Separate parseable UUIDs from junk; junk is missing by definition
+	response := &models.ResolveUsersResponse{
+		Users:   []models.UserDisplayInfo{},
+		Missing: []string{},
+	}
+	valid := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			response.Missing = append(response.Missing, id)
+			continue
+		}
+		valid = append(valid, parsed)
+	}
+
+	// 2️⃣ One IN query for the whole batch
+	var users []models.User
+	if len(valid) > 0 {
+		if err := s.db.Where("id IN ?", valid).Find(&users).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	found := make(map[string]bool, len(users))
+	for _, u := range users {
+		found[u.ID.String()] = true
+		response.Users = append(response.Users, models.UserDisplayInfo{
+			ID:     u.ID.String(),
+			Name:   u.Name,
+			Email:  u.Email,
+			Role:   u.Role,
+			Status: u.Status,
+		})
+	}
+
+	// 3️⃣ Valid UUIDs that didn't resolve
+	for _, id := range valid {
+		if !found[id.String()] {
+			response.Missing = append(response.Missing, id.String())
+		}
+	}
+
+	return response, nil
+}
+
+// AnonymizeUser scrubs a user's PII for GDPR "right to be forgotten": name
+// and email become tombstone values, credentials and tokens are cleared, and
+// the status becomes "anonymized" (which can never log in). The row — and
+// therefore the user id referenced by messages and audit entries — stays, so
+// references still resolve, just without a real identity behind them.
+func (s *userService) AnonymizeUser(claims *utils.JWTClaims, targetUserID string) error {
+	targetUUID, err := uuid.Parse(targetUserID)
+	if err != nil {
+		return errors.New("invalid target user id")
+	}
+
+	if claims.UserID == targetUserID {
+		return errors.New("you cannot anonymize yourself")
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var target models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND organization_id = ?", targetUUID, claims.OrganizationID).
+			First(&target).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("user not found")
+			}
+			return err
+		}
+
+		if target.Role == "owner" {
+			return errors.New("owner cannot be anonymized")
+		}
+		if target.Status == "anonymized" {
+			return errors.New("user is already anonymized")
+		}
+
+		// Tombstone values: the id keeps the email unique per org without
+		// carrying any PII
+		return tx.Model(&models.User{}).Where("id = ?", targetUUID).Updates(map[string]interface{}{
+			"name":          "Deleted User",
+			"email":         fmt.Sprintf("anonymized-%s@deleted.invalid", targetUUID),
+			"email_status":  "ok",
+			"password":      "",
+			"invite_token":  nil,
+			"expires_at":    nil,
+			"locale":        "en",
+			"status":        "anonymized",
+			"token_version": gorm.Expr("token_version + 1"),
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	cache.InvalidateUser(targetUserID)
+	// Original id, no PII — the audit trail must not re-identify the person
+	recordAudit(s.db, claims, "user.anonymize", fmt.Sprintf("user %s anonymized", targetUserID))
+	return nil
+}
+
+// ForceActivateUser is the support escape hatch for users whose verification
+// email never arrived: an owner activates the account directly. The route is
+// owner-only (members/maintainers can't reach it, so nobody self-promotes),
+// the target must be in the caller's org, and the manual action lands in the
+// audit log with the acting owner.
+func (s *userService) ForceActivateUser(claims *utils.JWTClaims, targetUserID string) error {
+	targetUUID, err := uuid.Parse(targetUserID)
+	if err != nil {
+		return errors.New("invalid target user id")
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var target models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND organization_id = ?", targetUUID, claims.OrganizationID).
+			First(&target).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("user not found")
+			}
+			return err
+		}
+
+		if target.Status == "active" {
+			return apperrors.Conflict("user is already active")
+		}
+		if target.Status == "anonymized" {
+			return apperrors.Conflict("anonymized users cannot be reactivated")
+		}
+
+		// Activate and invalidate any outstanding verification/invite token
+		return tx.Model(&models.User{}).Where("id = ?", targetUUID).Updates(map[string]interface{}{
+			"status":       "active",
+			"invite_token": nil,
+			"expires_at":   nil,
 		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	cache.InvalidateUser(targetUserID)
+	recordAudit(s.db, claims, "user.force_activate",
+		fmt.Sprintf("user %s manually activated by support action", targetUserID))
+
+	events.Publish(events.Event{
+		Type:    "user.verified",
+		OrgID:   claims.OrganizationID,
+		ActorID: targetUserID,
+	})
+	return nil
 }
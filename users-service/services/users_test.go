@@ -0,0 +1,49 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/models"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	"gorm.io/gorm"
+)
+
+func TestDeletedUsersAreInvisibleToNormalQueries(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	svc := NewUserService(db)
+
+	org := testutil.NewOrganization(db).Active().Build()
+	owner := testutil.NewUser(db, org).Owner().Active().Build()
+	member := testutil.NewUser(db, org).Active().Build()
+
+	if err := svc.DeleteUser(org.ID.String(), owner.ID.String(), "owner", member.ID.String()); err != nil {
+		t.Fatalf("DeleteUser failed: %v", err)
+	}
+
+	// gorm's soft-delete scope hides the row from every normal query
+	var loaded models.User
+	err := db.First(&loaded, "id = ?", member.ID).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("deleted user still visible: err=%v", err)
+	}
+
+	var count int64
+	if err := db.Model(&models.User{}).Where("organization_id = ?", org.ID).Count(&count).Error; err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("org user count = %d, want 1 (owner only)", count)
+	}
+
+	// Unscoped still reaches the row, with the deletion recorded
+	if err := db.Unscoped().First(&loaded, "id = ?", member.ID).Error; err != nil {
+		t.Fatalf("Unscoped lookup failed: %v", err)
+	}
+	if !loaded.DeletedAt.Valid {
+		t.Error("DeletedAt not set on the soft-deleted row")
+	}
+	if loaded.DeletedBy == nil || *loaded.DeletedBy != owner.ID {
+		t.Errorf("DeletedBy = %v, want %s", loaded.DeletedBy, owner.ID)
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/apperrors"
+	"github.com/Bipul-Dubey/ai-knowledgebase/shared/testutil"
+	utils "github.com/Bipul-Dubey/ai-knowledgebase/shared/utils"
+)
+
+// signedVerifyLink wraps a pending user's raw token the way the verification
+// email does.
+func signedVerifyLink(h *authHarness, userID, token string) string {
+	return utils.SignActionToken(utils.LinkPurposeVerify, userID, token, h.clock.Now().Add(time.Hour))
+}
+
+func TestDoubleVerificationIsIdempotent(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Build()
+	user := testutil.NewUser(h.db, org).Owner().Pending(time.Hour).Build()
+	link := signedVerifyLink(h, user.ID.String(), *user.InviteToken)
+
+	first, err := h.auth.VerifyAccount(context.Background(), link)
+	if err != nil {
+		t.Fatalf("first verification failed: %v", err)
+	}
+	if !first.IsVerified || first.Status != "active" {
+		t.Fatalf("first verification: %+v, want verified/active", first)
+	}
+
+	// Double-clicking the same link is a success, not a scary error
+	second, err := h.auth.VerifyAccount(context.Background(), link)
+	if err != nil {
+		t.Fatalf("re-verification errored: %v", err)
+	}
+	if !second.IsVerified || second.UserID != user.ID {
+		t.Errorf("re-verification: %+v, want the same verified user", second)
+	}
+}
+
+func TestVerificationErrorClassesStayDistinct(t *testing.T) {
+	h := newAuthHarness(t)
+	org := testutil.NewOrganization(h.db).Build()
+	user := testutil.NewUser(h.db, org).Owner().Pending(time.Hour).Build()
+	link := signedVerifyLink(h, user.ID.String(), *user.InviteToken)
+
+	// Expired: the clock moves past the row's expiry before anyone clicks
+	h.clock.Advance(2 * time.Hour)
+	_, err := h.auth.VerifyAccount(context.Background(), link)
+	if err == nil {
+		t.Fatal("an expired token verified successfully")
+	}
+	if code := apperrors.Code(err); code != "token_expired" {
+		t.Errorf("expired token code = %q, want token_expired", code)
+	}
+
+	// Invalid: an unknown token is a different failure than an expired one
+	_, err = h.auth.VerifyAccount(context.Background(), "never-issued-token")
+	if err == nil {
+		t.Fatal("an unknown token verified successfully")
+	}
+	if code := apperrors.Code(err); code != "invalid_token" {
+		t.Errorf("unknown token code = %q, want invalid_token", code)
+	}
+}